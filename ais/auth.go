@@ -14,15 +14,20 @@
 //         a user should have logged at least once to GCP before running any AIStore operation
 // 2. AuthN server is enabled and everything is set up
 //    - AIS reads userID from HTTP request header: 'Authorization: Bearer <token>'.
+//    - S3 requests are signed with AWS Signature V4 using access key pairs
+//      that are generated by AuthN and pushed to the cluster.
 package ais
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/cmn/jsp"
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
@@ -33,11 +38,27 @@ type (
 		Version int64    `json:"version,string"`
 	}
 
+	// AccessKey is a pair of AWS-style credentials of a user; the secret
+	// is used to verify S3 requests signed with AWS Signature V4. The secret
+	// is never stored or sent in plaintext: it is encrypted with the key derived
+	// from the cluster secret (see SealAccessSecret).
+	AccessKey struct {
+		UserID string `json:"user"`
+		Secret string `json:"secret"`          // encrypted secret access key
+		Admin  bool   `json:"admin,omitempty"` // the key of the superuser
+	}
+	// AccessKeyList is the full list of access keys pushed by authn
+	AccessKeyList struct {
+		Keys    map[string]*AccessKey `json:"keys"` // access key ID => key
+		Version int64                 `json:"version,string"`
+	}
+
 	authRec struct {
 		userID  string
 		issued  time.Time
 		expires time.Time
 		isGuest bool
+		isAdmin bool
 	}
 
 	authList map[string]*authRec
@@ -50,12 +71,20 @@ type (
 		// Authn sends these tokens to primary for broadcasting
 		revokedTokens map[string]bool
 		version       int64
+		// access keys to verify S3 requests (persisted)
+		accessKeys *AccessKeyList
 	}
 )
 
+const (
+	accessKeysFname   = ".ais.keys"       // access key list basename
+	accessKeysPurpose = "ais access keys" // see cmn.NewSecretCipher
+)
+
 var (
 	// interface guard
 	_ revs = &TokenList{}
+	_ revs = &AccessKeyList{}
 )
 
 var guestAcc = &authRec{
@@ -102,6 +131,7 @@ func decryptToken(tokenStr string) (*authRec, error) {
 	if rec.expires, err = time.Parse(time.RFC822, expireStr); err != nil {
 		return nil, invalTokenErr
 	}
+	rec.isAdmin, _ = claims["admin"].(bool) // optional
 
 	return rec, nil
 }
@@ -144,6 +174,7 @@ func (a *authManager) updateRevokedList(tokens *TokenList) {
 //   - must not be revoked one
 //   - must not be expired
 //   - must have all mandatory fields: userID, creds, issued, expires
+//
 // Returns decrypted token information if it is valid
 func (a *authManager) validateToken(token string) (ar *authRec, err error) {
 	a.Lock()
@@ -205,11 +236,9 @@ func (a *authManager) revokedTokenList() *TokenList {
 	return tlist
 }
 
-//
 // Implementation of revs interface
 //
 // Token list doesn't need versioning: receivers keep adding received tokens to their internal lists
-//
 func (t *TokenList) tag() string    { return revsTokenTag }
 func (t *TokenList) version() int64 { return t.Version }
 func (t *TokenList) marshal() []byte {
@@ -217,3 +246,92 @@ func (t *TokenList) marshal() []byte {
 	cmn.AssertNoErr(err)
 	return b
 }
+
+//
+// Access keys
+//
+
+// Loads the access key list saved by the previous run
+func (a *authManager) loadAccessKeys(config *cmn.Config) {
+	keys := &AccessKeyList{Keys: make(map[string]*AccessKey)}
+	fpath := filepath.Join(config.Confdir, accessKeysFname)
+	if err := jsp.Load(fpath, keys, jsp.CCSign()); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("failed to load access keys from %s, err: %v", fpath, err)
+	}
+	a.Lock()
+	a.accessKeys = keys
+	a.Unlock()
+}
+
+// Replaces the access key list with the received one. Unlike revoked tokens,
+// the list always comes in full, so the older lists are ignored.
+func (a *authManager) updateAccessKeys(keys *AccessKeyList) {
+	if keys == nil {
+		return
+	}
+	a.Lock()
+	if keys.Version == 0 {
+		// authn pushed a new list
+		keys.Version = a.accessKeys.Version + 1
+	} else if keys.Version <= a.accessKeys.Version {
+		glog.Errorf("Current access key list v%d is greater than or equal to received v%d",
+			a.accessKeys.Version, keys.Version)
+		a.Unlock()
+		return
+	}
+	if keys.Keys == nil {
+		keys.Keys = make(map[string]*AccessKey)
+	}
+	a.accessKeys = keys
+	a.Unlock()
+
+	fpath := filepath.Join(cmn.GCO.Get().Confdir, accessKeysFname)
+	if err := jsp.Save(fpath, keys, jsp.CCSign()); err != nil {
+		glog.Errorf("failed to save access keys to %s, err: %v", fpath, err)
+	}
+}
+
+func (a *authManager) accessKeyList() *AccessKeyList {
+	a.Lock()
+	keys := a.accessKeys
+	a.Unlock()
+	return keys
+}
+
+func (a *authManager) accessKey(id string) (*AccessKey, bool) {
+	a.Lock()
+	key, ok := a.accessKeys.Keys[id]
+	a.Unlock()
+	return key, ok
+}
+
+// SealAccessSecret encrypts the secret access key with the key derived from
+// the cluster secret (`auth.secret`) that is shared by AuthN and the cluster.
+func SealAccessSecret(authSecret, secret string) (string, error) {
+	aead, err := cmn.NewSecretCipher(authSecret, accessKeysPurpose)
+	if err != nil {
+		return "", err
+	}
+	return cmn.SealSecret(aead, secret)
+}
+
+// Returns decrypted secret access key.
+func (k *AccessKey) secret() (string, error) {
+	aead, err := cmn.NewSecretCipher(cmn.GCO.Get().Auth.Secret, accessKeysPurpose)
+	if err != nil {
+		return "", err
+	}
+	secret, err := cmn.OpenSecret(aead, k.Secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the secret of the access key of user %q: %v", k.UserID, err)
+	}
+	return secret, nil
+}
+
+func (k *AccessKeyList) tag() string    { return revsAccessKeyTag }
+func (k *AccessKeyList) version() int64 { return k.Version }
+func (k *AccessKeyList) marshal() []byte {
+	b, err := jsonCompat.Marshal(k)
+	cmn.AssertNoErr(err)
+	return b
+}
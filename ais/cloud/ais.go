@@ -68,7 +68,7 @@ func (r *remAisClust) String() string {
 	return fmt.Sprintf("%s: %s => (%q, %v, %s)", aisCloudPrefix, r.url, aliases, r.smap.UUID, r.smap)
 }
 
-// NOTE: this and the next method are part of the of the *extended* AIS cloud API
+// NOTE: this and the next two methods are part of the of the *extended* AIS cloud API
 //       in addition to the basic GetObj, et al.
 
 // apply new or updated (attach, detach) cmn.CloudConfAIS configuration
@@ -176,6 +176,16 @@ func (m *AisCloudProvider) GetInfo(clusterConf cmn.CloudConfAIS) (cia cmn.CloudI
 	return
 }
 
+// BaseParams returns API parameters to access the attached remote cluster
+// given its UUID or alias (used by the bucket replication, see: replication).
+func (m *AisCloudProvider) BaseParams(uuid string) (api.BaseParams, error) {
+	remAis, err := m.remoteCluster(uuid)
+	if err != nil {
+		return api.BaseParams{}, err
+	}
+	return remAis.bp, nil
+}
+
 // A list of remote AIS URLs can contains both HTTP and HTTPS links at the
 // same time. So, the method must use both kind of clients and select the
 // correct one at the moment it sends a request. First successful request
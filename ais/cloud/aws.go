@@ -10,6 +10,7 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -98,6 +99,9 @@ func (awsp *awsProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg
 	if msg.PageMarker != "" {
 		params.Marker = aws.String(msg.PageMarker)
 	}
+	if msg.Delimiter != "" {
+		params.Delimiter = aws.String(msg.Delimiter)
+	}
 	if msg.PageSize != 0 {
 		if msg.PageSize > awsMaxPageSize {
 			glog.Warningf("AWS maximum page size is %d (%d requested). Returning the first %d keys",
@@ -127,6 +131,13 @@ func (awsp *awsProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg
 
 		bckList.Entries = append(bckList.Entries, entry)
 	}
+	if len(resp.CommonPrefixes) != 0 {
+		for _, prefix := range resp.CommonPrefixes {
+			entry := &cmn.BucketEntry{Name: *prefix.Prefix, Flags: cmn.EntryIsDir}
+			bckList.Entries = append(bckList.Entries, entry)
+		}
+		sort.Slice(bckList.Entries, func(i, j int) bool { return bckList.Entries[i].Name < bckList.Entries[j].Name })
+	}
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("[list_bucket] count %d", len(bckList.Entries))
 	}
@@ -134,7 +145,11 @@ func (awsp *awsProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg
 	if *resp.IsTruncated {
 		// For AWS, resp.NextMarker is only set when a query has a delimiter.
 		// Without a delimiter, NextMarker should be the last returned key.
-		bckList.PageMarker = bckList.Entries[len(bckList.Entries)-1].Name
+		if resp.NextMarker != nil {
+			bckList.PageMarker = *resp.NextMarker
+		} else {
+			bckList.PageMarker = bckList.Entries[len(bckList.Entries)-1].Name
+		}
 	}
 
 	if len(bckList.Entries) == 0 {
@@ -304,7 +319,7 @@ func (awsp *awsProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 	// may not have ais metadata
 	if cksumType, ok := obj.Metadata[awsChecksumType]; ok {
 		if cksumValue, ok := obj.Metadata[awsChecksumVal]; ok {
-			cksum = cmn.NewCksum(*cksumType, *cksumValue)
+			cksum = aisCloudCksum(*cksumType, *cksumValue)
 		}
 	}
 
@@ -313,6 +328,7 @@ func (awsp *awsProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 	if !strings.Contains(md5, awsMultipartDelim) {
 		cksumToCheck = cmn.NewCksum(cmn.ChecksumMD5, md5)
 	}
+	cksumToCheck = coldGetCksum(lom, cksum, cksumToCheck)
 	lom.SetCksum(cksum)
 	if obj.VersionId != nil {
 		lom.SetVersion(*obj.VersionId)
@@ -6,6 +6,10 @@ package cloud
 
 import (
 	"context"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
 )
 
 // Declare a new type for Context field names
@@ -37,3 +41,47 @@ func getStringFromContext(ct context.Context, fieldName contextID) string {
 
 	return strVal
 }
+
+// Returns the checksum that ais has stored with the Cloud object's metadata
+// (when PUT via ais), nil if it is missing or of unsupported type.
+//
+// nolint:unused,deadcode // used by `aws` and `gcp` but needs to be compiled by tags
+func aisCloudCksum(cksumType, cksumValue string) *cmn.Cksum {
+	if cmn.ValidateCksumType(cksumType) != nil {
+		return nil
+	}
+	return cmn.NewCksum(cksumType, cksumValue)
+}
+
+// Selects the checksum to validate the cold GET against: the one stored by ais
+// with the object, if computed with the bucket's checksum type (eg. sha256),
+// otherwise the one exposed by the Cloud provider (eg. md5).
+//
+// nolint:unused,deadcode // used by `aws` and `gcp` but needs to be compiled by tags
+func coldGetCksum(lom *cluster.LOM, aisCksum, cloudCksum *cmn.Cksum) *cmn.Cksum {
+	if aisCksum != nil && aisCksum.Type() == lom.CksumConf().Type {
+		return aisCksum
+	}
+	return cloudCksum
+}
+
+// Adds the object to the page of the listed objects or, when listing with
+// a delimiter, rolls it up into a directory entry (see cmn.SelectMsg.DirName).
+// Used by the providers that list objects themselves (fs, http, and hdfs),
+// the objects must be added in lexicographical order. The entry of the object
+// is created by `newEntry`. Returns true when the page is full.
+func addListEntry(bckList *cmn.BucketList, msg *cmn.SelectMsg, name string, pageSize int,
+	newEntry func() *cmn.BucketEntry) bool {
+	if !strings.HasPrefix(name, msg.Prefix) || msg.SkipMarked(name) {
+		return false
+	}
+	if dir, ok := msg.DirName(name); ok {
+		if l := len(bckList.Entries); l > 0 && bckList.Entries[l-1].Name == dir {
+			return false
+		}
+		bckList.Entries = append(bckList.Entries, &cmn.BucketEntry{Name: dir, Flags: cmn.EntryIsDir})
+	} else {
+		bckList.Entries = append(bckList.Entries, newEntry())
+	}
+	return len(bckList.Entries) == pageSize
+}
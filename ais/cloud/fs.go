@@ -0,0 +1,329 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// fs:// buckets are backed by a POSIX directory (e.g. NFS mount) that must be
+// accessible by all targets under the same path (see cmn.ExtraProps). Objects
+// are files in the directory and its subdirectories: GET cold-reads the file,
+// PUT writes it through, and listing walks the directory.
+// Files do not have versions - modification time (in nanoseconds) is used instead.
+
+type (
+	fsProvider struct {
+		t cluster.Target
+	}
+)
+
+// PUT writes a temporary file next to the destination and renames it
+const fsTmpSuffix = ".ais-tmp"
+
+var (
+	_ cluster.CloudProvider = &fsProvider{}
+)
+
+func NewFS(t cluster.Target) cluster.CloudProvider { return &fsProvider{t: t} }
+
+func fsBucketPath(bck *cluster.Bck) string {
+	if bck.Props == nil {
+		return ""
+	}
+	return filepath.Clean(bck.Props.Extra.Path)
+}
+
+func fsVersion(finfo os.FileInfo) string { return strconv.FormatInt(finfo.ModTime().UnixNano(), 10) }
+
+// resolves symlinks in the longest existing prefix of the path (the rest, e.g.
+// the object that is about to be created, does not exist yet)
+func fsEvalSymlinks(p string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(p)
+	if err == nil || !os.IsNotExist(err) {
+		return resolved, err
+	}
+	parent := filepath.Dir(p)
+	if parent == p {
+		return p, nil
+	}
+	if resolved, err = fsEvalSymlinks(parent); err != nil {
+		return "", err
+	}
+	return filepath.Join(resolved, filepath.Base(p)), nil
+}
+
+// returns the bucket's directory with symlinks resolved; the directory must be
+// located under one of the configured roots (see cmn.FSBucketsConf)
+func (fp *fsProvider) bucketDir(bck *cluster.Bck) (string, error, int) {
+	dir := fsBucketPath(bck)
+	if dir == "" {
+		return "", cmn.NewErrorRemoteBucketDoesNotExist(bck.Bck, fp.t.Snode().Name()), http.StatusNotFound
+	}
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		err, errCode := fp.fsErrorToAISError(err, bck.Bck, "")
+		return "", err, errCode
+	}
+	roots := cmn.FSBucketsConf{Roots: make([]string, 0, len(cmn.GCO.Get().FSBuckets.Roots))}
+	for _, root := range cmn.GCO.Get().FSBuckets.Roots {
+		if r, err := filepath.EvalSymlinks(root); err == nil {
+			root = r
+		}
+		roots.Roots = append(roots.Roots, root)
+	}
+	if !roots.Allows(resolved) {
+		return "", fmt.Errorf("%s: %q is not under any of the allowed roots (fs_buckets.roots)", bck, dir),
+			http.StatusForbidden
+	}
+	return resolved, nil, 0
+}
+
+// object name must not escape the bucket's directory (e.g. via ".." or symlinks)
+func (fp *fsProvider) objPath(bck *cluster.Bck, objName string) (string, error, int) {
+	dir, err, errCode := fp.bucketDir(bck)
+	if err != nil {
+		return "", err, errCode
+	}
+	p := filepath.Join(dir, objName)
+	if !strings.HasPrefix(p, dir+string(filepath.Separator)) || strings.HasSuffix(p, fsTmpSuffix) {
+		return "", fmt.Errorf("invalid object name %q", objName), http.StatusBadRequest
+	}
+	if p, err = fsEvalSymlinks(p); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	if !strings.HasPrefix(p, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object name %q: resolves outside of the bucket", objName), http.StatusBadRequest
+	}
+	return p, nil, 0
+}
+
+func (fp *fsProvider) fsErrorToAISError(err error, bck cmn.Bck, objName string) (error, int) {
+	if !os.IsNotExist(err) {
+		return err, http.StatusInternalServerError
+	}
+	if objName == "" {
+		return cmn.NewErrorRemoteBucketDoesNotExist(bck, fp.t.Snode().Name()), http.StatusNotFound
+	}
+	msg := fmt.Sprintf("%s/%s not found", bck, objName)
+	return &cmn.HTTPError{Status: http.StatusNotFound, Message: msg}, http.StatusNotFound
+}
+
+func (fp *fsProvider) statObj(bck *cluster.Bck, objName string) (p string, finfo os.FileInfo, err error, errCode int) {
+	if p, err, errCode = fp.objPath(bck, objName); err != nil {
+		return
+	}
+	if finfo, err = os.Stat(p); err == nil && finfo.IsDir() {
+		err = os.ErrNotExist
+	}
+	if err != nil {
+		err, errCode = fp.fsErrorToAISError(err, bck.Bck, objName)
+	}
+	return
+}
+
+func (fp *fsProvider) Provider() string {
+	return cmn.ProviderFS
+}
+
+// fs:// buckets exist only in BMD: they cannot be discovered
+func (fp *fsProvider) ListBuckets(ctx context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	return cmn.BucketNames{}, nil, 0
+}
+
+func (fp *fsProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	var dir string
+	if dir, err, errCode = fp.bucketDir(bck); err != nil {
+		return cmn.SimpleKVs{}, err, errCode
+	}
+	finfo, err := os.Stat(dir)
+	if err == nil && !finfo.IsDir() {
+		err = os.ErrNotExist
+	}
+	if err != nil {
+		err, errCode = fp.fsErrorToAISError(err, bck.Bck, "")
+		return cmn.SimpleKVs{}, err, errCode
+	}
+	bckProps = make(cmn.SimpleKVs, 3)
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderFS
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	bckProps[cmn.HeaderBucketPath] = fsBucketPath(bck)
+	return
+}
+
+// Objects are listed in lexicographical order of their names via depth-first
+// traversal, skipping subdirectories that cannot contain the requested page.
+func (fp *fsProvider) ListObjects(ctx context.Context, bck *cluster.Bck,
+	msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	var (
+		dir      string
+		pageSize = msg.PageSize
+	)
+	if dir, err, errCode = fp.bucketDir(bck); err != nil {
+		return
+	}
+	if pageSize == 0 {
+		pageSize = cmn.DefaultListPageSize
+	}
+	bckList = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, initialBucketListSize)}
+	if _, err = fp.listDir(dir, "", msg, pageSize, bckList); err != nil {
+		err, errCode = fp.fsErrorToAISError(err, bck.Bck, "")
+		return nil, err, errCode
+	}
+	if len(bckList.Entries) == pageSize {
+		msg.PageMarker = bckList.Entries[len(bckList.Entries)-1].Name
+		bckList.PageMarker = msg.PageMarker
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d(marker: %s)", len(bckList.Entries), bckList.PageMarker)
+	}
+	return
+}
+
+// returns true when the page is full
+func (fp *fsProvider) listDir(dir, rel string, msg *cmn.SelectMsg, pageSize int, bckList *cmn.BucketList) (bool, error) {
+	finfos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false, err
+	}
+	// names of directories sort as if followed by the separator
+	sortKey := func(finfo os.FileInfo) string {
+		if finfo.IsDir() {
+			return finfo.Name() + "/"
+		}
+		return finfo.Name()
+	}
+	sort.Slice(finfos, func(i, j int) bool { return sortKey(finfos[i]) < sortKey(finfos[j]) })
+	for _, finfo := range finfos {
+		name := rel + sortKey(finfo)
+		if finfo.IsDir() {
+			if !strings.HasPrefix(name, msg.Prefix) && !strings.HasPrefix(msg.Prefix, name) {
+				continue
+			}
+			if name <= msg.PageMarker && !strings.HasPrefix(msg.PageMarker, name) {
+				continue // the entire subtree has been listed
+			}
+			full, err := fp.listDir(filepath.Join(dir, finfo.Name()), name, msg, pageSize, bckList)
+			if full || err != nil {
+				return full, err
+			}
+			continue
+		}
+		if !finfo.Mode().IsRegular() || strings.HasSuffix(name, fsTmpSuffix) {
+			continue
+		}
+		full := addListEntry(bckList, msg, name, pageSize, func() *cmn.BucketEntry {
+			entry := &cmn.BucketEntry{Name: name}
+			if strings.Contains(msg.Props, cmn.GetPropsSize) {
+				entry.Size = finfo.Size()
+			}
+			if strings.Contains(msg.Props, cmn.GetPropsVersion) {
+				entry.Version = fsVersion(finfo)
+			}
+			return entry
+		})
+		if full {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (fp *fsProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	var finfo os.FileInfo
+	if _, finfo, err, errCode = fp.statObj(lom.Bck(), lom.ObjName); err != nil {
+		return cmn.SimpleKVs{}, err, errCode
+	}
+	objMeta = make(cmn.SimpleKVs, 3)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderFS
+	objMeta[cmn.HeaderObjSize] = strconv.FormatInt(finfo.Size(), 10)
+	objMeta[cmn.HeaderObjVersion] = fsVersion(finfo)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s", lom)
+	}
+	return
+}
+
+func (fp *fsProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	var (
+		p     string
+		finfo os.FileInfo
+		file  *os.File
+	)
+	if p, finfo, err, errCode = fp.statObj(lom.Bck(), lom.ObjName); err != nil {
+		return
+	}
+	if file, err = os.Open(p); err != nil {
+		return fp.fsErrorToAISError(err, lom.Bck().Bck, lom.ObjName)
+	}
+	defer file.Close()
+	lom.SetVersion(fsVersion(finfo))
+	err = fp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       file,
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+func (fp *fsProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	var (
+		p     string
+		finfo os.FileInfo
+		bck   = lom.Bck()
+	)
+	if p, err, errCode = fp.objPath(bck, lom.ObjName); err != nil {
+		return
+	}
+	buf, slab := fp.t.GetMMSA().Alloc()
+	defer slab.Free(buf)
+	// the bucket's directory must exist (e.g., NFS is mounted)
+	tmpPath := p + "." + cmn.GenTie() + fsTmpSuffix
+	if _, err = cmn.SaveReaderSafe(tmpPath, p, r, buf, cmn.ChecksumNone, -1, fsBucketPath(bck)); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	if finfo, err = os.Stat(p); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[put_object] %s", lom)
+	}
+	return fsVersion(finfo), nil, http.StatusOK
+}
+
+func (fp *fsProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (err error, errCode int) {
+	var p string
+	if p, _, err, errCode = fp.statObj(lom.Bck(), lom.ObjName); err != nil {
+		return
+	}
+	if err = os.Remove(p); err != nil {
+		return fp.fsErrorToAISError(err, lom.Bck().Bck, lom.ObjName)
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[delete_object] %s", lom)
+	}
+	return nil, http.StatusOK
+}
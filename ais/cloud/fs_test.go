@@ -0,0 +1,107 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestFSListObjects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fs-bucket")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"a.txt", "a/b.txt", "a/c/d.txt", "a-b.txt", "z.txt", "z.txt" + fsTmpSuffix} {
+		path := filepath.Join(dir, name)
+		tassert.CheckFatal(t, cmn.CreateDir(filepath.Dir(path)))
+		tassert.CheckFatal(t, ioutil.WriteFile(path, []byte(name), 0644))
+	}
+
+	outside, err := ioutil.TempDir("", "fs-outside")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(outside)
+	tassert.CheckFatal(t, os.Symlink(outside, filepath.Join(dir, "escape")))
+
+	config := cmn.GCO.BeginUpdate()
+	config.FSBuckets.Roots = []string{dir}
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.FSBuckets.Roots = nil
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	var (
+		ctx   = context.Background()
+		fp    = NewFS(cluster.NewTargetMock(cluster.NewBaseBownerMock())).(*fsProvider)
+		props = cmn.DefaultBucketProps()
+	)
+	props.Provider = cmn.ProviderFS
+	props.Extra = cmn.ExtraProps{Path: dir + "/"}
+	bck := cluster.NewBck("nfs", cmn.ProviderFS, cmn.NsGlobal, props)
+	tassert.CheckFatal(t, props.Extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderFS}))
+
+	bckProps, err, _ := fp.HeadBucket(ctx, bck)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bckProps[cmn.HeaderBucketPath] == dir, "unexpected props %v", bckProps)
+
+	all := []string{"a-b.txt", "a.txt", "a/b.txt", "a/c/d.txt", "z.txt"}
+	tests := []struct {
+		msg      *cmn.SelectMsg
+		expected []string
+	}{
+		{&cmn.SelectMsg{}, all},
+		{&cmn.SelectMsg{Prefix: "a/"}, []string{"a/b.txt", "a/c/d.txt"}},
+		{&cmn.SelectMsg{Prefix: "a/c"}, []string{"a/c/d.txt"}},
+		{&cmn.SelectMsg{PageSize: 3}, all[:3]},
+		{&cmn.SelectMsg{PageSize: 3, PageMarker: "a/b.txt"}, all[3:]},
+		{&cmn.SelectMsg{Delimiter: "/"}, []string{"a-b.txt", "a.txt", "a/", "z.txt"}},
+		{&cmn.SelectMsg{Delimiter: "/", Prefix: "a/"}, []string{"a/b.txt", "a/c/"}},
+		{&cmn.SelectMsg{Delimiter: "/", PageSize: 3}, []string{"a-b.txt", "a.txt", "a/"}},
+		{&cmn.SelectMsg{Delimiter: "/", PageSize: 3, PageMarker: "a/"}, []string{"z.txt"}},
+	}
+	for _, test := range tests {
+		bckList, err, _ := fp.ListObjects(ctx, bck, test.msg)
+		tassert.CheckFatal(t, err)
+		names := make([]string, 0, len(bckList.Entries))
+		for _, entry := range bckList.Entries {
+			names = append(names, entry.Name)
+		}
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "%+v: expected %v, got %v", test.msg, test.expected, names)
+	}
+
+	// object names must stay within the bucket's directory
+	for _, objName := range []string{"../etc/passwd", "a/../../x", "", "z.txt" + fsTmpSuffix, "escape/x", "escape"} {
+		_, err, errCode := fp.objPath(bck, objName)
+		tassert.Errorf(t, err != nil && errCode == http.StatusBadRequest, "%q: expected %d, got %d (%v)",
+			objName, http.StatusBadRequest, errCode, err)
+	}
+	p, err, _ := fp.objPath(bck, "a/c/d.txt")
+	tassert.CheckFatal(t, err)
+	resolved, err := filepath.EvalSymlinks(dir)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, p == filepath.Join(resolved, "a/c/d.txt"), "unexpected path %q", p)
+
+	// the bucket's directory must be under one of the allowed roots
+	for _, path := range []string{outside, filepath.Join(dir, "escape")} {
+		props.Extra.Path = path
+		_, err, errCode := fp.ListObjects(ctx, bck, &cmn.SelectMsg{})
+		tassert.Errorf(t, err != nil && errCode == http.StatusForbidden, "%q: expected %d, got %d (%v)",
+			path, http.StatusForbidden, errCode, err)
+	}
+	props.Extra.Path = outside
+	err = props.Extra.ValidateAsProps(&cmn.ValidationArgs{Provider: cmn.ProviderFS})
+	tassert.Errorf(t, err != nil, "expected %q to be rejected", outside)
+}
@@ -116,8 +116,8 @@ func (gcpp *gcpProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg
 		cloudBck  = bck.CloudBck()
 	)
 
-	if msg.Prefix != "" {
-		query = &storage.Query{Prefix: msg.Prefix}
+	if msg.Prefix != "" || msg.Delimiter != "" {
+		query = &storage.Query{Prefix: msg.Prefix, Delimiter: msg.Delimiter}
 	}
 	if msg.PageMarker != "" {
 		pageToken = msg.PageMarker
@@ -139,6 +139,11 @@ func (gcpp *gcpProvider) ListObjects(ctx context.Context, bck *cluster.Bck, msg
 	bckList = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, initialBucketListSize)}
 	bckList.PageMarker = nextPageToken
 	for _, attrs := range objs {
+		// synthetic directory entry (listing with a delimiter)
+		if attrs.Prefix != "" {
+			bckList.Entries = append(bckList.Entries, &cmn.BucketEntry{Name: attrs.Prefix, Flags: cmn.EntryIsDir})
+			continue
+		}
 		entry := &cmn.BucketEntry{}
 		entry.Name = attrs.Name
 		if strings.Contains(msg.Props, cmn.GetPropsSize) {
@@ -265,8 +270,8 @@ func (gcpp *gcpProvider) GetObj(ctx context.Context, workFQN string, lom *cluste
 		return
 	}
 
-	cksum := cmn.NewCksum(attrs.Metadata[gcpChecksumType], attrs.Metadata[gcpChecksumVal])
-	cksumToCheck := cmn.NewCksum(cmn.ChecksumMD5, hex.EncodeToString(attrs.MD5))
+	cksum := aisCloudCksum(attrs.Metadata[gcpChecksumType], attrs.Metadata[gcpChecksumVal])
+	cksumToCheck := coldGetCksum(lom, cksum, cmn.NewCksum(cmn.ChecksumMD5, hex.EncodeToString(attrs.MD5)))
 
 	rc, err := o.NewReader(gctx)
 	if err != nil {
@@ -0,0 +1,482 @@
+// +build hdfs
+
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// HDFS is accessed via WebHDFS REST API (https://hadoop.apache.org/docs/stable/hadoop-project-dist/hadoop-hdfs/WebHDFS.html).
+// Buckets are directories under the configured root (see cmn.CloudConfHDFS), objects are files
+// inside those directories; object names with slashes map to nested directories.
+// HDFS does not version files - modification time (in milliseconds) is used instead.
+
+type (
+	hdfsProvider struct {
+		t          cluster.Target
+		conf       cmn.CloudConfHDFS
+		nameNode   *url.URL
+		client     *http.Client // follows redirects to datanodes
+		noRedirect *http.Client // CREATE: datanode location is needed to send data
+	}
+	hdfsFileStatus struct {
+		PathSuffix       string `json:"pathSuffix"`
+		Type             string `json:"type"`
+		Length           int64  `json:"length"`
+		ModificationTime int64  `json:"modificationTime"`
+	}
+	hdfsFileStatusResp struct {
+		FileStatus hdfsFileStatus `json:"FileStatus"`
+	}
+	hdfsListStatusResp struct {
+		FileStatuses struct {
+			FileStatus []hdfsFileStatus `json:"FileStatus"`
+		} `json:"FileStatuses"`
+	}
+	hdfsBooleanResp struct {
+		Boolean bool `json:"boolean"`
+	}
+	hdfsError struct {
+		status    int
+		Exception struct {
+			Exception string `json:"exception"`
+			Message   string `json:"message"`
+		} `json:"RemoteException"`
+	}
+)
+
+const (
+	webhdfsPrefix = "/webhdfs/v1"
+
+	hdfsTypeFile = "FILE"
+	hdfsTypeDir  = "DIRECTORY"
+
+	hdfsFileNotFound = "FileNotFoundException"
+)
+
+var (
+	_ cluster.CloudProvider = &hdfsProvider{}
+
+	errHDFSNotFile = errors.New("not a file")
+)
+
+func NewHDFS(t cluster.Target, config *cmn.Config) (cluster.CloudProvider, error) {
+	v, ok := config.Cloud.ProviderConf(cmn.ProviderHDFS)
+	if !ok {
+		return nil, errors.New("hdfs: missing cloud configuration")
+	}
+	conf, ok := v.(cmn.CloudConfHDFS)
+	if !ok {
+		return nil, fmt.Errorf("hdfs: invalid cloud configuration %+v (%T)", v, v)
+	}
+	nameNode, err := url.Parse(conf.NameNode)
+	if err != nil {
+		return nil, fmt.Errorf("hdfs: failed to parse namenode URL: %v", err)
+	}
+	args := cmn.TransportArgs{
+		UseHTTPS:   nameNode.Scheme == "https",
+		SkipVerify: config.Net.HTTP.SkipVerify,
+	}
+	noRedirect := cmn.NewClient(args)
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &hdfsProvider{
+		t:          t,
+		conf:       conf,
+		nameNode:   nameNode,
+		client:     cmn.NewClient(args),
+		noRedirect: noRedirect,
+	}, nil
+}
+
+func (e *hdfsError) Error() string {
+	if e.Exception.Exception == "" {
+		return fmt.Sprintf("hdfs: %s", http.StatusText(e.status))
+	}
+	return fmt.Sprintf("hdfs: %s: %s", e.Exception.Exception, e.Exception.Message)
+}
+
+func (e *hdfsError) notFound() bool {
+	return e.status == http.StatusNotFound || e.Exception.Exception == hdfsFileNotFound
+}
+
+func (hp *hdfsProvider) hdfsErrorToAISError(err error, bck cmn.Bck, objName string) (error, int) {
+	if err == errHDFSNotFile {
+		msg := fmt.Sprintf("%s/%s not found", bck, objName)
+		return &cmn.HTTPError{Status: http.StatusNotFound, Message: msg}, http.StatusNotFound
+	}
+	hdfsErr, ok := err.(*hdfsError)
+	if !ok {
+		return err, http.StatusInternalServerError
+	}
+	if !hdfsErr.notFound() {
+		return hdfsErr, hdfsErr.status
+	}
+	if objName == "" {
+		return cmn.NewErrorRemoteBucketDoesNotExist(bck, hp.t.Snode().Name()), http.StatusNotFound
+	}
+	msg := fmt.Sprintf("%s/%s not found", bck, objName)
+	return &cmn.HTTPError{Status: http.StatusNotFound, Message: msg}, http.StatusNotFound
+}
+
+//
+// paths and requests
+//
+
+func (hp *hdfsProvider) bucketPath(bckName string) string {
+	return path.Join(hp.conf.Root, bckName)
+}
+
+// object name must not escape its bucket directory (e.g. via "..")
+func (hp *hdfsProvider) objPath(bckName, objName string) (string, error) {
+	var (
+		dir = hp.bucketPath(bckName)
+		p   = path.Join(dir, objName)
+	)
+	if !strings.HasPrefix(p, dir+"/") {
+		return "", fmt.Errorf("invalid object name %q", objName)
+	}
+	return p, nil
+}
+
+func (hp *hdfsProvider) newRequest(ctx context.Context, method, p, op string, query url.Values,
+	body io.Reader) (*http.Request, error) {
+	u := *hp.nameNode
+	u.Path = path.Join(hp.nameNode.Path, webhdfsPrefix, p)
+	if query == nil {
+		query = make(url.Values, 2)
+	}
+	query.Set("op", op)
+	if hp.conf.User != "" {
+		query.Set("user.name", hp.conf.User)
+	}
+	u.RawQuery = query.Encode()
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// Executes the request and decodes JSON response into `v`, if defined.
+// Otherwise, the caller must close the response body.
+func (hp *hdfsProvider) do(client *http.Client, req *http.Request, v interface{}) (*http.Response, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		hdfsErr := &hdfsError{status: resp.StatusCode}
+		b, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if len(b) > 0 {
+			_ = jsoniter.Unmarshal(b, hdfsErr)
+		}
+		return nil, hdfsErr
+	}
+	if v == nil {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	return resp, jsoniter.NewDecoder(resp.Body).Decode(v)
+}
+
+func (hp *hdfsProvider) fileStatus(ctx context.Context, p string) (*hdfsFileStatus, error) {
+	req, err := hp.newRequest(ctx, http.MethodGet, p, "GETFILESTATUS", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp hdfsFileStatusResp
+	if _, err := hp.do(hp.client, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp.FileStatus, nil
+}
+
+func (hp *hdfsProvider) listStatus(ctx context.Context, p string) ([]hdfsFileStatus, error) {
+	req, err := hp.newRequest(ctx, http.MethodGet, p, "LISTSTATUS", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp hdfsListStatusResp
+	if _, err := hp.do(hp.client, req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.FileStatuses.FileStatus, nil
+}
+
+func (hp *hdfsProvider) objStatus(ctx context.Context, p string) (*hdfsFileStatus, error) {
+	st, err := hp.fileStatus(ctx, p)
+	if err == nil && st.Type != hdfsTypeFile {
+		err = errHDFSNotFile
+	}
+	return st, err
+}
+
+func hdfsVersion(st *hdfsFileStatus) string { return strconv.FormatInt(st.ModificationTime, 10) }
+
+//
+// CloudProvider interface
+//
+
+func (hp *hdfsProvider) Provider() string {
+	return cmn.ProviderHDFS
+}
+
+func (hp *hdfsProvider) ListBuckets(ctx context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	statuses, err := hp.listStatus(ctx, hp.conf.Root)
+	if err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cmn.Bck{Provider: cmn.ProviderHDFS}, "")
+		return
+	}
+	for _, st := range statuses {
+		if st.Type != hdfsTypeDir || cmn.ValidateBckName(st.PathSuffix) != nil {
+			continue
+		}
+		buckets = append(buckets, cmn.Bck{
+			Name:     st.PathSuffix,
+			Provider: cmn.ProviderHDFS,
+		})
+	}
+	return
+}
+
+func (hp *hdfsProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	var (
+		cloudBck = bck.CloudBck()
+		st       *hdfsFileStatus
+	)
+	bckProps = make(cmn.SimpleKVs, 2)
+	if st, err = hp.fileStatus(ctx, hp.bucketPath(cloudBck.Name)); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, "")
+		return
+	}
+	if st.Type != hdfsTypeDir {
+		return bckProps, cmn.NewErrorRemoteBucketDoesNotExist(cloudBck, hp.t.Snode().Name()), http.StatusNotFound
+	}
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderHDFS
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	return
+}
+
+// HDFS lists directory entries without pagination; the objects are therefore
+// collected via depth-first traversal in lexicographical order of their names,
+// skipping (sub)directories that cannot contain the requested page.
+func (hp *hdfsProvider) ListObjects(ctx context.Context, bck *cluster.Bck,
+	msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	var (
+		cloudBck = bck.CloudBck()
+		pageSize = msg.PageSize
+	)
+	if pageSize == 0 {
+		pageSize = cmn.DefaultListPageSize
+	}
+	bckList = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, initialBucketListSize)}
+	if _, err = hp.listDir(ctx, hp.bucketPath(cloudBck.Name), "", msg, pageSize, bckList); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, "")
+		return nil, err, errCode
+	}
+	if len(bckList.Entries) == pageSize {
+		msg.PageMarker = bckList.Entries[len(bckList.Entries)-1].Name
+		bckList.PageMarker = msg.PageMarker
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d(marker: %s)", len(bckList.Entries), bckList.PageMarker)
+	}
+	return
+}
+
+// returns true when the page is full
+func (hp *hdfsProvider) listDir(ctx context.Context, dir, rel string, msg *cmn.SelectMsg, pageSize int,
+	bckList *cmn.BucketList) (bool, error) {
+	statuses, err := hp.listStatus(ctx, dir)
+	if err != nil {
+		return false, err
+	}
+	// names of directories sort as if followed by the separator
+	sortKey := func(st *hdfsFileStatus) string {
+		if st.Type == hdfsTypeDir {
+			return st.PathSuffix + "/"
+		}
+		return st.PathSuffix
+	}
+	sort.Slice(statuses, func(i, j int) bool { return sortKey(&statuses[i]) < sortKey(&statuses[j]) })
+	for i := range statuses {
+		st := &statuses[i]
+		name := rel + sortKey(st)
+		if st.Type == hdfsTypeDir {
+			if !strings.HasPrefix(name, msg.Prefix) && !strings.HasPrefix(msg.Prefix, name) {
+				continue
+			}
+			if name <= msg.PageMarker && !strings.HasPrefix(msg.PageMarker, name) {
+				continue // the entire subtree has been listed
+			}
+			full, err := hp.listDir(ctx, path.Join(dir, st.PathSuffix), name, msg, pageSize, bckList)
+			if full || err != nil {
+				return full, err
+			}
+			continue
+		}
+		full := addListEntry(bckList, msg, name, pageSize, func() *cmn.BucketEntry {
+			entry := &cmn.BucketEntry{Name: name}
+			if strings.Contains(msg.Props, cmn.GetPropsSize) {
+				entry.Size = st.Length
+			}
+			if strings.Contains(msg.Props, cmn.GetPropsVersion) {
+				entry.Version = hdfsVersion(st)
+			}
+			return entry
+		})
+		if full {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (hp *hdfsProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	var (
+		cloudBck = lom.Bck().CloudBck()
+		p        string
+		st       *hdfsFileStatus
+	)
+	objMeta = make(cmn.SimpleKVs, 3)
+	if p, err = hp.objPath(cloudBck.Name, lom.ObjName); err != nil {
+		return objMeta, err, http.StatusBadRequest
+	}
+	if st, err = hp.objStatus(ctx, p); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+		return
+	}
+	objMeta[cmn.HeaderObjSize] = strconv.FormatInt(st.Length, 10)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderHDFS
+	objMeta[cmn.HeaderObjVersion] = hdfsVersion(st)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s", lom)
+	}
+	return
+}
+
+func (hp *hdfsProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	var (
+		cloudBck = lom.Bck().CloudBck()
+		p        string
+		st       *hdfsFileStatus
+		req      *http.Request
+		resp     *http.Response
+	)
+	if p, err = hp.objPath(cloudBck.Name, lom.ObjName); err != nil {
+		return err, http.StatusBadRequest
+	}
+	if st, err = hp.objStatus(ctx, p); err != nil {
+		return hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+	}
+	if req, err = hp.newRequest(ctx, http.MethodGet, p, "OPEN", nil, nil); err != nil {
+		return err, http.StatusInternalServerError
+	}
+	// namenode redirects to a datanode that has the data
+	if resp, err = hp.do(hp.client, req, nil); err != nil {
+		return hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+	}
+	defer resp.Body.Close()
+	lom.SetVersion(hdfsVersion(st))
+	err = hp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       resp.Body,
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+// CREATE is a two-step operation: namenode responds with a redirect to the datanode
+// that will store the data, and the data is then sent to that datanode
+func (hp *hdfsProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	var (
+		cloudBck = lom.Bck().CloudBck()
+		p        string
+		req      *http.Request
+		resp     *http.Response
+		st       *hdfsFileStatus
+	)
+	if p, err = hp.objPath(cloudBck.Name, lom.ObjName); err != nil {
+		return "", err, http.StatusBadRequest
+	}
+	query := url.Values{"overwrite": []string{"true"}}
+	if req, err = hp.newRequest(ctx, http.MethodPut, p, "CREATE", query, nil); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	if resp, err = hp.do(hp.noRedirect, req, nil); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+		return
+	}
+	resp.Body.Close()
+	location := resp.Header.Get("Location")
+	if resp.StatusCode != http.StatusTemporaryRedirect || location == "" {
+		err = fmt.Errorf("hdfs: failed to create %s/%s: unexpected response %q", cloudBck, lom.ObjName, resp.Status)
+		return "", err, http.StatusInternalServerError
+	}
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPut, location, r); err != nil {
+		return "", err, http.StatusInternalServerError
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if resp, err = hp.do(hp.client, req, nil); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+		return
+	}
+	resp.Body.Close()
+	if st, err = hp.objStatus(ctx, p); err != nil {
+		err, errCode = hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[put_object] %s", lom)
+	}
+	return hdfsVersion(st), nil, http.StatusOK
+}
+
+func (hp *hdfsProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (error, int) {
+	var (
+		cloudBck = lom.Bck().CloudBck()
+		resp     hdfsBooleanResp
+	)
+	p, err := hp.objPath(cloudBck.Name, lom.ObjName)
+	if err != nil {
+		return err, http.StatusBadRequest
+	}
+	req, err := hp.newRequest(ctx, http.MethodDelete, p, "DELETE", nil, nil)
+	if err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if _, err := hp.do(hp.client, req, &resp); err != nil {
+		return hp.hdfsErrorToAISError(err, cloudBck, lom.ObjName)
+	}
+	if !resp.Boolean {
+		return hp.hdfsErrorToAISError(errHDFSNotFile, cloudBck, lom.ObjName)
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[delete_object] %s", lom)
+	}
+	return nil, http.StatusOK
+}
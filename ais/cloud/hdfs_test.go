@@ -0,0 +1,279 @@
+// +build hdfs
+
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+const hdfsTestMpath = "/tmp/hdfs-tests"
+
+type (
+	// in-process WebHDFS: namenode serves metadata, "/datanode" serves data
+	webhdfsMock struct {
+		mu    sync.Mutex
+		dirs  map[string]struct{}
+		files map[string][]byte
+		srv   *httptest.Server
+	}
+	hdfsTargetMock struct {
+		*cluster.TargetMock
+		si *cluster.Snode
+	}
+)
+
+func (t *hdfsTargetMock) Snode() *cluster.Snode { return t.si }
+
+func newWebhdfsMock() *webhdfsMock {
+	m := &webhdfsMock{dirs: map[string]struct{}{"/": {}}, files: make(map[string][]byte)}
+	m.srv = httptest.NewServer(m)
+	return m
+}
+
+func (m *webhdfsMock) put(p string, data []byte) {
+	for dir := path.Dir(p); dir != "/"; dir = path.Dir(dir) {
+		m.dirs[dir] = struct{}{}
+	}
+	m.files[p] = data
+}
+
+func (m *webhdfsMock) status(p string) (st hdfsFileStatus, ok bool) {
+	st.PathSuffix, st.ModificationTime = path.Base(p), 1000
+	if data, exists := m.files[p]; exists {
+		st.Type, st.Length = hdfsTypeFile, int64(len(data))
+		return st, true
+	}
+	_, ok = m.dirs[p]
+	st.Type = hdfsTypeDir
+	return
+}
+
+func (m *webhdfsMock) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if strings.HasPrefix(r.URL.Path, "/datanode") {
+		p := strings.TrimPrefix(r.URL.Path, "/datanode")
+		if r.Method == http.MethodPut {
+			data, _ := ioutil.ReadAll(r.Body)
+			m.put(p, data)
+			w.WriteHeader(http.StatusCreated)
+			return
+		}
+		w.Write(m.files[p])
+		return
+	}
+	p := path.Clean("/" + strings.TrimPrefix(r.URL.Path, webhdfsPrefix))
+	switch r.URL.Query().Get("op") {
+	case "GETFILESTATUS":
+		st, ok := m.status(p)
+		if !ok {
+			m.notFound(w, p)
+			return
+		}
+		w.Write(cmn.MustMarshal(hdfsFileStatusResp{FileStatus: st}))
+	case "LISTSTATUS":
+		if _, ok := m.dirs[p]; !ok {
+			m.notFound(w, p)
+			return
+		}
+		var resp hdfsListStatusResp
+		for child := range m.dirs {
+			if child != "/" && path.Dir(child) == p {
+				st, _ := m.status(child)
+				resp.FileStatuses.FileStatus = append(resp.FileStatuses.FileStatus, st)
+			}
+		}
+		for child := range m.files {
+			if path.Dir(child) == p {
+				st, _ := m.status(child)
+				resp.FileStatuses.FileStatus = append(resp.FileStatuses.FileStatus, st)
+			}
+		}
+		w.Write(cmn.MustMarshal(resp))
+	case "OPEN":
+		if _, ok := m.files[p]; !ok {
+			m.notFound(w, p)
+			return
+		}
+		http.Redirect(w, r, "/datanode"+p, http.StatusTemporaryRedirect)
+	case "CREATE":
+		w.Header().Set("Location", m.srv.URL+"/datanode"+p)
+		w.WriteHeader(http.StatusTemporaryRedirect)
+	case "DELETE":
+		_, ok := m.files[p]
+		delete(m.files, p)
+		w.Write(cmn.MustMarshal(hdfsBooleanResp{Boolean: ok}))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (m *webhdfsMock) notFound(w http.ResponseWriter, p string) {
+	var hdfsErr hdfsError
+	hdfsErr.Exception.Exception = hdfsFileNotFound
+	hdfsErr.Exception.Message = "File does not exist: " + p
+	w.WriteHeader(http.StatusNotFound)
+	w.Write(cmn.MustMarshal(hdfsErr))
+}
+
+func newHDFSTestProvider(t *testing.T, m *webhdfsMock, bmd *cluster.BownerMock) *hdfsProvider {
+	config := cmn.GCO.BeginUpdate()
+	config.Cloud.Conf = map[string]interface{}{
+		cmn.ProviderHDFS: cmn.CloudConfHDFS{NameNode: m.srv.URL, Root: "/data"},
+	}
+	err := config.Cloud.Validate(config)
+	cmn.GCO.CommitUpdate(config)
+	tassert.CheckFatal(t, err)
+
+	si := &cluster.Snode{DaemonID: "target", DaemonType: cmn.Target}
+	si.SetName()
+	cp, err := NewHDFS(&hdfsTargetMock{TargetMock: cluster.NewTargetMock(bmd), si: si}, cmn.GCO.Get())
+	tassert.CheckFatal(t, err)
+	return cp.(*hdfsProvider)
+}
+
+func TestHDFSBuckets(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   = newWebhdfsMock()
+		hp  = newHDFSTestProvider(t, m, cluster.NewBaseBownerMock())
+	)
+	defer m.srv.Close()
+	m.put("/data/bck1/obj", []byte("data"))
+	m.put("/data/bck2/obj", []byte("data"))
+	m.put("/data/not-a-bucket", []byte("data"))
+
+	buckets, err, _ := hp.ListBuckets(ctx, cmn.QueryBcks{Provider: cmn.ProviderHDFS})
+	tassert.CheckFatal(t, err)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Name < buckets[j].Name })
+	expected := cmn.BucketNames{
+		{Name: "bck1", Provider: cmn.ProviderHDFS},
+		{Name: "bck2", Provider: cmn.ProviderHDFS},
+	}
+	tassert.Errorf(t, reflect.DeepEqual(buckets, expected), "expected %v, got %v", expected, buckets)
+
+	props, err, _ := hp.HeadBucket(ctx, cluster.NewBck("bck1", cmn.ProviderHDFS, cmn.NsGlobal))
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, props[cmn.HeaderCloudProvider] == cmn.ProviderHDFS, "unexpected props %v", props)
+
+	_, _, errCode := hp.HeadBucket(ctx, cluster.NewBck("bck3", cmn.ProviderHDFS, cmn.NsGlobal))
+	tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d", http.StatusNotFound, errCode)
+	_, _, errCode = hp.HeadBucket(ctx, cluster.NewBck("not-a-bucket", cmn.ProviderHDFS, cmn.NsGlobal))
+	tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d", http.StatusNotFound, errCode)
+}
+
+func TestHDFSListObjects(t *testing.T) {
+	var (
+		ctx = context.Background()
+		m   = newWebhdfsMock()
+		hp  = newHDFSTestProvider(t, m, cluster.NewBaseBownerMock())
+		bck = cluster.NewBck("bck", cmn.ProviderHDFS, cmn.NsGlobal)
+	)
+	defer m.srv.Close()
+	for _, name := range []string{"x/y/z", "a/d", "a-c", "a/b", "b"} {
+		m.put("/data/bck/"+name, []byte(name))
+	}
+
+	list := func(msg *cmn.SelectMsg) (names []string) {
+		bckList, err, _ := hp.ListObjects(ctx, bck, msg)
+		tassert.CheckFatal(t, err)
+		for _, entry := range bckList.Entries {
+			names = append(names, entry.Name)
+		}
+		return
+	}
+	tests := []struct {
+		msg      *cmn.SelectMsg
+		expected []string
+	}{
+		{&cmn.SelectMsg{}, []string{"a-c", "a/b", "a/d", "b", "x/y/z"}},
+		{&cmn.SelectMsg{PageSize: 2}, []string{"a-c", "a/b"}},
+		{&cmn.SelectMsg{PageSize: 2, PageMarker: "a/b"}, []string{"a/d", "b"}},
+		{&cmn.SelectMsg{PageSize: 2, PageMarker: "b"}, []string{"x/y/z"}},
+		{&cmn.SelectMsg{Prefix: "a/"}, []string{"a/b", "a/d"}},
+		{&cmn.SelectMsg{Prefix: "x/y"}, []string{"x/y/z"}},
+		{&cmn.SelectMsg{Delimiter: "/"}, []string{"a-c", "a/", "b", "x/"}},
+		{&cmn.SelectMsg{Delimiter: "/", PageSize: 2, PageMarker: "a/"}, []string{"b", "x/"}},
+	}
+	for _, test := range tests {
+		names := list(test.msg)
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "%+v: expected %v, got %v", test.msg, test.expected, names)
+	}
+
+	_, _, errCode := hp.ListObjects(ctx, cluster.NewBck("none", cmn.ProviderHDFS, cmn.NsGlobal), &cmn.SelectMsg{})
+	tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d", http.StatusNotFound, errCode)
+}
+
+func TestHDFSObjects(t *testing.T) {
+	tassert.CheckFatal(t, cmn.CreateDir(hdfsTestMpath))
+	defer os.RemoveAll(hdfsTestMpath)
+	fs.InitMountedFS()
+	fs.Mountpaths.DisableFsIDCheck()
+	tassert.CheckFatal(t, fs.Mountpaths.Add(hdfsTestMpath))
+	defer fs.Mountpaths.Remove(hdfsTestMpath)
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+
+	var (
+		ctx  = context.Background()
+		m    = newWebhdfsMock()
+		bmd  = cluster.NewBaseBownerMock()
+		hp   = newHDFSTestProvider(t, m, bmd)
+		bck  = cmn.Bck{Name: "bck", Provider: cmn.ProviderHDFS, Ns: cmn.NsGlobal}
+		data = []byte("hdfs object data")
+	)
+	defer m.srv.Close()
+	bmd.Add(cluster.NewBck(bck.Name, bck.Provider, bck.Ns, &cmn.BucketProps{Cksum: cmn.CksumConf{Type: cmn.ChecksumNone}}))
+	m.put("/data/bck/existing", []byte("data"))
+
+	lom := &cluster.LOM{T: hp.t, ObjName: "dir/obj"}
+	tassert.CheckFatal(t, lom.Init(bck))
+
+	version, err, _ := hp.PutObj(ctx, bytes.NewReader(data), lom)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, version != "", "expected version")
+	tassert.Errorf(t, bytes.Equal(m.files["/data/bck/dir/obj"], data), "unexpected content %q", m.files["/data/bck/dir/obj"])
+
+	objMeta, err, _ := hp.HeadObj(ctx, lom)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, objMeta[cmn.HeaderObjSize] == "16", "unexpected size %q", objMeta[cmn.HeaderObjSize])
+	tassert.Errorf(t, objMeta[cmn.HeaderObjVersion] == version, "expected version %q, got %q", version, objMeta[cmn.HeaderObjVersion])
+
+	err, _ = hp.GetObj(ctx, lom.FQN+".work", lom)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, lom.Version() == version, "expected version %q, got %q", version, lom.Version())
+
+	err, _ = hp.DeleteObj(ctx, lom)
+	tassert.CheckFatal(t, err)
+	_, _, errCode := hp.HeadObj(ctx, lom)
+	tassert.Errorf(t, errCode == http.StatusNotFound, "expected %d, got %d", http.StatusNotFound, errCode)
+	err, errCode = hp.DeleteObj(ctx, lom)
+	tassert.Errorf(t, err != nil && errCode == http.StatusNotFound, "expected %d, got %d (%v)", http.StatusNotFound, errCode, err)
+
+	// directories are not objects, and object names must stay within the bucket
+	lom = &cluster.LOM{T: hp.t, ObjName: "dir"}
+	tassert.CheckFatal(t, lom.Init(bck))
+	err, errCode = hp.GetObj(ctx, lom.FQN+".work", lom)
+	tassert.Errorf(t, err != nil && errCode == http.StatusNotFound, "expected %d, got %d (%v)", http.StatusNotFound, errCode, err)
+	_, err = hp.objPath(bck.Name, "../other/obj")
+	tassert.Errorf(t, err != nil, "expected error for object name outside the bucket")
+}
@@ -0,0 +1,280 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// ht:// buckets are read-only and backed by arbitrary HTTP(S) origins: object
+// is fetched from <extra.original_url>/<object name>. Listing, if enabled,
+// is served from the manifest file (see cmn.ExtraProps).
+
+type (
+	httpProvider struct {
+		t         cluster.Target
+		client    *http.Client
+		mu        sync.Mutex
+		manifests map[string]*httpManifest // manifest URL => parsed manifest
+	}
+	// parsed manifest gets reused (ie., not refetched and re-sorted) for as long
+	// as the origin reports the same ETag
+	httpManifest struct {
+		etag  string
+		names []string // sorted (read-only)
+	}
+)
+
+var (
+	_ cluster.CloudProvider = &httpProvider{}
+)
+
+func NewHTTP(t cluster.Target, config *cmn.Config) cluster.CloudProvider {
+	hp := &httpProvider{
+		t: t,
+		client: cmn.NewClient(cmn.TransportArgs{
+			UseHTTPS:        true,
+			SkipVerify:      config.Net.HTTP.SkipVerify,
+			UseHTTPProxyEnv: true,
+		}),
+		manifests: make(map[string]*httpManifest),
+	}
+	hp.client.CheckRedirect = cmn.StripOriginHeader // see setOriginHeader
+	return hp
+}
+
+func httpOrigURL(bck *cluster.Bck) string {
+	if bck.Props == nil {
+		return ""
+	}
+	return strings.TrimSuffix(bck.Props.Extra.OrigURLBck, "/")
+}
+
+func httpObjURL(origURL, objName string) string {
+	segments := strings.Split(objName, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return origURL + "/" + strings.Join(segments, "/")
+}
+
+// Object version: ETag, if provided by the origin, otherwise Last-Modified
+func httpVersion(header http.Header) string {
+	if etag := strings.TrimPrefix(header.Get("ETag"), "W/"); etag != "" {
+		return strings.Trim(etag, "\"")
+	}
+	return header.Get("Last-Modified")
+}
+
+func (hp *httpProvider) httpErrorToAISError(resp *http.Response, bck cmn.Bck, objName string) (error, int) {
+	if resp.StatusCode == http.StatusNotFound {
+		msg := fmt.Sprintf("%s/%s not found", bck, objName)
+		return &cmn.HTTPError{Status: http.StatusNotFound, Message: msg}, http.StatusNotFound
+	}
+	return fmt.Errorf("%s/%s: origin responded with %q", bck, objName, resp.Status), resp.StatusCode
+}
+
+func (hp *httpProvider) request(ctx context.Context, method string, bck *cluster.Bck,
+	objName string) (resp *http.Response, err error, errCode int) {
+	var (
+		req     *http.Request
+		origURL = httpOrigURL(bck)
+	)
+	if origURL == "" {
+		return nil, cmn.NewErrorRemoteBucketDoesNotExist(bck.Bck, hp.t.Snode().Name()), http.StatusNotFound
+	}
+	if req, err = http.NewRequestWithContext(ctx, method, httpObjURL(origURL, objName), nil); err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	setOriginHeader(ctx, req)
+	if resp, err = hp.client.Do(req); err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		err, errCode = hp.httpErrorToAISError(resp, bck.Bck, objName)
+		resp.Body.Close()
+		return nil, err, errCode
+	}
+	return resp, nil, 0
+}
+
+// adds the headers (eg. credentials) given in the context to the request
+func setOriginHeader(ctx context.Context, req *http.Request) {
+	if header, ok := ctx.Value(cmn.CtxOriginHeader).(http.Header); ok {
+		for key, values := range header {
+			req.Header[key] = values
+		}
+	}
+}
+
+func (hp *httpProvider) Provider() string {
+	return cmn.ProviderHTTP
+}
+
+// ht:// buckets exist only in BMD: they cannot be discovered
+func (hp *httpProvider) ListBuckets(ctx context.Context, _ cmn.QueryBcks) (buckets cmn.BucketNames, err error, errCode int) {
+	return cmn.BucketNames{}, nil, 0
+}
+
+func (hp *httpProvider) HeadBucket(ctx context.Context, bck *cluster.Bck) (bckProps cmn.SimpleKVs, err error, errCode int) {
+	origURL := httpOrigURL(bck)
+	if origURL == "" {
+		return cmn.SimpleKVs{}, cmn.NewErrorRemoteBucketDoesNotExist(bck.Bck, hp.t.Snode().Name()), http.StatusNotFound
+	}
+	bckProps = make(cmn.SimpleKVs, 4)
+	bckProps[cmn.HeaderCloudProvider] = cmn.ProviderHTTP
+	bckProps[cmn.HeaderBucketVerEnabled] = "true"
+	bckProps[cmn.HeaderOrigURLBck] = origURL
+	bckProps[cmn.HeaderBucketManifest] = bck.Props.Extra.Manifest
+	return
+}
+
+func (hp *httpProvider) ListObjects(ctx context.Context, bck *cluster.Bck,
+	msg *cmn.SelectMsg) (bckList *cmn.BucketList, err error, errCode int) {
+	var (
+		names    []string
+		pageSize = msg.PageSize
+	)
+	if bck.Props == nil || bck.Props.Extra.Manifest == "" {
+		return nil, fmt.Errorf("%s: listing requires %s", bck, cmn.HeaderBucketManifest), http.StatusNotImplemented
+	}
+	if names, err, errCode = hp.readManifest(ctx, bck); err != nil {
+		return
+	}
+	if pageSize == 0 {
+		pageSize = cmn.DefaultListPageSize
+	}
+	bckList = &cmn.BucketList{Entries: make([]*cmn.BucketEntry, 0, initialBucketListSize)}
+	for i := sort.SearchStrings(names, msg.PageMarker); i < len(names); i++ {
+		name := names[i]
+		if addListEntry(bckList, msg, name, pageSize, func() *cmn.BucketEntry { return &cmn.BucketEntry{Name: name} }) {
+			msg.PageMarker = bckList.Entries[len(bckList.Entries)-1].Name
+			bckList.PageMarker = msg.PageMarker
+			break
+		}
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[list_bucket] count %d(marker: %s)", len(bckList.Entries), bckList.PageMarker)
+	}
+	return
+}
+
+// returns sorted object names listed in the manifest: one name per line,
+// empty lines and lines starting with '#' are ignored
+func (hp *httpProvider) readManifest(ctx context.Context, bck *cluster.Bck) (names []string, err error, errCode int) {
+	var (
+		req      *http.Request
+		resp     *http.Response
+		manifest = bck.Props.Extra.Manifest
+	)
+	if u, err := url.Parse(manifest); err != nil || !u.IsAbs() {
+		manifest = httpObjURL(httpOrigURL(bck), manifest)
+	}
+	if req, err = http.NewRequestWithContext(ctx, http.MethodGet, manifest, nil); err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	setOriginHeader(ctx, req)
+	hp.mu.Lock()
+	cached := hp.manifests[manifest]
+	hp.mu.Unlock()
+	if cached != nil {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if resp, err = hp.client.Do(req); err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return cached.names, nil, 0
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("%s: failed to read manifest %q: %s", bck, manifest, resp.Status), resp.StatusCode
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" || strings.HasPrefix(name, "#") {
+			continue
+		}
+		names = append(names, strings.TrimPrefix(name, "/"))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err, http.StatusInternalServerError
+	}
+	sort.Strings(names)
+
+	hp.mu.Lock()
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		hp.manifests[manifest] = &httpManifest{etag: etag, names: names}
+	} else {
+		delete(hp.manifests, manifest)
+	}
+	hp.mu.Unlock()
+	return
+}
+
+func (hp *httpProvider) HeadObj(ctx context.Context, lom *cluster.LOM) (objMeta cmn.SimpleKVs, err error, errCode int) {
+	var resp *http.Response
+	if resp, err, errCode = hp.request(ctx, http.MethodHead, lom.Bck(), lom.ObjName); err != nil {
+		return cmn.SimpleKVs{}, err, errCode
+	}
+	resp.Body.Close()
+	objMeta = make(cmn.SimpleKVs, 3)
+	objMeta[cmn.HeaderCloudProvider] = cmn.ProviderHTTP
+	if resp.ContentLength >= 0 {
+		objMeta[cmn.HeaderObjSize] = strconv.FormatInt(resp.ContentLength, 10)
+	}
+	if version := httpVersion(resp.Header); version != "" {
+		objMeta[cmn.HeaderObjVersion] = version
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[head_object] %s", lom)
+	}
+	return
+}
+
+func (hp *httpProvider) GetObj(ctx context.Context, workFQN string, lom *cluster.LOM) (err error, errCode int) {
+	var resp *http.Response
+	if resp, err, errCode = hp.request(ctx, http.MethodGet, lom.Bck(), lom.ObjName); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	lom.SetVersion(httpVersion(resp.Header))
+	err = hp.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       resp.Body,
+		WorkFQN:      workFQN,
+		RecvType:     cluster.ColdGet,
+		WithFinalize: false,
+	})
+	if err != nil {
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("[get_object] %s", lom)
+	}
+	return
+}
+
+func (hp *httpProvider) PutObj(ctx context.Context, r io.Reader, lom *cluster.LOM) (version string, err error, errCode int) {
+	return "", fmt.Errorf("%s: %q buckets are read-only", lom, cmn.ProviderHTTP), http.StatusMethodNotAllowed
+}
+
+func (hp *httpProvider) DeleteObj(ctx context.Context, lom *cluster.LOM) (error, int) {
+	return fmt.Errorf("%s: %q buckets are read-only", lom, cmn.ProviderHTTP), http.StatusMethodNotAllowed
+}
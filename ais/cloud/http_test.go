@@ -0,0 +1,99 @@
+// Package cloud contains implementation of various cloud providers.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cloud
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestHTTPObjURLVersion(t *testing.T) {
+	u := httpObjURL("https://example.com/datasets", "train/shard 01.tar")
+	tassert.Errorf(t, u == "https://example.com/datasets/train/shard%2001.tar", "unexpected URL %q", u)
+
+	header := make(http.Header)
+	header.Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+	tassert.Errorf(t, httpVersion(header) == "Wed, 21 Oct 2015 07:28:00 GMT", "expected Last-Modified version")
+	header.Set("ETag", `W/"abc123"`)
+	tassert.Errorf(t, httpVersion(header) == "abc123", "expected ETag version, got %q", httpVersion(header))
+}
+
+func TestHTTPListObjects(t *testing.T) {
+	var (
+		etag     = `"v1"`
+		manifest = "# objects\nval/01.tar\n\ntrain/02.tar\n/train/01.tar\n"
+		fetched  int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/index.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fetched++
+		w.Write([]byte(manifest))
+	}))
+	defer srv.Close()
+
+	var (
+		ctx   = context.Background()
+		hp    = NewHTTP(cluster.NewTargetMock(cluster.NewBaseBownerMock()), cmn.GCO.Get())
+		props = cmn.DefaultBucketProps()
+	)
+	props.Provider = cmn.ProviderHTTP
+	props.Extra = cmn.ExtraProps{OrigURLBck: srv.URL + "/data/", Manifest: "index.txt"}
+	bck := cluster.NewBck("mirror", cmn.ProviderHTTP, cmn.NsGlobal, props)
+
+	bckProps, err, _ := hp.HeadBucket(ctx, bck)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, bckProps[cmn.HeaderOrigURLBck] == srv.URL+"/data", "unexpected props %v", bckProps)
+
+	tests := []struct {
+		msg      *cmn.SelectMsg
+		expected []string
+	}{
+		{&cmn.SelectMsg{}, []string{"train/01.tar", "train/02.tar", "val/01.tar"}},
+		{&cmn.SelectMsg{Prefix: "train/"}, []string{"train/01.tar", "train/02.tar"}},
+		{&cmn.SelectMsg{PageSize: 2}, []string{"train/01.tar", "train/02.tar"}},
+		{&cmn.SelectMsg{PageSize: 2, PageMarker: "train/02.tar"}, []string{"val/01.tar"}},
+		{&cmn.SelectMsg{Delimiter: "/"}, []string{"train/", "val/"}},
+		{&cmn.SelectMsg{Delimiter: "/", PageSize: 1, PageMarker: "train/"}, []string{"val/"}},
+	}
+	for _, test := range tests {
+		bckList, err, _ := hp.ListObjects(ctx, bck, test.msg)
+		tassert.CheckFatal(t, err)
+		names := make([]string, 0, len(bckList.Entries))
+		for _, entry := range bckList.Entries {
+			names = append(names, entry.Name)
+		}
+		tassert.Errorf(t, reflect.DeepEqual(names, test.expected), "%+v: expected %v, got %v", test.msg, test.expected, names)
+	}
+	tassert.Errorf(t, fetched == 1, "expected the manifest to be fetched once, got %d", fetched)
+
+	// manifest changed at the origin
+	etag, manifest = `"v2"`, "test/01.tar\n"
+	bckList, err, _ := hp.ListObjects(ctx, bck, &cmn.SelectMsg{})
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(bckList.Entries) == 1 && bckList.Entries[0].Name == "test/01.tar",
+		"expected the updated manifest, got %v", bckList.Entries)
+	tassert.Errorf(t, fetched == 2, "expected the manifest to be refetched, got %d", fetched)
+
+	// no manifest - no listing
+	props.Extra.Manifest = ""
+	_, err, errCode := hp.ListObjects(ctx, bck, &cmn.SelectMsg{})
+	tassert.Errorf(t, err != nil && errCode == http.StatusNotImplemented, "expected %d, got %d (%v)",
+		http.StatusNotImplemented, errCode, err)
+}
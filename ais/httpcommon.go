@@ -493,6 +493,8 @@ func (h *httprunner) initSI(daemonType string) {
 	}
 
 	h.si = newSnode(daemonID, config.Net.HTTP.Proto, daemonType, publicAddr, intraControlAddr, intraDataAddr)
+	// failure domain (rack, zone) of the node - EC spreads slices across them
+	h.si.Zone = os.Getenv("AIS_ZONE")
 }
 
 func mustDiffer(ip1 net.IP, port1 int, use1 bool, ip2 net.IP, port2 int, use2 bool, tag string) {
@@ -1215,6 +1217,37 @@ func (h *httprunner) extractRevokedTokenList(payload msPayload) (*TokenList, err
 	return tokenList, nil
 }
 
+func (h *httprunner) extractAccessKeyList(payload msPayload) (*AccessKeyList, error) {
+	var (
+		msg       aisMsg
+		bytes, ok = payload[revsAccessKeyTag]
+	)
+	if !ok {
+		return nil, nil
+	}
+	if msgValue, ok := payload[revsAccessKeyTag+revsActionTag]; ok {
+		if err := jsoniter.Unmarshal(msgValue, &msg); err != nil {
+			err := fmt.Errorf(
+				"failed to unmarshal action message, value (%+v, %T), err: %v",
+				msgValue, msgValue, err)
+			return nil, err
+		}
+	}
+
+	keyList := &AccessKeyList{}
+	if err := jsoniter.Unmarshal(bytes, keyList); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal access key list, err: %v", err)
+	}
+
+	s := ""
+	if msg.Action != "" {
+		s = ", action " + msg.Action
+	}
+	glog.Infof("received AccessKeyList v%d nkeys %d%s", keyList.Version, len(keyList.Keys), s)
+
+	return keyList, nil
+}
+
 // ================================== Background =========================================
 //
 // Generally, AIStore clusters can be deployed with an arbitrary numbers of proxies.
@@ -1424,6 +1457,9 @@ func (h *httprunner) bucketPropsToHdr(bck *cluster.Bck, hdr http.Header) {
 		} else if fieldName == cmn.HeaderBucketCreated {
 			created := time.Unix(0, field.Value().(int64))
 			hdr.Set(cmn.HeaderBucketCreated, created.Format(time.RFC3339))
+		} else if fieldName == cmn.HeaderBucketLifecycleRules {
+			hdr.Set(fieldName, string(cmn.MustMarshal(field.Value())))
+			return nil, false
 		}
 
 		hdr.Set(fieldName, fmt.Sprintf("%v", field.Value()))
@@ -23,8 +23,9 @@ const (
 	revsRMDTag  = "RMD"
 	revsBMDTag  = "BMD"
 
-	revsTokenTag  = "token"
-	revsActionTag = "-action" // to make a pair (revs, action)
+	revsTokenTag     = "token"
+	revsAccessKeyTag = "accesskeys"
+	revsActionTag    = "-action" // to make a pair (revs, action)
 )
 const (
 	revsReqSync = iota
@@ -39,6 +39,7 @@ const (
 	fmtUnknownAct = "unexpected action message <- JSON [%v]"
 	fmtUnknownQue = "unexpected query [what=%s]"
 	guestError    = "guest account does not have permissions for the operation"
+	adminError    = "the operation requires admin permissions"
 	ciePrefix     = "cluster integrity error: cie#"
 	githubHome    = "https://github.com/NVIDIA/aistore"
 	listBuckets   = "listBuckets"
@@ -116,6 +117,7 @@ func (p *proxyrunner) Run() error {
 		revokedTokens: make(map[string]bool),
 		version:       1,
 	}
+	p.authn.loadAccessKeys(config)
 
 	p.rproxy.init()
 
@@ -147,6 +149,7 @@ func (p *proxyrunner) Run() error {
 		{r: cmn.Daemon, h: p.daemonHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 		{r: cmn.Cluster, h: p.clusterHandler, net: []string{cmn.NetworkPublic, cmn.NetworkIntraControl}},
 		{r: cmn.Tokens, h: p.tokenHandler, net: []string{cmn.NetworkPublic}},
+		{r: cmn.AccessKeys, h: p.accessKeyHandler, net: []string{cmn.NetworkIntraControl}},
 		{r: cmn.Sort, h: dsortHandler, net: []string{cmn.NetworkPublic}},
 
 		{r: cmn.Metasync, h: p.metasyncHandler, net: []string{cmn.NetworkIntraControl}},
@@ -157,7 +160,14 @@ func (p *proxyrunner) Run() error {
 	}
 	p.registerNetworkHandlers(networkHandlers)
 
-	p.registerPublicNetHandler("/s3", p.s3Handler)
+	s3Handler := p.s3Handler
+	if config.Auth.Enabled {
+		s3Handler = wrapHandler(p.s3Handler, p.checkS3Auth)
+	}
+	p.registerPublicNetHandler("/s3", s3Handler)
+
+	// Prometheus
+	p.registerPublicNetHandler("/metrics", p.metricsHandler)
 
 	glog.Infof("%s: [public net] listening on: %s", p.si, p.si.PublicNet.DirectURL)
 	if p.si.PublicNet.DirectURL != p.si.IntraControlNet.DirectURL {
@@ -567,6 +577,11 @@ func (p *proxyrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 		}
 		fallthrough // fallthrough
 	case cmn.ActDestroyLB:
+		if msg.Action == cmn.ActDestroyLB && bck.Props.Retention.Enabled {
+			p.invalmsghdlr(w, r, fmt.Sprintf("cannot destroy bucket %s with enabled retention", bck),
+				http.StatusForbidden)
+			return
+		}
 		if p.forwardCP(w, r, &msg, bucket, nil) {
 			return
 		}
@@ -676,6 +691,13 @@ ExtractRMD:
 		p.authn.updateRevokedList(revokedTokens)
 	}
 
+	accessKeys, err := p.extractAccessKeyList(payload)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		p.authn.updateAccessKeys(accessKeys)
+	}
+
 	if len(errs) > 0 {
 		msg := fmt.Sprintf("%v", errs)
 		p.invalmsghdlr(w, r, msg)
@@ -683,6 +705,18 @@ ExtractRMD:
 	}
 }
 
+// GET /metrics (Prometheus)
+func (p *proxyrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /metrics path")
+		return
+	}
+	w.Header().Set("Content-Type", stats.PromContentType)
+	if err := getproxystatsrunner().WriteProm(w); err != nil {
+		glog.Errorf("%s: failed to write metrics: %v", p.si, err)
+	}
+}
+
 // GET /v1/health
 // TODO: split/separate ais-internal vs external calls
 func (p *proxyrunner) healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -802,15 +836,33 @@ func (p *proxyrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 			p.invalmsghdlr(w, r, err.Error())
 			return
 		}
-		if bck.Bck.IsCloud(cmn.AnyCloud) {
+		if bck.Bck.IsCloud(cmn.AnyCloud) && !cmn.IsExplicitProvider(bck.Provider) {
 			p.invalmsghdlr(w, r, fmt.Sprintf(fmtErr, msg.Action, bck.Provider))
 			return
 		}
+		// fs:// buckets expose the targets' filesystems - admin only
+		if bck.Provider == cmn.ProviderFS {
+			if err := p.checkAdmin(r); err != nil {
+				p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
 		if p.forwardCP(w, r, &msg, bucket, nil) {
 			return
 		}
-		bck.Provider = cmn.ProviderAIS
-		if err := p.createBucket(&msg, bck); err != nil {
+		var cloudHeader []http.Header
+		if cmn.IsExplicitProvider(bck.Provider) {
+			// ht:// and fs:// buckets cannot be discovered - must be created with their origins
+			header, err := extraBucketHeader(&msg, bck.Provider)
+			if err != nil {
+				p.invalmsghdlr(w, r, err.Error())
+				return
+			}
+			cloudHeader = append(cloudHeader, header)
+		} else {
+			bck.Provider = cmn.ProviderAIS
+		}
+		if err := p.createBucket(&msg, bck, cloudHeader...); err != nil {
 			errCode := http.StatusInternalServerError
 			if _, ok := err.(*cmn.ErrorBucketAlreadyExists); ok {
 				errCode = http.StatusConflict
@@ -1183,6 +1235,24 @@ func (p *proxyrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		}
 		p.promoteFQN(w, r, bck, &msg)
 		return
+	case cmn.ActObjRetention:
+		params := cmn.ActValRetention{}
+		if err = cmn.TryUnmarshal(msg.Value, &params); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if params.LegalHold != nil {
+			if err = p.checkAdmin(r); err != nil {
+				p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+		if err = bck.Allow(cmn.AccessPUT); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		p.objRetention(w, r, bck)
+		return
 	default:
 		s := fmt.Sprintf(fmtUnknownAct, msg)
 		p.invalmsghdlr(w, r, s)
@@ -1265,6 +1335,12 @@ func (p *proxyrunner) httpbckpatch(w http.ResponseWriter, r *http.Request) {
 		p.invalmsghdlr(w, r, err.Error())
 		return
 	}
+	if bck.Provider == cmn.ProviderFS && propsToUpdate.Extra != nil && propsToUpdate.Extra.Path != nil {
+		if err = p.checkAdmin(r); err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+	}
 	if err = p.setBucketProps(msg, bck, propsToUpdate); err != nil {
 		p.invalmsghdlr(w, r, err.Error())
 	}
@@ -1511,7 +1587,7 @@ func (p *proxyrunner) syncCBmeta(w http.ResponseWriter, r *http.Request,
 		p.invalmsghdlr(w, r, erc.Error())
 		return
 	}
-	if !cmn.IsValidProvider(queryBck.Provider) {
+	if !cmn.IsValidProvider(queryBck.Provider) || cmn.IsExplicitProvider(queryBck.Provider) {
 		err = cmn.NewErrorBucketDoesNotExist(queryBck.Bck, p.si.Name())
 		p.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
 		return
@@ -1550,6 +1626,27 @@ func (p *proxyrunner) syncCBmeta(w http.ResponseWriter, r *http.Request,
 	return
 }
 
+// returns props of a new ht:// or fs:// bucket in the form of cloud bucket header
+func extraBucketHeader(msg *cmn.ActionMsg, provider string) (http.Header, error) {
+	var extra cmn.ExtraProps
+	if msg.Value == nil {
+		return nil, fmt.Errorf("cannot %s: %q bucket requires extra props", msg.Action, provider)
+	}
+	if err := cmn.TryUnmarshal(msg.Value, &extra); err != nil {
+		return nil, fmt.Errorf("cannot %s: invalid extra props %v: %v", msg.Action, msg.Value, err)
+	}
+	if err := extra.ValidateAsProps(&cmn.ValidationArgs{Provider: provider}); err != nil {
+		return nil, err
+	}
+	header := make(http.Header, 4)
+	header.Set(cmn.HeaderCloudProvider, provider)
+	header.Set(cmn.HeaderBucketVerEnabled, "true")
+	header.Set(cmn.HeaderOrigURLBck, extra.OrigURLBck)
+	header.Set(cmn.HeaderBucketManifest, extra.Manifest)
+	header.Set(cmn.HeaderBucketPath, extra.Path)
+	return header, nil
+}
+
 func (p *proxyrunner) cbExists(queryBck *cluster.Bck) (header http.Header, err error) {
 	var (
 		tsi   *cluster.Snode
@@ -1867,6 +1964,27 @@ func (p *proxyrunner) objRename(w http.ResponseWriter, r *http.Request, bck *clu
 	p.statsT.Add(stats.RenameCount, 1)
 }
 
+func (p *proxyrunner) objRetention(w http.ResponseWriter, r *http.Request, bck *cluster.Bck) {
+	started := time.Now()
+	apitems, err := p.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	objName := apitems[1]
+	smap := p.owner.smap.get()
+	si, err := cluster.HrwTarget(bck.MakeUname(objName), &smap.Smap)
+	if err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("RETENTION %s %s/%s => %s", r.Method, bck.Name, objName, si)
+	}
+	// code 307 to redirect with the original JSON payload (see objRename)
+	redirectURL := p.redirectURL(r, si, started, cmn.NetworkIntraControl)
+	http.Redirect(w, r, redirectURL, http.StatusTemporaryRedirect)
+}
+
 func (p *proxyrunner) promoteFQN(w http.ResponseWriter, r *http.Request, bck *cluster.Bck, msg *cmn.ActionMsg) {
 	apiItems, err := p.checkRESTItems(w, r, 1, false, cmn.Version, cmn.Objects)
 	if err != nil {
@@ -1989,6 +2107,54 @@ func (p *proxyrunner) httpTokenDelete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// PUT /v1/accesskeys - authn pushes the full list of access keys
+// The request is served on the control network only and must carry
+// the token of the admin (authn signs it with the cluster secret).
+func (p *proxyrunner) httpAccessKeysPut(w http.ResponseWriter, r *http.Request) {
+	keyList := &AccessKeyList{}
+	if _, err := p.checkRESTItems(w, r, 0, false, cmn.Version, cmn.AccessKeys); err != nil {
+		return
+	}
+	if !cmn.GCO.Get().Auth.Enabled {
+		p.invalmsghdlr(w, r, "access keys require authentication to be enabled", http.StatusForbidden)
+		return
+	}
+	if err := p.checkAdmin(r); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	if err := cmn.ReadJSON(w, r, keyList); err != nil {
+		return
+	}
+	smap := p.owner.smap.get()
+	if smap == nil || !smap.isValid() {
+		p.invalmsghdlr(w, r, fmt.Sprintf("%s must be starting up: cannot update access keys", p.si))
+		return
+	}
+	if !smap.isPrimary(p.si) {
+		// unlike forwardCP, forward via control network
+		res := p.call(callArgs{
+			si: smap.ProxySI,
+			req: cmn.ReqArgs{
+				Method: http.MethodPut,
+				Header: http.Header{cmn.HeaderAuthorization: []string{r.Header.Get(cmn.HeaderAuthorization)}},
+				Base:   smap.ProxySI.URL(cmn.NetworkIntraControl),
+				Path:   cmn.URLPath(cmn.Version, cmn.AccessKeys),
+				Body:   cmn.MustMarshal(keyList),
+			},
+			timeout: cmn.DefaultTimeout,
+		})
+		if res.err != nil {
+			p.invalmsghdlr(w, r, res.err.Error(), res.status)
+		}
+		return
+	}
+	keyList.Version = 0 // the primary assigns the version
+	p.authn.updateAccessKeys(keyList)
+	msg := p.newAisMsgStr(cmn.ActAccessKeys, nil, nil)
+	_ = p.metasyncer.sync(revsPair{p.authn.accessKeyList(), msg})
+}
+
 // Read a token from request header and validates it
 // Header format:
 //		'Authorization: Bearer <token>'
@@ -2016,6 +2182,26 @@ func (p *proxyrunner) validateToken(r *http.Request) (*authRec, error) {
 	return auth, nil
 }
 
+// Returns an error if AuthN is enabled and the request is not made by admin
+func (p *proxyrunner) checkAdmin(r *http.Request) error {
+	if !cmn.GCO.Get().Auth.Enabled {
+		return nil
+	}
+	auth, err := p.validateToken(r)
+	if err != nil {
+		return err
+	}
+	if !auth.isAdmin {
+		return errors.New(adminError)
+	}
+	return nil
+}
+
+// Legal hold and bypassing governance-mode retention are allowed only for admin
+func retentionOverride(header http.Header) bool {
+	return header.Get(cmn.HeaderObjLegalHold) != "" || header.Get(cmn.HeaderBypassRetention) != ""
+}
+
 // A wrapper to check any request before delegating the request to real handler
 // If authentication is disabled, it does nothing.
 // If authentication is enabled, it looks for token in request header and
@@ -2042,6 +2228,10 @@ func (p *proxyrunner) checkHTTPAuth(h http.HandlerFunc) http.HandlerFunc {
 				p.invalmsghdlr(w, r, guestError, http.StatusUnauthorized)
 				return
 			}
+			if !auth.isAdmin && retentionOverride(r.Header) {
+				p.invalmsghdlr(w, r, adminError, http.StatusForbidden)
+				return
+			}
 			if glog.FastV(4, glog.SmoduleAIS) {
 				if auth.isGuest {
 					glog.Info("Guest access granted")
@@ -2600,6 +2790,15 @@ func (p *proxyrunner) tokenHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (p *proxyrunner) accessKeyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPut:
+		p.httpAccessKeysPut(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /accesskeys path")
+	}
+}
+
 // http reverse-proxy handler, to handle unmodified requests
 // (not to confuse with p.rproxy)
 func (p *proxyrunner) reverseProxyHandler(w http.ResponseWriter, r *http.Request) {
@@ -2670,6 +2869,8 @@ func (p *proxyrunner) httpcluget(w http.ResponseWriter, r *http.Request) {
 		p.queryClusterSysinfo(w, r, what)
 	case cmn.GetWhatXactStats, cmn.GetWhatXactRunStatus:
 		p.queryXaction(w, r, what)
+	case cmn.GetWhatXactHistory:
+		p.queryXactHistory(w, r, what)
 	case cmn.GetWhatMountpaths:
 		p.queryClusterMountpaths(w, r, what)
 	case cmn.GetWhatRemoteAIS:
@@ -2718,6 +2919,25 @@ func (p *proxyrunner) queryXaction(w http.ResponseWriter, r *http.Request, what
 	}
 }
 
+func (p *proxyrunner) queryXactHistory(w http.ResponseWriter, r *http.Request, what string) {
+	msg := cmn.XactHistoryMsg{}
+	if cmn.ReadJSON(w, r, &msg) != nil {
+		return
+	}
+	results := p.bcastGet(bcastArgs{
+		req: cmn.ReqArgs{
+			Path:  cmn.URLPath(cmn.Version, cmn.Xactions),
+			Query: r.URL.Query(),
+			Body:  cmn.MustMarshal(msg),
+		},
+		timeout: cmn.GCO.Get().Timeout.MaxKeepalive,
+	})
+	targetResults := p._queryResults(w, r, results)
+	if targetResults != nil {
+		p.writeJSON(w, r, cmn.MustMarshal(targetResults), what)
+	}
+}
+
 func (p *proxyrunner) queryClusterSysinfo(w http.ResponseWriter, r *http.Request, what string) {
 	fetchResults := func(broadcastType int) (cmn.JSONRawMsgs, string) {
 		results := p.bcastTo(bcastArgs{
@@ -3013,6 +3233,7 @@ func (p *proxyrunner) updateAndDistribute(nsi *cluster.Snode, msg *cmn.ActionMsg
 		func(clone *smapX) {
 			var (
 				tokens = p.authn.revokedTokenList()
+				keys   = p.authn.accessKeyList()
 				bmd    = p.owner.bmd.get()
 			)
 			// metasync
@@ -3038,6 +3259,9 @@ func (p *proxyrunner) updateAndDistribute(nsi *cluster.Snode, msg *cmn.ActionMsg
 			if len(tokens.Tokens) > 0 {
 				pairs = append(pairs, revsPair{tokens, aisMsg})
 			}
+			if len(keys.Keys) > 0 {
+				pairs = append(pairs, revsPair{keys, aisMsg})
+			}
 			_ = p.metasyncer.sync(pairs...)
 		},
 	)
@@ -3543,6 +3767,12 @@ func (p *proxyrunner) requiresRebalance(prev, cur *smapX) bool {
 			if !cur.isPresent(si) {
 				return true
 			}
+			// The same goes for a target that has moved to another failure
+			// domain: the placement of EC slices depends on the domains
+			// (see cluster.HrwTargetList).
+			if cur.GetTarget(si.ID()).Zone != si.Zone {
+				return true
+			}
 		}
 	}
 
@@ -18,12 +18,14 @@ import (
 	jsoniter "github.com/json-iterator/go"
 )
 
-func (p *proxyrunner) broadcastDownloadRequest(method, path string, body []byte, query url.Values) chan callResult {
+func (p *proxyrunner) broadcastDownloadRequest(method, path string, body []byte, query url.Values,
+	header http.Header) chan callResult {
 	query.Add(cmn.URLParamProxyID, p.si.ID())
 	query.Add(cmn.URLParamUnixTime, cmn.UnixNano2S(time.Now().UnixNano()))
 	args := bcastArgs{
 		req: cmn.ReqArgs{
 			Method: method,
+			Header: header,
 			Path:   cmn.URLPath(cmn.Version, cmn.Download, path),
 			Query:  query,
 			Body:   body,
@@ -41,7 +43,7 @@ func (p *proxyrunner) broadcastDownloadAdminRequest(method, path string, msg *do
 		err         *callResult
 	)
 	body := cmn.MustMarshal(msg)
-	responses := p.broadcastDownloadRequest(method, path, body, url.Values{})
+	responses := p.broadcastDownloadRequest(method, path, body, url.Values{}, nil)
 	respCnt := len(responses)
 	if respCnt == 0 {
 		return nil, http.StatusInternalServerError, cluster.ErrNoTargets
@@ -119,7 +121,13 @@ func (p *proxyrunner) broadcastStartDownloadRequest(r *http.Request, id string)
 	query := r.URL.Query()
 	query.Set(cmn.URLParamID, id)
 
-	responses := p.broadcastDownloadRequest(http.MethodPost, r.URL.Path, body, query)
+	// custom headers and credentials to access the source (if any)
+	var header http.Header
+	if auth := r.Header.Get(cmn.HeaderDlSourceAuth); auth != "" {
+		header = make(http.Header, 1)
+		header.Set(cmn.HeaderDlSourceAuth, auth)
+	}
+	responses := p.broadcastDownloadRequest(http.MethodPost, r.URL.Path, body, query, header)
 	failures := make([]error, 0, len(responses))
 	for resp := range responses {
 		if resp.err != nil {
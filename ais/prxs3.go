@@ -54,6 +54,10 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 				p.getBckVersioningS3(w, r, apitems[0])
 				return
 			}
+			if _, uploads := q[s3compat.URLParamMptUploads]; uploads {
+				p.listMptUploadsS3(w, r, apitems[0])
+				return
+			}
 			// only bucket name - list objects in the bucket
 			p.bckListS3(w, r, apitems[0])
 			return
@@ -77,11 +81,23 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		}
 		p.putObjS3(w, r, apitems)
 	case http.MethodPost:
+		q := r.URL.Query()
+		if len(apitems) > 1 {
+			_, uploads := q[s3compat.URLParamMptUploads]
+			_, uploadID := q[s3compat.URLParamMptUploadID]
+			if !uploads && !uploadID {
+				p.invalmsghdlr(w, r, "invalid request")
+				return
+			}
+			// start or complete multipart upload: handled by the target
+			// that owns the object, the same way as a regular PUT
+			p.directPutObjS3(w, r, apitems)
+			return
+		}
 		if len(apitems) != 1 {
 			p.invalmsghdlr(w, r, "bucket name expected")
 			return
 		}
-		q := r.URL.Query()
 		if _, multiple := q[s3compat.URLParamMultiDelete]; !multiple {
 			p.invalmsghdlr(w, r, "invalid request")
 			return
@@ -109,6 +125,67 @@ func (p *proxyrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Verifies AWS Signature V4 of S3 request before passing it to the handler.
+// Unsigned requests are allowed only for read-only access and only if
+// guest access is enabled.
+func (p *proxyrunner) checkS3Auth(h http.HandlerFunc) http.HandlerFunc {
+	wrappedFunc := func(w http.ResponseWriter, r *http.Request) {
+		sig, err := s3compat.ParseSigV4(r)
+		if err == s3compat.ErrNoSignature {
+			if cmn.GCO.Get().Auth.AllowGuest && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+				h.ServeHTTP(w, r)
+				return
+			}
+			p.invalmsghdlr(w, r, "Not authorized", http.StatusForbidden)
+			return
+		}
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key, ok := p.authn.accessKey(sig.AccessKey)
+		if !ok {
+			p.invalmsghdlr(w, r, fmt.Sprintf("access key %q does not exist", sig.AccessKey), http.StatusForbidden)
+			return
+		}
+		secret, err := key.secret()
+		if err != nil {
+			p.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := sig.Verify(r, secret, time.Now()); err != nil {
+			glog.Errorf("%s: %s %s signed by %q: %v", p.si, r.Method, r.URL.Path, sig.AccessKey, err)
+			p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		if !key.Admin && s3compat.ObjLockOverride(r.Header) {
+			p.invalmsghdlr(w, r, adminError, http.StatusForbidden)
+			return
+		}
+		query := r.URL.Query()
+		if sig.Streaming(r) {
+			// target verifies the chunks of the payload: pass it the seed signature
+			query.Set(s3compat.URLParamSeedCredential, sig.Credential())
+			query.Set(s3compat.URLParamSeedDate, sig.AmzDate)
+			query.Set(s3compat.URLParamSeedSignature, sig.Signature)
+		} else {
+			// the payload is verified by the node that reads it: by this proxy or,
+			// if the request gets redirected, by the target
+			payloadHash := sig.PayloadHash(r)
+			r.Body = s3compat.NewPayloadReader(r.Body, payloadHash)
+			query.Set(s3compat.URLParamPayloadHash, payloadHash)
+			query.Set(s3compat.URLParamPayloadMAC,
+				s3compat.PayloadMAC(cmn.GCO.Get().Auth.Secret, r.Method, r.URL.Path, payloadHash))
+		}
+		r.URL.RawQuery = query.Encode()
+		if glog.FastV(4, glog.SmoduleAIS) {
+			glog.Infof("S3 request signed by %s (user %s)", sig.AccessKey, key.UserID)
+		}
+		h.ServeHTTP(w, r)
+	}
+	return wrappedFunc
+}
+
 // GET s3/
 func (p *proxyrunner) bckNamesToS3(w http.ResponseWriter) {
 	bmd := p.owner.bmd.get()
@@ -272,14 +349,53 @@ func (p *proxyrunner) bckListS3(w http.ResponseWriter, r *http.Request, bucket s
 		smsg.TaskID = taskID
 		time.Sleep(time.Second)
 	}
-	resp := s3compat.NewListObjectResult()
-	resp.PageMarker = smsg.PageMarker
+	resp := s3compat.NewListObjectResult(bck.Name, r.URL.Query())
 	resp.FillFromAisBckList(bckList)
 	b := resp.MustMarshal()
 	w.Header().Set("Content-Type", s3compat.ContentType)
 	w.Write(b)
 }
 
+// GET s3/bckName?uploads
+func (p *proxyrunner) listMptUploadsS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(p.owner.bmd, nil); err != nil {
+		p.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := bck.Allow(cmn.AccessGET); err != nil {
+		p.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	args := bcastArgs{
+		req: cmn.ReqArgs{
+			Path:  cmn.URLPath(s3compat.Root, bck.Name),
+			Query: r.URL.Query(),
+		},
+		timeout: cmn.DefaultTimeout,
+		network: cmn.NetworkIntraData,
+		to:      cluster.Targets,
+	}
+	resp := s3compat.NewListMptUploadsResult(bck.Name)
+	results := p.bcastGet(args)
+	for res := range results {
+		if res.err != nil {
+			p.invalmsghdlr(w, r, res.details, res.status)
+			return
+		}
+		tgtResp := &s3compat.ListMptUploadsResult{}
+		if err := xml.Unmarshal(res.outjson, tgtResp); err != nil {
+			p.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		resp.Uploads = append(resp.Uploads, tgtResp.Uploads...)
+	}
+	resp.Sort()
+	b := resp.MustMarshal()
+	w.Header().Set("Content-Type", s3compat.ContentType)
+	w.Write(b)
+}
+
 // PUT s3/bckName/objName - with HeaderObjSrc in request header - a source
 func (p *proxyrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	started := time.Now()
@@ -375,6 +491,12 @@ func (p *proxyrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, ite
 
 // PUT s3/bckName/objName
 func (p *proxyrunner) putObjS3(w http.ResponseWriter, r *http.Request, items []string) {
+	// multipart upload part (including part copy) goes to the target
+	// that owns the destination object
+	if _, uploadID := r.URL.Query()[s3compat.URLParamMptUploadID]; uploadID {
+		p.directPutObjS3(w, r, items)
+		return
+	}
 	if r.Header.Get(s3compat.HeaderObjSrc) == "" {
 		p.directPutObjS3(w, r, items)
 		return
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"reflect"
 	"strconv"
 	"time"
 
@@ -606,12 +605,9 @@ func (p *proxyrunner) makeNprops(bck *cluster.Bck, propsToUpdate cmn.BucketProps
 	)
 	nprops = bprops.Clone()
 	nprops.Apply(propsToUpdate)
-	if bprops.EC.Enabled && nprops.EC.Enabled {
-		if !reflect.DeepEqual(bprops.EC, nprops.EC) {
-			err = errors.New("once enabled, EC configuration can be only disabled but cannot be changed")
-			return
-		}
-	} else if nprops.EC.Enabled {
+	// NOTE: changing the configuration of the erasure coded bucket makes targets
+	// re-encode the bucket's objects (see: cmn.ActECReencode)
+	if !bprops.EC.Enabled && nprops.EC.Enabled {
 		if nprops.EC.DataSlices == 0 {
 			nprops.EC.DataSlices = 1
 		}
@@ -619,6 +615,12 @@ func (p *proxyrunner) makeNprops(bck *cluster.Bck, propsToUpdate cmn.BucketProps
 			nprops.EC.ParitySlices = 1
 		}
 	}
+	if bprops.Retention.Enabled && bprops.Retention.Mode == cmn.RetentionCompliance {
+		if !nprops.Retention.Enabled || nprops.Retention.Mode != cmn.RetentionCompliance {
+			err = errors.New("compliance-mode retention can be neither disabled nor changed to governance mode")
+			return
+		}
+	}
 	if !bprops.Mirror.Enabled && nprops.Mirror.Enabled {
 		if nprops.Mirror.Copies == 1 {
 			nprops.Mirror.Copies = cmn.MaxI64(cfg.Mirror.Copies, 2)
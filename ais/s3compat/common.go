@@ -25,6 +25,17 @@ const (
 	versioningEnabled   = "Enabled"
 	versioningDisabled  = "Suspended"
 
+	// list objects
+	URLParamListType = "list-type" // URL parameter
+	listTypeV2       = "2"
+
+	// multipart upload
+	URLParamMptUploads  = "uploads"    // URL parameter
+	URLParamMptUploadID = "uploadId"   // URL parameter
+	URLParamMptPartNo   = "partNumber" // URL parameter
+	maxPartNum          = 10000
+	maxUploads          = 1000
+
 	s3Namespace = "http://s3.amazonaws.com/doc/2006-03-01"
 	// TODO: can it be omitted? // storageClass = "STANDARD"
 
@@ -32,13 +43,20 @@ const (
 	// TODO: move to cmn/http.go
 	HeaderSize         = "Content-Length"
 	HeaderContentType  = "Content-Type"
+	HeaderContentMD5   = "Content-MD5"
 	HeaderAcceptRanges = "Content-Range"
 	HeaderContentRange = "Accept-Ranges"
-	headerETag         = "ETag"
+	HeaderETag         = "ETag"
 	headerVersion      = "x-amz-version-id"
 	HeaderObjSrc       = "x-amz-copy-source"
+	HeaderObjSrcRange  = "x-amz-copy-source-range"
 	HeaderRange        = "Range"
 	headerAtime        = "Last-Modified"
+
+	// user-defined metadata
+	HeaderMetaPrefix     = "x-amz-meta-"              // x-amz-meta-<key>: <value>
+	HeaderMetaDirective  = "x-amz-metadata-directive" // copy object: COPY (default) or REPLACE metadata
+	MetaDirectiveReplace = "REPLACE"
 )
 
 // Parses range in RFC2616 format(bytes=N-N, bytes=-N, bytes=N-), and returns
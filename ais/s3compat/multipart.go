@@ -0,0 +1,362 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// NOTE: multipart uploads are tracked in memory by the target that owns
+// the destination object (the one that is selected by HRW). Parts are
+// stored as work files, so in case of target restart all unfinished
+// uploads are lost, and the parts get cleaned up by the housekeeper.
+
+type (
+	// Initiate multipart upload response
+	InitiateMptUploadResult struct {
+		Ns       string `xml:"xmlns,attr"`
+		Bucket   string `xml:"Bucket"`
+		Key      string `xml:"Key"`
+		UploadID string `xml:"UploadId"`
+	}
+
+	// Complete multipart upload request and response
+	CompleteMptUpload struct {
+		Parts []*PartInfo `xml:"Part"`
+	}
+	CompleteMptUploadResult struct {
+		Ns     string `xml:"xmlns,attr"`
+		Bucket string `xml:"Bucket"`
+		Key    string `xml:"Key"`
+		ETag   string `xml:"ETag"`
+	}
+	PartInfo struct {
+		PartNumber   int64  `xml:"PartNumber"`
+		ETag         string `xml:"ETag"`
+		Size         int64  `xml:"Size,omitempty"`
+		LastModified string `xml:"LastModified,omitempty"`
+	}
+
+	// Response for part copy request
+	CopyPartResult struct {
+		LastModified string `xml:"LastModified"`
+		ETag         string `xml:"ETag"`
+	}
+
+	// List parts response
+	ListPartsResult struct {
+		Ns       string      `xml:"xmlns,attr"`
+		Bucket   string      `xml:"Bucket"`
+		Key      string      `xml:"Key"`
+		UploadID string      `xml:"UploadId"`
+		Parts    []*PartInfo `xml:"Part"`
+	}
+
+	// List multipart uploads response
+	ListMptUploadsResult struct {
+		Ns          string        `xml:"xmlns,attr"`
+		Bucket      string        `xml:"Bucket"`
+		MaxUploads  int           `xml:"MaxUploads"`
+		IsTruncated bool          `xml:"IsTruncated"`
+		Uploads     []*UploadInfo `xml:"Upload"`
+	}
+	UploadInfo struct {
+		Key       string `xml:"Key"`
+		UploadID  string `xml:"UploadId"`
+		Initiated string `xml:"Initiated"`
+	}
+
+	// Staged part of a multipart upload
+	MptPart struct {
+		MD5  string // MD5 of the part (hex-encoded), used as part ETag
+		FQN  string // FQN of the work file that keeps the part data
+		Size int64  // part size in bytes
+		Num  int64  // part number
+	}
+	mptUpload struct {
+		bckName    string
+		objName    string
+		parts      []*MptPart    // sorted by part number
+		ctime      time.Time     // the time when the upload was initiated
+		customMD   cmn.SimpleKVs // user-defined metadata of the resulting object
+		completing bool          // parts are being assembled into the object (see StartCompletion)
+	}
+	mptUploads struct {
+		sync.RWMutex
+		m map[string]*mptUpload // uploadID => upload
+	}
+)
+
+var ups = &mptUploads{m: make(map[string]*mptUpload)}
+
+// Start tracking a new multipart upload.
+func InitUpload(id, bckName, objName string, customMD cmn.SimpleKVs) {
+	ups.Lock()
+	ups.m[id] = &mptUpload{
+		bckName:  bckName,
+		objName:  objName,
+		parts:    make([]*MptPart, 0, 8),
+		ctime:    time.Now(),
+		customMD: customMD,
+	}
+	ups.Unlock()
+}
+
+// Add (or replace, if a part with the same number already exists) a part
+// of the multipart upload. A replaced part's work file is removed.
+func AddPart(id string, npart *MptPart) error {
+	ups.Lock()
+	defer ups.Unlock()
+	mpt, ok := ups.m[id]
+	if !ok {
+		return fmt.Errorf("upload %q not found", id)
+	}
+	if mpt.completing {
+		return fmt.Errorf("upload %q is being completed", id)
+	}
+	idx := sort.Search(len(mpt.parts), func(i int) bool { return mpt.parts[i].Num >= npart.Num })
+	switch {
+	case idx < len(mpt.parts) && mpt.parts[idx].Num == npart.Num:
+		if err := os.Remove(mpt.parts[idx].FQN); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		mpt.parts[idx] = npart
+	default:
+		mpt.parts = append(mpt.parts, nil)
+		copy(mpt.parts[idx+1:], mpt.parts[idx:])
+		mpt.parts[idx] = npart
+	}
+	return nil
+}
+
+// Return the object name of the upload; the upload must belong to the bucket.
+func UploadObjName(id, bckName string) (string, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	mpt, ok := ups.m[id]
+	if !ok || mpt.bckName != bckName {
+		return "", fmt.Errorf("upload %q not found", id)
+	}
+	return mpt.objName, nil
+}
+
+// Return user-defined metadata that was provided when the upload was initiated.
+func UploadCustomMD(id string) cmn.SimpleKVs {
+	ups.RLock()
+	defer ups.RUnlock()
+	if mpt, ok := ups.m[id]; ok {
+		return mpt.customMD
+	}
+	return nil
+}
+
+// Validate the list of parts sent with "complete multipart upload" request
+// against the staged parts. Returns the staged parts in the requested order.
+// On success, the upload gets locked: until FinishUpload or CancelCompletion,
+// its parts cannot be added, replaced, or removed (the parts' work files are
+// being read while the object is assembled).
+func StartCompletion(id string, parts []*PartInfo) ([]*MptPart, error) {
+	ups.Lock()
+	defer ups.Unlock()
+	mpt, ok := ups.m[id]
+	if !ok {
+		return nil, fmt.Errorf("upload %q not found", id)
+	}
+	if mpt.completing {
+		return nil, fmt.Errorf("upload %q is being completed", id)
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("upload %q: list of parts is empty", id)
+	}
+	res := make([]*MptPart, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 && part.PartNumber <= parts[i-1].PartNumber {
+			return nil, fmt.Errorf("upload %q: parts must be in ascending order", id)
+		}
+		idx := sort.Search(len(mpt.parts), func(i int) bool { return mpt.parts[i].Num >= part.PartNumber })
+		if idx >= len(mpt.parts) || mpt.parts[idx].Num != part.PartNumber {
+			return nil, fmt.Errorf("upload %q: part %d not found", id, part.PartNumber)
+		}
+		staged := mpt.parts[idx]
+		if etag := strings.Trim(part.ETag, "\""); etag != "" && etag != staged.MD5 {
+			return nil, fmt.Errorf("upload %q: part %d ETag mismatch (%s vs %s)", id, part.PartNumber, etag, staged.MD5)
+		}
+		res = append(res, staged)
+	}
+	mpt.completing = true
+	return res, nil
+}
+
+// Unlock the upload after a failed attempt to complete it.
+func CancelCompletion(id string) {
+	ups.Lock()
+	if mpt, ok := ups.m[id]; ok {
+		mpt.completing = false
+	}
+	ups.Unlock()
+}
+
+// Stop tracking the upload and remove all its work files.
+func FinishUpload(id string) (err error) {
+	return finishUpload(id, false /*abort*/)
+}
+
+// Same as FinishUpload but fails if the upload is being completed.
+func AbortUpload(id string) (err error) {
+	return finishUpload(id, true /*abort*/)
+}
+
+func finishUpload(id string, abort bool) (err error) {
+	ups.Lock()
+	mpt, ok := ups.m[id]
+	if !ok {
+		ups.Unlock()
+		return fmt.Errorf("upload %q not found", id)
+	}
+	if abort && mpt.completing {
+		ups.Unlock()
+		return fmt.Errorf("upload %q is being completed", id)
+	}
+	delete(ups.m, id)
+	ups.Unlock()
+	for _, part := range mpt.parts {
+		if errRm := os.Remove(part.FQN); errRm != nil && !os.IsNotExist(errRm) {
+			err = errRm
+		}
+	}
+	return
+}
+
+// Return the list of uploaded parts of the upload.
+func ListParts(id, bckName string) (*ListPartsResult, error) {
+	ups.RLock()
+	defer ups.RUnlock()
+	mpt, ok := ups.m[id]
+	if !ok || mpt.bckName != bckName {
+		return nil, fmt.Errorf("upload %q not found", id)
+	}
+	res := &ListPartsResult{
+		Ns:       s3Namespace,
+		Bucket:   mpt.bckName,
+		Key:      mpt.objName,
+		UploadID: id,
+		Parts:    make([]*PartInfo, 0, len(mpt.parts)),
+	}
+	for _, part := range mpt.parts {
+		res.Parts = append(res.Parts, &PartInfo{
+			PartNumber: part.Num,
+			ETag:       part.MD5,
+			Size:       part.Size,
+		})
+	}
+	return res, nil
+}
+
+// Return the list of active uploads to the bucket.
+func ListUploads(bckName string) *ListMptUploadsResult {
+	res := NewListMptUploadsResult(bckName)
+	ups.RLock()
+	for id, mpt := range ups.m {
+		if mpt.bckName != bckName {
+			continue
+		}
+		res.Uploads = append(res.Uploads, &UploadInfo{
+			Key:       mpt.objName,
+			UploadID:  id,
+			Initiated: mpt.ctime.UTC().Format(time.RFC3339),
+		})
+	}
+	ups.RUnlock()
+	res.Sort()
+	return res
+}
+
+// Part number must be in the range 1..10000 (see AWS docs).
+func ParsePartNum(s string) (int64, error) {
+	partNum, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || partNum < 1 || partNum > maxPartNum {
+		return 0, fmt.Errorf("invalid part number %q (must be a number in the range 1..%d)", s, maxPartNum)
+	}
+	return partNum, nil
+}
+
+// ETag of an object assembled from parts: MD5 of concatenated binary MD5s
+// of the parts with the number of parts appended, e.g. "<hex>-5".
+func MptETag(parts []*MptPart) string {
+	h := md5.New()
+	for _, part := range parts {
+		b, err := hex.DecodeString(part.MD5)
+		cmn.AssertNoErr(err)
+		h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil)) + "-" + strconv.Itoa(len(parts))
+}
+
+func NewInitiateMptUploadResult(bucket, objName, id string) *InitiateMptUploadResult {
+	return &InitiateMptUploadResult{Ns: s3Namespace, Bucket: bucket, Key: objName, UploadID: id}
+}
+
+func (r *InitiateMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func NewCompleteMptUploadResult(bucket, objName, etag string) *CompleteMptUploadResult {
+	return &CompleteMptUploadResult{Ns: s3Namespace, Bucket: bucket, Key: objName, ETag: etag}
+}
+
+func (r *CompleteMptUploadResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func (r *CopyPartResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func (r *ListPartsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
+
+func NewListMptUploadsResult(bckName string) *ListMptUploadsResult {
+	return &ListMptUploadsResult{
+		Ns:         s3Namespace,
+		Bucket:     bckName,
+		MaxUploads: maxUploads,
+		Uploads:    make([]*UploadInfo, 0),
+	}
+}
+
+func (r *ListMptUploadsResult) Sort() {
+	sort.Slice(r.Uploads, func(i, j int) bool {
+		if r.Uploads[i].Key != r.Uploads[j].Key {
+			return r.Uploads[i].Key < r.Uploads[j].Key
+		}
+		return r.Uploads[i].Initiated < r.Uploads[j].Initiated
+	})
+}
+
+func (r *ListMptUploadsResult) MustMarshal() []byte {
+	b, err := xml.Marshal(r)
+	cmn.AssertNoErr(err)
+	return []byte(xml.Header + string(b))
+}
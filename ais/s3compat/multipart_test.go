@@ -0,0 +1,93 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestMptParts(t *testing.T) {
+	const (
+		id      = "upload-id"
+		bckName = "bck"
+		objName = "obj"
+	)
+	customMD := cmn.SimpleKVs{"author": "unknown"}
+	InitUpload(id, bckName, objName, customMD)
+	defer FinishUpload(id)
+
+	// add parts out of order, and then replace the second one
+	for _, num := range []int64{3, 1, 2, 2} {
+		err := AddPart(id, &MptPart{MD5: md5Hex(string(rune('a' + num))), FQN: "/nonexistent", Num: num, Size: 1})
+		tassert.CheckFatal(t, err)
+	}
+	parts, err := ListParts(id, bckName)
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(parts.Parts) == 3, "expected 3 parts, got %d", len(parts.Parts))
+	for i, part := range parts.Parts {
+		tassert.Errorf(t, part.PartNumber == int64(i+1), "expected part %d, got %d", i+1, part.PartNumber)
+	}
+
+	_, err = ListParts(id, "another-bucket")
+	tassert.Errorf(t, err != nil, "expected error listing parts of another bucket")
+
+	// parts must be in ascending order
+	_, err = StartCompletion(id, []*PartInfo{{PartNumber: 2}, {PartNumber: 1}})
+	tassert.Errorf(t, err != nil, "expected error for unordered parts")
+	// ETag mismatch
+	_, err = StartCompletion(id, []*PartInfo{{PartNumber: 1, ETag: md5Hex("x")}})
+	tassert.Errorf(t, err != nil, "expected error for mismatched ETag")
+	// unknown part
+	_, err = StartCompletion(id, []*PartInfo{{PartNumber: 4}})
+	tassert.Errorf(t, err != nil, "expected error for nonexistent part")
+
+	staged, err := StartCompletion(id, []*PartInfo{{PartNumber: 1, ETag: "\"" + md5Hex("b") + "\""}, {PartNumber: 3}})
+	tassert.CheckFatal(t, err)
+	tassert.Fatalf(t, len(staged) == 2 && staged[0].Num == 1 && staged[1].Num == 3, "unexpected parts: %v", staged)
+
+	// parts of the upload that is being completed are locked
+	err = AddPart(id, &MptPart{MD5: md5Hex("c"), FQN: "/nonexistent", Num: 2, Size: 1})
+	tassert.Errorf(t, err != nil, "expected error adding part to the upload that is being completed")
+	_, err = StartCompletion(id, []*PartInfo{{PartNumber: 1}})
+	tassert.Errorf(t, err != nil, "expected error completing the upload twice")
+	tassert.Errorf(t, AbortUpload(id) != nil, "expected error aborting the upload that is being completed")
+	CancelCompletion(id)
+	tassert.CheckFatal(t, AddPart(id, &MptPart{MD5: md5Hex("c"), FQN: "/nonexistent", Num: 2, Size: 1}))
+
+	tassert.Errorf(t, UploadCustomMD(id)["author"] == "unknown", "unexpected custom metadata %v", UploadCustomMD(id))
+
+	uploads := ListUploads(bckName)
+	tassert.Fatalf(t, len(uploads.Uploads) == 1, "expected 1 upload, got %d", len(uploads.Uploads))
+	tassert.Errorf(t, uploads.Uploads[0].Key == objName, "expected %q, got %q", objName, uploads.Uploads[0].Key)
+}
+
+func TestMptETag(t *testing.T) {
+	parts := []*MptPart{{MD5: md5Hex("part1")}, {MD5: md5Hex("part2")}}
+	b1, _ := hex.DecodeString(parts[0].MD5)
+	b2, _ := hex.DecodeString(parts[1].MD5)
+	expected := md5Hex(string(b1)+string(b2)) + "-2"
+	etag := MptETag(parts)
+	tassert.Errorf(t, etag == expected, "expected %q, got %q", expected, etag)
+}
+
+func TestParsePartNum(t *testing.T) {
+	for _, s := range []string{"0", "-1", "10001", "abc", ""} {
+		_, err := ParsePartNum(s)
+		tassert.Errorf(t, err != nil, "expected error for part number %q", s)
+	}
+	num, err := ParsePartNum("10000")
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, num == 10000, "expected 10000, got %d", num)
+}
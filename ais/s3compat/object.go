@@ -17,16 +17,23 @@ import (
 )
 
 type (
-	// List objects response
+	// List objects response (both V1 and V2 API versions)
 	ListObjectResult struct {
-		Ns          string     `xml:"xmlns,attr"`
-		Prefix      string     `xml:"Prefix"`
-		KeyCount    int        `xml:"KeyCount"` // number of objects in the response
-		MaxKeys     int        `xml:"MaxKeys"`
-		IsTruncated bool       `xml:"IsTruncated"`           // true if there are more pages to read
-		PageMarker  string     `xml:"ContinuationToken"`     // original PageMarker
-		NextMarker  string     `xml:"NextContinuationToken"` // PageMarker to read the next page
-		Contents    []*ObjInfo `xml:"Contents"`              // list of objects
+		Ns             string          `xml:"xmlns,attr"`
+		Name           string          `xml:"Name"` // bucket name
+		Prefix         string          `xml:"Prefix"`
+		Delimiter      string          `xml:"Delimiter,omitempty"`
+		KeyCount       int             `xml:"KeyCount,omitempty"` // number of objects in the response (V2)
+		MaxKeys        int             `xml:"MaxKeys"`
+		IsTruncated    bool            `xml:"IsTruncated"`                     // true if there are more pages to read
+		Marker         string          `xml:"Marker,omitempty"`                // original PageMarker (V1)
+		NextMarker     string          `xml:"NextMarker,omitempty"`            // PageMarker to read the next page (V1)
+		PageMarker     string          `xml:"ContinuationToken,omitempty"`     // original PageMarker (V2)
+		NextPageMarker string          `xml:"NextContinuationToken,omitempty"` // PageMarker to read the next page (V2)
+		StartAfter     string          `xml:"StartAfter,omitempty"`            // (V2)
+		Contents       []*ObjInfo      `xml:"Contents"`                        // list of objects
+		CommonPrefixes []*CommonPrefix `xml:"CommonPrefixes"`                  // list of "directories"
+		v2             bool
 	}
 	ObjInfo struct {
 		Key          string `xml:"Key"`
@@ -35,6 +42,9 @@ type (
 		Size         int64  `xml:"Size"`
 		Class        string `xml:"StorageClass"`
 	}
+	CommonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
 
 	// Response for object copy request
 	CopyObjectResult struct {
@@ -43,14 +53,24 @@ type (
 	}
 )
 
+// Fills SelectMsg from the query of S3 list objects request. Both V1
+// ("marker") and V2 ("list-type=2", "continuation-token", "start-after")
+// paging is supported.
 func FillMsgFromS3Query(query url.Values, msg *cmn.SelectMsg) {
 	mxStr := query.Get("max-keys")
-	if pageSize, err := strconv.Atoi(mxStr); err != nil && pageSize != 0 {
-		msg.PageSize = pageSize
+	if pageSize, err := strconv.Atoi(mxStr); err == nil && pageSize > 0 {
+		msg.PageSize = cmn.Min(pageSize, cmn.DefaultListPageSize)
 	}
 	if prefix := query.Get("prefix"); prefix != "" {
 		msg.Prefix = prefix
 	}
+	if delimiter := query.Get("delimiter"); delimiter != "" {
+		msg.Delimiter = delimiter
+	}
+	if query.Get(URLParamListType) != listTypeV2 {
+		msg.PageMarker = query.Get("marker")
+		return
+	}
 	var marker string
 	if marker = query.Get("continuation-token"); marker != "" {
 		msg.PageMarker = marker
@@ -62,12 +82,27 @@ func FillMsgFromS3Query(query url.Values, msg *cmn.SelectMsg) {
 	}
 }
 
-func NewListObjectResult() *ListObjectResult {
-	return &ListObjectResult{
-		Ns:       s3Namespace,
-		MaxKeys:  1000,
-		Contents: make([]*ObjInfo, 0),
+func NewListObjectResult(bucket string, query url.Values) *ListObjectResult {
+	r := &ListObjectResult{
+		Ns:             s3Namespace,
+		Name:           bucket,
+		Prefix:         query.Get("prefix"),
+		Delimiter:      query.Get("delimiter"),
+		MaxKeys:        cmn.DefaultListPageSize,
+		Contents:       make([]*ObjInfo, 0),
+		CommonPrefixes: make([]*CommonPrefix, 0),
+		v2:             query.Get(URLParamListType) == listTypeV2,
+	}
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && maxKeys > 0 && maxKeys < r.MaxKeys {
+		r.MaxKeys = maxKeys
 	}
+	if r.v2 {
+		r.PageMarker = query.Get("continuation-token")
+		r.StartAfter = query.Get("start-after")
+	} else {
+		r.Marker = query.Get("marker")
+	}
+	return r
 }
 
 func (r *ListObjectResult) MustMarshal() []byte {
@@ -77,6 +112,10 @@ func (r *ListObjectResult) MustMarshal() []byte {
 }
 
 func (r *ListObjectResult) Add(entry *cmn.BucketEntry) {
+	if entry.IsDir() {
+		r.CommonPrefixes = append(r.CommonPrefixes, &CommonPrefix{Prefix: entry.Name})
+		return
+	}
 	r.Contents = append(r.Contents, entryToS3(entry))
 }
 
@@ -90,9 +129,13 @@ func entryToS3(entry *cmn.BucketEntry) *ObjInfo {
 }
 
 func (r *ListObjectResult) FillFromAisBckList(bckList *cmn.BucketList) {
-	r.KeyCount = len(bckList.Entries)
 	r.IsTruncated = bckList.PageMarker != ""
-	r.NextMarker = bckList.PageMarker
+	if r.v2 {
+		r.KeyCount = len(bckList.Entries)
+		r.NextPageMarker = bckList.PageMarker
+	} else {
+		r.NextMarker = bckList.PageMarker
+	}
 	for _, e := range bckList.Entries {
 		r.Add(e)
 	}
@@ -106,9 +149,11 @@ func SetHeaderFromSizeVersion(header http.Header, size int64, version string) {
 
 func SetHeaderFromLOM(header http.Header, lom *cluster.LOM) {
 	if cksum := lom.Cksum(); cksum != nil {
-		header.Set(headerETag, cksum.Value())
+		header.Set(HeaderETag, cksum.Value())
 	}
 	header.Set(headerAtime, lom.Atime().UTC().Format(time.RFC3339))
+	cmn.CustomMDToHeader(lom.CustomMD(), header, HeaderMetaPrefix)
+	setObjLockHeader(header, lom)
 	SetHeaderFromSizeVersion(header, lom.Size(), lom.Version())
 }
 
@@ -0,0 +1,55 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestFillMsgFromS3Query(t *testing.T) {
+	tests := []struct {
+		query  string
+		marker string
+		size   int
+	}{
+		{"prefix=a/&delimiter=/&max-keys=10&marker=a/b", "a/b", 10},
+		{"list-type=2&continuation-token=a/c&start-after=a/b", "a/c", 0},
+		{"list-type=2&start-after=a/b&max-keys=5000", "a/b", cmn.DefaultListPageSize},
+	}
+	for _, test := range tests {
+		query, err := url.ParseQuery(test.query)
+		tassert.CheckFatal(t, err)
+		msg := &cmn.SelectMsg{}
+		FillMsgFromS3Query(query, msg)
+		tassert.Errorf(t, msg.PageMarker == test.marker, "%s: expected marker %q, got %q", test.query, test.marker, msg.PageMarker)
+		tassert.Errorf(t, msg.PageSize == test.size, "%s: expected page size %d, got %d", test.query, test.size, msg.PageSize)
+	}
+}
+
+func TestListObjectResult(t *testing.T) {
+	bckList := &cmn.BucketList{
+		Entries: []*cmn.BucketEntry{
+			{Name: "a/b/", Flags: cmn.ObjStatusOK | cmn.EntryIsDir},
+			{Name: "a/c", Size: 10},
+		},
+		PageMarker: "a/c",
+	}
+
+	query, _ := url.ParseQuery("prefix=a/&delimiter=/")
+	r := NewListObjectResult("bck", query)
+	r.FillFromAisBckList(bckList)
+	tassert.Fatalf(t, len(r.Contents) == 1 && r.Contents[0].Key == "a/c", "unexpected contents: %v", r.Contents)
+	tassert.Fatalf(t, len(r.CommonPrefixes) == 1 && r.CommonPrefixes[0].Prefix == "a/b/", "unexpected prefixes: %v", r.CommonPrefixes)
+	tassert.Errorf(t, r.IsTruncated && r.NextMarker == "a/c" && r.NextPageMarker == "", "unexpected V1 markers")
+
+	query, _ = url.ParseQuery("list-type=2&prefix=a/&delimiter=/")
+	r = NewListObjectResult("bck", query)
+	r.FillFromAisBckList(bckList)
+	tassert.Errorf(t, r.NextPageMarker == "a/c" && r.NextMarker == "" && r.KeyCount == 2, "unexpected V2 markers")
+}
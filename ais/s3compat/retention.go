@@ -0,0 +1,70 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// object lock (WORM)
+const (
+	HeaderObjLockMode        = "x-amz-object-lock-mode"
+	HeaderObjLockRetainUntil = "x-amz-object-lock-retain-until-date"
+	HeaderObjLockLegalHold   = "x-amz-object-lock-legal-hold"
+	HeaderBypassGovernance   = "x-amz-bypass-governance-retention"
+
+	legalHoldOn  = "ON"
+	legalHoldOff = "OFF"
+)
+
+// ObjLockFromHeader returns the retention of a new object requested with
+// `x-amz-object-lock-*` headers. The mode, if defined, must be the bucket's one.
+func ObjLockFromHeader(header http.Header, conf *cmn.RetentionConf) (ret cmn.ObjectRetention, err error) {
+	if mode := header.Get(HeaderObjLockMode); mode != "" && !strings.EqualFold(mode, conf.Mode) {
+		err = fmt.Errorf("object lock mode %q does not match the bucket's %q", mode, conf.Mode)
+		return
+	}
+	ret.RetainUntil = header.Get(HeaderObjLockRetainUntil)
+	switch hold := header.Get(HeaderObjLockLegalHold); strings.ToUpper(hold) {
+	case "", legalHoldOff:
+	case legalHoldOn:
+		ret.LegalHold = true
+	default:
+		err = fmt.Errorf("invalid legal hold %q (expected %s or %s)", hold, legalHoldOn, legalHoldOff)
+	}
+	return
+}
+
+// ObjLockOverride returns true if the request changes legal hold or bypasses
+// governance-mode retention: both are allowed only for admin.
+func ObjLockOverride(header http.Header) bool {
+	return header.Get(HeaderObjLockLegalHold) != "" || BypassGovernance(header)
+}
+
+func BypassGovernance(header http.Header) bool {
+	return cmn.IsParseBool(header.Get(HeaderBypassGovernance))
+}
+
+func setObjLockHeader(header http.Header, lom *cluster.LOM) {
+	conf := lom.RetentionConf()
+	if !conf.Enabled {
+		return
+	}
+	if until := lom.RetainUntil(); until != 0 {
+		header.Set(HeaderObjLockMode, strings.ToUpper(conf.Mode))
+		header.Set(HeaderObjLockRetainUntil, time.Unix(0, until).UTC().Format(time.RFC3339))
+	}
+	if lom.LegalHold() {
+		header.Set(HeaderObjLockLegalHold, legalHoldOn)
+	} else {
+		header.Set(HeaderObjLockLegalHold, legalHoldOff)
+	}
+}
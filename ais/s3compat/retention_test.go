@@ -0,0 +1,41 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestObjLockFromHeader(t *testing.T) {
+	conf := &cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionGovernance}
+
+	header := make(http.Header)
+	header.Set(HeaderObjLockMode, "GOVERNANCE")
+	header.Set(HeaderObjLockRetainUntil, "2021-01-01T00:00:00Z")
+	header.Set(HeaderObjLockLegalHold, "ON")
+	ret, err := ObjLockFromHeader(header, conf)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, ret.RetainUntil == "2021-01-01T00:00:00Z", "unexpected retain-until %q", ret.RetainUntil)
+	tassert.Errorf(t, ret.LegalHold, "expected legal hold")
+	tassert.Errorf(t, ObjLockOverride(header), "legal hold must require admin")
+
+	header.Set(HeaderObjLockMode, "COMPLIANCE")
+	_, err = ObjLockFromHeader(header, conf)
+	tassert.Errorf(t, err != nil, "expected error for mismatched mode")
+
+	header = make(http.Header)
+	header.Set(HeaderObjLockLegalHold, "maybe")
+	_, err = ObjLockFromHeader(header, conf)
+	tassert.Errorf(t, err != nil, "expected error for invalid legal hold")
+
+	header = make(http.Header)
+	tassert.Errorf(t, !ObjLockOverride(header), "unexpected override")
+	header.Set(HeaderBypassGovernance, "true")
+	tassert.Errorf(t, BypassGovernance(header) && ObjLockOverride(header), "expected governance bypass")
+}
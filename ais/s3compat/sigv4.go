@@ -0,0 +1,562 @@
+// Package s3compat provides Amazon S3 compatibility layer
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package s3compat
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// AWS Signature Version 4 (see https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html).
+// Supported are:
+//  - signature in the 'Authorization' header,
+//  - presigned URL (signature in the query parameters),
+//  - chunked upload ('aws-chunked' content encoding) where each chunk
+//    of the payload is signed with the signature of the previous one.
+//
+// Proxy verifies the signature of a request before redirecting it to
+// a target. For chunked uploads, the proxy passes the credential, the date,
+// and the seed signature to the target in the redirect URL, so that the
+// target can verify the signatures of the chunks while receiving the data.
+// Otherwise, the proxy passes the signed SHA-256 of the payload (authenticated
+// with the cluster secret, see PayloadMAC), and the target verifies the payload
+// against it (see NewPayloadReader).
+
+const (
+	signV4Algo       = "AWS4-HMAC-SHA256"
+	chunkSignV4Algo  = "AWS4-HMAC-SHA256-PAYLOAD"
+	scopeTerminator  = "aws4_request"
+	amzDateFormat    = "20060102T150405Z"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	emptySHA256      = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	maxClockSkew    = 15 * time.Minute
+	maxPresignedAge = 7 * 24 * time.Hour
+	maxChunkHdrLen  = 4 * cmn.KiB
+
+	// Headers
+	HeaderAmzDate          = "X-Amz-Date"
+	HeaderContentSHA256    = "X-Amz-Content-Sha256"
+	HeaderDecodedSize      = "X-Amz-Decoded-Content-Length"
+	HeaderContentEncoding  = "Content-Encoding"
+	contentEncodingChunked = "aws-chunked"
+
+	// presigned URL parameters
+	queryAlgorithm     = "X-Amz-Algorithm"
+	queryCredential    = "X-Amz-Credential"
+	queryDate          = "X-Amz-Date"
+	queryExpires       = "X-Amz-Expires"
+	querySignedHeaders = "X-Amz-SignedHeaders"
+	querySignature     = "X-Amz-Signature"
+
+	// URL parameters added by proxy when it redirects a chunked upload
+	URLParamSeedCredential = "seed_cred" // URL parameter
+	URLParamSeedDate       = "seed_date" // URL parameter
+	URLParamSeedSignature  = "seed_sig"  // URL parameter
+	URLParamPayloadHash    = "payload_sha256"
+	URLParamPayloadMAC     = "payload_mac"
+)
+
+type (
+	// Parsed AWS signature V4 of a request
+	SigV4 struct {
+		AccessKey     string
+		Date          string // date of the credential scope (YYYYMMDD)
+		Region        string
+		Service       string
+		AmzDate       string // request timestamp (ISO8601 basic format)
+		SignedHeaders []string
+		Signature     string
+		expires       time.Duration // validity period of presigned URL
+		presigned     bool
+	}
+
+	// Decodes 'aws-chunked' payload and, if the signing key is set,
+	// verifies the signature of every chunk.
+	chunkedReader struct {
+		r         *bufio.Reader
+		body      io.ReadCloser
+		sig       *SigV4
+		key       []byte
+		prevSig   string // signature of the previous chunk (seed signature for the first one)
+		chunkSig  string // signature of the current chunk
+		remaining int64  // bytes left to read in the current chunk
+		h         hash.Hash
+		err       error
+	}
+
+	// Computes SHA-256 of the payload and fails the final read if it does
+	// not match the one that the request was signed with.
+	payloadReader struct {
+		body     io.ReadCloser
+		h        hash.Hash
+		expected string
+	}
+)
+
+var (
+	ErrNoSignature      = errors.New("request is not signed")
+	ErrSignatureInvalid = errors.New("the request signature does not match the signature calculated by the server")
+	ErrPayloadInvalid   = errors.New("the provided '" + HeaderContentSHA256 + "' does not match what was computed")
+)
+
+// Returns the signature of the request. Returns ErrNoSignature if the request
+// is neither signed in the header, nor presigned.
+func ParseSigV4(r *http.Request) (*SigV4, error) {
+	if auth := r.Header.Get(cmn.HeaderAuthorization); auth != "" {
+		return parseSigV4Header(r, auth)
+	}
+	if query := r.URL.Query(); query.Get(queryAlgorithm) != "" {
+		return parseSigV4Query(query)
+	}
+	return nil, ErrNoSignature
+}
+
+// Format of the header (line breaks added for readability):
+//
+//	AWS4-HMAC-SHA256 Credential=<access-key>/<date>/<region>/<service>/aws4_request,
+//	SignedHeaders=host;range;x-amz-date, Signature=<hex-signature>
+func parseSigV4Header(r *http.Request, auth string) (*SigV4, error) {
+	if !strings.HasPrefix(auth, signV4Algo+" ") {
+		return nil, fmt.Errorf("unsupported authorization type (expecting %q)", signV4Algo)
+	}
+	sig := &SigV4{AmzDate: r.Header.Get(HeaderAmzDate)}
+	if sig.AmzDate == "" {
+		// fall back to 'Date' header
+		tm, err := http.ParseTime(r.Header.Get("Date"))
+		if err != nil {
+			return nil, errors.New("missing request date")
+		}
+		sig.AmzDate = tm.UTC().Format(amzDateFormat)
+	}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, signV4Algo+" "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(field), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid authorization header field %q", field)
+		}
+		switch kv[0] {
+		case "Credential":
+			if err := sig.parseCredential(kv[1]); err != nil {
+				return nil, err
+			}
+		case "SignedHeaders":
+			sig.SignedHeaders = strings.Split(kv[1], ";")
+		case "Signature":
+			sig.Signature = kv[1]
+		}
+	}
+	if err := sig.validate(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+func parseSigV4Query(query url.Values) (*SigV4, error) {
+	if algo := query.Get(queryAlgorithm); algo != signV4Algo {
+		return nil, fmt.Errorf("unsupported signature algorithm %q (expecting %q)", algo, signV4Algo)
+	}
+	sig := &SigV4{
+		AmzDate:   query.Get(queryDate),
+		Signature: query.Get(querySignature),
+		presigned: true,
+	}
+	if err := sig.parseCredential(query.Get(queryCredential)); err != nil {
+		return nil, err
+	}
+	if headers := query.Get(querySignedHeaders); headers != "" {
+		sig.SignedHeaders = strings.Split(headers, ";")
+	}
+	secs, err := strconv.ParseInt(query.Get(queryExpires), 10, 64)
+	if err != nil || secs <= 0 {
+		return nil, fmt.Errorf("invalid %s value %q", queryExpires, query.Get(queryExpires))
+	}
+	sig.expires = time.Duration(secs) * time.Second
+	if sig.expires > maxPresignedAge {
+		return nil, fmt.Errorf("%s must be less than %v", queryExpires, maxPresignedAge)
+	}
+	if err := sig.validate(); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Restores the seed signature of a chunked upload from the query of the
+// request redirected by a proxy. Returns nil if the query does not contain it.
+func SeedFromQuery(query url.Values) (*SigV4, error) {
+	cred := query.Get(URLParamSeedCredential)
+	if cred == "" {
+		return nil, nil
+	}
+	sig := &SigV4{AmzDate: query.Get(URLParamSeedDate), Signature: query.Get(URLParamSeedSignature)}
+	if err := sig.parseCredential(cred); err != nil {
+		return nil, err
+	}
+	if sig.AmzDate == "" || sig.Signature == "" {
+		return nil, errors.New("incomplete seed signature")
+	}
+	return sig, nil
+}
+
+// Credential=<access-key>/<date>/<region>/<service>/aws4_request
+func (sig *SigV4) parseCredential(cred string) error {
+	parts := strings.Split(cred, "/")
+	if len(parts) != 5 || parts[0] == "" || parts[4] != scopeTerminator {
+		return fmt.Errorf("invalid credential %q", cred)
+	}
+	sig.AccessKey, sig.Date, sig.Region, sig.Service = parts[0], parts[1], parts[2], parts[3]
+	return nil
+}
+
+func (sig *SigV4) validate() error {
+	if sig.AccessKey == "" {
+		return errors.New("missing credential")
+	}
+	if sig.Signature == "" {
+		return errors.New("missing signature")
+	}
+	if len(sig.SignedHeaders) == 0 {
+		return errors.New("missing signed headers")
+	}
+	if _, err := time.Parse(amzDateFormat, sig.AmzDate); err != nil {
+		return fmt.Errorf("invalid request date %q", sig.AmzDate)
+	}
+	if !strings.HasPrefix(sig.AmzDate, sig.Date) {
+		return fmt.Errorf("credential date %q does not match request date %q", sig.Date, sig.AmzDate)
+	}
+	return nil
+}
+
+func (sig *SigV4) scope() string {
+	return sig.Date + "/" + sig.Region + "/" + sig.Service + "/" + scopeTerminator
+}
+
+// Returns credential in the format used by 'Authorization' header.
+func (sig *SigV4) Credential() string { return sig.AccessKey + "/" + sig.scope() }
+
+// Returns true if the payload of the request is sent in signed chunks.
+func (sig *SigV4) Streaming(r *http.Request) bool {
+	return !sig.presigned && r.Header.Get(HeaderContentSHA256) == streamingPayload
+}
+
+// Derives the key used to sign requests from the secret access key.
+func (sig *SigV4) SigningKey(secret string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), sig.Date)
+	key = hmacSHA256(key, sig.Region)
+	key = hmacSHA256(key, sig.Service)
+	return hmacSHA256(key, scopeTerminator)
+}
+
+// Returns the value that the signature uses for the payload: either hex-encoded
+// SHA-256 of the payload, or one of "UNSIGNED-PAYLOAD" and "STREAMING-AWS4-HMAC-SHA256-PAYLOAD".
+func (sig *SigV4) PayloadHash(r *http.Request) string {
+	payloadHash := r.Header.Get(HeaderContentSHA256)
+	if payloadHash == "" || sig.presigned {
+		return unsignedPayload
+	}
+	return payloadHash
+}
+
+// Verifies the signature of the request with the secret access key. The
+// payload itself is verified while it is read: by the reader returned by
+// NewChunkedReader (chunked uploads) or NewPayloadReader (all other requests).
+func (sig *SigV4) Verify(r *http.Request, secret string, now time.Time) error {
+	tm, err := time.Parse(amzDateFormat, sig.AmzDate)
+	cmn.AssertNoErr(err) // validated when parsed
+	if payloadHash := sig.PayloadHash(r); payloadHash != unsignedPayload && payloadHash != streamingPayload &&
+		!isSHA256Hex(payloadHash) {
+		return fmt.Errorf("unsupported %s value %q", HeaderContentSHA256, payloadHash)
+	}
+	if sig.presigned {
+		if now.Before(tm.Add(-maxClockSkew)) {
+			return errors.New("request is not valid yet")
+		}
+		if now.After(tm.Add(sig.expires)) {
+			return errors.New("request has expired")
+		}
+	} else if d := now.Sub(tm); d > maxClockSkew || d < -maxClockSkew {
+		return errors.New("the difference between the request time and the server's time is too large")
+	}
+	expected := hmacSHA256(sig.SigningKey(secret), sig.stringToSign(r))
+	if !hmac.Equal([]byte(hex.EncodeToString(expected)), []byte(sig.Signature)) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+func (sig *SigV4) stringToSign(r *http.Request) string {
+	h := sha256.Sum256([]byte(sig.canonicalRequest(r)))
+	return signV4Algo + "\n" + sig.AmzDate + "\n" + sig.scope() + "\n" + hex.EncodeToString(h[:])
+}
+
+func (sig *SigV4) canonicalRequest(r *http.Request) string {
+	var sb strings.Builder
+	sb.WriteString(r.Method)
+	sb.WriteByte('\n')
+	sb.WriteString(uriEncode(r.URL.Path, false /*encodeSlash*/))
+	sb.WriteByte('\n')
+	sb.WriteString(canonicalQuery(r.URL.Query(), sig.presigned))
+	sb.WriteByte('\n')
+	headers := append([]string{}, sig.SignedHeaders...)
+	sort.Strings(headers)
+	for _, name := range headers {
+		sb.WriteString(name)
+		sb.WriteByte(':')
+		sb.WriteString(headerValue(r, name))
+		sb.WriteByte('\n')
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(strings.Join(headers, ";"))
+	sb.WriteByte('\n')
+	sb.WriteString(sig.PayloadHash(r))
+	return sb.String()
+}
+
+func canonicalQuery(query url.Values, presigned bool) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		if presigned && key == querySignature {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// Go server removes a few headers from the request header map,
+// so their values are restored from the request.
+func headerValue(r *http.Request, name string) string {
+	switch name {
+	case "host":
+		return r.Host
+	case "content-length":
+		if v := r.Header.Get(name); v != "" {
+			return v
+		}
+		return strconv.FormatInt(r.ContentLength, 10)
+	case "transfer-encoding":
+		return strings.Join(r.TransferEncoding, ",")
+	}
+	values := r.Header[http.CanonicalHeaderKey(name)]
+	trimmed := make([]string, len(values))
+	for i, v := range values {
+		trimmed[i] = strings.Join(strings.Fields(v), " ")
+	}
+	return strings.Join(trimmed, ",")
+}
+
+// URI-encodes a string as required by AWS: every byte except
+// the unreserved characters ('A'-'Z', 'a'-'z', '0'-'9', '-', '.', '_', '~')
+// is percent-encoded; '/' is kept as is unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	const hexUpper = "0123456789ABCDEF"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '.' || c == '_' || c == '~' || (c == '/' && !encodeSlash) {
+			sb.WriteByte(c)
+			continue
+		}
+		sb.WriteByte('%')
+		sb.WriteByte(hexUpper[c>>4])
+		sb.WriteByte(hexUpper[c&0xf])
+	}
+	return sb.String()
+}
+
+func isSHA256Hex(s string) bool {
+	if len(s) != 2*sha256.Size {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// Returns the size of the decoded payload of a chunked upload.
+func DecodedSize(r *http.Request) (int64, error) {
+	size, err := strconv.ParseInt(r.Header.Get(HeaderDecodedSize), 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("invalid %s value %q", HeaderDecodedSize, r.Header.Get(HeaderDecodedSize))
+	}
+	return size, nil
+}
+
+// Returns true if the payload of the request is encoded with 'aws-chunked'.
+func IsChunked(r *http.Request) bool {
+	if r.Header.Get(HeaderContentSHA256) == streamingPayload {
+		return true
+	}
+	for _, enc := range strings.Split(r.Header.Get(HeaderContentEncoding), ",") {
+		if strings.TrimSpace(enc) == contentEncodingChunked {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the reader that decodes 'aws-chunked' payload. If the seed
+// signature and the signing key are defined, the reader verifies the
+// signature of each chunk and fails the read on mismatch.
+func NewChunkedReader(body io.ReadCloser, seed *SigV4, key []byte) io.ReadCloser {
+	cr := &chunkedReader{r: bufio.NewReader(body), body: body, sig: seed, key: key, h: sha256.New()}
+	if seed != nil {
+		cr.prevSig = seed.Signature
+	}
+	return cr
+}
+
+func (cr *chunkedReader) Read(b []byte) (n int, err error) {
+	if cr.err != nil {
+		return 0, cr.err
+	}
+	if cr.remaining == 0 {
+		if cr.err = cr.nextChunk(); cr.err != nil {
+			return 0, cr.err
+		}
+	}
+	if int64(len(b)) > cr.remaining {
+		b = b[:cr.remaining]
+	}
+	n, err = cr.r.Read(b)
+	cr.h.Write(b[:n])
+	cr.remaining -= int64(n)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && cr.remaining == 0 {
+		err = cr.endChunk()
+	}
+	cr.err = err
+	return n, err
+}
+
+func (cr *chunkedReader) Close() error { return cr.body.Close() }
+
+// Chunk header: <hex-size>;chunk-signature=<signature>\r\n
+func (cr *chunkedReader) nextChunk() error {
+	line, err := cr.r.ReadSlice('\n')
+	if err != nil {
+		if err == io.EOF || err == bufio.ErrBufferFull {
+			return errors.New("invalid chunk header")
+		}
+		return err
+	}
+	if len(line) > maxChunkHdrLen {
+		return errors.New("chunk header is too long")
+	}
+	line = bytes.TrimRight(line, "\r\n")
+	parts := strings.SplitN(string(line), ";", 2)
+	size, err := strconv.ParseInt(parts[0], 16, 64)
+	if err != nil || size < 0 {
+		return fmt.Errorf("invalid chunk size %q", parts[0])
+	}
+	cr.chunkSig = ""
+	if len(parts) == 2 {
+		cr.chunkSig = strings.TrimPrefix(parts[1], "chunk-signature=")
+	}
+	cr.h.Reset()
+	cr.remaining = size
+	if size > 0 {
+		return nil
+	}
+	// the last (zero-size) chunk
+	if err := cr.verifyChunk(); err != nil {
+		return err
+	}
+	if err := cr.readCRLF(); err != nil {
+		return err
+	}
+	return io.EOF
+}
+
+func (cr *chunkedReader) endChunk() error {
+	if err := cr.readCRLF(); err != nil {
+		return err
+	}
+	return cr.verifyChunk()
+}
+
+func (cr *chunkedReader) readCRLF() error {
+	var crlf [2]byte
+	if _, err := io.ReadFull(cr.r, crlf[:]); err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	if crlf[0] != '\r' || crlf[1] != '\n' {
+		return errors.New("malformed chunk: missing CRLF")
+	}
+	return nil
+}
+
+func (cr *chunkedReader) verifyChunk() error {
+	if cr.key == nil {
+		return nil
+	}
+	stringToSign := chunkSignV4Algo + "\n" + cr.sig.AmzDate + "\n" + cr.sig.scope() + "\n" +
+		cr.prevSig + "\n" + emptySHA256 + "\n" + hex.EncodeToString(cr.h.Sum(nil))
+	expected := hex.EncodeToString(hmacSHA256(cr.key, stringToSign))
+	if !hmac.Equal([]byte(expected), []byte(cr.chunkSig)) {
+		return ErrSignatureInvalid
+	}
+	cr.prevSig = cr.chunkSig
+	return nil
+}
+
+// Authenticates the payload hash that proxy passes to target in the redirect
+// URL of the request, so that it cannot be replaced by a client.
+func PayloadMAC(secret, method, path, payloadHash string) string {
+	return hex.EncodeToString(hmacSHA256([]byte(secret), method+"\n"+path+"\n"+payloadHash))
+}
+
+// Returns the reader that verifies the payload against its signed SHA-256
+// (see SigV4.PayloadHash) and fails the final read on mismatch. The body is
+// returned as is if the payload is not signed.
+func NewPayloadReader(body io.ReadCloser, payloadHash string) io.ReadCloser {
+	if payloadHash == unsignedPayload {
+		return body
+	}
+	return &payloadReader{body: body, h: sha256.New(), expected: payloadHash}
+}
+
+func (pr *payloadReader) Read(b []byte) (n int, err error) {
+	n, err = pr.body.Read(b)
+	pr.h.Write(b[:n])
+	if err == io.EOF && hex.EncodeToString(pr.h.Sum(nil)) != pr.expected {
+		err = ErrPayloadInvalid
+	}
+	return
+}
+
+func (pr *payloadReader) Close() error { return pr.body.Close() }
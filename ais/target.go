@@ -23,12 +23,16 @@ import (
 	"github.com/NVIDIA/aistore/ais/cloud"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/dsort"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/housekeep/hk"
+	"github.com/NVIDIA/aistore/housekeep/scrub"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/replication"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/NVIDIA/aistore/xaction"
@@ -69,6 +73,8 @@ type (
 	}
 	clouds struct {
 		ais *cloud.AisCloudProvider
+		ht  cluster.CloudProvider
+		fs  cluster.CloudProvider
 		ext cluster.CloudProvider
 	}
 	// main
@@ -246,10 +252,22 @@ func (t *targetrunner) Run() error {
 		revokedTokens: make(map[string]bool),
 		version:       1,
 	}
+	t.authn.loadAccessKeys(config)
 
 	// transactions
 	t.transactions.init(t)
 
+	// lifecycle rules
+	hk.Housekeeper.Register("lifecycle", t.lifecycleHK, hk.DayInterval)
+
+	// periodic data scrubbing
+	hk.Housekeeper.Register("scrub", t.scrubHK, hkStartupInterval)
+
+	// cross-cluster bucket replication
+	if err := replication.Init(t, t.cloud.ais, getstorstatsrunner(), config.Confdir); err != nil {
+		glog.Errorf("%s: failed to load replication queue: %v", t.si, err)
+	}
+
 	//
 	// REST API: register storage target's handler(s) and start listening
 	//
@@ -264,6 +282,8 @@ func (t *targetrunner) Run() error {
 
 	t.rebManager = reb.NewManager(t, config, getstorstatsrunner())
 	ec.Init(t, xaction.Registry)
+	downloader.InitSync(t, t.statsT, xaction.Registry)
+	xaction.Registry.InitHistory(config.Confdir)
 
 	aborted, _ := reb.IsRebalancing(cmn.ActResilver)
 	if aborted {
@@ -272,6 +292,15 @@ func (t *targetrunner) Run() error {
 			t.rebManager.RunResilver("", false /*skipGlobMisplaced*/)
 		}()
 	}
+	if scrub.MarkerExists() {
+		go func() {
+			for !t.ClusterStarted() {
+				time.Sleep(time.Second)
+			}
+			glog.Infoln("resuming scrub...")
+			t.runScrub("")
+		}()
+	}
 
 	dsort.RegisterNode(t.owner.smap, t.owner.bmd, t.si, t.gmm, t, t.statsT)
 	if err := t.httprunner.run(); err != nil {
@@ -288,6 +317,9 @@ func (c *clouds) init(t *targetrunner, config *cmn.Config) {
 			glog.Errorf("%s: %v - proceeding to start anyway...", t.si, err)
 		}
 	}
+	// ht:// (HTTP origins) and fs:// (shared directories) always enabled as well
+	c.ht = cloud.NewHTTP(t, config)
+	c.fs = cloud.NewFS(t)
 	// 3rd part cloud: empty stubs unless populated via build tags
 	var (
 		err error
@@ -299,6 +331,8 @@ func (c *clouds) init(t *targetrunner, config *cmn.Config) {
 		c.ext, err = cloud.NewGCP(t)
 	case cmn.ProviderAzure:
 		c.ext, err = cloud.NewAzure(t)
+	case cmn.ProviderHDFS:
+		c.ext, err = cloud.NewHDFS(t, config)
 	case "":
 		c.ext, err = cloud.NewDummyCloud()
 	default:
@@ -340,6 +374,9 @@ func (t *targetrunner) initRecvHandlers() {
 	if cmn.GCO.Get().Net.UseIntraData {
 		t.registerIntraDataNetHandler("/s3", t.s3Handler)
 	}
+
+	// Prometheus
+	t.registerPublicNetHandler("/metrics", t.metricsHandler)
 }
 
 // target-only stats
@@ -366,6 +403,13 @@ func (t *targetrunner) registerStats() {
 	// download
 	t.statsT.Register(stats.DownloadSize, stats.KindCounter)
 	t.statsT.Register(stats.DownloadLatency, stats.KindLatency)
+	// cross-cluster replication
+	t.statsT.Register(stats.ReplCount, stats.KindCounter)
+	t.statsT.Register(stats.ReplSize, stats.KindCounter)
+	t.statsT.Register(stats.ErrReplCount, stats.KindCounter)
+	t.statsT.Register(stats.ReplBacklog, stats.KindSpecial)
+	t.statsT.Register(stats.ReplLag, stats.KindSpecial)
+
 	// dsort
 	t.statsT.Register(stats.DSortCreationReqCount, stats.KindCounter)
 	t.statsT.Register(stats.DSortCreationReqLatency, stats.KindLatency)
@@ -377,6 +421,7 @@ func (t *targetrunner) registerStats() {
 func (t *targetrunner) Stop(err error) {
 	glog.Infof("Stopping %s, err: %v", t.GetRunName(), err)
 	xaction.Registry.AbortAll()
+	replication.Stop()
 	if t.publicServer.s != nil {
 		t.unregister() // ignore errors
 	}
@@ -663,6 +708,24 @@ func (t *targetrunner) httpobjput(w http.ResponseWriter, r *http.Request) {
 	}
 	lom.SetAtimeUnix(started.UnixNano())
 	if appendTy == "" {
+		customMD, err := cmn.CustomMDFromHeader(r.Header, cmn.HeaderObjCustomMD)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		lom.SetCustomMD(customMD)
+		ret, err := retentionFromHeader(r.Header)
+		if err == nil {
+			err = setRetention(lom, ret)
+		}
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := t.checkRetention(lom, cmn.IsParseBool(r.Header.Get(cmn.HeaderBypassRetention))); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
 		if err, errCode := t.doPut(r, lom, started); err != nil {
 			t.fshc(err, lom.FQN)
 			t.invalmsghdlr(w, r, err.Error(), errCode)
@@ -750,6 +813,7 @@ func (t *targetrunner) httpbckdelete(w http.ResponseWriter, r *http.Request) {
 			t.invalmsghdlr(w, r, err.Error())
 			return
 		}
+		xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
 
 		go xact.Run(args)
 	default:
@@ -786,7 +850,8 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.objDelete(t.contextWithAuth(r.Header), lom, evict)
+	bypass := cmn.IsParseBool(r.Header.Get(cmn.HeaderBypassRetention))
+	err, errCode := t.objDelete(t.contextWithAuth(r.Header), lom, evict, bypass)
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -798,8 +863,6 @@ func (t *targetrunner) httpobjdelete(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	// EC cleanup if EC is enabled
-	ec.ECM.CleanupObject(lom)
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("DELETE: %s, %d µs", lom, int64(time.Since(started)/time.Microsecond))
 	}
@@ -886,6 +949,7 @@ func (t *targetrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 			t.invalmsghdlr(w, r, err.Error())
 			return
 		}
+		xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
 
 		go xact.Run(args)
 	case cmn.ActListObjects:
@@ -914,7 +978,7 @@ func (t *targetrunner) httpbckpost(w http.ResponseWriter, r *http.Request) {
 		case cmn.ActAbort:
 			break // nothing to do
 		case cmn.ActCommit:
-			err = t.commitECEncode(bck)
+			err = t.commitECEncode(bck, t.xactInitiator(r))
 		default:
 			cmn.Assert(false)
 		}
@@ -940,6 +1004,8 @@ func (t *targetrunner) httpobjpost(w http.ResponseWriter, r *http.Request) {
 		t.renameObject(w, r, &msg)
 	case cmn.ActPromote:
 		t.promoteFQN(w, r, &msg)
+	case cmn.ActObjRetention:
+		t.objRetention(w, r, &msg)
 	default:
 		s := fmt.Sprintf(fmtUnknownAct, msg)
 		t.invalmsghdlr(w, r, s)
@@ -1128,6 +1194,8 @@ func (t *targetrunner) httpobjhead(w http.ResponseWriter, r *http.Request) {
 			objProps.Checksum.Value = cksum.Value()
 		}
 		objProps.NumCopies = lom.NumCopies()
+		objProps.Retention = retentionProps(lom)
+		cmn.CustomMDToHeader(lom.CustomMD(), hdr, cmn.HeaderObjCustomMD)
 		if lom.Bck().Props.EC.Enabled {
 			if md, err := ec.ObjectMetadata(lom.Bck(), objName); err == nil {
 				hdr.Set(cmn.HeaderObjECMeta, ec.MetaToString(md))
@@ -1329,7 +1397,7 @@ func (t *targetrunner) putMirror(lom *cluster.LOM) {
 	}
 }
 
-func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bool) (error, int) {
+func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict, bypass bool) (error, int) {
 	var (
 		cloudErr     error
 		cloudErrCode int
@@ -1347,6 +1415,11 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 	} else if !delFromCloud && cmn.IsObjNotExist(err) {
 		return err, http.StatusNotFound
 	}
+	if delFromAIS {
+		if err := lom.AllowModify(bypass); err != nil {
+			return err, http.StatusForbidden
+		}
+	}
 
 	if delFromCloud {
 		if err, errCode := t.Cloud(lom.Bck()).DeleteObj(ctx, lom); err != nil {
@@ -1371,11 +1444,17 @@ func (t *targetrunner) objDelete(ctx context.Context, lom *cluster.LOM, evict bo
 				stats.NamedVal64{Name: stats.LruEvictCount, Value: 1},
 				stats.NamedVal64{Name: stats.LruEvictSize, Value: lom.Size()},
 			)
+		} else {
+			replication.Delete(lom)
 		}
 	}
 	if cloudErr != nil {
 		return fmt.Errorf("failed to delete from cloud: %w", cloudErr), cloudErrCode
 	}
+	if errRet == nil {
+		// EC cleanup if EC is enabled
+		ec.ECM.CleanupObject(lom)
+	}
 	return errRet, 0
 }
 
@@ -1407,6 +1486,18 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 		t.invalmsghdlr(w, r, fmt.Sprintf("%s: cannot rename erasure-coded object", lom))
 		return
 	}
+	if err = t.checkRetention(lom, false /*bypass*/); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	lomTo := &cluster.LOM{T: t, ObjName: msg.Name}
+	if err = lomTo.Init(bck.Bck); err == nil {
+		err = t.checkRetention(lomTo, false /*bypass*/)
+	}
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 
 	buf, slab := t.gmm.Alloc()
 	ri := &replicInfo{smap: t.owner.smap.get(), t: t, bckTo: lom.Bck(), buf: buf, localOnly: false, finalize: true}
@@ -1416,12 +1507,21 @@ func (t *targetrunner) renameObject(w http.ResponseWriter, r *http.Request, msg
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	if copied {
-		lom.Lock(true)
-		if err = lom.Remove(); err != nil {
-			t.invalmsghdlr(w, r, err.Error())
-		}
-		lom.Unlock(true)
+	if !copied {
+		return
+	}
+	lom.Lock(true)
+	err = lom.Remove()
+	lom.Unlock(true)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	// replication: the object copied to another target gets replicated by the latter (as a PUT)
+	if si, err := cluster.HrwTarget(lom.Bck().MakeUname(msg.Name), &ri.smap.Smap); err == nil && si.ID() != t.si.ID() {
+		replication.Delete(lom)
+	} else {
+		replication.Rename(lom, msg.Name)
 	}
 }
 
@@ -1486,6 +1586,7 @@ func (t *targetrunner) promoteFQN(w http.ResponseWriter, r *http.Request, msg *c
 			t.invalmsghdlr(w, r, err.Error())
 			return
 		}
+		xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
 		go xact.Run()
 		return
 	}
@@ -42,7 +42,7 @@ func Test_BucketNames(t *testing.T) {
 
 	printBucketNames(buckets)
 
-	for _, provider := range []string{cmn.ProviderAmazon, cmn.ProviderGoogle, cmn.ProviderAzure} {
+	for _, provider := range []string{cmn.ProviderAmazon, cmn.ProviderGoogle, cmn.ProviderAzure, cmn.ProviderHDFS} {
 		query := cmn.QueryBcks{Provider: provider}
 		cloudBuckets, err := api.ListBuckets(baseParams, query)
 		tassert.CheckError(t, err)
@@ -52,6 +52,7 @@ type ecOptions struct {
 	objCount    int
 	dataCnt     int
 	parityCnt   int
+	localCnt    int
 	minTgt      int
 	pattern     string
 	sema        *cmn.DynSemaphore
@@ -81,7 +82,7 @@ func (o ecOptions) init(t *testing.T, proxyURL string) *ecOptions {
 	return &o
 }
 func (o *ecOptions) sliceTotal() int {
-	return o.dataCnt + o.parityCnt
+	return o.dataCnt + o.parityCnt + o.localCnt
 }
 
 type ecTest struct {
@@ -103,6 +104,7 @@ func defaultECBckProps(o *ecOptions) cmn.BucketPropsToUpdate {
 			ObjSizeLimit: api.Int64(ecObjLimit),
 			DataSlices:   api.Int(o.dataCnt),
 			ParitySlices: api.Int(o.parityCnt),
+			LocalGroups:  api.Int(o.localCnt),
 		},
 	}
 }
@@ -610,8 +612,8 @@ func damageMetadataCksum(t *testing.T, slicePath string) {
 	tassert.CheckFatal(t, err)
 }
 
-// Short test to make sure that EC options cannot be changed after
-// EC is enabled
+// Short test to make sure that EC options are validated and can be changed
+// after EC is enabled
 func TestECChange(t *testing.T) {
 	var (
 		proxyURL = tutils.RandomProxyURL()
@@ -668,13 +670,73 @@ func TestECChange(t *testing.T) {
 	bucketProps.EC.Enabled = api.Bool(true)
 	bucketProps.EC.ObjSizeLimit = api.Int64(300000)
 	err = api.SetBucketProps(baseParams, bck, bucketProps)
-	tassert.Errorf(t, err != nil, "Modifiying EC properties must fail")
+	tassert.Errorf(t, err == nil, "Modifying EC properties failed: %v", err)
 
 	tutils.Logln("Resetting bucket properties")
 	err = api.ResetBucketProps(baseParams, bck)
 	tassert.Errorf(t, err == nil, "Resetting properties should work")
 }
 
+// Changes the number of data and parity slices of the bucket with EC'ed objects
+// and checks that all objects get re-encoded
+func TestECReencode(t *testing.T) {
+	tutils.CheckSkip(t, tutils.SkipTestArgs{Long: true})
+
+	var (
+		bck = cmn.Bck{
+			Name:     TestBucketName + "-ec-reencode",
+			Provider: cmn.ProviderAIS,
+		}
+		proxyURL   = tutils.RandomProxyURL()
+		baseParams = tutils.BaseAPIParams(proxyURL)
+	)
+
+	o := ecOptions{
+		minTgt:      4,
+		dataCnt:     1,
+		parityCnt:   1,
+		objCount:    20,
+		objSize:     ecMinBigSize,
+		concurrency: 8,
+		pattern:     "obj-reencode-%04d",
+		silent:      true,
+	}.init(t, proxyURL)
+
+	newLocalBckWithProps(t, baseParams, bck, defaultECBckProps(o), o)
+	defer tutils.DestroyBucket(t, proxyURL, bck)
+
+	wg := sync.WaitGroup{}
+	wg.Add(o.objCount)
+	for i := 0; i < o.objCount; i++ {
+		objName := fmt.Sprintf(o.pattern, i)
+		go func(i int) {
+			defer wg.Done()
+			createECObject(t, baseParams, bck, objName, i, o)
+		}(i)
+	}
+	wg.Wait()
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	tutils.Logln("Changing EC from 1:1 to 2:2")
+	o.dataCnt, o.parityCnt = 2, 2
+	setBucketECProps(t, baseParams, bck, defaultECBckProps(o))
+
+	xactArgs := api.XactReqArgs{Kind: cmn.ActECReencode, Bck: bck, Timeout: rebalanceTimeout}
+	err := api.WaitForXaction(baseParams, xactArgs)
+	tassert.CheckFatal(t, err)
+
+	totalCnt, objSize, sliceSize, doEC := randObjectSize(0, 0, o)
+	for i := 0; i < o.objCount; i++ {
+		objName := fmt.Sprintf(o.pattern, i)
+		foundParts, mainObjPath := waitForECFinishes(t, totalCnt, objSize, sliceSize, doEC, bck, objName)
+		ecCheckSlices(t, foundParts, bck, ecTestDir+objName, objSize, sliceSize, totalCnt)
+		tassert.Errorf(t, mainObjPath != "", "Full copy of %s is not found", objName)
+	}
+	assertBucketSize(t, baseParams, bck, o.objCount)
+}
+
 func createECReplicas(t *testing.T, baseParams api.BaseParams, bck cmn.Bck, objName string, o *ecOptions) {
 	o.sema.Acquire()
 	defer o.sema.Release()
@@ -943,6 +1005,43 @@ func TestECRestoreObjAndSlice(t *testing.T) {
 	}
 }
 
+// Same as TestECRestoreObjAndSlice but with local parity groups: the slices
+// include the local parity ones that must be restored as well
+func TestECLocalParity(t *testing.T) {
+	var (
+		bck = cmn.Bck{
+			Name:     TestBucketName + "-ec-local",
+			Provider: cmn.ProviderAIS,
+		}
+		proxyURL   = tutils.RandomProxyURL()
+		baseParams = tutils.BaseAPIParams(proxyURL)
+	)
+
+	o := ecOptions{
+		minTgt:      5,
+		dataCnt:     2,
+		parityCnt:   1,
+		localCnt:    2,
+		objCount:    50,
+		concurrency: 8,
+		pattern:     "obj-local-%04d",
+	}.init(t, proxyURL)
+
+	newLocalBckWithProps(t, baseParams, bck, defaultECBckProps(o), o)
+	defer tutils.DestroyBucket(t, proxyURL, bck)
+
+	wg := sync.WaitGroup{}
+	wg.Add(o.objCount)
+	for i := 0; i < o.objCount; i++ {
+		go func(objName string, i int) {
+			defer wg.Done()
+			createDamageRestoreECFile(t, baseParams, bck, objName, i, o)
+		}(fmt.Sprintf(o.pattern, i), i)
+	}
+	wg.Wait()
+	assertBucketSize(t, baseParams, bck, o.objCount)
+}
+
 func putECFile(baseParams api.BaseParams, bck cmn.Bck, objName string) error {
 	objSize := int64(ecMinBigSize * 2)
 	objPath := ecTestDir + objName
@@ -150,6 +150,7 @@ func (ri *replicInfo) putRemote(lom *cluster.LOM, objNameTo string, si *cluster.
 	req.Header.Set(cmn.HeaderObjCksumVal, cksumValue)
 	req.Header.Set(cmn.HeaderObjVersion, lom.Version())
 	req.Header.Set(cmn.HeaderObjAtime, cmn.UnixNano2S(lom.AtimeUnix()))
+	cmn.CustomMDToHeader(lom.CustomMD(), req.Header, cmn.HeaderObjCustomMD)
 
 	resp, err1 := ri.t.httpclientGetPut.Do(req)
 	if err1 != nil {
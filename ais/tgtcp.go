@@ -657,8 +657,10 @@ func (t *targetrunner) _recvBMD(newBMD *bucketMD, msg *aisMsg, tag, caller strin
 	bmd = t.owner.bmd.get()
 	curVer = bmd.version()
 	var (
-		bcksToDelete = make([]*cluster.Bck, 0, 4)
-		_, psi       = t.getPrimaryURLAndSI()
+		bcksToDelete   = make([]*cluster.Bck, 0, 4)
+		bcksToReencode = make([]*cluster.Bck, 0, 4)
+		bcksToBackfill = make([]*cluster.Bck, 0, 4)
+		_, psi         = t.getPrimaryURLAndSI()
 	)
 	if err = bmd.validateUUID(newBMD, t.si, psi, ""); err != nil {
 		t.owner.bmd.Unlock()
@@ -711,6 +713,14 @@ func (t *targetrunner) _recvBMD(newBMD *bucketMD, msg *aisMsg, tag, caller strin
 			}
 			if obck.Props.EC.Enabled && !nbck.Props.EC.Enabled {
 				xaction.Registry.DoAbort(cmn.ActECEncode, nbck)
+				xaction.Registry.DoAbort(cmn.ActECReencode, nbck)
+			} else if obck.Props.EC.Enabled && ecLayoutChanged(&obck.Props.EC, &nbck.Props.EC) {
+				bcksToReencode = append(bcksToReencode, nbck)
+			}
+			if obck.Props.Replication.Enabled && !nbck.Props.Replication.Enabled {
+				xaction.Registry.DoAbort(cmn.ActReplBackfill, nbck)
+			} else if replDstChanged(&obck.Props.Replication, &nbck.Props.Replication) {
+				bcksToBackfill = append(bcksToBackfill, nbck)
 			}
 			return true
 		})
@@ -744,10 +754,36 @@ func (t *targetrunner) _recvBMD(newBMD *bucketMD, msg *aisMsg, tag, caller strin
 		// ecmanager will get updated BMD upon its init()
 		ec.ECM.BucketsMDChanged()
 	}
+	for _, bck := range bcksToReencode {
+		// restart the running one, if any, to revisit the objects it has already passed
+		xaction.Registry.DoAbort(cmn.ActECReencode, bck)
+		if xact := xaction.Registry.RenewECReencode(t, bck); xact != nil {
+			go xact.Run()
+		}
+	}
+	for _, bck := range bcksToBackfill {
+		xaction.Registry.DoAbort(cmn.ActReplBackfill, bck)
+		if xact := xaction.Registry.RenewReplBackfill(t, bck); xact != nil {
+			go xact.Run()
+		}
+	}
 
 	return
 }
 
+// ecLayoutChanged returns true if the erasure coded objects must be re-encoded
+// to conform to the new EC configuration of the bucket.
+func ecLayoutChanged(oconf, nconf *cmn.ECConf) bool {
+	return oconf.DataSlices != nconf.DataSlices || oconf.ParitySlices != nconf.ParitySlices ||
+		oconf.LocalGroups != nconf.LocalGroups || oconf.ObjSizeLimit != nconf.ObjSizeLimit
+}
+
+// replDstChanged returns true if the existing objects of the bucket must be
+// replicated: replication has been enabled or its destination has changed.
+func replDstChanged(oconf, nconf *cmn.BckReplicationConf) bool {
+	return nconf.Enabled && (!oconf.Enabled || oconf.Cluster != nconf.Cluster || oconf.Bucket != nconf.Bucket)
+}
+
 func (t *targetrunner) receiveSmap(newSmap *smapX, msg *aisMsg, caller string) (err error) {
 	var (
 		s, from string
@@ -1049,6 +1085,13 @@ func (t *targetrunner) metasyncHandlerPut(w http.ResponseWriter, r *http.Request
 		t.authn.updateRevokedList(revokedTokens)
 	}
 
+	accessKeys, err := t.extractAccessKeyList(payload)
+	if err != nil {
+		errs = append(errs, err)
+	} else {
+		t.authn.updateAccessKeys(accessKeys)
+	}
+
 	if len(errs) > 0 {
 		msg := fmt.Sprintf("%v", errs)
 		t.invalmsghdlr(w, r, msg)
@@ -1075,6 +1118,23 @@ func (t *targetrunner) metasyncHandlerPost(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// GET /metrics (Prometheus)
+func (t *targetrunner) metricsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		cmn.InvalidHandlerWithMsg(w, r, "invalid method for /metrics path")
+		return
+	}
+	xacts, err := xaction.Registry.GetStats(xaction.XactQuery{})
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", stats.PromContentType)
+	if err := getstorstatsrunner().WriteProm(w, xacts); err != nil {
+		glog.Errorf("%s: failed to write metrics: %v", t.si, err)
+	}
+}
+
 // GET /v1/health (cmn.Health)
 // TODO: clusterInfo - see p.healthHandler
 func (t *targetrunner) healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -1198,13 +1258,14 @@ func (t *targetrunner) beginECEncode(bck *cluster.Bck) (err error) {
 	return err
 }
 
-func (t *targetrunner) commitECEncode(bckFrom *cluster.Bck) (err error) {
+func (t *targetrunner) commitECEncode(bckFrom *cluster.Bck, initiator string) (err error) {
 	var xact *ec.XactBckEncode
 	xact, err = xaction.Registry.RenewECEncodeXact(t, bckFrom, cmn.ActCommit)
 	if err != nil {
 		glog.Error(err) // must not happen at commit time
 		return err
 	}
+	xaction.Registry.SetInitiator(xact, initiator)
 	go xact.Run()
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"os"
@@ -43,6 +44,12 @@ func (t *targetrunner) Cloud(bck *cluster.Bck) cluster.CloudProvider {
 	if bck.Bck.IsRemoteAIS() {
 		return t.cloud.ais
 	}
+	switch bck.Bck.Provider {
+	case cmn.ProviderHTTP:
+		return t.cloud.ht
+	case cmn.ProviderFS:
+		return t.cloud.fs
+	}
 	if bck.Props != nil {
 		if t.cloud.ext.Provider() == bck.CloudBck().Provider {
 			return t.cloud.ext
@@ -122,6 +129,18 @@ func (t *targetrunner) RunLRU(id string) {
 	xlru.EndTime(time.Now())
 }
 
+func (t *targetrunner) runScrub(id string) {
+	if t.RebalanceInfo().IsRebalancing {
+		glog.Infoln("Warning: rebalancing (local or global) is in progress, skipping scrub run")
+		return
+	}
+	xscrub := xaction.Registry.RenewScrub(id, t)
+	if xscrub == nil {
+		return
+	}
+	xscrub.Run() // blocking
+}
+
 // slight variation vs t.httpobjget()
 func (t *targetrunner) GetObject(w io.Writer, lom *cluster.LOM, started time.Time) error {
 	goi := &getObjInfo{
@@ -162,6 +181,36 @@ func (t *targetrunner) PutObject(params cluster.PutObjectParams) error {
 	return err
 }
 
+// PutObjectToTarget sends the content of the object to the given target which
+// stores it as any other PUT (ie., with its copies, EC slices, etc.)
+func (t *targetrunner) PutObjectToTarget(lom *cluster.LOM, r io.Reader, size int64, si *cluster.Snode) error {
+	query := cmn.AddBckToQuery(nil, lom.Bck().Bck)
+	query.Set(cmn.URLParamProxyID, t.owner.smap.get().ProxySI.ID())
+	args := cmn.ReqArgs{
+		Method: http.MethodPut,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+		Query:  query,
+		BodyR:  r,
+	}
+	req, _, cancel, err := args.ReqWithTimeout(lom.Config().Timeout.SendFile)
+	if err != nil {
+		return cmn.NewFailedToCreateHTTPRequest(err)
+	}
+	defer cancel()
+	req.ContentLength = size
+	resp, err := t.httpclientGetPut.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to PUT %s to %s, err: %v", lom, si, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("failed to PUT %s to %s, status %d: %s", lom, si, resp.StatusCode, string(b))
+	}
+	return nil
+}
+
 func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []byte, localOnly bool) (copied bool, err error) {
 	ri := &replicInfo{smap: t.owner.smap.get(),
 		bckTo:     bckTo,
@@ -175,6 +224,12 @@ func (t *targetrunner) CopyObject(lom *cluster.LOM, bckTo *cluster.Bck, buf []by
 	return
 }
 
+// DeleteObject deletes (or evicts) the object along with its EC slices and
+// replicas; the object's retention is enforced
+func (t *targetrunner) DeleteObject(ctx context.Context, lom *cluster.LOM, evict bool) (error, int) {
+	return t.objDelete(ctx, lom, evict, false /*bypass*/)
+}
+
 // FIXME: recomputes checksum if called with a bad one (optimize)
 func (t *targetrunner) GetCold(ctx context.Context, lom *cluster.LOM, prefetch bool) (err error, errCode int) {
 	if prefetch {
@@ -20,8 +20,10 @@ import (
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/housekeep/scrub"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/reb"
+	"github.com/NVIDIA/aistore/replication"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -152,6 +154,9 @@ func (poi *putObjInfo) finalize() (err error, errCode int) {
 	}
 
 	poi.t.putMirror(poi.lom)
+	if !poi.migrated {
+		replication.Put(poi.lom)
+	}
 	return
 }
 
@@ -192,6 +197,9 @@ func (poi *putObjInfo) tryFinalize() (err error, errCode int) {
 	lom.Lock(true)
 	defer lom.Unlock(true)
 
+	if !poi.migrated {
+		lom.SetCtimeUnix(time.Now().UnixNano())
+	}
 	if poi.version != "" {
 		// TODO: currently we set the version to opaque string. It can possibly
 		//  break the default incrementation if the opaque string is not an
@@ -363,8 +371,8 @@ write:
 
 func (goi *getObjInfo) getObject() (err error, errCode int) {
 	var (
-		doubleCheck, retry, retried, coldGet, capRead bool
-		capInfo                                       cmn.CapacityInfo
+		doubleCheck, retry, retried, coldGet, capRead, repaired bool
+		capInfo                                                 cmn.CapacityInfo
 	)
 	// under lock: lom init, restore from cluster
 	goi.lom.Lock(false)
@@ -432,12 +440,23 @@ do:
 			glog.Error(err)
 			if _, ok := err.(*cmn.BadCksumError); ok {
 				if goi.lom.Bck().IsAIS() {
-					// TODO: recover from copies or EC if available (scruber).
-					// Removing object and copies at this point seems too harsh.
+					goi.lom.Unlock(false)
+					// try to recover from local copies or EC (and retry once)
+					if !repaired {
+						repaired = true
+						source, errRepair := scrub.RepairObject(goi.t, goi.lom)
+						if errRepair == nil {
+							glog.Infof("%s: restored from %s", goi.lom, source)
+							goi.lom.Lock(false)
+							goto do
+						}
+						glog.Error(errRepair)
+					}
+					goi.lom.Lock(true)
 					if err := goi.lom.Remove(); err != nil {
 						glog.Warningf("%s - failed to remove, err: %v", err, err)
 					}
-					goi.lom.Unlock(false)
+					goi.lom.Unlock(true)
 					return err, http.StatusInternalServerError
 				}
 				coldGet = true
@@ -595,6 +614,11 @@ func (goi *getObjInfo) getFromNeighbor(lom *cluster.LOM, tsi *cluster.Snode) (ok
 		return
 	}
 	lom.SetAtimeUnix(atime)
+	if ctime := resp.Header.Get(cmn.HeaderObjCtime); ctime != "" {
+		if tu, err := cmn.S2UnixNano(ctime); err == nil {
+			lom.SetCtimeUnix(tu)
+		}
+	}
 	lom.SetCksum(cksum)
 	poi := &putObjInfo{
 		t:        goi.t,
@@ -649,6 +673,10 @@ func (goi *getObjInfo) finalize(coldGet bool) (retry bool, err error, errCode in
 		}
 		hdr.Set(cmn.HeaderObjSize, strconv.FormatInt(goi.lom.Size(), 10))
 		hdr.Set(cmn.HeaderObjAtime, cmn.UnixNano2S(goi.lom.AtimeUnix()))
+		if ctime := goi.lom.CtimeUnix(); ctime != 0 {
+			hdr.Set(cmn.HeaderObjCtime, cmn.UnixNano2S(ctime))
+		}
+		cmn.CustomMDToHeader(goi.lom.CustomMD(), hdr, cmn.HeaderObjCustomMD)
 	}
 
 	// loopback if disk IO is disabled
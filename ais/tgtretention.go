@@ -0,0 +1,161 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+// Object retention (WORM): an object in a bucket with enabled retention cannot be
+// overwritten, deleted, renamed, or evicted until its retain-until time passes,
+// and while it is under legal hold. Governance-mode retention can be bypassed,
+// and legal hold can be placed or removed, only by admin (enforced by proxy).
+
+// Checks whether an existing object (if any) can be overwritten or removed.
+func (t *targetrunner) checkRetention(lom *cluster.LOM, bypass bool) error {
+	if !lom.RetentionConf().Enabled {
+		return nil
+	}
+	existing := &cluster.LOM{T: t, ObjName: lom.ObjName}
+	if err := existing.Init(lom.Bck().Bck); err != nil {
+		return err
+	}
+	existing.Lock(false)
+	err := existing.Load(false)
+	existing.Unlock(false)
+	if err != nil {
+		if cmn.IsObjNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return existing.AllowModify(bypass)
+}
+
+// Parses the retention requested for a new object via native API.
+func retentionFromHeader(header http.Header) (ret cmn.ObjectRetention, err error) {
+	ret.RetainUntil = header.Get(cmn.HeaderObjRetainUntil)
+	if hold := header.Get(cmn.HeaderObjLegalHold); hold != "" {
+		if ret.LegalHold, err = cmn.ParseBool(hold); err != nil {
+			err = fmt.Errorf("invalid legal hold %q: %v", hold, err)
+		}
+	}
+	return
+}
+
+// Sets the retention of a new object: either explicitly requested or
+// the bucket's default.
+func setRetention(lom *cluster.LOM, ret cmn.ObjectRetention) error {
+	lom.SetRetainUntil(0)
+	lom.SetLegalHold(false)
+	if ret.RetainUntil == "" && !ret.LegalHold {
+		lom.InitRetention()
+		return nil
+	}
+	if !lom.RetentionConf().Enabled {
+		return fmt.Errorf("%s: retention is not enabled for bucket %s", lom, lom.Bck())
+	}
+	if ret.RetainUntil == "" {
+		lom.InitRetention()
+	} else {
+		until, err := parseRetainUntil(ret.RetainUntil)
+		if err != nil {
+			return err
+		}
+		lom.SetRetainUntil(until)
+	}
+	lom.SetLegalHold(ret.LegalHold)
+	return nil
+}
+
+func parseRetainUntil(s string) (int64, error) {
+	until, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retain-until time %q (expected RFC3339): %v", s, err)
+	}
+	return until.UnixNano(), nil
+}
+
+// Changes the retention of an existing object: retain-until time can be only
+// extended, unless governance-mode retention is bypassed.
+func (t *targetrunner) updateRetention(lom *cluster.LOM, retainUntil string, legalHold *bool, bypass bool) (error, int) {
+	if !lom.RetentionConf().Enabled {
+		return fmt.Errorf("%s: retention is not enabled for bucket %s", lom, lom.Bck()), http.StatusBadRequest
+	}
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false); err != nil {
+		if cmn.IsObjNotExist(err) {
+			return err, http.StatusNotFound
+		}
+		return err, http.StatusInternalServerError
+	}
+	if retainUntil != "" {
+		until, err := parseRetainUntil(retainUntil)
+		if err != nil {
+			return err, http.StatusBadRequest
+		}
+		if until < lom.RetainUntil() && lom.RetainUntil() > time.Now().UnixNano() {
+			if !bypass || lom.RetentionConf().Mode == cmn.RetentionCompliance {
+				err = fmt.Errorf("%s: cannot shorten retention (%s mode)", lom, lom.RetentionConf().Mode)
+				return err, http.StatusForbidden
+			}
+		}
+		lom.SetRetainUntil(until)
+	}
+	if legalHold != nil {
+		lom.SetLegalHold(*legalHold)
+	}
+	if err := lom.PersistWithCopies(); err != nil {
+		return err, http.StatusInternalServerError
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: retain until %d, legal hold %t", lom, lom.RetainUntil(), lom.LegalHold())
+	}
+	return nil, 0
+}
+
+// POST /v1/objects/bucket-name/object-name { "action": "objretention" }
+func (t *targetrunner) objRetention(w http.ResponseWriter, r *http.Request, msg *cmn.ActionMsg) {
+	apitems, err := t.checkRESTItems(w, r, 2, false, cmn.Version, cmn.Objects)
+	if err != nil {
+		return
+	}
+	bucket, objName := apitems[0], apitems[1]
+	params := cmn.ActValRetention{}
+	if err := cmn.TryUnmarshal(msg.Value, &params); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bck, err := newBckFromQuery(bucket, r.URL.Query())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	lom := &cluster.LOM{T: t, ObjName: objName}
+	if err = lom.Init(bck.Bck); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	bypass := cmn.IsParseBool(r.Header.Get(cmn.HeaderBypassRetention))
+	if err, errCode := t.updateRetention(lom, params.RetainUntil, params.LegalHold, bypass); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+	}
+}
+
+// Retention of the object as reported by HEAD.
+func retentionProps(lom *cluster.LOM) (props cmn.ObjectRetention) {
+	if until := lom.RetainUntil(); until != 0 {
+		props.RetainUntil = time.Unix(0, until).UTC().Format(time.RFC3339)
+	}
+	props.LegalHold = lom.LegalHold()
+	return
+}
@@ -5,10 +5,12 @@
 package ais
 
 import (
+	"crypto/hmac"
 	"fmt"
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,7 +18,6 @@ import (
 	"github.com/NVIDIA/aistore/ais/s3compat"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
-	"github.com/NVIDIA/aistore/ec"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/tar2tf"
 )
@@ -28,14 +29,58 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var (
+		query       = r.URL.Query()
+		_, uploads  = query[s3compat.URLParamMptUploads]
+		_, uploadID = query[s3compat.URLParamMptUploadID]
+	)
 	switch r.Method {
 	case http.MethodHead:
 		t.headObjS3(w, r, apitems)
 	case http.MethodGet:
+		if len(apitems) == 1 && uploads {
+			t.listMptUploadsS3(w, r, apitems[0])
+			return
+		}
+		if uploadID {
+			t.listMptPartsS3(w, r, apitems)
+			return
+		}
 		t.getObjS3(w, r, apitems)
 	case http.MethodPut:
+		if s3compat.IsChunked(r) {
+			err = t.decodeChunkedS3(r)
+		} else {
+			err = t.verifyPayloadS3(r)
+		}
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		if uploadID {
+			t.putMptPartS3(w, r, apitems)
+			return
+		}
 		t.putObjS3(w, r, apitems)
+	case http.MethodPost:
+		if err := t.verifyPayloadS3(r); err != nil {
+			t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
+		if uploads {
+			t.startMptUploadS3(w, r, apitems)
+			return
+		}
+		if uploadID {
+			t.completeMptUploadS3(w, r, apitems)
+			return
+		}
+		t.invalmsghdlr(w, r, "invalid request")
 	case http.MethodDelete:
+		if uploadID {
+			t.abortMptUploadS3(w, r, apitems)
+			return
+		}
 		t.delObjS3(w, r, apitems)
 	default:
 		s := fmt.Sprintf("Invalid HTTP Method: %v %s", r.Method, r.URL.Path)
@@ -43,6 +88,61 @@ func (t *targetrunner) s3Handler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Replaces the body of the request that has 'aws-chunked' payload with
+// the decoding reader. If the request was redirected by a proxy that
+// verified its signature, the reader verifies the signatures of the chunks.
+func (t *targetrunner) decodeChunkedS3(r *http.Request) error {
+	size, err := s3compat.DecodedSize(r)
+	if err != nil {
+		return err
+	}
+	seed, err := s3compat.SeedFromQuery(r.URL.Query())
+	if err != nil {
+		return err
+	}
+	var signingKey []byte
+	if seed != nil {
+		key, ok := t.authn.accessKey(seed.AccessKey)
+		if !ok {
+			return fmt.Errorf("access key %q does not exist", seed.AccessKey)
+		}
+		secret, err := key.secret()
+		if err != nil {
+			return err
+		}
+		signingKey = seed.SigningKey(secret)
+	} else if cmn.GCO.Get().Auth.Enabled {
+		return fmt.Errorf("%s: chunked payload must be signed", t.si)
+	}
+	r.Body = s3compat.NewChunkedReader(r.Body, seed, signingKey)
+	r.ContentLength = size
+	r.Header.Set("Content-Length", strconv.FormatInt(size, 10))
+	return nil
+}
+
+// Wraps the body of the request with the reader that verifies the payload
+// against the SHA-256 that the request was signed with. The hash is passed
+// (and authenticated) by the proxy that verified the signature.
+func (t *targetrunner) verifyPayloadS3(r *http.Request) error {
+	var (
+		config      = cmn.GCO.Get()
+		query       = r.URL.Query()
+		payloadHash = query.Get(s3compat.URLParamPayloadHash)
+	)
+	if payloadHash == "" {
+		if config.Auth.Enabled {
+			return fmt.Errorf("%s: payload must be signed", t.si)
+		}
+		return nil
+	}
+	mac := s3compat.PayloadMAC(config.Auth.Secret, r.Method, r.URL.Path, payloadHash)
+	if !hmac.Equal([]byte(mac), []byte(query.Get(s3compat.URLParamPayloadMAC))) {
+		return fmt.Errorf("%s: invalid payload signature", t.si)
+	}
+	r.Body = s3compat.NewPayloadReader(r.Body, payloadHash)
+	return nil
+}
+
 func (t *targetrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items []string) {
 	config := cmn.GCO.Get()
 	src := r.Header.Get(s3compat.HeaderObjSrc)
@@ -84,10 +184,22 @@ func (t *targetrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items [
 		return
 	}
 	var (
-		si   *cluster.Snode
-		smap = t.owner.smap.get()
-		err  error
+		si       *cluster.Snode
+		smap     = t.owner.smap.get()
+		customMD = lom.CustomMD()
+		err      error
 	)
+	if strings.EqualFold(r.Header.Get(s3compat.HeaderMetaDirective), s3compat.MetaDirectiveReplace) {
+		if customMD, err = cmn.CustomMDFromHeader(r.Header, s3compat.HeaderMetaPrefix); err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+	}
+	ret, err := s3compat.ObjLockFromHeader(r.Header, &bckDst.Props.Retention)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
 	objName := path.Join(items[1:]...)
 	si, err = cluster.HrwTarget(bckDst.MakeUname(objName), &smap.Smap)
 	if err != nil {
@@ -96,11 +208,11 @@ func (t *targetrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items [
 	}
 
 	if si.ID() == t.Snode().ID() {
-		err = t.localObjCopy(lom, bckDst.Bck, objName)
+		err = t.localObjCopy(lom, bckDst.Bck, objName, customMD, ret)
 	} else {
 		lom.Lock(false)
 		defer lom.Unlock(false)
-		err = t.sendObj(lom, si, bckDst.Bck, objName)
+		err = t.sendObj(lom, si, bckDst.Bck, objName, customMD, ret)
 	}
 	if err != nil {
 		t.invalmsghdlr(w, r, err.Error())
@@ -113,7 +225,8 @@ func (t *targetrunner) copyObjS3(w http.ResponseWriter, r *http.Request, items [
 	w.Write(result.MustMarshal())
 }
 
-func (t *targetrunner) sendObj(src *cluster.LOM, si *cluster.Snode, bck cmn.Bck, objName string) error {
+func (t *targetrunner) sendObj(src *cluster.LOM, si *cluster.Snode, bck cmn.Bck, objName string,
+	customMD cmn.SimpleKVs, ret cmn.ObjectRetention) error {
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("AISS3 COPY OBJECT: %s/%s => %s [%s/%s]", src.Bck().Bck, src.ObjName, si, bck, objName)
 	}
@@ -142,6 +255,13 @@ func (t *targetrunner) sendObj(src *cluster.LOM, si *cluster.Snode, bck cmn.Bck,
 		req.Header.Set(cmn.HeaderObjCksumVal, cksum.Value())
 	}
 	req.Header.Set(cmn.HeaderObjVersion, src.Version())
+	cmn.CustomMDToHeader(customMD, req.Header, cmn.HeaderObjCustomMD)
+	if ret.RetainUntil != "" {
+		req.Header.Set(cmn.HeaderObjRetainUntil, ret.RetainUntil)
+	}
+	if ret.LegalHold {
+		req.Header.Set(cmn.HeaderObjLegalHold, "true")
+	}
 	req.ContentLength = src.Size()
 	resp, err := t.httpclientGetPut.Do(req)
 	if err != nil {
@@ -151,7 +271,8 @@ func (t *targetrunner) sendObj(src *cluster.LOM, si *cluster.Snode, bck cmn.Bck,
 	return nil
 }
 
-func (t *targetrunner) localObjCopy(src *cluster.LOM, bck cmn.Bck, objName string) error {
+func (t *targetrunner) localObjCopy(src *cluster.LOM, bck cmn.Bck, objName string,
+	customMD cmn.SimpleKVs, ret cmn.ObjectRetention) error {
 	if glog.FastV(4, glog.SmoduleAIS) {
 		glog.Infof("AISS3 COPY OBJECT(local): %s/%s => %s/%s", src.Bck().Bck, src.ObjName, bck, objName)
 	}
@@ -166,6 +287,13 @@ func (t *targetrunner) localObjCopy(src *cluster.LOM, bck cmn.Bck, objName strin
 	}
 
 	dstLom.SetAtimeUnix(started.UnixNano())
+	dstLom.SetCustomMD(customMD)
+	if err := setRetention(dstLom, ret); err != nil {
+		return err
+	}
+	if err := t.checkRetention(dstLom, false /*bypass*/); err != nil {
+		return err
+	}
 	file, err := os.Open(src.FQN)
 	if err != nil {
 		t.fshc(err, src.FQN)
@@ -218,6 +346,24 @@ func (t *targetrunner) directPutObjS3(w http.ResponseWriter, r *http.Request, it
 	if lom.Bck().IsAIS() && lom.VerConf().Enabled {
 		lom.Load() // need to know the current version if versioning enabled
 	}
+	customMD, err := cmn.CustomMDFromHeader(r.Header, s3compat.HeaderMetaPrefix)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	lom.SetCustomMD(customMD)
+	ret, err := s3compat.ObjLockFromHeader(r.Header, lom.RetentionConf())
+	if err == nil {
+		err = setRetention(lom, ret)
+	}
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := t.checkRetention(lom, s3compat.BypassGovernance(r.Header)); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
 	lom.SetAtimeUnix(started.UnixNano())
 	if err, errCode := t.doPut(r, lom, started); err != nil {
 		t.fshc(err, lom.FQN)
@@ -399,7 +545,8 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		t.invalmsghdlr(w, r, err.Error())
 		return
 	}
-	err, errCode := t.objDelete(t.contextWithAuth(r.Header), lom, false)
+	bypass := s3compat.BypassGovernance(r.Header)
+	err, errCode := t.objDelete(t.contextWithAuth(r.Header), lom, false, bypass)
 	if err != nil {
 		if errCode == http.StatusNotFound {
 			t.invalmsghdlrsilent(w, r,
@@ -411,6 +558,4 @@ func (t *targetrunner) delObjS3(w http.ResponseWriter, r *http.Request, items []
 		}
 		return
 	}
-	// EC cleanup if EC is enabled
-	ec.ECM.CleanupObject(lom)
 }
@@ -0,0 +1,444 @@
+// Package ais provides core functionality for the AIStore object storage.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ais
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais/s3compat"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// reads one or more opened files in a row: used to PUT the parts of a multipart
+// upload as a single object (in the order defined by the "complete" request)
+type mptPartsReader struct {
+	io.Reader
+	files  []*os.File
+	unlock func() // if defined, releases the lock(s) that protect the files
+}
+
+func (r *mptPartsReader) Close() (err error) {
+	for _, file := range r.files {
+		if errClose := file.Close(); errClose != nil {
+			err = errClose
+		}
+	}
+	if r.unlock != nil {
+		r.unlock()
+		r.unlock = nil
+	}
+	return
+}
+
+// Initialize the LOM of the destination object of a multipart upload.
+func (t *targetrunner) mptLOM(r *http.Request, items []string) (lom *cluster.LOM, err error) {
+	if len(items) < 2 {
+		return nil, fmt.Errorf("object name is undefined")
+	}
+	config := cmn.GCO.Get()
+	bck := cluster.NewBck(items[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err = bck.Init(t.owner.bmd, nil); err != nil {
+		return
+	}
+	lom = &cluster.LOM{T: t, ObjName: path.Join(items[1:]...)}
+	if err = lom.Init(bck.Bck, config); err != nil {
+		if _, ok := err.(*cmn.ErrorRemoteBucketDoesNotExist); ok {
+			t.BMDVersionFixup(r, cmn.Bck{}, true /* sleep */)
+			err = lom.Init(bck.Bck, config)
+		}
+	}
+	return
+}
+
+// POST s3/bckName/objName?uploads
+func (t *targetrunner) startMptUploadS3(w http.ResponseWriter, r *http.Request, items []string) {
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	customMD, err := cmn.CustomMDFromHeader(r.Header, s3compat.HeaderMetaPrefix)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	uploadID := cmn.GenUUID()
+	s3compat.InitUpload(uploadID, lom.BckName(), lom.ObjName, customMD)
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3 MPT: started upload %q for %s", uploadID, lom)
+	}
+	result := s3compat.NewInitiateMptUploadResult(lom.BckName(), lom.ObjName, uploadID)
+	w.Header().Set(s3compat.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// PUT s3/bckName/objName?partNumber=N&uploadId=ID
+// PUT s3/bckName/objName?partNumber=N&uploadId=ID - with HeaderObjSrc in request header
+func (t *targetrunner) putMptPartS3(w http.ResponseWriter, r *http.Request, items []string) {
+	var (
+		query    = r.URL.Query()
+		uploadID = query.Get(s3compat.URLParamMptUploadID)
+		copySrc  = r.Header.Get(s3compat.HeaderObjSrc)
+		reader   io.ReadCloser
+		size     int64
+	)
+	partNum, err := s3compat.ParsePartNum(query.Get(s3compat.URLParamMptPartNo))
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	config := cmn.GCO.Get()
+	if capInfo := t.AvgCapUsed(config); capInfo.OOS {
+		t.invalmsghdlr(w, r, capInfo.Err.Error())
+		return
+	}
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if _, err := s3compat.UploadObjName(uploadID, lom.BckName()); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if copySrc == "" {
+		reader = r.Body
+		if sizeStr := r.Header.Get("Content-Length"); sizeStr != "" {
+			if sz, ers := strconv.ParseInt(sizeStr, 10, 64); ers == nil {
+				size = sz
+			}
+		}
+	} else {
+		reader, size, err = t.mptCopySrcReader(r, copySrc)
+		if err != nil {
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+	}
+
+	partFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfileMpt)
+	part, err := t.writeMptPart(lom, partFQN, reader, size)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	part.Num = partNum
+	if md5b64 := r.Header.Get(s3compat.HeaderContentMD5); md5b64 != "" && copySrc == "" {
+		if b, err := base64.StdEncoding.DecodeString(md5b64); err != nil || hex.EncodeToString(b) != part.MD5 {
+			cmn.RemoveFile(partFQN)
+			t.invalmsghdlr(w, r, fmt.Sprintf("part %d: %s mismatch", partNum, s3compat.HeaderContentMD5))
+			return
+		}
+	}
+	if err := s3compat.AddPart(uploadID, part); err != nil {
+		cmn.RemoveFile(partFQN)
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3 MPT: upload %q, %s part %d (%s)", uploadID, lom, partNum, cmn.B2S(part.Size, 1))
+	}
+	if copySrc == "" {
+		w.Header().Set(s3compat.HeaderETag, part.MD5)
+		return
+	}
+	result := s3compat.CopyPartResult{LastModified: time.Now().UTC().Format(time.RFC3339), ETag: part.MD5}
+	w.Header().Set(s3compat.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// Save the part data to a work file and compute its MD5 on the fly.
+// NOTE: `reader` is closed on the end of the call.
+func (t *targetrunner) writeMptPart(lom *cluster.LOM, partFQN string, reader io.ReadCloser,
+	size int64) (part *s3compat.MptPart, err error) {
+	var (
+		file    *os.File
+		buf     []byte
+		slab    *memsys.Slab
+		written int64
+		cksum   = cmn.NewCksumHash(cmn.ChecksumMD5)
+	)
+	defer reader.Close()
+	if file, err = lom.CreateFile(partFQN); err != nil {
+		return
+	}
+	if size == 0 {
+		buf, slab = t.gmm.Alloc()
+	} else {
+		buf, slab = t.gmm.Alloc(size)
+	}
+	written, err = io.CopyBuffer(cmn.NewWriterMulti(cksum.H, file), reader, buf)
+	slab.Free(buf)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		if nestedErr := cmn.RemoveFile(partFQN); nestedErr != nil {
+			glog.Errorf("Nested (%v): failed to remove %s, err: %v", err, partFQN, nestedErr)
+		}
+		return
+	}
+	cksum.Finalize()
+	part = &s3compat.MptPart{MD5: cksum.Value(), FQN: partFQN, Size: written}
+	return
+}
+
+// Returns the reader for the source object (or its range) of "upload part copy"
+// request. The source object is read locally if this target owns it,
+// otherwise it is read from the owner target.
+func (t *targetrunner) mptCopySrcReader(r *http.Request, src string) (reader io.ReadCloser, size int64, err error) {
+	var (
+		config = cmn.GCO.Get()
+		parts  = strings.SplitN(strings.Trim(src, "/"), "/", 2) // in AWS examples the path starts with "/"
+	)
+	if len(parts) < 2 {
+		return nil, 0, fmt.Errorf("copy source %q is not an object name", src)
+	}
+	bckSrc := cluster.NewBck(parts[0], cmn.ProviderAIS, cmn.NsGlobal)
+	if err = bckSrc.Init(t.owner.bmd, nil); err != nil {
+		return
+	}
+	lom := &cluster.LOM{T: t, ObjName: strings.Trim(parts[1], "/")}
+	if err = lom.Init(bckSrc.Bck, config); err != nil {
+		return
+	}
+	smap := t.owner.smap.get()
+	si, err := cluster.HrwTarget(lom.Uname(), &smap.Smap)
+	if err != nil {
+		return
+	}
+	if si.ID() != t.si.ID() {
+		return t.mptRemoteSrcReader(r, si, lom)
+	}
+
+	// the source object remains read-locked until the returned reader is closed
+	lom.Lock(false)
+	defer func() {
+		if err != nil {
+			lom.Unlock(false)
+		}
+	}()
+	if err = lom.Load(); err != nil {
+		return
+	}
+	offset, length := int64(0), lom.Size()
+	if rng := r.Header.Get(s3compat.HeaderObjSrcRange); rng != "" {
+		if offset, length, err = s3compat.ParseS3Range(rng, lom.Size()); err != nil {
+			return
+		}
+	}
+	file, err := os.Open(lom.FQN)
+	if err != nil {
+		t.fshc(err, lom.FQN)
+		return
+	}
+	reader = &mptPartsReader{
+		Reader: io.NewSectionReader(file, offset, length),
+		files:  []*os.File{file},
+		unlock: func() { lom.Unlock(false) },
+	}
+	return reader, length, nil
+}
+
+func (t *targetrunner) mptRemoteSrcReader(r *http.Request, si *cluster.Snode, lom *cluster.LOM) (io.ReadCloser, int64, error) {
+	query := cmn.AddBckToQuery(nil, lom.Bck().Bck)
+	if rng := r.Header.Get(s3compat.HeaderObjSrcRange); rng != "" {
+		// the size is unknown yet - HEAD the source object first
+		size, err := t.mptRemoteSrcSize(si, lom)
+		if err != nil {
+			return nil, 0, err
+		}
+		offset, length, err := s3compat.ParseS3Range(rng, size)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.Set(cmn.URLParamOffset, strconv.FormatInt(offset, 10))
+		query.Set(cmn.URLParamLength, strconv.FormatInt(length, 10))
+	}
+	args := cmn.ReqArgs{
+		Method: http.MethodGet,
+		Base:   si.URL(cmn.NetworkIntraData),
+		Path:   cmn.URLPath(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+		Query:  query,
+	}
+	req, err := args.Req()
+	if err != nil {
+		return nil, 0, cmn.NewFailedToCreateHTTPRequest(err)
+	}
+	resp, err := t.httpclientGetPut.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("failed to read %s from %s: %s", lom, si, resp.Status)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+func (t *targetrunner) mptRemoteSrcSize(si *cluster.Snode, lom *cluster.LOM) (int64, error) {
+	query := cmn.AddBckToQuery(nil, lom.Bck().Bck)
+	query.Set(cmn.URLParamSilent, "true")
+	res := t.call(callArgs{
+		si: si,
+		req: cmn.ReqArgs{
+			Method: http.MethodHead,
+			Base:   si.URL(cmn.NetworkIntraControl),
+			Path:   cmn.URLPath(cmn.Version, cmn.Objects, lom.BckName(), lom.ObjName),
+			Query:  query,
+		},
+		timeout: cmn.DefaultTimeout,
+	})
+	if res.err != nil {
+		return 0, res.err
+	}
+	return strconv.ParseInt(res.header.Get(cmn.HeaderObjSize), 10, 64)
+}
+
+// POST s3/bckName/objName?uploadId=ID
+func (t *targetrunner) completeMptUploadS3(w http.ResponseWriter, r *http.Request, items []string) {
+	var (
+		started  = time.Now()
+		uploadID = r.URL.Query().Get(s3compat.URLParamMptUploadID)
+		req      = &s3compat.CompleteMptUpload{}
+	)
+	defer r.Body.Close()
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if _, err := s3compat.UploadObjName(uploadID, lom.BckName()); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := xml.NewDecoder(r.Body).Decode(req); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if err := t.checkRetention(lom, false /*bypass*/); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusForbidden)
+		return
+	}
+	// from now on and until the object is assembled the parts cannot be replaced or removed
+	parts, err := s3compat.StartCompletion(uploadID, req.Parts)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	reader := &mptPartsReader{files: make([]*os.File, 0, len(parts))}
+	readers := make([]io.Reader, 0, len(parts))
+	size := int64(0)
+	for _, part := range parts {
+		file, err := os.Open(part.FQN)
+		if err != nil {
+			reader.Close()
+			s3compat.CancelCompletion(uploadID)
+			t.fshc(err, part.FQN)
+			t.invalmsghdlr(w, r, err.Error())
+			return
+		}
+		reader.files = append(reader.files, file)
+		readers = append(readers, file)
+		size += part.Size
+	}
+	reader.Reader = io.MultiReader(readers...)
+
+	if lom.Bck().IsAIS() && lom.VerConf().Enabled {
+		lom.Load() // need to know the current version if versioning enabled
+	}
+	lom.SetAtimeUnix(started.UnixNano())
+	lom.SetCustomMD(s3compat.UploadCustomMD(uploadID))
+	// the resulting object gets the bucket's default retention
+	lom.SetRetainUntil(0)
+	lom.SetLegalHold(false)
+	lom.InitRetention()
+	poi := &putObjInfo{
+		started: started,
+		t:       t,
+		lom:     lom,
+		r:       reader,
+		size:    size,
+		ctx:     t.contextWithAuth(r.Header),
+		workFQN: fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+	}
+	if err, errCode := poi.putObject(); err != nil {
+		s3compat.CancelCompletion(uploadID)
+		t.fshc(err, lom.FQN)
+		t.invalmsghdlr(w, r, err.Error(), errCode)
+		return
+	}
+	if err := s3compat.FinishUpload(uploadID); err != nil {
+		glog.Errorf("AISS3 MPT: failed to cleanup upload %q of %s, err: %v", uploadID, lom, err)
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("AISS3 MPT: completed upload %q, %s (%d parts, %s)", uploadID, lom, len(parts), cmn.B2S(size, 1))
+	}
+	result := s3compat.NewCompleteMptUploadResult(lom.BckName(), lom.ObjName, s3compat.MptETag(parts))
+	w.Header().Set(s3compat.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// DELETE s3/bckName/objName?uploadId=ID
+func (t *targetrunner) abortMptUploadS3(w http.ResponseWriter, r *http.Request, items []string) {
+	uploadID := r.URL.Query().Get(s3compat.URLParamMptUploadID)
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	if _, err := s3compat.UploadObjName(uploadID, lom.BckName()); err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := s3compat.AbortUpload(uploadID); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET s3/bckName/objName?uploadId=ID
+func (t *targetrunner) listMptPartsS3(w http.ResponseWriter, r *http.Request, items []string) {
+	uploadID := r.URL.Query().Get(s3compat.URLParamMptUploadID)
+	lom, err := t.mptLOM(r, items)
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	result, err := s3compat.ListParts(uploadID, lom.BckName())
+	if err != nil {
+		t.invalmsghdlr(w, r, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set(s3compat.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
+
+// GET s3/bckName?uploads
+func (t *targetrunner) listMptUploadsS3(w http.ResponseWriter, r *http.Request, bucket string) {
+	bck := cluster.NewBck(bucket, cmn.ProviderAIS, cmn.NsGlobal)
+	if err := bck.Init(t.owner.bmd, nil); err != nil {
+		t.invalmsghdlr(w, r, err.Error())
+		return
+	}
+	result := s3compat.ListUploads(bck.Name)
+	w.Header().Set(s3compat.HeaderContentType, s3compat.ContentType)
+	w.Write(result.MustMarshal())
+}
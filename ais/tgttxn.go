@@ -30,6 +30,8 @@ type txnServerCtx struct {
 	caller  string
 	bck     *cluster.Bck
 	event   string
+
+	initiator string // who has requested the transaction (see: xactInitiator)
 }
 
 // verb /v1/txn
@@ -146,7 +148,9 @@ func (t *targetrunner) makeNCopies(c *txnServerCtx) error {
 		}
 		// do the work in xaction
 		xaction.Registry.DoAbort(cmn.ActPutCopies, c.bck)
-		xaction.Registry.RenewBckMakeNCopies(c.bck, t, int(copies))
+		if xact := xaction.Registry.RenewBckMakeNCopies(c.bck, t, int(copies)); xact != nil {
+			xaction.Registry.SetInitiator(xact, c.initiator)
+		}
 	default:
 		cmn.Assert(false)
 	}
@@ -205,7 +209,10 @@ func (t *targetrunner) setBucketProps(c *txnServerCtx) error {
 		}
 		if remirror(txnSetBprops.bprops, txnSetBprops.nprops) {
 			xaction.Registry.DoAbort(cmn.ActPutCopies, c.bck)
-			xaction.Registry.RenewBckMakeNCopies(c.bck, t, int(txnSetBprops.nprops.Mirror.Copies))
+			xact := xaction.Registry.RenewBckMakeNCopies(c.bck, t, int(txnSetBprops.nprops.Mirror.Copies))
+			if xact != nil {
+				xaction.Registry.SetInitiator(xact, c.initiator)
+			}
 		}
 	default:
 		cmn.Assert(false)
@@ -288,6 +295,7 @@ func (t *targetrunner) renameBucket(c *txnServerCtx) error {
 		if err != nil {
 			return err // must not happen at commit time
 		}
+		xaction.Registry.SetInitiator(xact, c.initiator)
 
 		err = fs.Mountpaths.RenameBucketDirs(txnRenB.bckFrom.Bck, txnRenB.bckTo.Bck)
 		if err != nil {
@@ -388,6 +396,7 @@ func (t *targetrunner) copyBucket(c *txnServerCtx) error {
 		if err != nil {
 			return err
 		}
+		xaction.Registry.SetInitiator(xact, c.initiator)
 		go xact.Run()
 	default:
 		cmn.Assert(false)
@@ -428,6 +437,7 @@ func (t *targetrunner) prepTxnServer(r *http.Request, msg *aisMsg, apiItems []st
 	)
 	c.msg = msg
 	c.caller = r.Header.Get(cmn.HeaderCallerName)
+	c.initiator = t.xactInitiator(r)
 	bucket, c.phase = apiItems[0], apiItems[1]
 	if c.bck, err = newBckFromQuery(bucket, query); err != nil {
 		return c, err
@@ -8,13 +8,20 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/housekeep/hk"
+	"github.com/NVIDIA/aistore/housekeep/scrub"
 	"github.com/NVIDIA/aistore/xaction"
 )
 
+// housekeeping jobs that depend on the cluster map and BMD retry this often until
+// the cluster has started
+const hkStartupInterval = 10 * time.Second
+
 // TODO: uplift via higher-level query and similar (#668)
 
 // verb /v1/xactions
@@ -29,6 +36,10 @@ func (t *targetrunner) xactHandler(w http.ResponseWriter, r *http.Request) {
 			xactMsg = cmn.XactionMsg{}
 			what    = r.URL.Query().Get(cmn.URLParamWhat)
 		)
+		if what == cmn.GetWhatXactHistory {
+			t.queryXactHistory(w, r, what)
+			return
+		}
 		if cmn.ReadJSON(w, r, &xactMsg) != nil {
 			return
 		}
@@ -108,6 +119,40 @@ func (t *targetrunner) queryXactStats(query xaction.XactQuery) ([]byte, error) {
 	return cmn.MustMarshal(xactStats), nil
 }
 
+func (t *targetrunner) queryXactHistory(w http.ResponseWriter, r *http.Request, what string) {
+	msg := cmn.XactHistoryMsg{}
+	if cmn.ReadJSON(w, r, &msg) != nil {
+		return
+	}
+	if msg.Kind != "" && !cmn.IsValidXaction(msg.Kind) {
+		t.invalmsghdlrsilent(w, r, cmn.NewXactionNotFoundError(msg.Kind).Error(), http.StatusNotFound)
+		return
+	}
+	records := xaction.Registry.GetHistory(xaction.XactHistoryQuery{
+		Kind:  msg.Kind,
+		Bck:   msg.Bck,
+		Since: msg.Since,
+		Until: msg.Until,
+		Limit: msg.Limit,
+	})
+	t.writeJSON(w, r, cmn.MustMarshal(records), what)
+}
+
+// xactInitiator returns who has requested the xaction (to be recorded in the
+// xaction history): the authenticated user or, otherwise, the node (proxy)
+// or the client which has sent the request.
+func (t *targetrunner) xactInitiator(r *http.Request) string {
+	if cmn.GCO.Get().Auth.Enabled {
+		if user, err := t.userFromRequest(r.Header); err == nil && user != nil {
+			return user.userID
+		}
+	}
+	if caller := r.Header.Get(cmn.HeaderCallerName); caller != "" {
+		return caller
+	}
+	return r.RemoteAddr
+}
+
 func (t *targetrunner) cmdXactStart(r *http.Request, xactMsg cmn.XactionMsg, bck *cluster.Bck) error {
 	const erfmb = "global xaction %q does not require bucket (%s) - ignoring it and proceeding to start"
 	const erfmn = "xaction %q requires a bucket to start"
@@ -123,6 +168,11 @@ func (t *targetrunner) cmdXactStart(r *http.Request, xactMsg cmn.XactionMsg, bck
 			glog.Errorf(erfmb, xactMsg.Kind, bck)
 		}
 		go t.rebManager.RunResilver(xactMsg.ID, false /*skipGlobMisplaced*/)
+	case cmn.ActScrub:
+		if bck != nil {
+			glog.Errorf(erfmb, xactMsg.Kind, bck)
+		}
+		go t.runScrub(xactMsg.ID)
 	// 2. with bucket
 	case cmn.ActPrefetch:
 		if bck == nil {
@@ -136,7 +186,41 @@ func (t *targetrunner) cmdXactStart(r *http.Request, xactMsg cmn.XactionMsg, bck
 		if err != nil {
 			return err
 		}
+		xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
 		go xact.Run(args)
+	case cmn.ActLifecycle:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		if !bck.Props.Lifecycle.Enabled {
+			return fmt.Errorf("%s: lifecycle is not enabled for bucket %s", t.si, bck)
+		}
+		if xact := xaction.Registry.RenewLifecycle(t, bck); xact != nil {
+			xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
+			go xact.Run()
+		}
+	case cmn.ActECReencode:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		if !bck.Props.EC.Enabled {
+			return fmt.Errorf("%s: EC is not enabled for bucket %s", t.si, bck)
+		}
+		if xact := xaction.Registry.RenewECReencode(t, bck); xact != nil {
+			xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
+			go xact.Run()
+		}
+	case cmn.ActReplBackfill:
+		if bck == nil {
+			return fmt.Errorf(erfmn, xactMsg.Kind)
+		}
+		if !bck.Props.Replication.Enabled {
+			return fmt.Errorf("%s: replication is not enabled for bucket %s", t.si, bck)
+		}
+		if xact := xaction.Registry.RenewReplBackfill(t, bck); xact != nil {
+			xaction.Registry.SetInitiator(xact, t.xactInitiator(r))
+			go xact.Run()
+		}
 	// 3. cannot start
 	case cmn.ActPutCopies:
 		return fmt.Errorf("cannot start xaction %q - it is invoked automatically by PUTs into mirrored bucket", xactMsg.Kind)
@@ -150,3 +234,60 @@ func (t *targetrunner) cmdXactStart(r *http.Request, xactMsg cmn.XactionMsg, bck
 	}
 	return nil
 }
+
+// Periodically evaluates lifecycle rules of all buckets, one bucket at a time.
+func (t *targetrunner) lifecycleHK() time.Duration {
+	if !t.ClusterStarted() {
+		return hkStartupInterval
+	}
+	if t.RebalanceInfo().IsRebalancing {
+		glog.Infoln("Warning: rebalancing (local or global) is in progress, postponing lifecycle run")
+		return time.Hour
+	}
+	var bcks []*cluster.Bck
+	t.GetBowner().Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		if bck.Props.Lifecycle.Enabled {
+			bcks = append(bcks, bck)
+		}
+		return false
+	})
+	if len(bcks) > 0 {
+		go t.runLifecycle(bcks)
+	}
+	return hk.DayInterval
+}
+
+// runLifecycle runs lifecycle xactions one bucket at a time; each xaction is renewed
+// right before it runs, so that the buckets waiting in line neither show up as running
+// nor block on-demand start (and the bucket that is already running gets skipped)
+func (t *targetrunner) runLifecycle(bcks []*cluster.Bck) {
+	for _, bck := range bcks {
+		if props, present := t.GetBowner().Get().Get(bck); !present || !props.Lifecycle.Enabled {
+			continue
+		}
+		if xact := xaction.Registry.RenewLifecycle(t, bck); xact != nil {
+			xact.Run()
+		}
+	}
+}
+
+// scrubHK starts the scrubber `scrub.interval` after the last completed run (see
+// cmn.ScrubConf); the long waits are capped so that the config changes get noticed
+func (t *targetrunner) scrubHK() time.Duration {
+	config := cmn.GCO.Get()
+	if config.Scrub.Interval == 0 {
+		return time.Hour
+	}
+	if !t.ClusterStarted() {
+		return hkStartupInterval
+	}
+	if t.RebalanceInfo().IsRebalancing {
+		glog.Infoln("Warning: rebalancing (local or global) is in progress, postponing scrub run")
+		return time.Hour
+	}
+	if next := scrub.LastRun().Add(config.Scrub.Interval); time.Now().Before(next) {
+		return cmn.MinDuration(time.Until(next), time.Hour)
+	}
+	go t.runScrub("")
+	return cmn.MinDuration(config.Scrub.Interval, time.Hour)
+}
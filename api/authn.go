@@ -7,6 +7,7 @@ package api
 import (
 	"errors"
 	"net/http"
+	"net/url"
 	"sort"
 
 	"github.com/NVIDIA/aistore/cmn"
@@ -19,6 +20,7 @@ type (
 		AdminPassword string
 		UserName      string
 		UserPassword  string
+		AccessKey     string
 	}
 
 	ClusterSpec struct {
@@ -49,6 +51,20 @@ type (
 		ID   string
 		URLs []string
 	}
+
+	// A pair of credentials to sign S3 requests (AWS Signature V4)
+	AccessKeyPair struct {
+		AccessKey string `json:"access_key"`
+		SecretKey string `json:"secret_key"`
+	}
+	AccessKeyRec struct {
+		AccessKey string `json:"access_key"`
+		UserName  string `json:"user"`
+	}
+
+	accessKeyReq struct {
+		UserName string `json:"user"`
+	}
 )
 
 func AddUser(baseParams BaseParams, spec AuthnSpec) error {
@@ -164,3 +180,44 @@ func GetClusterAuthN(baseParams BaseParams, spec ClusterSpec) ([]*AuthClusterRec
 	sort.Slice(rec, less)
 	return rec, err
 }
+
+// Generates a new access key pair for the user. The secret key is returned
+// only by this call, it is never sent again.
+func AddAccessKey(baseParams BaseParams, spec AuthnSpec) (pair *AccessKeyPair, err error) {
+	baseParams.Method = http.MethodPost
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.AccessKeys),
+		Body:       cmn.MustMarshal(accessKeyReq{UserName: spec.UserName}),
+		User:       spec.AdminName,
+		Password:   spec.AdminPassword,
+	}, &pair)
+	return pair, err
+}
+
+func DeleteAccessKey(baseParams BaseParams, spec AuthnSpec) error {
+	baseParams.Method = http.MethodDelete
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.AccessKeys, spec.AccessKey),
+		User:       spec.AdminName,
+		Password:   spec.AdminPassword,
+	})
+}
+
+// Returns access keys of the user, or all access keys if the user is not defined.
+func GetAccessKeys(baseParams BaseParams, spec AuthnSpec) (keys []*AccessKeyRec, err error) {
+	var query url.Values
+	if spec.UserName != "" {
+		query = url.Values{"user": []string{spec.UserName}}
+	}
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.AccessKeys),
+		Query:      query,
+		User:       spec.AdminName,
+		Password:   spec.AdminPassword,
+	}, &keys)
+	return keys, err
+}
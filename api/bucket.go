@@ -123,13 +123,18 @@ func GetBucketsSummaries(baseParams BaseParams, query cmn.QueryBcks, msg *cmn.Se
 
 // CreateBucket API
 //
-// CreateBucket sends a HTTP request to a proxy to create an ais bucket with the given name
-func CreateBucket(baseParams BaseParams, bck cmn.Bck) error {
+// CreateBucket sends a HTTP request to a proxy to create an ais bucket with the given name.
+// To create ht:// bucket, the origin URL (and, optionally, the manifest) must be provided via `extra`.
+func CreateBucket(baseParams BaseParams, bck cmn.Bck, extra ...cmn.ExtraProps) error {
+	msg := cmn.ActionMsg{Action: cmn.ActCreateLB}
+	if len(extra) > 0 {
+		msg.Value = extra[0]
+	}
 	baseParams.Method = http.MethodPost
 	return DoHTTPRequest(ReqParams{
 		BaseParams: baseParams,
 		Path:       cmn.URLPath(cmn.Version, cmn.Buckets, bck.Name),
-		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActCreateLB}),
+		Body:       cmn.MustMarshal(msg),
 		Query:      cmn.AddBckToQuery(nil, bck),
 	})
 }
@@ -32,6 +32,17 @@ func AbortDSort(baseParams BaseParams, managerUUID string) error {
 	})
 }
 
+// ResumeDSort resumes aborted dSort job, skipping the output shards which
+// have already been created.
+func ResumeDSort(baseParams BaseParams, managerUUID string) error {
+	baseParams.Method = http.MethodPost
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Sort, cmn.Resume),
+		Query:      url.Values{cmn.URLParamID: []string{managerUUID}},
+	})
+}
+
 func MetricsDSort(baseParams BaseParams, managerUUID string) (metrics map[string]*dsort.Metrics, err error) {
 	baseParams.Method = http.MethodGet
 	err = DoHTTPRequest(ReqParams{
@@ -44,7 +44,9 @@ type PutObjectArgs struct {
 	Object     string
 	Cksum      *cmn.Cksum
 	Reader     cmn.ReadOpenCloser
-	Size       uint64 // optional
+	Size       uint64               // optional
+	CustomMD   cmn.SimpleKVs        // optional user-defined metadata
+	Retention  *cmn.ObjectRetention // optional retention (WORM); legal hold is admin-only
 }
 
 type PromoteArgs struct {
@@ -77,7 +79,7 @@ type FlushArgs struct {
 
 // HeadObject API
 //
-// Returns the size and version of the object specified by bucket/object
+// Returns the size, version, and user-defined metadata of the object specified by bucket/object
 func HeadObject(baseParams BaseParams, bck cmn.Bck, object string, checkExists ...bool) (*cmn.ObjectProps, error) {
 	checkIsCached := false
 	if len(checkExists) > 0 {
@@ -116,6 +118,9 @@ func HeadObject(baseParams BaseParams, bck cmn.Bck, object string, checkExists .
 	if err != nil {
 		return nil, err
 	}
+	if objProps.CustomMD, err = cmn.CustomMDFromHeader(resp.Header, cmn.HeaderObjCustomMD); err != nil {
+		return nil, err
+	}
 	return objProps, nil
 }
 
@@ -241,6 +246,15 @@ func PutObject(args PutObjectArgs, replicateOpts ...ReplicateObjectInput) (err e
 			req.Header.Set(cmn.HeaderObjCksumType, args.Cksum.Type())
 			req.Header.Set(cmn.HeaderObjCksumVal, args.Cksum.Value())
 		}
+		cmn.CustomMDToHeader(args.CustomMD, req.Header, cmn.HeaderObjCustomMD)
+		if args.Retention != nil {
+			if args.Retention.RetainUntil != "" {
+				req.Header.Set(cmn.HeaderObjRetainUntil, args.Retention.RetainUntil)
+			}
+			if args.Retention.LegalHold {
+				req.Header.Set(cmn.HeaderObjLegalHold, "true")
+			}
+		}
 		if len(replicateOpts) > 0 {
 			req.Header.Set(cmn.HeaderObjReplicSrc, replicateOpts[0].SourceURL)
 		}
@@ -389,6 +403,26 @@ func RenameObject(baseParams BaseParams, bck cmn.Bck, oldName, newName string) e
 	})
 }
 
+// SetObjectRetention API
+//
+// Extends the retention of an existing object and/or places (removes) its legal hold.
+// Legal hold and `bypass` (to shorten governance-mode retention) require admin permissions.
+func SetObjectRetention(baseParams BaseParams, bck cmn.Bck, object string, retention cmn.ActValRetention,
+	bypass bool) error {
+	header := make(http.Header)
+	if bypass {
+		header.Set(cmn.HeaderBypassRetention, "true")
+	}
+	baseParams.Method = http.MethodPost
+	return DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Objects, bck.Name, object),
+		Body:       cmn.MustMarshal(cmn.ActionMsg{Action: cmn.ActObjRetention, Value: retention}),
+		Query:      cmn.AddBckToQuery(nil, bck),
+		Header:     header,
+	})
+}
+
 // PromoteFileOrDir API
 //
 // promote AIS-colocated files and directories to objects (NOTE: advanced usage only)
@@ -433,6 +467,7 @@ func DownloadSingleWithParam(baseParams BaseParams, dlBody downloader.DlSingleBo
 		BaseParams: baseParams,
 		Path:       cmn.URLPath(cmn.Version, cmn.Download),
 		Query:      query,
+		Header:     dlBody.AsHeader(),
 	})
 }
 
@@ -453,6 +488,7 @@ func DownloadRangeWithParam(baseParams BaseParams, dlBody downloader.DlRangeBody
 		BaseParams: baseParams,
 		Path:       cmn.URLPath(cmn.Version, cmn.Download),
 		Query:      query,
+		Header:     dlBody.AsHeader(),
 	})
 }
 
@@ -480,6 +516,7 @@ func DownloadMultiWithParam(baseParams BaseParams, dlBody downloader.DlMultiBody
 		Path:       cmn.URLPath(cmn.Version, cmn.Download),
 		Body:       cmn.MustMarshal(msg),
 		Query:      query,
+		Header:     dlBody.AsHeader(),
 	})
 }
 
@@ -500,6 +537,7 @@ func DownloadCloudWithParam(baseParams BaseParams, dlBody downloader.DlCloudBody
 		BaseParams: baseParams,
 		Path:       cmn.URLPath(cmn.Version, cmn.Download),
 		Query:      query,
+		Header:     dlBody.AsHeader(),
 	})
 }
 
@@ -127,13 +127,20 @@ func readResp(reqParams ReqParams, resp *http.Response, v interface{}) (*wrapped
 			wresp.n = n
 		} else {
 			hdrCksumType := resp.Header.Get(cmn.HeaderObjCksumType)
+			if hdrCksumType == "" {
+				hdrCksumType = cmn.ChecksumNone
+			} else if err := cmn.ValidateCksumType(hdrCksumType); err != nil {
+				return nil, err
+			}
 			// TODO: use MMSA
 			n, cksum, err := cmn.CopyAndChecksum(w, resp.Body, nil, hdrCksumType)
 			if err != nil {
 				return nil, err
 			}
 			wresp.n = n
-			wresp.cksumValue = cksum.Value()
+			if cksum != nil {
+				wresp.cksumValue = cksum.Value()
+			}
 		}
 	} else {
 		var err error
@@ -29,6 +29,16 @@ type (
 		Latest  bool    // Determines if we should get latest or all xactions
 		Timeout time.Duration
 	}
+
+	NodesXactHistory map[string][]*stats.XactRecord
+
+	XactHistoryArgs struct {
+		Kind  string    // Optional xaction kind, see: cmn.XactsMeta
+		Bck   cmn.Bck   // Optional bucket
+		Since time.Time // Optional, only xactions finished at or after
+		Until time.Time // Optional, only xactions finished before
+		Limit int       // Optional, max number of the latest records from each target
+	}
 )
 
 func (xs NodesXactStats) Running() bool {
@@ -130,6 +140,29 @@ func GetXactionStats(baseParams BaseParams, args XactReqArgs) (xactStats NodesXa
 	return xactStats, err
 }
 
+// GetXactionHistory API
+//
+// GetXactionHistory gets the records of finished xactions from the xaction
+// history of each target. Unlike GetXactionStats, the history is persisted
+// and is not cleaned up.
+func GetXactionHistory(baseParams BaseParams, args XactHistoryArgs) (history NodesXactHistory, err error) {
+	msg := cmn.XactHistoryMsg{
+		Kind:  args.Kind,
+		Bck:   args.Bck,
+		Since: args.Since,
+		Until: args.Until,
+		Limit: args.Limit,
+	}
+	baseParams.Method = http.MethodGet
+	err = DoHTTPRequest(ReqParams{
+		BaseParams: baseParams,
+		Path:       cmn.URLPath(cmn.Version, cmn.Cluster),
+		Body:       cmn.MustMarshal(msg),
+		Query:      url.Values{cmn.URLParamWhat: []string{cmn.GetWhatXactHistory}},
+	}, &history)
+	return history, err
+}
+
 // WaitForXaction API
 //
 // WaitForXaction waits for a given xaction to complete.
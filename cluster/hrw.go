@@ -50,6 +50,12 @@ func HrwTarget(uname string, smap *Smap) (si *Snode, err error) {
 // Sorts all targets in a cluster by their respective HRW (weights) in a descending order;
 // returns resulting subset (aka slice) that has the requested length = count.
 // Returns error if the cluster does not have enough targets.
+//
+// If the targets are labeled with failure domains (see Snode.Zone), the list
+// alternates the domains: it takes the HRW-best target from every domain (the
+// domains are ordered by their best targets), then the second best ones, etc.
+// Either way, the first target in the list is the one returned by HrwTarget,
+// and the shorter list of the same object is a prefix of the longer one.
 func HrwTargetList(uname string, smap *Smap, count int) (sis Nodes, err error) {
 	cmn.Assert(count > 0)
 	cnt := smap.CountTargets()
@@ -60,21 +66,53 @@ func HrwTargetList(uname string, smap *Smap, count int) (sis Nodes, err error) {
 	var (
 		arr    = make([]tsi, cnt)
 		digest = xxhash.ChecksumString64S(uname, cmn.MLCG32)
+		zoned  bool
 		i      int
 	)
 	sis = make(Nodes, count)
 	for _, sinfo := range smap.Tmap {
 		cs := xoshiro256.Hash(sinfo.idDigest ^ digest)
 		arr[i] = tsi{sinfo, cs}
+		zoned = zoned || sinfo.Zone != ""
 		i++
 	}
 	sort.Slice(arr, func(i, j int) bool { return arr[i].hash > arr[j].hash })
+	if zoned {
+		arr = spreadZones(arr)
+	}
 	for i := 0; i < count; i++ {
 		sis[i] = arr[i].node
 	}
 	return
 }
 
+// Reorders HRW-sorted targets so that consecutive targets belong to different
+// failure domains as long as possible (round-robin across the domains).
+func spreadZones(arr []tsi) []tsi {
+	var (
+		zones  = make([][]tsi, 0, 4)
+		zoneID = make(map[string]int, 4)
+		spread = make([]tsi, 0, len(arr))
+	)
+	for _, t := range arr {
+		idx, ok := zoneID[t.node.Zone]
+		if !ok {
+			idx = len(zones)
+			zoneID[t.node.Zone] = idx
+			zones = append(zones, make([]tsi, 0, len(arr)))
+		}
+		zones[idx] = append(zones[idx], t)
+	}
+	for round := 0; len(spread) < len(arr); round++ {
+		for _, zone := range zones {
+			if round < len(zone) {
+				spread = append(spread, zone[round])
+			}
+		}
+	}
+	return spread
+}
+
 func HrwProxy(smap *Smap, idToSkip string) (pi *Snode, err error) {
 	var (
 		max     uint64
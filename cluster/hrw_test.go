@@ -0,0 +1,68 @@
+// Package cluster provides common interfaces and local access to cluster-level metadata
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cluster
+
+import (
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HRW", func() {
+	newSmap := func(zones ...string) *Smap {
+		smap := &Smap{Tmap: make(NodeMap, len(zones))}
+		for i, zone := range zones {
+			si := &Snode{DaemonID: fmt.Sprintf("t%d", i), DaemonType: cmn.Target, Zone: zone}
+			smap.Tmap[si.ID()] = si
+		}
+		smap.InitDigests()
+		return smap
+	}
+
+	Describe("HrwTargetList", func() {
+		It("should start with HrwTarget and keep shorter lists as prefixes", func() {
+			for _, smap := range []*Smap{
+				newSmap("", "", "", "", "", ""),
+				newSmap("r1", "r1", "r1", "r2", "r2", "r3"),
+			} {
+				for i := 0; i < 100; i++ {
+					uname := fmt.Sprintf("bck/obj-%d", i)
+					si, err := HrwTarget(uname, smap)
+					Expect(err).NotTo(HaveOccurred())
+					all, err := HrwTargetList(uname, smap, smap.CountTargets())
+					Expect(err).NotTo(HaveOccurred())
+					Expect(all[0].ID()).To(Equal(si.ID()))
+					for cnt := 1; cnt < len(all); cnt++ {
+						sis, err := HrwTargetList(uname, smap, cnt)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(sis).To(Equal(all[:cnt]))
+					}
+				}
+			}
+		})
+
+		It("should spread targets across failure domains", func() {
+			smap := newSmap("r1", "r1", "r1", "r2", "r2", "r2", "r3", "r3", "r3")
+			for i := 0; i < 100; i++ {
+				sis, err := HrwTargetList(fmt.Sprintf("bck/obj-%d", i), smap, 6)
+				Expect(err).NotTo(HaveOccurred())
+				perZone := make(map[string]int, 3)
+				for _, si := range sis {
+					perZone[si.Zone]++
+				}
+				Expect(perZone).To(Equal(map[string]int{"r1": 2, "r2": 2, "r3": 2}))
+				Expect(sis[0].Zone).NotTo(Equal(sis[1].Zone))
+				Expect(sis[1].Zone).NotTo(Equal(sis[2].Zone))
+			}
+		})
+
+		It("should return error if there are not enough targets", func() {
+			_, err := HrwTargetList("bck/obj", newSmap("r1", "r2"), 3)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -37,16 +37,20 @@ const fmtNestedErr = "nested err: %v"
 const lomInitialVersion = "1"
 
 type (
-	// NOTE: sizeof(lmeta) = 72 as of 4/16
+	// NOTE: sizeof(lmeta) = 104 as of 6/20
 	lmeta struct {
-		uname   string
-		version string
-		size    int64
-		atime   int64
-		atimefs int64
-		bckID   uint64
-		cksum   *cmn.Cksum // ReCache(ref)
-		copies  fs.MPI     // ditto
+		uname       string
+		version     string
+		size        int64
+		atime       int64
+		atimefs     int64
+		ctime       int64 // creation time, unix nano (is preserved when the object migrates)
+		bckID       uint64
+		retainUntil int64         // object retention (WORM), unix nano
+		legalHold   bool          // ditto
+		cksum       *cmn.Cksum    // ReCache(ref)
+		copies      fs.MPI        // ditto
+		customMD    cmn.SimpleKVs // user-defined metadata
 	}
 	LOM struct {
 		md      lmeta  // local meta
@@ -74,6 +78,8 @@ func InitTarget() {
 		glog.SetV(glog.SmoduleCluster, logLvl)
 	}
 	maxLmeta.Store(xattrMaxSize)
+	// user-defined metadata (see cmn.ValidateCustomMD) must leave room for the rest of the xattr
+	cmn.Assert(cmn.MaxCustomMDSize <= xattrMaxSize/2)
 }
 
 //
@@ -90,6 +96,8 @@ func (lom *LOM) SetCksum(cksum *cmn.Cksum)  { lom.md.cksum = cksum }
 func (lom *LOM) Atime() time.Time           { return time.Unix(0, lom.md.atime) }
 func (lom *LOM) AtimeUnix() int64           { return lom.md.atime }
 func (lom *LOM) SetAtimeUnix(tu int64)      { lom.md.atime = tu }
+func (lom *LOM) CtimeUnix() int64           { return lom.md.ctime }
+func (lom *LOM) SetCtimeUnix(tu int64)      { lom.md.ctime = tu }
 func (lom *LOM) ECEnabled() bool            { return lom.Bprops().EC.Enabled }
 func (lom *LOM) LRUEnabled() bool           { return lom.Bprops().LRU.Enabled }
 func (lom *LOM) IsHRW() bool                { return lom.HrwFQN == lom.FQN } // subj to resilvering
@@ -109,6 +117,55 @@ func (lom *LOM) MirrorConf() *cmn.MirrorConf { return &lom.Bprops().Mirror }
 func (lom *LOM) CksumConf() *cmn.CksumConf   { return lom.bck.CksumConf() }
 func (lom *LOM) VerConf() *cmn.VersionConf   { return &lom.Bprops().Versioning }
 
+// user-defined metadata
+func (lom *LOM) CustomMD() cmn.SimpleKVs      { return lom.md.customMD }
+func (lom *LOM) SetCustomMD(md cmn.SimpleKVs) { lom.md.customMD = md }
+
+// object retention (WORM)
+func (lom *LOM) RetainUntil() int64                { return lom.md.retainUntil }
+func (lom *LOM) SetRetainUntil(tu int64)           { lom.md.retainUntil = tu }
+func (lom *LOM) LegalHold() bool                   { return lom.md.legalHold }
+func (lom *LOM) SetLegalHold(hold bool)            { lom.md.legalHold = hold }
+func (lom *LOM) RetentionConf() *cmn.RetentionConf { return &lom.Bprops().Retention }
+
+// AllowModify returns an error if the object cannot be overwritten, deleted,
+// renamed or evicted: it is under legal hold or its retention period has not
+// expired yet. Governance-mode retention can be bypassed (admin only).
+func (lom *LOM) AllowModify(bypass bool) error {
+	if lom.md.legalHold {
+		return cmn.NewObjRetainedError(lom.String(), lom.md.retainUntil, true)
+	}
+	if lom.md.retainUntil <= time.Now().UnixNano() {
+		return nil
+	}
+	if bypass && lom.RetentionConf().Mode != cmn.RetentionCompliance {
+		return nil
+	}
+	return cmn.NewObjRetainedError(lom.String(), lom.md.retainUntil, false)
+}
+
+// PersistWithCopies saves the metadata of the object and all its copies,
+// e.g. after its retention has changed
+// NOTE: uname for LOM must be already locked.
+func (lom *LOM) PersistWithCopies() (err error) {
+	if err = lom.syncMetaWithCopies(); err != nil {
+		return
+	}
+	if err = lom.Persist(); err == nil {
+		lom.ReCache()
+	}
+	return
+}
+
+// InitRetention sets the default retention of a new object, if configured
+func (lom *LOM) InitRetention() {
+	if conf := lom.RetentionConf(); conf.Enabled && lom.md.retainUntil == 0 {
+		if period := conf.DefaultPeriod(); period > 0 {
+			lom.md.retainUntil = time.Now().Add(period).UnixNano()
+		}
+	}
+}
+
 func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.copies = nil
 	if lom.MirrorConf().Enabled && lom.Bck().Equal(from.Bck(), true /* must have same BID*/) {
@@ -123,6 +180,9 @@ func (lom *LOM) CopyMetadata(from *LOM) {
 	lom.md.size = from.md.size
 	lom.md.version = from.md.version
 	lom.md.atime = from.md.atime
+	lom.md.customMD = from.md.customMD
+	lom.md.retainUntil = from.md.retainUntil
+	lom.md.legalHold = from.md.legalHold
 }
 
 func (lom *LOM) CloneCopiesMd() int {
@@ -289,7 +349,7 @@ func (lom *LOM) syncMetaWithCopies() (err error) {
 		}
 		lom.delCopyMd(copyFQN)
 		if err1 := fs.Access(copyFQN); err1 != nil && !os.IsNotExist(err1) {
-			lom.T.FSHC(err, copyFQN) // TODO: notify scrubber
+			lom.T.FSHC(err, copyFQN) // NOTE: the copy gets restored by the scrubber
 		}
 	}
 	return
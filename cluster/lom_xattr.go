@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -49,13 +50,18 @@ const (
 	lomObjVersion
 	lomObjSize
 	lomObjCopies
+	lomCustomMD
+	lomRetainUntil
+	lomLegalHold
+	lomCtime
 )
 
 // packing format separators
 const (
-	copyFQNSepa = "\x00"
-	recordSepa  = "\xe3/\xbd"
-	lenRecSepa  = len(recordSepa)
+	copyFQNSepa  = "\x00"
+	customMDSepa = "\x00"
+	recordSepa   = "\xe3/\xbd"
+	lenRecSepa   = len(recordSepa)
 )
 
 const prefLen = 10 // 10B prefix [ version = 1 | checksum-type | 64-bit xxhash ]
@@ -170,6 +176,8 @@ func (md *lmeta) unmarshal(buf []byte) (err error) {
 		cksumType, cksumValue             string
 		haveSize, haveVersion, haveCopies bool
 		haveCksumType, haveCksumValue     bool
+		haveCustomMD, haveRetention       bool
+		haveLegalHold, haveCtime          bool
 		last                              bool
 	)
 	if len(buf) < prefLen {
@@ -250,16 +258,51 @@ func (md *lmeta) unmarshal(buf []byte) (err error) {
 				}
 				md.copies[copyFQN] = mpathInfo
 			}
+		case lomCustomMD:
+			if haveCustomMD {
+				return errors.New(invalid + " #6")
+			}
+			entries := strings.Split(val, customMDSepa)
+			if len(entries)%2 != 0 {
+				return errors.New(invalid + " #6.1")
+			}
+			haveCustomMD = true
+			md.customMD = make(cmn.SimpleKVs, len(entries)/2)
+			for i := 0; i < len(entries); i += 2 {
+				md.customMD[entries[i]] = entries[i+1]
+			}
+		case lomRetainUntil:
+			if haveRetention {
+				return errors.New(invalid + " #7")
+			}
+			if md.retainUntil, err = strconv.ParseInt(val, 10, 64); err != nil {
+				return errors.New(invalid + " #7.1")
+			}
+			haveRetention = true
+		case lomLegalHold:
+			if haveLegalHold {
+				return errors.New(invalid + " #8")
+			}
+			md.legalHold = true
+			haveLegalHold = true
+		case lomCtime:
+			if haveCtime {
+				return errors.New(invalid + " #12")
+			}
+			if md.ctime, err = strconv.ParseInt(val, 10, 64); err != nil {
+				return errors.New(invalid + " #12.1")
+			}
+			haveCtime = true
 		default:
-			return errors.New(invalid + " #6")
+			return errors.New(invalid + " #9")
 		}
 	}
 	if haveCksumType != haveCksumValue {
-		return errors.New(invalid + " #7")
+		return errors.New(invalid + " #10")
 	}
 	md.cksum = cmn.NewCksum(cksumType, cksumValue)
 	if !haveSize {
-		return errors.New(invalid + " #8")
+		return errors.New(invalid + " #11")
 	}
 	return
 }
@@ -283,6 +326,23 @@ func (md *lmeta) marshal(mm *memsys.MMSA, mdSize int64) (buf []byte) {
 	}
 	binary.BigEndian.PutUint64(b8[:], uint64(md.size))
 	buf = _marshRecord(mm, buf, lomObjSize, string(b8[:]), false)
+	if len(md.customMD) > 0 {
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomCustomMD, "", false)
+		buf = _marshCustomMD(mm, buf, md.customMD)
+	}
+	if md.retainUntil != 0 {
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomRetainUntil, strconv.FormatInt(md.retainUntil, 10), false)
+	}
+	if md.legalHold {
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomLegalHold, "", false)
+	}
+	if md.ctime != 0 {
+		buf = mm.Append(buf, recordSepa)
+		buf = _marshRecord(mm, buf, lomCtime, strconv.FormatInt(md.ctime, 10), false)
+	}
 	if len(md.copies) > 0 {
 		buf = mm.Append(buf, recordSepa)
 		buf = _marshRecord(mm, buf, lomObjCopies, "", false)
@@ -323,3 +383,20 @@ func _marshCopies(mm *memsys.MMSA, buf []byte, copies fs.MPI) []byte {
 	}
 	return buf
 }
+
+func _marshCustomMD(mm *memsys.MMSA, buf []byte, md cmn.SimpleKVs) []byte {
+	var (
+		i   int
+		num = len(md)
+	)
+	for k, v := range md {
+		i++
+		buf = mm.Append(buf, k)
+		buf = mm.Append(buf, customMDSepa)
+		buf = mm.Append(buf, v)
+		if i < num {
+			buf = mm.Append(buf, customMDSepa)
+		}
+	}
+	return buf
+}
@@ -6,6 +6,7 @@ package cluster_test
 
 import (
 	"os"
+	"time"
 
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
@@ -93,6 +94,60 @@ var _ = Describe("LOM Xattributes", func() {
 				Expect(lom.GetCopies()).To(BeEquivalentTo(newLom.GetCopies()))
 			})
 
+			It("should save cryptographic checksums to disk", func() {
+				for _, cksumType := range []string{cmn.ChecksumSHA256, cmn.ChecksumBLAKE3} {
+					lom := filePut(localFQN, testFileSize, tMock)
+					cksum, err := lom.ComputeCksum(cksumType)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cksum.Value()).To(HaveLen(64))
+					lom.SetCksum(cksum.Clone())
+					Expect(lom.Persist()).NotTo(HaveOccurred())
+
+					lom.Uncache()
+					newLom := NewBasicLom(localFQN, tMock)
+					Expect(newLom.Load(false)).NotTo(HaveOccurred())
+					Expect(newLom.Cksum()).To(BeEquivalentTo(lom.Cksum()))
+					Expect(newLom.Cksum().Type()).To(Equal(cksumType))
+				}
+			})
+
+			It("should save user-defined metadata to disk", func() {
+				customMD := cmn.SimpleKVs{"author": "unknown", "empty": "", "source": "s3://bucket/obj"}
+				lom := filePut(localFQN, testFileSize, tMock)
+				lom.SetCustomMD(customMD)
+				Expect(lom.AddCopy(fqns[0], copyMpathInfo)).NotTo(HaveOccurred())
+
+				lom.Uncache()
+				newLom := NewBasicLom(localFQN, tMock)
+				err := newLom.Load(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newLom.CustomMD()).To(BeEquivalentTo(customMD))
+				Expect(newLom.GetCopies()).To(BeEquivalentTo(lom.GetCopies()))
+			})
+
+			It("should save retention to disk", func() {
+				retainUntil := time.Now().Add(time.Hour).UnixNano()
+				lom := filePut(localFQN, testFileSize, tMock)
+				lom.SetRetainUntil(retainUntil)
+				lom.SetLegalHold(true)
+				Expect(lom.Persist()).NotTo(HaveOccurred())
+
+				lom.Uncache()
+				newLom := NewBasicLom(localFQN, tMock)
+				err := newLom.Load(false)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(newLom.RetainUntil()).To(Equal(retainUntil))
+				Expect(newLom.LegalHold()).To(BeTrue())
+				err = newLom.AllowModify(true)
+				Expect(cmn.IsErrObjRetained(err)).To(BeTrue())
+
+				newLom.SetLegalHold(false)
+				Expect(cmn.IsErrObjRetained(newLom.AllowModify(false))).To(BeTrue())
+				Expect(newLom.AllowModify(true)).NotTo(HaveOccurred()) // governance mode
+				newLom.SetRetainUntil(time.Now().Add(-time.Hour).UnixNano())
+				Expect(newLom.AllowModify(false)).NotTo(HaveOccurred())
+			})
+
 			It("should override old values", func() {
 				lom := filePut(localFQN, testFileSize, tMock)
 				lom.SetCksum(cmn.NewCksum(cmn.ChecksumXXHash, "test_checksum"))
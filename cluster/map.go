@@ -42,6 +42,7 @@ type (
 		PublicNet       NetInfo `json:"public_net"`        // cmn.NetworkPublic
 		IntraControlNet NetInfo `json:"intra_control_net"` // cmn.NetworkIntraControl
 		IntraDataNet    NetInfo `json:"intra_data_net"`    // cmn.NetworkIntraData
+		Zone            string  `json:"zone,omitempty"`    // failure domain (e.g., rack or availability zone)
 		idDigest        uint64
 		name            string
 		LocalNet        *net.IPNet `json:"-"`
@@ -109,7 +110,7 @@ func (d *Snode) URL(network string) string {
 }
 
 func (a *Snode) Equals(b *Snode) bool {
-	return a.ID() == b.ID() && a.DaemonType == b.DaemonType &&
+	return a.ID() == b.ID() && a.DaemonType == b.DaemonType && a.Zone == b.Zone &&
 		reflect.DeepEqual(a.PublicNet, b.PublicNet) &&
 		reflect.DeepEqual(a.IntraControlNet, b.IntraControlNet) &&
 		reflect.DeepEqual(a.IntraDataNet, b.IntraDataNet)
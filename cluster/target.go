@@ -86,7 +86,9 @@ type Target interface {
 
 	GetObject(w io.Writer, lom *LOM, started time.Time) error
 	PutObject(params PutObjectParams) error
+	PutObjectToTarget(lom *LOM, r io.Reader, size int64, si *Snode) error
 	CopyObject(lom *LOM, bckTo *Bck, buf []byte, localOnly bool) (bool, error)
+	DeleteObject(ctx context.Context, lom *LOM, evict bool) (error, int)
 	GetCold(ctx context.Context, lom *LOM, prefetch bool) (error, int)
 	PromoteFile(srcFQN string, bck *Bck, objName string, cksum *cmn.Cksum, overwrite, safe, verbose bool) (err error)
 	LookupRemoteSingle(lom *LOM, si *Snode) bool
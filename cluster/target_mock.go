@@ -66,3 +66,9 @@ func (*TargetMock) RebalanceInfo() RebalanceInfo {
 func (*TargetMock) CheckCloudVersion(_ context.Context, _ *LOM) (bool, error, int) {
 	return false, nil, 0
 }
+func (*TargetMock) PutObjectToTarget(_ *LOM, _ io.Reader, _ int64, _ *Snode) error {
+	return nil
+}
+func (*TargetMock) DeleteObject(_ context.Context, _ *LOM, _ bool) (error, int) {
+	return nil, 0
+}
@@ -28,6 +28,9 @@ func init() {
 	if conf.Auth.ExpirePeriod == 0 {
 		conf.Auth.ExpirePeriod = time.Minute * 30
 	}
+	if conf.Auth.Secret == "" {
+		conf.Auth.Secret = "aBitLongSecretKey"
+	}
 }
 
 func createUsers(mgr *userManager, t *testing.T) {
@@ -226,6 +229,57 @@ func TestToken(t *testing.T) {
 	deleteUsers(mgr, false, t)
 }
 
+func TestAccessKeys(t *testing.T) {
+	mgr := newUserManager(dbPath)
+	if mgr == nil {
+		t.Fatal("Manager has not been created")
+	}
+	createUsers(mgr, t)
+	defer os.Remove(mgr.keysPath)
+
+	_, err := mgr.addAccessKey("someuser")
+	if err == nil {
+		t.Error("Access key generated for non-existing user")
+	}
+	pair1, err := mgr.addAccessKey(users[0])
+	tassert.CheckFatal(t, err)
+	pair2, err := mgr.addAccessKey(users[1])
+	tassert.CheckFatal(t, err)
+	if pair1.AccessKey == pair2.AccessKey || pair1.SecretKey == pair2.SecretKey {
+		t.Errorf("Duplicated access keys generated: %+v, %+v", pair1, pair2)
+	}
+	if len(pair1.AccessKey) != accessKeyLen || pair1.SecretKey == "" {
+		t.Errorf("Invalid access key generated: %+v", pair1)
+	}
+
+	keys := mgr.userAccessKeys(users[0])
+	if len(keys) != 1 || keys[0].AccessKey != pair1.AccessKey {
+		t.Errorf("Invalid access keys of %s: %+v", users[0], keys)
+	}
+	keyList := mgr.accessKeyList()
+	if key, ok := keyList.Keys[pair2.AccessKey]; !ok || key.UserID != users[1] {
+		t.Errorf("Access key %s not found in the list pushed to clusters", pair2.AccessKey)
+	} else if key.Secret == pair2.SecretKey || strings.Contains(key.Secret, pair2.SecretKey) {
+		t.Errorf("Secret key of %s is not encrypted", pair2.AccessKey)
+	}
+
+	// reload from file
+	newmgr := newUserManager(dbPath)
+	if len(newmgr.userAccessKeys("")) != 2 {
+		t.Errorf("Expected 2 access keys after reload, found %d", len(newmgr.userAccessKeys("")))
+	}
+
+	tassert.CheckError(t, mgr.delAccessKey(pair1.AccessKey))
+	if err := mgr.delAccessKey(pair1.AccessKey); err == nil {
+		t.Errorf("Access key %s deleted twice", pair1.AccessKey)
+	}
+	// deleting a user deletes all user's access keys
+	deleteUsers(mgr, false, t)
+	if keys := mgr.userAccessKeys(""); len(keys) != 0 {
+		t.Errorf("Access keys of deleted users remain: %+v", keys)
+	}
+}
+
 func clusterList(t *testing.T) {
 	cluList := &clusterConfig{
 		Conf: map[string][]string{
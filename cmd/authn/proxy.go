@@ -29,12 +29,32 @@ func (m *userManager) broadcastRevoked(token string) {
 	tokenList := ais.TokenList{Tokens: []string{token}}
 	body := cmn.MustMarshal(tokenList)
 
-	m.broadcast(http.MethodDelete, cmn.Tokens, body)
+	m.broadcast(http.MethodDelete, cmn.Tokens, body, "")
+}
+
+// push the full list of access keys to all clusters
+func (m *userManager) broadcastAccessKeys() {
+	conf.Cluster.mtx.RLock()
+	if len(conf.Cluster.Conf) == 0 {
+		conf.Cluster.mtx.RUnlock()
+		glog.Warning("primary proxy is not defined")
+		return
+	}
+	conf.Cluster.mtx.RUnlock()
+
+	token, err := adminToken()
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	body := cmn.MustMarshal(m.accessKeyList())
+	m.broadcast(http.MethodPut, cmn.AccessKeys, body, token)
 }
 
 // broadcast the request to all clusters. If a cluster has a few URLS,
 // it sends to the first working one. Clusters are processed in parallel.
-func (m *userManager) broadcast(method, path string, body []byte) {
+// If the token is defined, the requests are authorized with it.
+func (m *userManager) broadcast(method, path string, body []byte, token string) {
 	conf.Cluster.mtx.RLock()
 	defer conf.Cluster.mtx.RUnlock()
 	wg := &sync.WaitGroup{}
@@ -44,12 +64,12 @@ func (m *userManager) broadcast(method, path string, body []byte) {
 			defer wg.Done()
 			var err error
 			for _, u := range urls {
-				if err = m.proxyRequest(method, u, path, body); err == nil {
+				if err = m.proxyRequest(method, u, path, body, token); err == nil {
 					break
 				}
 			}
 			if err != nil {
-				glog.Errorf("Failed to sync revoked tokens with %q: %v", cid, err)
+				glog.Errorf("Failed to sync %s with %q: %v", path, cid, err)
 			}
 		}(cid, urls)
 	}
@@ -57,7 +77,7 @@ func (m *userManager) broadcast(method, path string, body []byte) {
 }
 
 // Generic function to send everything to a proxy
-func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte) error {
+func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte, token string) error {
 	startRequest := time.Now()
 	for {
 		url := proxyURL + cmn.URLPath(cmn.Version, path)
@@ -71,6 +91,9 @@ func (m *userManager) proxyRequest(method, proxyURL, path string, injson []byte)
 			client = m.clientHTTPS
 		}
 		request.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			request.Header.Set(cmn.HeaderAuthorization, cmn.HeaderBearer+" "+token)
+		}
 		response, err := client.Do(request)
 		var respCode int
 		if response != nil {
@@ -22,6 +22,7 @@ const (
 	pathUsers    = "users"
 	pathTokens   = "tokens"
 	pathClusters = "clusters"
+	pathKeys     = "accesskeys"
 )
 
 // a message to generate token
@@ -41,6 +42,16 @@ type tokenMsg struct {
 	Token string `json:"token"`
 }
 
+// a message to generate a new access key pair for S3 requests
+// POST: <version>/<pathKeys>
+//		Body: <keyMsg>
+//	Returns: <accessKeyPair>
+// list access keys: GET <version>/<pathKeys>[?user=<username>]
+// delete access key: DEL <version>/<pathKeys>/<access-key>
+type keyMsg struct {
+	UserID string `json:"user"`
+}
+
 //-------------------------------------
 // global functions (borrowed from ais)
 //-------------------------------------
@@ -126,6 +137,7 @@ func (a *authServ) registerPublicHandlers() {
 	a.registerHandler(cmn.URLPath(cmn.Version, pathUsers), a.userHandler)
 	a.registerHandler(cmn.URLPath(cmn.Version, pathTokens), a.tokenHandler)
 	a.registerHandler(cmn.URLPath(cmn.Version, pathClusters), a.clusterHandler)
+	a.registerHandler(cmn.URLPath(cmn.Version, pathKeys), a.keyHandler)
 }
 
 func (a *authServ) userHandler(w http.ResponseWriter, r *http.Request) {
@@ -165,6 +177,19 @@ func (a *authServ) clusterHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (a *authServ) keyHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		a.httpKeyGet(w, r)
+	case http.MethodPost:
+		a.httpKeyPost(w, r)
+	case http.MethodDelete:
+		a.httpKeyDel(w, r)
+	default:
+		cmn.InvalidHandlerWithMsg(w, r, "Unsupported method for /accesskeys handler")
+	}
+}
+
 // Deletes existing token, a.k.a log out
 func (a *authServ) httpRevokeToken(w http.ResponseWriter, r *http.Request) {
 	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathTokens); err != nil {
@@ -348,6 +373,61 @@ func (a *authServ) writeJSON(w http.ResponseWriter, r *http.Request, jsbytes []b
 	glog.Errorf("%s: failed to write json, err: %v", tag, err)
 }
 
+// Generates a new access key pair for a user
+func (a *authServ) httpKeyPost(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathKeys); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	msg := &keyMsg{}
+	if err := cmn.ReadJSON(w, r, msg); err != nil {
+		glog.Errorf("Failed to read request body: %v\n", err)
+		return
+	}
+	if msg.UserID == "" {
+		cmn.InvalidHandlerWithMsg(w, r, "User name is not defined")
+		return
+	}
+	pair, err := a.users.addAccessKey(msg.UserID)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("Failed to add access key: %v", err))
+		return
+	}
+	if glog.V(4) {
+		glog.Infof("Added access key %s for user %s\n", pair.AccessKey, msg.UserID)
+	}
+	a.writeJSON(w, r, cmn.MustMarshal(pair), "add access key")
+}
+
+func (a *authServ) httpKeyGet(w http.ResponseWriter, r *http.Request) {
+	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathKeys); err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	keys := a.users.userAccessKeys(r.URL.Query().Get("user"))
+	a.writeJSON(w, r, cmn.MustMarshal(keys), "list access keys")
+}
+
+func (a *authServ) httpKeyDel(w http.ResponseWriter, r *http.Request) {
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, pathKeys)
+	if err != nil {
+		return
+	}
+	if err := a.checkAuthorization(w, r); err != nil {
+		glog.Errorf("Not authorized: %v\n", err)
+		return
+	}
+	if err := a.users.delAccessKey(apiItems[0]); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusNotFound)
+	}
+}
+
 func (a *authServ) httpSrvPost(w http.ResponseWriter, r *http.Request) {
 	if _, err := checkRESTItems(w, r, 0, cmn.Version, pathClusters); err != nil {
 		return
@@ -378,6 +458,7 @@ func (a *authServ) httpSrvPost(w http.ResponseWriter, r *http.Request) {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	go a.users.broadcastAccessKeys()
 }
 
 func (a *authServ) httpSrvPut(w http.ResponseWriter, r *http.Request) {
@@ -410,6 +491,7 @@ func (a *authServ) httpSrvPut(w http.ResponseWriter, r *http.Request) {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	go a.users.broadcastAccessKeys()
 }
 
 func (a *authServ) httpSrvDelete(w http.ResponseWriter, r *http.Request) {
@@ -6,15 +6,20 @@
 package main
 
 import (
+	"crypto/rand"
+	"encoding/base32"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/ais"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/cmn/jsp"
 	jwt "github.com/dgrijalva/jwt-go"
@@ -22,9 +27,13 @@ import (
 
 const (
 	userListFile     = "users.json"
+	keyListFile      = "keys.json"
+	accessKeyLen     = 20                        // length of generated access key ID
+	secretKeyBytes   = 30                        // number of random bytes in generated secret key
 	proxyTimeout     = 2 * time.Minute           // maximum time for syncing Authn data with primary proxy
 	proxyRetryTime   = 5 * time.Second           // an interval between primary proxy detection attempts
 	foreverTokenTime = 24 * 365 * 20 * time.Hour // kind of never-expired token
+	adminTokenTime   = 10 * time.Minute          // lifetime of the token that authn uses to push access keys
 )
 
 type (
@@ -39,11 +48,23 @@ type (
 		Expires time.Time `json:"expires"`
 		Token   string    `json:"token"`
 	}
+	// access key without secret, returned by list request
+	accessKeyInfo struct {
+		AccessKey string `json:"access_key"`
+		UserID    string `json:"user"`
+	}
+	// newly generated access key pair, the only response that includes secret
+	accessKeyPair struct {
+		AccessKey string `json:"access_key"`
+		SecretKey string `json:"secret_key"`
+	}
 	userManager struct {
 		mtx         sync.Mutex
 		Path        string               `json:"-"`
 		Users       map[string]*userInfo `json:"users"`
 		tokens      map[string]*tokenInfo
+		keysPath    string
+		keys        map[string]*ais.AccessKey // access key ID => key
 		clientHTTP  *http.Client
 		clientHTTPS *http.Client
 	}
@@ -66,9 +87,14 @@ func newUserManager(dbPath string) *userManager {
 		Path:        dbPath,
 		Users:       make(map[string]*userInfo, 10),
 		tokens:      make(map[string]*tokenInfo, 10),
+		keysPath:    filepath.Join(filepath.Dir(dbPath), keyListFile),
+		keys:        make(map[string]*ais.AccessKey, 10),
 		clientHTTP:  clientHTTP,
 		clientHTTPS: clientHTTPS,
 	}
+	if err = jsp.Load(mgr.keysPath, &mgr.keys, jsp.Plain()); err != nil && !os.IsNotExist(err) {
+		glog.Fatalf("Failed to load access key list: %v\n", err)
+	}
 	if _, err = os.Stat(dbPath); err != nil {
 		if !os.IsNotExist(err) {
 			glog.Fatalf("Failed to load user list: %v\n", err)
@@ -149,11 +175,26 @@ func (m *userManager) delUser(userID string) error {
 	token, ok := m.tokens[userID]
 	delete(m.tokens, userID)
 	err := m.saveUsers()
+	keysDeleted := 0
+	for id, key := range m.keys {
+		if key.UserID == userID {
+			delete(m.keys, id)
+			keysDeleted++
+		}
+	}
+	if keysDeleted != 0 {
+		if errKeys := m.saveKeys(); err == nil {
+			err = errKeys
+		}
+	}
 	m.mtx.Unlock()
 
 	if ok {
 		go m.broadcastRevoked(token.Token)
 	}
+	if keysDeleted != 0 {
+		go m.broadcastAccessKeys()
+	}
 
 	return err
 }
@@ -206,6 +247,7 @@ func (m *userManager) issueToken(userID, pwd string) (string, error) {
 		"issued":   issued.Format(time.RFC822),
 		"expires":  expires.Format(time.RFC822),
 		"username": userID,
+		"admin":    userID == conf.Auth.Username,
 	})
 	tokenString, err := t.SignedString([]byte(conf.Auth.Secret))
 	if err != nil {
@@ -223,6 +265,23 @@ func (m *userManager) issueToken(userID, pwd string) (string, error) {
 	return tokenString, nil
 }
 
+// Generates a short-lived token of the superuser: AIS proxies require it to
+// accept the list of access keys
+func adminToken() (string, error) {
+	issued := time.Now()
+	t := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"issued":   issued.Format(time.RFC822),
+		"expires":  issued.Add(adminTokenTime).Format(time.RFC822),
+		"username": conf.Auth.Username,
+		"admin":    true,
+	})
+	tokenString, err := t.SignedString([]byte(conf.Auth.Secret))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %v", err)
+	}
+	return tokenString, nil
+}
+
 // Delete existing token, a.k.a log out
 // If the token was removed successfully then it sends the proxy a new valid token list
 func (m *userManager) revokeToken(token string) {
@@ -262,3 +321,105 @@ func (m *userManager) userByToken(token string) (*userInfo, error) {
 
 	return nil, fmt.Errorf("token not found")
 }
+
+// save access key list to file; the caller must hold the lock
+func (m *userManager) saveKeys() error {
+	if err := jsp.Save(m.keysPath, &m.keys, jsp.Plain()); err != nil {
+		return fmt.Errorf("failed to save access key list: %v", err)
+	}
+	return nil
+}
+
+// Generates a new access key pair for an existing user. The secret key is
+// returned only once, it cannot be retrieved later
+func (m *userManager) addAccessKey(userID string) (*accessKeyPair, error) {
+	m.mtx.Lock()
+	if _, ok := m.Users[userID]; !ok {
+		m.mtx.Unlock()
+		return nil, fmt.Errorf("user %s %s", userID, cmn.DoesNotExist)
+	}
+	pair, err := genAccessKeyPair()
+	if err != nil {
+		m.mtx.Unlock()
+		return nil, err
+	}
+	if _, ok := m.keys[pair.AccessKey]; ok {
+		m.mtx.Unlock()
+		return nil, errors.New("failed to generate unique access key, try again")
+	}
+	sealed, err := ais.SealAccessSecret(conf.Auth.Secret, pair.SecretKey)
+	if err != nil {
+		m.mtx.Unlock()
+		return nil, fmt.Errorf("failed to encrypt secret key: %v", err)
+	}
+	m.keys[pair.AccessKey] = &ais.AccessKey{UserID: userID, Secret: sealed}
+	if err = m.saveKeys(); err != nil {
+		delete(m.keys, pair.AccessKey)
+		m.mtx.Unlock()
+		return nil, err
+	}
+	m.mtx.Unlock()
+
+	go m.broadcastAccessKeys()
+	return pair, nil
+}
+
+// Deletes an existing access key
+func (m *userManager) delAccessKey(accessKey string) error {
+	m.mtx.Lock()
+	if _, ok := m.keys[accessKey]; !ok {
+		m.mtx.Unlock()
+		return fmt.Errorf("access key %s %s", accessKey, cmn.DoesNotExist)
+	}
+	delete(m.keys, accessKey)
+	err := m.saveKeys()
+	m.mtx.Unlock()
+
+	go m.broadcastAccessKeys()
+	return err
+}
+
+// Returns access keys (without secrets) of a user or of all users if
+// userID is empty
+func (m *userManager) userAccessKeys(userID string) []*accessKeyInfo {
+	m.mtx.Lock()
+	list := make([]*accessKeyInfo, 0, len(m.keys))
+	for id, key := range m.keys {
+		if userID == "" || key.UserID == userID {
+			list = append(list, &accessKeyInfo{AccessKey: id, UserID: key.UserID})
+		}
+	}
+	m.mtx.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].UserID != list[j].UserID {
+			return list[i].UserID < list[j].UserID
+		}
+		return list[i].AccessKey < list[j].AccessKey
+	})
+	return list
+}
+
+// Returns a copy of the full access key list to push to clusters
+func (m *userManager) accessKeyList() *ais.AccessKeyList {
+	m.mtx.Lock()
+	keyList := &ais.AccessKeyList{Keys: make(map[string]*ais.AccessKey, len(m.keys))}
+	for id, key := range m.keys {
+		keyList.Keys[id] = &ais.AccessKey{UserID: key.UserID, Secret: key.Secret, Admin: key.UserID == conf.Auth.Username}
+	}
+	m.mtx.Unlock()
+	return keyList
+}
+
+// Access key ID is an uppercase alphanumeric string similar to AWS ones,
+// secret key is a base64-encoded random string
+func genAccessKeyPair() (*accessKeyPair, error) {
+	b := make([]byte, accessKeyLen+secretKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate access key: %v", err)
+	}
+	id := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b[:accessKeyLen])
+	return &accessKeyPair{
+		AccessKey: id[:accessKeyLen],
+		SecretKey: base64.StdEncoding.EncodeToString(b[accessKeyLen:]),
+	}, nil
+}
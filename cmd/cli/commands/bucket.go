@@ -54,12 +54,12 @@ func validateBucket(c *cli.Context, bck cmn.Bck, tag string, optional bool) (cmn
 }
 
 // Creates new ais buckets
-func createBuckets(c *cli.Context, buckets []cmn.Bck) (err error) {
+func createBuckets(c *cli.Context, buckets []cmn.Bck, extra ...cmn.ExtraProps) (err error) {
 	// TODO: on "soft" error (bucket already exists) we will
 	// emit zero exit code - this may be problematic when using
 	// in scripts.
 	for _, bck := range buckets {
-		if err = api.CreateBucket(defaultAPIParams, bck); err != nil {
+		if err = api.CreateBucket(defaultAPIParams, bck, extra...); err != nil {
 			if herr, ok := err.(*cmn.HTTPError); ok {
 				if herr.Status == http.StatusConflict {
 					fmt.Fprintf(c.App.Writer, "Bucket %q already exists\n", bck)
@@ -168,6 +168,9 @@ func listBucketObj(c *cli.Context, bck cmn.Bck) error {
 	}
 
 	msg := &cmn.SelectMsg{Props: props, Prefix: prefix, Cached: flagIsSet(c, cachedFlag)}
+	if flagIsSet(c, delimiterFlag) {
+		msg.Delimiter = parseStrFlag(c, delimiterFlag)
+	}
 	query := url.Values{}
 	query = cmn.AddBckToQuery(query, bck)
 	query.Add(cmn.URLParamPrefix, prefix)
@@ -460,6 +463,15 @@ func printBckHeadTable(c *cli.Context, props cmn.BucketProps, section string) er
 			{"ec", props.EC.String()},
 			{"lru", props.LRU.String()},
 			{"versioning", props.Versioning.String()},
+			{"retention", props.Retention.String()},
+			{"lifecycle", props.Lifecycle.String()},
+			{"replication", props.Replication.String()},
+		}
+		if props.Provider == cmn.ProviderHTTP {
+			propList = append(propList, prop{"original_url", props.Extra.OrigURLBck}, prop{"manifest", props.Extra.Manifest})
+		}
+		if props.Provider == cmn.ProviderFS {
+			propList = append(propList, prop{"path", props.Extra.Path})
 		}
 	}
 
@@ -32,6 +32,7 @@ const (
 	commandPut       = "put"
 	commandRemove    = "rm"
 	commandRename    = "rename"
+	commandResume    = "resume"
 	commandSet       = "set"
 	commandSetCopies = "set-copies"
 	commandShow      = "show"
@@ -57,6 +58,7 @@ const (
 	subcmdMountpath = "mountpath"
 	subcmdCluster   = "cluster"
 	subcmdPrimary   = "primary"
+	subcmdRetention = "retention"
 
 	// Show subcommands
 	subcmdShowBucket    = subcmdBucket
@@ -98,10 +100,14 @@ const (
 	subcmdStopDsort    = subcmdDsort
 	subcmdStopDownload = subcmdDownload
 
+	// Resume subcommands
+	subcmdResumeDsort = subcmdDsort
+
 	// Set subcommand
-	subcmdSetConfig  = subcmdConfig
-	subcmdSetProps   = subcmdProps
-	subcmdSetPrimary = subcmdPrimary
+	subcmdSetConfig    = subcmdConfig
+	subcmdSetProps     = subcmdProps
+	subcmdSetPrimary   = subcmdPrimary
+	subcmdSetRetention = subcmdRetention
 
 	// Attach/Detach subcommand
 	subcmdAttachRemoteAIS = subcmdRemoteAIS
@@ -180,6 +186,9 @@ const (
 	addAuthClusterArgument    = "CLUSTER_ID URL[,URL...]"
 	deleteAuthClusterArgument = "CLUSTER_ID"
 	showAuthClusterArgument   = "[CLUSTER_ID]"
+	addAuthKeyArgument        = "USER_NAME"
+	deleteAuthKeyArgument     = "ACCESS_KEY"
+	showAuthKeyArgument       = "[USER_NAME]"
 )
 
 // Flags
@@ -199,6 +208,7 @@ var (
 	// Bucket
 	jsonspecFlag      = cli.StringFlag{Name: "jsonspec", Usage: "bucket properties in JSON format"}
 	markerFlag        = cli.StringFlag{Name: "marker", Usage: "list objects alphabetically starting from the object after the marker"}
+	delimiterFlag     = cli.StringFlag{Name: "delimiter", Usage: "roll up object names that contain the delimiter after the prefix into a single \"directory\" entry, e.g. \"/\""}
 	objLimitFlag      = cli.IntFlag{Name: "limit", Usage: "limit object count", Value: 0}
 	pageSizeFlag      = cli.IntFlag{Name: "page-size", Usage: "maximum number of entries by list objects call", Value: 1000}
 	templateFlag      = cli.StringFlag{Name: "template", Usage: "template for matching object names"}
@@ -210,6 +220,12 @@ var (
 	pagedFlag         = cli.BoolFlag{Name: "paged", Usage: "fetch and print the bucket list page by page, ignored in fast mode"}
 	showUnmatchedFlag = cli.BoolTFlag{Name: "show-unmatched", Usage: "list objects that were not matched by regex and template"}
 	activeFlag        = cli.BoolFlag{Name: "active", Usage: "show only running xactions"}
+	xactHistoryFlag   = cli.BoolFlag{Name: "history", Usage: "show finished xactions recorded in the (persistent) xaction history of each target"}
+	sinceFlag         = cli.StringFlag{Name: "since", Usage: "show only xactions finished at or after given time: RFC3339 time or duration, eg. '24h' (finished in the last 24 hours)"}
+	untilFlag         = cli.StringFlag{Name: "until", Usage: "show only xactions finished before given time: RFC3339 time or duration, eg. '1h' (finished more than an hour ago)"}
+	origURLFlag       = cli.StringFlag{Name: "original-url", Usage: "base URL of the HTTP(S) origin of 'ht://' bucket"}
+	manifestFlag      = cli.StringFlag{Name: "manifest", Usage: "URL (or path relative to the original URL) of the file that lists objects of 'ht://' bucket"}
+	bckPathFlag       = cli.StringFlag{Name: "path", Usage: "absolute path to the directory of 'fs://' bucket (must be the same on all targets)"}
 
 	// Daeclu
 	countFlag = cli.IntFlag{Name: "count", Usage: "total number of generated reports", Value: countDefault}
@@ -220,6 +236,11 @@ var (
 	limitConnectionsFlag  = cli.IntFlag{Name: "limit-connections,conns", Usage: "number of connections each target can make concurrently (each target can handle at most #mountpaths connections)"}
 	limitBytesPerHourFlag = cli.StringFlag{Name: "limit-bytes-per-hour,limit-bph,bph", Usage: "number of bytes (can end with suffix (k, MB, GiB, ...)) that all targets can maximally download in hour"}
 	objectsListFlag       = cli.StringFlag{Name: "object-list,from", Usage: "path to file containing JSON array of strings with object names to download"}
+	dlManifestFlag        = cli.StringFlag{Name: "manifest", Usage: "path to file containing JSON array of manifest entries (object name, link, size and md5/sha256/crc32c checksums) to download and verify"}
+	dlHeaderFlag          = cli.StringSliceFlag{Name: "header,H", Usage: "custom request header sent to the source, eg. 'X-Api-Key: secret' (can be repeated)"}
+	dlAuthUserFlag        = cli.StringFlag{Name: "auth-user", Usage: "username for basic authentication to the source (password is read from " + dlAuthPasswordEnv + " or prompted)"}
+	dlAuthTokenFlag       = cli.StringFlag{Name: "auth-token", Usage: "bearer token to access the source (default: " + dlAuthTokenEnv + ")"}
+	dlExtractFlag         = cli.BoolFlag{Name: "extract", Usage: "store the members of downloaded archives (.tar, .tar.gz, .tgz, .zip) as objects '<archive-name-without-extension>/<member-path>' instead of the archives"}
 
 	// dSort
 	dsortBucketFlag   = cli.StringFlag{Name: "bucket", Value: cmn.DSortNameLowercase + "-testing", Usage: "bucket where shards will be put"}
@@ -243,8 +264,14 @@ var (
 	overwriteFlag = cli.BoolFlag{Name: "overwrite,o", Usage: "overwrite destination if exists"}
 	targetFlag    = cli.StringFlag{Name: "target", Usage: "ais target ID"}
 	yesFlag       = cli.BoolFlag{Name: "yes,y", Usage: "assume 'yes' for all questions"}
+	customMDFlag  = cli.StringFlag{Name: "custom", Usage: "user-defined object metadata, comma-separated key=value pairs, e.g.: 'author=john,source=camera'"}
 	chunkSizeFlag = cli.StringFlag{Name: "chunk-size", Usage: "chunk size used for each request, can contain prefix 'b', 'KiB', 'MB'", Value: "10MB"}
 
+	// Object retention
+	retainUntilFlag      = cli.StringFlag{Name: "retain-until", Usage: "retain object until the specified time (RFC3339), e.g.: '2021-01-01T00:00:00Z'"}
+	legalHoldFlag        = cli.StringFlag{Name: "legal-hold", Usage: "place ('on') or remove ('off') legal hold (requires admin permissions)"}
+	bypassGovernanceFlag = cli.BoolFlag{Name: "bypass-governance", Usage: "shorten governance-mode retention (requires admin permissions)"}
+
 	longRunFlags = []cli.Flag{refreshFlag, countFlag}
 
 	baseLstRngFlags = []cli.Flag{
@@ -30,6 +30,11 @@ var (
 			descriptionFlag,
 			limitConnectionsFlag,
 			objectsListFlag,
+			dlManifestFlag,
+			dlHeaderFlag,
+			dlAuthUserFlag,
+			dlAuthTokenFlag,
+			dlExtractFlag,
 		},
 		subcmdStartDsort: {
 			specFileFlag,
@@ -102,6 +107,19 @@ var (
 				},
 			},
 		},
+		{
+			Name:  commandResume,
+			Usage: "resumes aborted jobs",
+			Subcommands: []cli.Command{
+				{
+					Name:         subcmdResumeDsort,
+					Usage:        fmt.Sprintf("resumes aborted %s job with given ID", cmn.DSortName),
+					ArgsUsage:    jobIDArgument,
+					Action:       resumeDsortHandler,
+					BashComplete: dsortIDAbortedCompletions,
+				},
+			},
+		},
 	}
 )
 
@@ -168,6 +186,7 @@ func startDownloadHandler(c *cli.Context) error {
 		description     = parseStrFlag(c, descriptionFlag)
 		timeout         = parseStrFlag(c, timeoutFlag)
 		objectsListPath = parseStrFlag(c, objectsListFlag)
+		manifestPath    = parseStrFlag(c, dlManifestFlag)
 		id              string
 	)
 
@@ -214,9 +233,47 @@ func startDownloadHandler(c *cli.Context) error {
 			Connections:  parseIntFlag(c, limitConnectionsFlag),
 			BytesPerHour: int(limitBPH),
 		},
+		Extract: flagIsSet(c, dlExtractFlag),
+	}
+
+	if basePayload.Headers, basePayload.Credentials, err = parseDlSourceAuth(c); err != nil {
+		return err
+	}
+	if err := basePayload.Validate(); err != nil {
+		return err
 	}
 
-	if objectsListPath != "" {
+	if objectsListPath != "" && manifestPath != "" {
+		return incorrectUsageMsg(c, "--object-list and --manifest flags cannot be used together")
+	}
+
+	if manifestPath != "" {
+		var manifest []downloader.DlManifestObj
+		{
+			file, err := os.Open(manifestPath)
+			if err != nil {
+				return err
+			}
+			err = jsoniter.NewDecoder(file).Decode(&manifest)
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("%q file doesn't seem to contain JSON array of manifest entries: %v", manifestPath, err)
+			}
+		}
+		if len(manifest) == 0 {
+			return fmt.Errorf("manifest %q is empty", manifestPath)
+		}
+		for i := range manifest {
+			// Links which are not full URLs are relative to the source.
+			if manifest[i].Link != "" && !strings.Contains(manifest[i].Link, "://") {
+				manifest[i].Link = link + "/" + manifest[i].Link
+			}
+		}
+		payload := downloader.DlMultiBody{
+			DlBase: basePayload,
+		}
+		id, err = api.DownloadMultiWithParam(defaultAPIParams, payload, manifest)
+	} else if objectsListPath != "" {
 		var objects []string
 		{
 			file, err := os.Open(objectsListPath)
@@ -354,3 +411,18 @@ func stopDsortHandler(c *cli.Context) (err error) {
 	fmt.Fprintf(c.App.Writer, "%s job %q successfully stopped\n", cmn.DSortName, id)
 	return
 }
+
+func resumeDsortHandler(c *cli.Context) (err error) {
+	id := c.Args().First()
+
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, cmn.DSortName+" job ID")
+	}
+
+	if err = api.ResumeDSort(defaultAPIParams, id); err != nil {
+		return
+	}
+
+	fmt.Fprintf(c.App.Writer, "%s job %q successfully resumed\n", cmn.DSortName, id)
+	return
+}
@@ -6,22 +6,29 @@
 package commands
 
 import (
+	"fmt"
+
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/urfave/cli"
 )
 
 var (
 	createCmdsFlags = map[string][]cli.Flag{
-		subcmdCreateBucket: {},
+		subcmdCreateBucket: {
+			origURLFlag,
+			manifestFlag,
+			bckPathFlag,
+		},
 	}
 
 	createCmds = []cli.Command{
 		{
 			Name:  commandCreate,
-			Usage: "create ais buckets",
+			Usage: "create ais, ht, and fs buckets",
 			Subcommands: []cli.Command{
 				{
 					Name:      subcmdCreateBucket,
-					Usage:     "create ais buckets",
+					Usage:     "create ais, ht, and fs buckets",
 					ArgsUsage: bucketsArgument,
 					Flags:     createCmdsFlags[subcmdCreateBucket],
 					Action:    createBucketHandler,
@@ -36,6 +43,24 @@ func createBucketHandler(c *cli.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if flagIsSet(c, origURLFlag) || flagIsSet(c, bckPathFlag) {
+		provider, flag := cmn.ProviderHTTP, origURLFlag
+		if flagIsSet(c, bckPathFlag) {
+			provider, flag = cmn.ProviderFS, bckPathFlag
+		}
+		for _, bck := range buckets {
+			if bck.Provider != provider {
+				return fmt.Errorf("flag %q requires %s%s buckets, got %q", flag.Name,
+					provider, cmn.BckProviderSeparator, bck)
+			}
+		}
+		extra := cmn.ExtraProps{
+			OrigURLBck: parseStrFlag(c, origURLFlag),
+			Manifest:   parseStrFlag(c, manifestFlag),
+			Path:       parseStrFlag(c, bckPathFlag),
+		}
+		return createBuckets(c, buckets, extra)
+	}
 	// TODO: remote AIS cluster: extend existing API with create-bucket, et al. (v4.x)
 	if err := validateLocalBuckets(buckets, "creating"); err != nil {
 		return err
@@ -8,6 +8,7 @@ package commands
 import (
 	"fmt"
 	"io"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -21,6 +22,12 @@ import (
 	"github.com/vbauerster/mpb/v4/decor"
 )
 
+// environment variables with credentials to access the source of the download
+const (
+	dlAuthPasswordEnv = "AIS_DL_PASSWORD"
+	dlAuthTokenEnv    = "AIS_DL_TOKEN"
+)
+
 type (
 	downloadingResult struct {
 		totalFiles        int
@@ -340,6 +347,15 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 		return
 	}
 
+	if d.Sync {
+		if !d.LastRun.IsZero() {
+			fmt.Fprintf(w, "Last sync: %s\n", d.LastRun.Format(time.RFC822))
+		}
+		if !d.NextRun.IsZero() {
+			fmt.Fprintf(w, "Next sync: %s\n", d.NextRun.Format(time.RFC822))
+		}
+	}
+
 	if d.JobFinished() {
 		fmt.Fprintf(w, "Done: %d file%s downloaded, %d error%s\n",
 			d.FinishedCnt, cmn.NounEnding(d.FinishedCnt), d.ErrorCnt, cmn.NounEnding(d.ErrorCnt))
@@ -383,3 +399,29 @@ func printDownloadStatus(w io.Writer, d downloader.DlStatusResp, verbose bool) {
 		fmt.Fprintf(w, "Errors (%d) occurred during the download. To see detailed info run `ais show download %s -v`\n", d.ErrorCnt, d.ID)
 	}
 }
+
+// parseDlSourceAuth returns custom headers and credentials which are used by
+// the targets to access the source of the download.
+func parseDlSourceAuth(c *cli.Context) (headers map[string]string, creds downloader.DlCredentials, err error) {
+	for _, header := range c.StringSlice(dlHeaderFlag.GetName()) {
+		idx := strings.IndexByte(header, ':')
+		if idx <= 0 {
+			return nil, creds, incorrectUsageMsg(c, "invalid header %q, should be in format: 'Key: value'", header)
+		}
+		if headers == nil {
+			headers = make(map[string]string)
+		}
+		headers[strings.TrimSpace(header[:idx])] = strings.TrimSpace(header[idx+1:])
+	}
+
+	creds.Username = parseStrFlag(c, dlAuthUserFlag)
+	if creds.Username != "" {
+		if creds.Password = os.Getenv(dlAuthPasswordEnv); creds.Password == "" {
+			creds.Password = readValue(c, "Password")
+		}
+	}
+	if creds.Token = parseStrFlag(c, dlAuthTokenFlag); creds.Token == "" && creds.Username == "" {
+		creds.Token = os.Getenv(dlAuthTokenEnv)
+	}
+	return headers, creds, nil
+}
@@ -27,6 +27,7 @@ var (
 		pagedFlag,
 		maxPagesFlag,
 		markerFlag,
+		delimiterFlag,
 		cachedFlag,
 	}
 
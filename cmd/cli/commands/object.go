@@ -35,6 +35,8 @@ type uploadParams struct {
 	workerCnt int
 	refresh   time.Duration
 	totalSize int64
+	customMD  cmn.SimpleKVs
+	retention *cmn.ObjectRetention
 }
 
 const (
@@ -137,6 +139,14 @@ func putSingleObject(c *cli.Context, bck cmn.Bck, objName, path string) (err err
 		bars     []*mpb.Bar
 	)
 
+	customMD, err := parseCustomMDFlag(c)
+	if err != nil {
+		return err
+	}
+	retention, err := parseRetentionFlags(c)
+	if err != nil {
+		return err
+	}
 	fh, err := cmn.NewFileHandle(path)
 	if err != nil {
 		return err
@@ -159,6 +169,8 @@ func putSingleObject(c *cli.Context, bck cmn.Bck, objName, path string) (err err
 		Bck:        bck,
 		Object:     objName,
 		Reader:     reader,
+		CustomMD:   customMD,
+		Retention:  retention,
 	}
 
 	err = api.PutObject(putArgs)
@@ -261,6 +273,14 @@ func putMultipleObjects(c *cli.Context, files []fileToObj, bck cmn.Bck) (err err
 		}
 	}
 
+	customMD, err := parseCustomMDFlag(c)
+	if err != nil {
+		return err
+	}
+	retention, err := parseRetentionFlags(c)
+	if err != nil {
+		return err
+	}
 	refresh := calcPutRefresh(c)
 	numWorkers := parseIntFlag(c, concurrencyFlag)
 	params := uploadParams{
@@ -269,6 +289,8 @@ func putMultipleObjects(c *cli.Context, files []fileToObj, bck cmn.Bck) (err err
 		workerCnt: numWorkers,
 		refresh:   refresh,
 		totalSize: totalSize,
+		customMD:  customMD,
+		retention: retention,
 	}
 	return uploadFiles(c, params)
 }
@@ -532,7 +554,14 @@ func uploadFiles(c *cli.Context, p uploadParams) error {
 		}
 		countReader := cmn.NewCallbackReadOpenCloser(reader, updateBar)
 
-		putArgs := api.PutObjectArgs{BaseParams: defaultAPIParams, Bck: p.bck, Object: f.name, Reader: countReader}
+		putArgs := api.PutObjectArgs{
+			BaseParams: defaultAPIParams,
+			Bck:        p.bck,
+			Object:     f.name,
+			Reader:     countReader,
+			CustomMD:   p.customMD,
+			Retention:  p.retention,
+		}
 		if err := api.PutObject(putArgs); err != nil {
 			str := fmt.Sprintf("Failed to put object %q: %v\n", f.name, err)
 			if showProgress {
@@ -566,6 +595,41 @@ func uploadFiles(c *cli.Context, p uploadParams) error {
 	return nil
 }
 
+// Parses user-defined object metadata: "key1=value1,key2=value2"
+func parseCustomMDFlag(c *cli.Context) (cmn.SimpleKVs, error) {
+	if !flagIsSet(c, customMDFlag) {
+		return nil, nil
+	}
+	return makePairs(makeList(parseStrFlag(c, customMDFlag), ","))
+}
+
+// Parses retention (WORM) of new objects
+func parseRetentionFlags(c *cli.Context) (*cmn.ObjectRetention, error) {
+	if !flagIsSet(c, retainUntilFlag) && !flagIsSet(c, legalHoldFlag) {
+		return nil, nil
+	}
+	retention := &cmn.ObjectRetention{RetainUntil: parseStrFlag(c, retainUntilFlag)}
+	if flagIsSet(c, legalHoldFlag) {
+		hold, err := parseLegalHold(parseStrFlag(c, legalHoldFlag))
+		if err != nil {
+			return nil, err
+		}
+		retention.LegalHold = hold
+	}
+	return retention, nil
+}
+
+func parseLegalHold(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid legal hold %q (expected 'on' or 'off')", s)
+	}
+}
+
 func calcPutRefresh(c *cli.Context) time.Duration {
 	refresh := 5 * time.Second
 	if flagIsSet(c, verboseFlag) && !flagIsSet(c, refreshFlag) {
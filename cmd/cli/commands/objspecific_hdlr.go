@@ -32,12 +32,15 @@ var (
 		commandPut: {
 			chunkSizeFlag,
 			concurrencyFlag,
+			customMDFlag,
 			dryRunFlag,
 			progressBarFlag,
 			recursiveFlag,
 			refreshFlag,
 			verboseFlag,
 			yesFlag,
+			retainUntilFlag,
+			legalHoldFlag,
 		},
 		commandPromote: {
 			recursiveFlag,
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cmn"
 	"github.com/urfave/cli"
 )
 
@@ -20,6 +21,11 @@ var (
 			resetFlag,
 		},
 		subcmdSetPrimary: {},
+		subcmdSetRetention: {
+			retainUntilFlag,
+			legalHoldFlag,
+			bypassGovernanceFlag,
+		},
 	}
 
 	setCmds = []cli.Command{
@@ -51,6 +57,14 @@ var (
 					Action:       setPrimaryHandler,
 					BashComplete: daemonCompletions(completeProxies),
 				},
+				{
+					Name:         subcmdSetRetention,
+					Usage:        "extend object retention and/or place (remove) legal hold",
+					ArgsUsage:    objectArgument,
+					Flags:        setCmdsFlags[subcmdSetRetention],
+					Action:       setRetentionHandler,
+					BashComplete: bucketCompletions(bckCompletionsOpts{separator: true}),
+				},
 			},
 		},
 	}
@@ -103,3 +117,35 @@ func setPrimaryHandler(c *cli.Context) (err error) {
 	}
 	return err
 }
+
+func setRetentionHandler(c *cli.Context) (err error) {
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "object name in the form bucket/object")
+	}
+	bck, objName, err := parseBckObjectURI(c.Args().First())
+	if err != nil {
+		return
+	}
+	if objName == "" {
+		return incorrectUsageMsg(c, "missing object name in the form bucket/object")
+	}
+	if bck, err = validateBucket(c, bck, "", false); err != nil {
+		return
+	}
+	if !flagIsSet(c, retainUntilFlag) && !flagIsSet(c, legalHoldFlag) {
+		return missingArgumentsError(c, fmt.Sprintf("%s or %s", retainUntilFlag.Name, legalHoldFlag.Name))
+	}
+	retention := cmn.ActValRetention{RetainUntil: parseStrFlag(c, retainUntilFlag)}
+	if flagIsSet(c, legalHoldFlag) {
+		hold, err := parseLegalHold(parseStrFlag(c, legalHoldFlag))
+		if err != nil {
+			return err
+		}
+		retention.LegalHold = &hold
+	}
+	if err = api.SetObjectRetention(defaultAPIParams, bck, objName, retention, flagIsSet(c, bypassGovernanceFlag)); err != nil {
+		return
+	}
+	fmt.Fprintf(c.App.Writer, "Retention of %q updated\n", bck.Name+"/"+objName)
+	return
+}
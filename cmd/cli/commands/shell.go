@@ -358,6 +358,10 @@ func dsortIDFinishedCompletions(c *cli.Context) {
 	suggestDsortID(c, (*dsort.JobInfo).IsFinished)
 }
 
+func dsortIDAbortedCompletions(c *cli.Context) {
+	suggestDsortID(c, func(job *dsort.JobInfo) bool { return job.Aborted })
+}
+
 func suggestDsortID(c *cli.Context, filter func(*dsort.JobInfo) bool) {
 	if c.NArg() > 0 {
 		return
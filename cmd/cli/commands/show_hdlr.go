@@ -29,6 +29,15 @@ type (
 		Stats   *[]daemonTemplateStats
 		Verbose bool
 	}
+
+	xactHistoryTemplateRecord struct {
+		DaemonID string
+		Record   *stats.XactRecord
+	}
+
+	xactHistoryTemplateCtx struct {
+		Records []xactHistoryTemplateRecord
+	}
 )
 
 var (
@@ -69,6 +78,9 @@ var (
 			allItemsFlag,
 			activeFlag,
 			verboseFlag,
+			xactHistoryFlag,
+			sinceFlag,
+			untilFlag,
 		},
 		subcmdShowRebalance: {
 			refreshFlag,
@@ -261,6 +273,9 @@ func showClusterHandler(c *cli.Context) (err error) {
 }
 
 func showXactionHandler(c *cli.Context) (err error) {
+	if flagIsSet(c, xactHistoryFlag) {
+		return showXactionHistory(c)
+	}
 	xactID, xactKind, bck, err := parseXactionFromArgs(c)
 	if err != nil {
 		return err
@@ -324,6 +339,48 @@ func showXactionHandler(c *cli.Context) (err error) {
 	return templates.DisplayOutput(ctx, c.App.Writer, templates.XactionsBodyTmpl, flagIsSet(c, jsonFlag))
 }
 
+func showXactionHistory(c *cli.Context) (err error) {
+	args := api.XactHistoryArgs{Kind: c.Args().Get(0)}
+	if args.Kind != "" && !cmn.IsValidXaction(args.Kind) {
+		return cmn.NewXactionNotFoundError(args.Kind)
+	}
+	if c.NArg() > 1 {
+		var objName string
+		if args.Bck, objName, err = parseBckObjectURI(c.Args().Get(1)); err != nil {
+			return
+		}
+		if objName != "" {
+			return objectNameArgumentNotSupported(c, objName)
+		}
+	}
+	if args.Since, err = parseTimeFlag(c, sinceFlag); err != nil {
+		return
+	}
+	if args.Until, err = parseTimeFlag(c, untilFlag); err != nil {
+		return
+	}
+
+	history, err := api.GetXactionHistory(defaultAPIParams, args)
+	if err != nil {
+		return
+	}
+	records := make([]xactHistoryTemplateRecord, 0, 16)
+	for daemonID, daemonRecords := range history {
+		for _, rec := range daemonRecords {
+			records = append(records, xactHistoryTemplateRecord{DaemonID: daemonID, Record: rec})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		ri, rj := records[i].Record, records[j].Record
+		if ri.EndTime().Equal(rj.EndTime()) {
+			return records[i].DaemonID < records[j].DaemonID
+		}
+		return ri.EndTime().After(rj.EndTime()) // descending by end time
+	})
+	ctx := xactHistoryTemplateCtx{Records: records}
+	return templates.DisplayOutput(ctx, c.App.Writer, templates.XactionHistoryTmpl, flagIsSet(c, jsonFlag))
+}
+
 func showObjectHandler(c *cli.Context) (err error) {
 	var (
 		fullObjName = c.Args().Get(0) // empty string if no arg given
@@ -38,6 +38,7 @@ const (
 	subcmdAuthLogout  = "logout"
 	subcmdAuthUser    = "user"
 	subcmdAuthCluster = "cluster"
+	subcmdAuthKey     = "key"
 )
 
 var (
@@ -62,6 +63,12 @@ var (
 							ArgsUsage: addAuthClusterArgument,
 							Action:    addAuthClusterHandler,
 						},
+						{
+							Name:      subcmdAuthKey,
+							Usage:     "generate a new access key pair to sign S3 requests",
+							ArgsUsage: addAuthKeyArgument,
+							Action:    addAuthKeyHandler,
+						},
 					},
 				},
 				{
@@ -80,6 +87,12 @@ var (
 							ArgsUsage: deleteAuthClusterArgument,
 							Action:    deleteAuthClusterHandler,
 						},
+						{
+							Name:      subcmdAuthKey,
+							Usage:     "remove an existing access key",
+							ArgsUsage: deleteAuthKeyArgument,
+							Action:    deleteAuthKeyHandler,
+						},
 					},
 				},
 				{
@@ -103,6 +116,12 @@ var (
 							ArgsUsage: showAuthClusterArgument,
 							Action:    showAuthClusterHandler,
 						},
+						{
+							Name:      subcmdAuthKey,
+							Usage:     "show access keys",
+							ArgsUsage: showAuthKeyArgument,
+							Action:    showAuthKeyHandler,
+						},
 					},
 				},
 				{
@@ -343,3 +362,59 @@ func showAuthClusterHandler(c *cli.Context) (err error) {
 
 	return templates.DisplayOutput(list, c.App.Writer, templates.AuthNClusterTmpl)
 }
+
+func addAuthKeyHandler(c *cli.Context) (err error) {
+	authnURL := cliAuthnURL()
+	if authnURL == "" {
+		return fmt.Errorf("AuthN URL is not set") // nolint:golint // name of the service
+	}
+	baseParams := cliAuthParams(authnURL)
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "user name")
+	}
+	spec := api.AuthnSpec{
+		AdminName:     cliAuthnAdminName(c),
+		AdminPassword: cliAuthnAdminPassword(c),
+		UserName:      c.Args().Get(0),
+	}
+	pair, err := api.AddAccessKey(baseParams, spec)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(pair, c.App.Writer, templates.AuthNNewKeyTmpl)
+}
+
+func deleteAuthKeyHandler(c *cli.Context) (err error) {
+	authnURL := cliAuthnURL()
+	if authnURL == "" {
+		return fmt.Errorf("AuthN URL is not set") // nolint:golint // name of the service
+	}
+	baseParams := cliAuthParams(authnURL)
+	if c.NArg() == 0 {
+		return missingArgumentsError(c, "access key")
+	}
+	spec := api.AuthnSpec{
+		AdminName:     cliAuthnAdminName(c),
+		AdminPassword: cliAuthnAdminPassword(c),
+		AccessKey:     c.Args().Get(0),
+	}
+	return api.DeleteAccessKey(baseParams, spec)
+}
+
+func showAuthKeyHandler(c *cli.Context) (err error) {
+	authnURL := cliAuthnURL()
+	if authnURL == "" {
+		return fmt.Errorf("AuthN URL is not set") // nolint:golint // name of the service
+	}
+	baseParams := cliAuthParams(authnURL)
+	spec := api.AuthnSpec{
+		AdminName:     cliAuthnAdminName(c),
+		AdminPassword: cliAuthnAdminPassword(c),
+		UserName:      c.Args().Get(0),
+	}
+	keys, err := api.GetAccessKeys(baseParams, spec)
+	if err != nil {
+		return err
+	}
+	return templates.DisplayOutput(keys, c.App.Writer, templates.AuthNKeyTmpl)
+}
@@ -390,6 +390,24 @@ func parseByteFlagToInt(c *cli.Context, flag cli.Flag) (int64, error) {
 	return b, nil
 }
 
+// Returns the time given by the flag either as RFC3339 time or as a duration
+// (the time that long ago), zero time if the flag is not set.
+func parseTimeFlag(c *cli.Context, flag cli.StringFlag) (time.Time, error) {
+	flagValue := parseStrFlag(c, flag)
+	if flagValue == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(flagValue); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, flagValue)
+	if err != nil {
+		return t, fmt.Errorf("%s (%s) is invalid, expected either RFC3339 time (eg. %s) or a duration (eg. 24h)",
+			flag.GetName(), flagValue, time.RFC3339)
+	}
+	return t, nil
+}
+
 // Returns a string containing the value of the `flag` in bytes, used for `offset` and `length` flags
 func getByteFlagValue(c *cli.Context, flag cli.Flag) (string, error) {
 	if flagIsSet(c, flag) {
@@ -7,6 +7,7 @@ package templates
 import (
 	"fmt"
 	"io"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"text/template"
@@ -190,7 +191,8 @@ const (
 		" Name: \t{{$obj.Proxy.Name}}\t \t{{$obj.Target.Name}}\n" +
 		" Factor: \t{{$obj.Proxy.Factor}}\t \t{{$obj.Target.Factor}}\n"
 	DownloaderConfTmpl = "\n{{$obj := .Downloader}}Downloader Config\n" +
-		" Timeout: {{$obj.TimeoutStr}}\n"
+		" Timeout: {{$obj.TimeoutStr}}\n" +
+		" Retries: {{$obj.Retries}}\n"
 	DSortConfTmpl = "\n{{$obj := .DSort}}Distributed Sort Config\n" +
 		" Duplicated Records:\t{{$obj.DuplicatedRecords}}\n" +
 		" Missing Shards:\t{{$obj.MissingShards}}\n" +
@@ -206,6 +208,7 @@ const (
 		" Minimum object size for EC:\t{{$obj.ObjSizeLimit}}\n" +
 		" Number of data slices:\t{{$obj.DataSlices}}\n" +
 		" Number of parity slices:\t{{$obj.ParitySlices}}\n" +
+		" Number of local parity groups:\t{{$obj.LocalGroups}}\n" +
 		" Rebalance batch size:\t{{$obj.BatchSize}}\n" +
 		" Compression options:\t{{$obj.Compression}}\n"
 	GlobalConfTmpl = "Config Directory: {{.Confdir}}\nCloud Provider: {{.Cloud.Provider}}\n"
@@ -233,7 +236,9 @@ const (
 	DownloadListHeader = "JOB ID\t STATUS\t ERRORS\t DESCRIPTION\n"
 	DownloadListBody   = "{{$value.ID}}\t " +
 		"{{if $value.Aborted}}Aborted" +
-		"{{else}}{{if $value.JobFinished}}Finished{{else}}{{$value.PendingCnt}} pending{{end}}" +
+		"{{else}}{{if $value.JobFinished}}" +
+		"{{if IsUnsetTime $value.NextRun}}Finished{{else}}Next run: {{FormatTime $value.NextRun}}{{end}}" +
+		"{{else}}{{$value.PendingCnt}} pending{{end}}" +
 		"{{end}}\t {{$value.ErrorCnt}}\t {{$value.Description}}\n"
 	DownloadListTmpl = DownloadListHeader + "{{ range $key, $value := . }}" + DownloadListBody + "{{end}}"
 
@@ -263,6 +268,19 @@ const (
 		"{{if (IsUnsetTime $xact.EndTimeX)}}-{{else}}{{FormatTime $xact.EndTimeX}}{{end}}\t " +
 		"{{$xact.AbortedX}}" +
 		"{{if $.Verbose}}\t " + XactionExtBody + "{{end}}\n"
+	XactionHistoryTmpl = "TARGET\t ID\t KIND\t BUCKET\t OBJECTS\t BYTES\t START\t END\t INITIATOR\t RESULT\n" +
+		"{{range $rec := $.Records}}" +
+		"{{$rec.DaemonID}}\t " +
+		"{{if $rec.Record.IDX}}{{$rec.Record.IDX}}{{else}}-{{end}}\t " +
+		"{{$rec.Record.KindX}}\t " +
+		"{{if $rec.Record.BckX.Name}}{{$rec.Record.BckX.Name}}{{else}}-{{end}}\t " +
+		"{{if (eq $rec.Record.ObjCountX 0) }}-{{else}}{{$rec.Record.ObjCountX}}{{end}}\t " +
+		"{{if (eq $rec.Record.BytesCountX 0) }}-{{else}}{{FormatBytesSigned $rec.Record.BytesCountX 2}}{{end}}\t " +
+		"{{FormatTime $rec.Record.StartTimeX}}\t " +
+		"{{FormatTime $rec.Record.EndTimeX}}\t " +
+		"{{$rec.Record.Initiator}}\t " +
+		"{{if $rec.Record.AbortedX}}aborted{{if $rec.Record.AbortReason}}: {{$rec.Record.AbortReason}}{{end}}{{else}}finished{{end}}\n" +
+		"{{end}}"
 	XactionExtBody = "{{if $xact.Ext}}" + // if not nil
 		"{{$first := true}}" +
 		"{{range $name, $val := $xact.Ext}}" +
@@ -303,6 +321,12 @@ const (
 		"{{ range $clu := . }}" +
 		"{{ $clu.ID }}\t{{ JoinList $clu.URLs }}\n" +
 		"{{end}}"
+	AuthNKeyTmpl = "AccessKey\tUser\n" +
+		"{{ range $key := . }}" +
+		"{{ $key.AccessKey }}\t{{ $key.UserName }}\n" +
+		"{{end}}"
+	AuthNNewKeyTmpl = "Access key: {{ .AccessKey }}\n" +
+		"Secret key: {{ .SecretKey }}\n"
 )
 
 var (
@@ -318,16 +342,19 @@ var (
 		"status":     "{{FormatObjStatus $obj}}",
 		"copies":     "{{$obj.Copies}}",
 		"cached":     "{{FormatObjIsCached $obj}}",
+		"custom":     "{{FormatCustomMD $obj.CustomMD}}",
 	}
 
 	ObjStatMap = map[string]string{
-		"cached":   "{{FormatBool .Present}}",
-		"size":     "{{FormatBytesSigned .Size 2}}",
-		"version":  "{{.Version}}",
-		"atime":    "{{if (eq .Atime 0)}}-{{else}}{{FormatUnixNano .Atime}}{{end}}",
-		"copies":   "{{if .NumCopies}}{{.NumCopies}}{{else}}-{{end}}",
-		"checksum": "{{if .Checksum.Value}}{{.Checksum.Value}}{{else}}-{{end}}",
-		"ec":       "{{if (eq .DataSlices 0)}}-{{else}}{{FormatEC .DataSlices .ParitySlices .IsECCopy}}{{end}}",
+		"cached":    "{{FormatBool .Present}}",
+		"size":      "{{FormatBytesSigned .Size 2}}",
+		"version":   "{{.Version}}",
+		"atime":     "{{if (eq .Atime 0)}}-{{else}}{{FormatUnixNano .Atime}}{{end}}",
+		"copies":    "{{if .NumCopies}}{{.NumCopies}}{{else}}-{{end}}",
+		"checksum":  "{{if .Checksum.Value}}{{.Checksum.Value}}{{else}}-{{end}}",
+		"ec":        "{{if (eq .DataSlices 0)}}-{{else}}{{FormatEC .DataSlices .ParitySlices .IsECCopy}}{{end}}",
+		"custom":    "{{FormatCustomMD .CustomMD}}",
+		"retention": "{{if .Retention.RetainUntil}}{{.Retention.RetainUntil}}{{else}}-{{end}}{{if .Retention.LegalHold}} (legal hold){{end}}",
 	}
 
 	funcMap = template.FuncMap{
@@ -339,6 +366,7 @@ var (
 		"FormatTime":          fmtTime,
 		"FormatUnixNano":      func(t int64) string { return cmn.FormatUnixNano(t, "") },
 		"FormatEC":            fmtEC,
+		"FormatCustomMD":      fmtCustomMD,
 		"FormatDur":           fmtDuration,
 		"FormatXactStatus":    fmtXactStatus,
 		"FormatObjStatus":     fmtObjStatus,
@@ -502,6 +530,21 @@ func fmtEC(data, parity int, isCopy bool) string {
 	return info
 }
 
+func fmtCustomMD(md cmn.SimpleKVs) string {
+	if len(md) == 0 {
+		return "-"
+	}
+	keys := make([]string, 0, len(md))
+	for k := range md {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = k + "=" + md[k]
+	}
+	return strings.Join(keys, ",")
+}
+
 func fmtDuration(d int64) string {
 	dNano := time.Duration(d * int64(time.Microsecond))
 	return duration.HumanDuration(dNano)
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 )
@@ -28,6 +29,13 @@ type ActValPromote struct {
 	Verbose   bool   `json:"verbose"`
 }
 
+// ActValRetention changes the retention of an existing object:
+// RetainUntil is RFC3339 time (empty - unchanged), LegalHold is admin-only.
+type ActValRetention struct {
+	RetainUntil string `json:"retain_until"`
+	LegalHold   *bool  `json:"legal_hold"`
+}
+
 const (
 	XactTypeGlobal = "global"
 	XactTypeBck    = "bucket"
@@ -42,6 +50,7 @@ type XactMetadata struct {
 var XactsMeta = map[string]XactMetadata{
 	// global kinds
 	ActLRU:       {Type: XactTypeGlobal, Startable: true},
+	ActScrub:     {Type: XactTypeGlobal, Startable: true},
 	ActElection:  {Type: XactTypeGlobal, Startable: false},
 	ActResilver:  {Type: XactTypeGlobal, Startable: true},
 	ActRebalance: {Type: XactTypeGlobal, Startable: true},
@@ -56,11 +65,14 @@ var XactsMeta = map[string]XactMetadata{
 	ActRenameLB:     {Type: XactTypeBck, Startable: false},
 	ActCopyBucket:   {Type: XactTypeBck, Startable: false},
 	ActECEncode:     {Type: XactTypeBck, Startable: false},
+	ActECReencode:   {Type: XactTypeBck, Startable: true},
 	ActEvictObjects: {Type: XactTypeBck, Startable: false},
 	ActDelete:       {Type: XactTypeBck, Startable: false},
 	ActLoadLomCache: {Type: XactTypeBck, Startable: false},
 	ActPrefetch:     {Type: XactTypeBck, Startable: true},
 	ActPromote:      {Type: XactTypeBck, Startable: false},
+	ActLifecycle:    {Type: XactTypeBck, Startable: true},
+	ActReplBackfill: {Type: XactTypeBck, Startable: true},
 
 	ActListObjects:   {Type: XactTypeTask, Startable: false},
 	ActSummaryBucket: {Type: XactTypeTask, Startable: false},
@@ -80,6 +92,9 @@ type SelectMsg struct {
 	TaskID     string `json:"taskid"`      // task ID for long running requests
 	Fast       bool   `json:"fast"`        // performs a fast traversal of the bucket contents (returns only names)
 	Cached     bool   `json:"cached"`      // for cloud buckets - list only cached objects
+	// if set, objects which names contain the delimiter after the prefix are
+	// rolled up into a single (directory) entry - see `SelectMsg.DirName`
+	Delimiter string `json:"delimiter,omitempty"`
 }
 
 // ListMsg contains a list of files and a duration within which to get them
@@ -110,13 +125,24 @@ type XactionMsg struct {
 	Finished bool   `json:"finished"`
 }
 
+// XactHistoryMsg selects the records of finished xactions from the xaction
+// history of each node (see: GetWhatXactHistory).
+type XactHistoryMsg struct {
+	Kind  string    `json:"kind"`
+	Bck   Bck       `json:"bck"`
+	Since time.Time `json:"since"` // only xactions finished at or after, zero - no limit
+	Until time.Time `json:"until"` // only xactions finished before, zero - no limit
+	Limit int       `json:"limit"` // max number of the latest records returned by each node, 0 - all
+}
+
 // GetPropsDefault is a list of default (most relevant) GetProps* options
 var GetPropsDefault = []string{
 	GetPropsChecksum, GetPropsSize, GetPropsAtime, GetPropsVersion,
 }
 
 // GetPropsAll is a list of all GetProps* options
-var GetPropsAll = append(GetPropsDefault, GetPropsCached, GetTargetURL, GetPropsStatus, GetPropsCopies, GetPropsEC)
+var GetPropsAll = append(GetPropsDefault, GetPropsCached, GetTargetURL, GetPropsStatus, GetPropsCopies, GetPropsEC, GetPropsCustom,
+	GetPropsRetention)
 
 // NeedLocalData returns true if ListObjects for a cloud bucket needs
 // to return object properties that can be retrieved only from local caches
@@ -124,6 +150,7 @@ func (msg *SelectMsg) NeedLocalData() bool {
 	return strings.Contains(msg.Props, GetPropsAtime) ||
 		strings.Contains(msg.Props, GetPropsStatus) ||
 		strings.Contains(msg.Props, GetPropsCopies) ||
+		strings.Contains(msg.Props, GetPropsCustom) ||
 		strings.Contains(msg.Props, GetPropsCached)
 }
 
@@ -148,21 +175,53 @@ func (msg *SelectMsg) AddProps(propNames ...string) {
 	msg.Props = props.String()
 }
 
+// DirName returns the name of the directory entry that the object rolls up
+// into when listing with a delimiter: the object name up to and including
+// the first occurrence of the delimiter after the prefix, e.g. for
+// prefix="a/", delimiter="/" the object "a/b/c" rolls up into "a/b/".
+func (msg *SelectMsg) DirName(objName string) (dir string, ok bool) {
+	if msg.Delimiter == "" || !strings.HasPrefix(objName, msg.Prefix) {
+		return
+	}
+	idx := strings.Index(objName[len(msg.Prefix):], msg.Delimiter)
+	if idx < 0 {
+		return
+	}
+	return objName[:len(msg.Prefix)+idx+len(msg.Delimiter)], true
+}
+
+// SkipMarked returns true if the object was already returned in the previous
+// pages: the object name is not greater than the page marker, or the page
+// marker is a directory entry (see `SelectMsg.DirName`) that contains the object.
+func (msg *SelectMsg) SkipMarked(objName string) bool {
+	if msg.PageMarker == "" {
+		return false
+	}
+	if objName <= msg.PageMarker {
+		return true
+	}
+	return msg.Delimiter != "" && strings.HasSuffix(msg.PageMarker, msg.Delimiter) &&
+		strings.HasPrefix(objName, msg.PageMarker)
+}
+
 // BucketEntry corresponds to a single entry in the BucketList and
 // contains file and directory metadata as per the SelectMsg
-// Flags is a bit field:
-// 0-2: objects status, all statuses are mutually exclusive, so it can hold up
-//      to 8 different statuses. Now only OK=0, Moved=1, Deleted=2 are supported
-// 3:   CheckExists (for cloud bucket it shows if the object in local cache)
+// Flags is a bit field (see EntryStatusMask, EntryIsCached, and EntryIsDir):
+// 0-4: objects status, all statuses are mutually exclusive, so it can hold up
+//      to 32 different statuses. Now only OK=0, Moved=1, Deleted=2 are supported
+// 6:   CheckExists (for cloud bucket it shows if the object in local cache)
+// 7:   IsDir (the entry is a directory, i.e., a common prefix of object names
+//      when listing with a delimiter)
 type BucketEntry struct {
-	Name      string `json:"name"`                  // name of the object - note: does not include the bucket name
-	Size      int64  `json:"size,string,omitempty"` // size in bytes
-	Checksum  string `json:"checksum,omitempty"`    // checksum
-	Atime     string `json:"atime,omitempty"`       // formatted as per SelectMsg.TimeFormat
-	Version   string `json:"version,omitempty"`     // version/generation ID. In GCP it is int64, in AWS it is a string
-	TargetURL string `json:"target_url,omitempty"`  // URL of target which has the entry
-	Copies    int16  `json:"copies,omitempty"`      // ## copies (non-replicated = 1)
-	Flags     uint16 `json:"flags,omitempty"`       // object flags, like CheckExists, IsMoved etc
+	Name      string    `json:"name"`                  // name of the object - note: does not include the bucket name
+	Size      int64     `json:"size,string,omitempty"` // size in bytes
+	Checksum  string    `json:"checksum,omitempty"`    // checksum
+	Atime     string    `json:"atime,omitempty"`       // formatted as per SelectMsg.TimeFormat
+	Version   string    `json:"version,omitempty"`     // version/generation ID. In GCP it is int64, in AWS it is a string
+	TargetURL string    `json:"target_url,omitempty"`  // URL of target which has the entry
+	Copies    int16     `json:"copies,omitempty"`      // ## copies (non-replicated = 1)
+	Flags     uint16    `json:"flags,omitempty"`       // object flags, like CheckExists, IsMoved etc
+	CustomMD  SimpleKVs `json:"custom_md,omitempty"`   // user-defined metadata
 }
 
 func (be *BucketEntry) CheckExists() bool {
@@ -172,6 +231,10 @@ func (be *BucketEntry) SetExists() {
 	be.Flags |= EntryIsCached
 }
 
+func (be *BucketEntry) IsDir() bool {
+	return be.Flags&EntryIsDir != 0
+}
+
 func (be *BucketEntry) IsStatusOK() bool {
 	return be.Flags&EntryStatusMask == 0
 }
@@ -264,6 +327,18 @@ type BucketProps struct {
 	// EC defines erasure coding setting for the bucket
 	EC ECConf `json:"ec"`
 
+	// Retention defines object lock (WORM) policy for the bucket
+	Retention RetentionConf `json:"retention"`
+
+	// Lifecycle defines the rules to expire and evict the bucket's objects
+	Lifecycle LifecycleConf `json:"lifecycle"`
+
+	// Replication defines asynchronous replication of the bucket to a remote AIS cluster
+	Replication BckReplicationConf `json:"replication"`
+
+	// Extra contains provider-specific properties
+	Extra ExtraProps `json:"extra,omitempty"`
+
 	// Bucket access attributes - see Allow* above
 	AccessAttrs uint64 `json:"access,string"`
 
@@ -278,13 +353,35 @@ type BucketProps struct {
 }
 
 type BucketPropsToUpdate struct {
-	BackendBck  *BckToUpdate         `json:"backend_bck"`
-	Versioning  *VersionConfToUpdate `json:"versioning"`
-	Cksum       *CksumConfToUpdate   `json:"checksum"`
-	LRU         *LRUConfToUpdate     `json:"lru"`
-	Mirror      *MirrorConfToUpdate  `json:"mirror"`
-	EC          *ECConfToUpdate      `json:"ec"`
-	AccessAttrs *uint64              `json:"access,string"`
+	BackendBck  *BckToUpdate                `json:"backend_bck"`
+	Versioning  *VersionConfToUpdate        `json:"versioning"`
+	Cksum       *CksumConfToUpdate          `json:"checksum"`
+	LRU         *LRUConfToUpdate            `json:"lru"`
+	Mirror      *MirrorConfToUpdate         `json:"mirror"`
+	EC          *ECConfToUpdate             `json:"ec"`
+	Retention   *RetentionConfToUpdate      `json:"retention"`
+	Lifecycle   *LifecycleConfToUpdate      `json:"lifecycle"`
+	Replication *BckReplicationConfToUpdate `json:"replication"`
+	Extra       *ExtraToUpdate              `json:"extra"`
+	AccessAttrs *uint64                     `json:"access,string"`
+}
+
+// ExtraProps - provider-specific bucket properties
+type ExtraProps struct {
+	// ht:// - base URL of the HTTP(S) origin: objects are fetched from <original_url>/<object name>
+	OrigURLBck string `json:"original_url,omitempty"`
+	// ht:// - URL of the file that lists object names, one per line; may be relative
+	// to the original URL. When not defined, the bucket cannot be listed
+	Manifest string `json:"manifest,omitempty"`
+	// fs:// - directory (e.g. NFS mount) that contains the objects; must be
+	// accessible by all targets under the same path
+	Path string `json:"path,omitempty"`
+}
+
+type ExtraToUpdate struct {
+	OrigURLBck *string `json:"original_url"`
+	Manifest   *string `json:"manifest"`
+	Path       *string `json:"path"`
 }
 
 type BckToUpdate struct {
@@ -297,6 +394,7 @@ type ECConf struct {
 	ObjSizeLimit int64  `json:"objsize_limit"` // objects below this size are replicated instead of EC'ed
 	DataSlices   int    `json:"data_slices"`   // number of data slices
 	ParitySlices int    `json:"parity_slices"` // number of parity slices/replicas
+	LocalGroups  int    `json:"local_groups"`  // number of local parity groups (LRC), 0 - disabled
 	Compression  string `json:"compression"`   // see CompressAlways, etc. enum
 	Enabled      bool   `json:"enabled"`       // EC is enabled
 	BatchSize    int    `json:"batch_size"`    // Batch size for EC rebalance
@@ -307,9 +405,58 @@ type ECConfToUpdate struct {
 	ObjSizeLimit *int64  `json:"objsize_limit"`
 	DataSlices   *int    `json:"data_slices"`
 	ParitySlices *int    `json:"parity_slices"`
+	LocalGroups  *int    `json:"local_groups"`
 	Compression  *string `json:"compression"`
 }
 
+// RetentionConf - per-bucket object lock (WORM) configuration
+type RetentionConf struct {
+	Mode    string `json:"mode"`    // RetentionGovernance or RetentionCompliance
+	Period  string `json:"period"`  // default retention period of a new object, e.g. "720h" (0 - none)
+	Enabled bool   `json:"enabled"` // compliance-mode retention, once enabled, cannot be disabled
+}
+
+type RetentionConfToUpdate struct {
+	Enabled *bool   `json:"enabled"`
+	Mode    *string `json:"mode"`
+	Period  *string `json:"period"`
+}
+
+// LifecycleConf - per-bucket lifecycle rules that are periodically evaluated
+// for each object by the lifecycle xaction
+type LifecycleConf struct {
+	Rules   []LifecycleRule `json:"rules"`
+	Enabled bool            `json:"enabled"`
+}
+
+// LifecycleRule applies to the objects with the given name prefix (empty - all objects);
+// zero values disable the corresponding actions
+type LifecycleRule struct {
+	Prefix           string `json:"prefix"`
+	ExpireDays       int64  `json:"expire_days"`       // delete objects created more than N days ago
+	EvictDays        int64  `json:"evict_days"`        // cloud buckets: evict cached objects not accessed for N days
+	DeleteNoncurrent bool   `json:"delete_noncurrent"` // cloud buckets: evict cached objects that are not the current version
+}
+
+type LifecycleConfToUpdate struct {
+	Enabled *bool            `json:"enabled"`
+	Rules   *[]LifecycleRule `json:"rules"`
+}
+
+// BckReplicationConf - per-bucket asynchronous replication of PUTs, DELETEs, and renames
+// to a bucket of an attached remote AIS cluster
+type BckReplicationConf struct {
+	Cluster string `json:"cluster"` // UUID or alias of the attached remote AIS cluster
+	Bucket  string `json:"bucket"`  // destination bucket (empty - same name as the source bucket)
+	Enabled bool   `json:"enabled"`
+}
+
+type BckReplicationConfToUpdate struct {
+	Enabled *bool   `json:"enabled"`
+	Cluster *string `json:"cluster"`
+	Bucket  *string `json:"bucket"`
+}
+
 func (c *VersionConf) String() string {
 	if !c.Enabled {
 		return "Disabled"
@@ -377,12 +524,81 @@ func (c *ECConf) String() string {
 		return "Disabled"
 	}
 	objSizeLimit := c.ObjSizeLimit
+	if c.LocalGroups > 0 {
+		return fmt.Sprintf("%d:%d, %d local (%s)", c.DataSlices, c.ParitySlices, c.LocalGroups, B2S(objSizeLimit, 0))
+	}
 	return fmt.Sprintf("%d:%d (%s)", c.DataSlices, c.ParitySlices, B2S(objSizeLimit, 0))
 }
 
+func (c *RetentionConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("%s | Period: %s", c.Mode, c.Period)
+}
+
+func (c *LifecycleConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	return fmt.Sprintf("%v", c.Rules)
+}
+
+func (c *BckReplicationConf) String() string {
+	if !c.Enabled {
+		return "Disabled"
+	}
+	if c.Bucket == "" {
+		return "Cluster: " + c.Cluster
+	}
+	return fmt.Sprintf("Cluster: %s | Bucket: %s", c.Cluster, c.Bucket)
+}
+
+// DstBck returns the destination bucket (at the remote cluster) of the replicated bucket
+func (c *BckReplicationConf) DstBck(bck Bck) Bck {
+	if c.Bucket != "" {
+		bck.Name = c.Bucket
+	}
+	return Bck{Name: bck.Name, Provider: ProviderAIS}
+}
+
+// Match returns the rules that apply to the object
+func (c *LifecycleConf) Match(objName string) (rules []LifecycleRule) {
+	for _, rule := range c.Rules {
+		if strings.HasPrefix(objName, rule.Prefix) {
+			rules = append(rules, rule)
+		}
+	}
+	return
+}
+
+func (r LifecycleRule) String() string {
+	var actions []string
+	if r.ExpireDays > 0 {
+		actions = append(actions, fmt.Sprintf("expire: %dd", r.ExpireDays))
+	}
+	if r.EvictDays > 0 {
+		actions = append(actions, fmt.Sprintf("evict: %dd", r.EvictDays))
+	}
+	if r.DeleteNoncurrent {
+		actions = append(actions, "noncurrent")
+	}
+	prefix := r.Prefix
+	if prefix == "" {
+		prefix = "*"
+	}
+	return prefix + " (" + strings.Join(actions, ", ") + ")"
+}
+
+// DefaultPeriod returns the retention period of a newly created object
+func (c *RetentionConf) DefaultPeriod() time.Duration {
+	period, _ := time.ParseDuration(c.Period) // validated by ValidateAsProps
+	return period
+}
+
 func (c *ECConf) RequiredEncodeTargets() int {
-	// data slices + parity slices + 1 target for original object
-	return c.DataSlices + c.ParitySlices + 1
+	// data slices + parity slices + local parity slices + 1 target for original object
+	return c.DataSlices + c.ParitySlices + c.LocalGroups + 1
 }
 
 func (c *ECConf) RequiredRestoreTargets() int {
@@ -402,6 +618,13 @@ type ObjectProps struct {
 	ParitySlices int              `list:"omit"`
 	IsECCopy     bool             `list:"omit"`
 	Present      bool             `json:"present"`
+	CustomMD     SimpleKVs        `list:"omit"`
+	Retention    ObjectRetention  `json:"retention"`
+}
+
+type ObjectRetention struct {
+	RetainUntil string `json:"retain_until"` // RFC3339, empty when not retained
+	LegalHold   bool   `json:"legal_hold"`
 }
 
 type ObjectCksumProps struct {
@@ -409,6 +632,81 @@ type ObjectCksumProps struct {
 	Value string `json:"value"`
 }
 
+const (
+	// MaxCustomMDKeys limits the number of user-defined object metadata entries
+	MaxCustomMDKeys = 64
+	// MaxCustomMDSize limits the encoded size of user-defined object metadata (see CustomMDSize).
+	// NOTE: the metadata is stored in the object's xattr and sent (twice, with EC) in the
+	// transport header; both are sized accordingly (see cluster/lom_xattr.go, transport/send.go)
+	MaxCustomMDSize = 2 * KiB
+	// per-entry encoding overhead: length-prefixed key and value (transport header)
+	customMDEntryOverhead = 2 * SizeofI64
+)
+
+// separators of the packed object metadata (see cluster/lom_xattr.go) that cannot be
+// a part of user-defined metadata
+var customMDSepas = []string{"\x00", "\xe3/\xbd"}
+
+// CustomMDFromHeader collects user-defined object metadata from the request header:
+// every header that starts with the given prefix (case-insensitive) is a key-value pair
+// where the key is the rest of the header name (lowercased).
+func CustomMDFromHeader(header http.Header, prefix string) (md SimpleKVs, err error) {
+	prefix = strings.ToLower(prefix)
+	for name, values := range header {
+		lname := strings.ToLower(name)
+		if !strings.HasPrefix(lname, prefix) || len(values) == 0 {
+			continue
+		}
+		key := lname[len(prefix):]
+		if key == "" {
+			return nil, fmt.Errorf("invalid custom metadata header %q: empty key", name)
+		}
+		if md == nil {
+			md = make(SimpleKVs, 4)
+		}
+		md[key] = strings.Join(values, ",")
+	}
+	if err = ValidateCustomMD(md); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ValidateCustomMD checks the number of entries, the encoded size, and the contents
+// of user-defined object metadata.
+func ValidateCustomMD(md SimpleKVs) error {
+	if len(md) > MaxCustomMDKeys {
+		return fmt.Errorf("custom metadata has too many entries: %d (max %d)", len(md), MaxCustomMDKeys)
+	}
+	if size := CustomMDSize(md); size > MaxCustomMDSize {
+		return fmt.Errorf("custom metadata is too large: %d bytes encoded (max %d)", size, MaxCustomMDSize)
+	}
+	for k, v := range md {
+		for _, sepa := range customMDSepas {
+			if strings.Contains(k, sepa) || strings.Contains(v, sepa) {
+				return fmt.Errorf("invalid custom metadata %q: contains reserved separator %q", k, sepa)
+			}
+		}
+	}
+	return nil
+}
+
+// CustomMDSize returns the size of user-defined object metadata including the
+// per-entry encoding overhead.
+func CustomMDSize(md SimpleKVs) (size int) {
+	for k, v := range md {
+		size += len(k) + len(v) + customMDEntryOverhead
+	}
+	return
+}
+
+// CustomMDToHeader is the inverse of CustomMDFromHeader.
+func CustomMDToHeader(md SimpleKVs, header http.Header, prefix string) {
+	for k, v := range md {
+		header.Set(prefix+k, v)
+	}
+}
+
 func DefaultBucketProps() *BucketProps {
 	c := GCO.Clone()
 	if c.Cksum.Type == "" {
@@ -421,6 +719,7 @@ func DefaultBucketProps() *BucketProps {
 		Versioning:  c.Versioning,
 		AccessAttrs: allowAllAccess,
 		EC:          c.EC,
+		Retention:   RetentionConf{Mode: RetentionGovernance, Period: "0s"},
 	}
 }
 
@@ -430,6 +729,9 @@ func CloudBucketProps(header http.Header) (props *BucketProps) {
 
 	props.Provider = header.Get(HeaderCloudProvider)
 	Assert(IsValidProvider(props.Provider))
+	props.Extra.OrigURLBck = header.Get(HeaderOrigURLBck)
+	props.Extra.Manifest = header.Get(HeaderBucketManifest)
+	props.Extra.Path = header.Get(HeaderBucketPath)
 	if verStr := header.Get(HeaderBucketVerEnabled); verStr != "" {
 		versioning, err := ParseBool(verStr)
 		AssertNoErr(err)
@@ -475,13 +777,17 @@ func (bp *BucketProps) Validate(targetCnt int) error {
 		if !bp.BackendBck.IsCloud() {
 			return fmt.Errorf("backend bucket should point to cloud bucket")
 		}
+		if IsExplicitProvider(bp.BackendBck.Provider) {
+			return fmt.Errorf("backend bucket cannot be %q bucket", bp.BackendBck.Provider)
+		}
 		if bp.Provider != ProviderAIS {
 			return fmt.Errorf("backend bucket can only be set for AIS buckets")
 		}
 	}
 
-	validationArgs := &ValidationArgs{TargetCnt: targetCnt}
-	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC}
+	validationArgs := &ValidationArgs{TargetCnt: targetCnt, Provider: bp.Provider}
+	validators := []PropsValidator{&bp.Cksum, &bp.LRU, &bp.Mirror, &bp.EC, &bp.Retention, &bp.Lifecycle,
+		&bp.Replication, &bp.Extra}
 	for _, validator := range validators {
 		if err := validator.ValidateAsProps(validationArgs); err != nil {
 			return err
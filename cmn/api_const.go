@@ -14,6 +14,8 @@ const (
 	ChecksumXXHash = "xxhash"
 	ChecksumMD5    = "md5"
 	ChecksumCRC32C = "crc32c"
+	ChecksumSHA256 = "sha256"
+	ChecksumBLAKE3 = "blake3"
 )
 
 // module names
@@ -33,6 +35,7 @@ const (
 	ActRebalance     = "rebalance"
 	ActResilver      = "resilver"
 	ActLRU           = "lru"
+	ActScrub         = "scrub"
 	ActSyncLB        = "synclb"
 	ActCreateLB      = "createlb"
 	ActDestroyLB     = "destroylb"
@@ -47,6 +50,7 @@ const (
 	ActSummaryBucket = "summarybck"
 	ActRenameObject  = "renameobj"
 	ActPromote       = "promote"
+	ActObjRetention  = "objretention"
 	ActEvictObjects  = "evictobj"
 	ActDelete        = "delete"
 	ActPrefetch      = "prefetch"
@@ -57,14 +61,18 @@ const (
 	ActUnregProxy    = "unregproxy"
 	ActNewPrimary    = "newprimary"
 	ActRevokeToken   = "revoketoken"
+	ActAccessKeys    = "accesskeys"
 	ActElection      = "election"
 	ActPutCopies     = "putcopies"
 	ActMakeNCopies   = "makencopies"
 	ActLoadLomCache  = "loadlomcache"
-	ActECGet         = "ecget"    // erasure decode objects
-	ActECPut         = "ecput"    // erasure encode objects
-	ActECRespond     = "ecresp"   // respond to other targets' EC requests
-	ActECEncode      = "ecencode" // erasure code a bucket
+	ActLifecycle     = "lifecycle"
+	ActReplBackfill  = "replbackfill"
+	ActECGet         = "ecget"      // erasure decode objects
+	ActECPut         = "ecput"      // erasure encode objects
+	ActECRespond     = "ecresp"     // respond to other targets' EC requests
+	ActECEncode      = "ecencode"   // erasure code a bucket
+	ActECReencode    = "ecreencode" // re-encode a bucket after its EC configuration changes
 	ActStartGFN      = "metasync-start-gfn"
 	ActRecoverBck    = "recoverbck"
 	ActAsyncTask     = "task"
@@ -112,15 +120,26 @@ const (
 	HeaderBucketVerValidateWarm = "versioning.validate_warm_get" // Validate version on warm GET
 	HeaderBucketAccessAttrs     = "access"                       // Bucket access attributes
 	HeaderBucketCreated         = "created"                      // Bucket creation time
+	HeaderBucketLifecycleRules  = "lifecycle.rules"              // Lifecycle rules (JSON)
+	HeaderOrigURLBck            = "extra.original_url"           // ht:// bucket: base URL of the HTTP(S) origin
+	HeaderBucketManifest        = "extra.manifest"               // ht:// bucket: manifest to list the bucket
+	HeaderBucketPath            = "extra.path"                   // fs:// bucket: directory containing the objects
 
 	// object meta
 	HeaderObjCksumType = "checksum.type"  // Checksum Type, one of SupportedChecksums()
 	HeaderObjCksumVal  = "checksum.value" // Checksum Value
 	HeaderObjAtime     = "atime"          // Object access time
+	HeaderObjCtime     = "ctime"          // Object creation time
 	HeaderObjReplicSrc = "replica_src"    // In replication PUT request specifies the source target
 	HeaderObjSize      = "size"           // Object size (bytes)
 	HeaderObjVersion   = "version"        // Object version/generation - ais or Cloud
 	HeaderObjECMeta    = "ec_meta"        // Info about EC object/slice/replica
+	HeaderObjCustomMD  = "custom_md."     // Prefix of user-defined metadata headers: custom_md.<key>
+
+	// object retention (WORM)
+	HeaderObjRetainUntil  = "retention.retain_until" // RFC3339 time before which the object cannot be modified
+	HeaderObjLegalHold    = "retention.legal_hold"   // Legal hold: "true" or "false" (admin only)
+	HeaderBypassRetention = "retention.bypass"       // Override governance-mode retention (admin only)
 
 	// intra-cluster: control
 	HeaderCallerID          = "caller.id"
@@ -132,6 +151,7 @@ const (
 
 	// custom
 	HeaderAppendHandle = "append.handle"
+	HeaderDlSourceAuth = "download.source_auth" // Downloader: custom headers and credentials to access the source (JSON, base64)
 
 	// intra-cluster: streams
 	HeaderSessID   = "session.id"
@@ -192,6 +212,10 @@ const (
 	URLParamDescription       = "description"
 	URLParamLimitConnections  = "limit_conn"
 	URLParamLimitBytesPerHour = "limit_bph"
+	URLParamSyncInterval      = "sync_interval"
+	URLParamSyncSchedule      = "sync_schedule"
+	URLParamSyncDelete        = "sync_delete"
+	URLParamExtract           = "extract"
 
 	// 2PC (control plane)
 	URLParamTxnTimeout = "txntout" // transaction timeout
@@ -213,6 +237,7 @@ const (
 	GetWhatStats         = "stats"
 	GetWhatXactStats     = "xstats"
 	GetWhatXactRunStatus = "xrunstatus"
+	GetWhatXactHistory   = "xhistory"
 	GetWhatSmapVote      = "smapvote"
 	GetWhatMountpaths    = "mountpaths"
 	GetWhatSnode         = "snode"
@@ -229,15 +254,17 @@ const (
 
 // SelectMsg.Props enum
 const (
-	GetPropsChecksum = "checksum"
-	GetPropsSize     = "size"
-	GetPropsAtime    = "atime"
-	GetPropsCached   = "cached"
-	GetPropsVersion  = "version"
-	GetTargetURL     = "target_url"
-	GetPropsStatus   = "status"
-	GetPropsCopies   = "copies"
-	GetPropsEC       = "ec"
+	GetPropsChecksum  = "checksum"
+	GetPropsSize      = "size"
+	GetPropsAtime     = "atime"
+	GetPropsCached    = "cached"
+	GetPropsVersion   = "version"
+	GetTargetURL      = "target_url"
+	GetPropsStatus    = "status"
+	GetPropsCopies    = "copies"
+	GetPropsEC        = "ec"
+	GetPropsCustom    = "custom"
+	GetPropsRetention = "retention"
 )
 
 // BucketEntry.Status
@@ -252,6 +279,7 @@ const (
 	EntryStatusBits = 5                          // N bits
 	EntryStatusMask = (1 << EntryStatusBits) - 1 // mask for N low bits
 	EntryIsCached   = 1 << (EntryStatusBits + 1) // StatusMaskBits + 1
+	EntryIsDir      = 1 << (EntryStatusBits + 2) // StatusMaskBits + 2
 )
 
 // List objects default page size
@@ -259,29 +287,35 @@ const (
 	DefaultListPageSize = 1000
 )
 
+// List objects: delimiter that rolls up object names into directories
+const (
+	DirDelimiter = "/"
+)
+
 // RESTful URL path: l1/l2/l3
 const (
 	// l1
 	Version = "v1"
 	// l2
-	Buckets   = "buckets"
-	Objects   = "objects"
-	EC        = "ec"
-	Download  = "download"
-	Daemon    = "daemon"
-	Cluster   = "cluster"
-	Tokens    = "tokens"
-	Metasync  = "metasync"
-	Health    = "health"
-	Vote      = "vote"
-	Transport = "transport"
-	Reverse   = "reverse"
-	Rebalance = "rebalance"
-	Txn       = "txn" // 2PC
-	Xactions  = "xactions"
-	Tar2Tf    = "tar2tf"
-	Users     = "users"    // AuthN
-	Clusters  = "clusters" // AuthN
+	Buckets    = "buckets"
+	Objects    = "objects"
+	EC         = "ec"
+	Download   = "download"
+	Daemon     = "daemon"
+	Cluster    = "cluster"
+	Tokens     = "tokens"
+	AccessKeys = "accesskeys"
+	Metasync   = "metasync"
+	Health     = "health"
+	Vote       = "vote"
+	Transport  = "transport"
+	Reverse    = "reverse"
+	Rebalance  = "rebalance"
+	Txn        = "txn" // 2PC
+	Xactions   = "xactions"
+	Tar2Tf     = "tar2tf"
+	Users      = "users"    // AuthN
+	Clusters   = "clusters" // AuthN
 
 	// l3
 	SyncSmap     = "syncsmap"
@@ -307,6 +341,8 @@ const (
 	FinishedAck = "finished-ack"
 	List        = "list"
 	Remove      = "remove"
+	Resume      = "resume"
+	Checkpoint  = "checkpoint"
 
 	// CLI
 	Target = "target"
@@ -322,6 +358,12 @@ const (
 	CompressRatio  = "ratio=%d" // adaptive: min ratio that warrants compression
 )
 
+// enum: object retention (WORM) modes
+const (
+	RetentionGovernance = "governance" // admin can bypass retention
+	RetentionCompliance = "compliance" // no one can bypass retention
+)
+
 // AuthN consts
 const (
 	HeaderAuthorization = "Authorization"
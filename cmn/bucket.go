@@ -17,7 +17,10 @@ const (
 	ProviderGoogle = "gcp"
 	ProviderAIS    = "ais"
 	ProviderAzure  = "azure"
-	allProviders   = "aws, gcp, ais, azure"
+	ProviderHDFS   = "hdfs"
+	ProviderHTTP   = "ht"
+	ProviderFS     = "fs"
+	allProviders   = "aws, gcp, ais, azure, hdfs, ht, fs"
 
 	NsUUIDPrefix = '@' // BEWARE: used by on-disk layout
 	NsNamePrefix = '#' // BEWARE: used by on-disk layout
@@ -63,6 +66,9 @@ var (
 		ProviderGoogle: {},
 		ProviderAmazon: {},
 		ProviderAzure:  {},
+		ProviderHDFS:   {},
+		ProviderHTTP:   {},
+		ProviderFS:     {},
 	}
 )
 
@@ -192,6 +198,12 @@ func (b Bck) IsCloud(anyCloud ...string) bool { // is 3rd party Cloud
 }
 func (b Bck) HasProvider() bool { return IsValidProvider(b.Provider) }
 
+// Buckets of the "explicit" providers (ht://, fs://) are defined by their
+// props (see ExtraProps) - cannot be discovered and must be created explicitly.
+func IsExplicitProvider(provider string) bool {
+	return provider == ProviderHTTP || provider == ProviderFS
+}
+
 func IsValidProvider(provider string) bool {
 	_, ok := Providers[provider]
 	return ok
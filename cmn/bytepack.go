@@ -104,6 +104,9 @@ func NewPacker(buf []byte, bufLen int) *BytePack {
 // Unpacker
 //
 
+// Len returns the number of unread bytes
+func (br *ByteUnpack) Len() int { return len(br.b) - br.off }
+
 func (br *ByteUnpack) ReadByte() (byte, error) {
 	if br.off >= len(br.b) {
 		return 0, ErrorBufferUnderrun
@@ -6,6 +6,7 @@ package cmn
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"hash"
@@ -13,6 +14,7 @@ import (
 	"sort"
 
 	"github.com/OneOfOne/xxhash"
+	"github.com/zeebo/blake3"
 )
 
 const (
@@ -44,6 +46,8 @@ var (
 		ChecksumXXHash: {},
 		ChecksumMD5:    {},
 		ChecksumCRC32C: {},
+		ChecksumSHA256: {},
+		ChecksumBLAKE3: {},
 	}
 )
 
@@ -96,6 +100,10 @@ func NewCksumHash(ty string) *CksumHash {
 		return &CksumHash{Cksum{ty: ty}, md5.New(), nil}
 	case ChecksumCRC32C:
 		return &CksumHash{Cksum{ty: ty}, NewCRC32C(), nil}
+	case ChecksumSHA256:
+		return &CksumHash{Cksum{ty: ty}, sha256.New(), nil}
+	case ChecksumBLAKE3:
+		return &CksumHash{Cksum{ty: ty}, blake3.New(), nil}
 	default:
 		AssertMsg(false, ValidateCksumType(ty).Error())
 	}
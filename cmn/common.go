@@ -46,6 +46,8 @@ const (
 	ExtTarTgz = ".tar.gz"
 	// ExtZip is zip files extension
 	ExtZip = ".zip"
+	// ExtTFRecord is TFRecord files extension
+	ExtTFRecord = ".tfrecord"
 
 	// Constant seeds for UUID generator
 	uuidWorker = 1
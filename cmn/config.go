@@ -9,6 +9,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -28,6 +29,8 @@ const (
 
 	KeepaliveHeartbeatType = "heartbeat"
 	KeepaliveAverageType   = "average"
+
+	DefaultHDFSNameNode = "http://localhost:9870" // WebHDFS default (Hadoop 3.x)
 )
 
 const (
@@ -57,7 +60,8 @@ const (
 
 type (
 	ValidationArgs struct {
-		TargetCnt int // for EC
+		TargetCnt int    // for EC
+		Provider  string // bucket's provider
 	}
 
 	Validator interface {
@@ -225,6 +229,8 @@ var (
 	_ Validator = &FSPathsConf{}
 	_ Validator = &TestfspathConf{}
 	_ Validator = &CompressionConf{}
+	_ Validator = &FSBucketsConf{}
+	_ Validator = &ScrubConf{}
 
 	_ PropsValidator = &CksumConf{}
 	_ PropsValidator = &LRUConf{}
@@ -270,6 +276,8 @@ type Config struct {
 	Downloader       DownloaderConf  `json:"downloader"`
 	DSort            DSortConf       `json:"distributed_sort"`
 	Compression      CompressionConf `json:"compression"`
+	FSBuckets        FSBucketsConf   `json:"fs_buckets"`
+	Scrub            ScrubConf       `json:"scrub"`
 }
 
 type CloudConf struct {
@@ -290,6 +298,13 @@ type RemoteAISInfo struct {
 }
 
 type CloudConfAIS map[string][]string // cluster alias -> [urls...]
+
+// HDFS is accessed via WebHDFS REST API; each bucket is a directory under the `root`
+type CloudConfHDFS struct {
+	NameNode string `json:"namenode"` // WebHDFS endpoint, e.g. http://namenode:9870
+	User     string `json:"user"`     // user name (simple authentication)
+	Root     string `json:"root"`     // HDFS directory containing buckets
+}
 type CloudInfoAIS map[string]*RemoteAISInfo
 
 type MirrorConf struct {
@@ -533,6 +548,21 @@ type KeepaliveConf struct {
 type DownloaderConf struct {
 	TimeoutStr string        `json:"timeout"`
 	Timeout    time.Duration `json:"-"`
+	Retries    int           `json:"retries"` // number of download attempts (0 - default)
+}
+
+// FSBucketsConf - fs:// buckets (see ExtraProps)
+type FSBucketsConf struct {
+	// directories under which fs:// buckets can be created (empty - fs:// buckets are disabled);
+	// symlinks get resolved before checking
+	Roots []string `json:"roots"`
+}
+
+// ScrubConf - background data scrubbing (see housekeep/scrub)
+type ScrubConf struct {
+	// IntervalStr: how often targets start the scrub ("" or "0" - only on demand)
+	IntervalStr string        `json:"interval"`
+	Interval    time.Duration `json:"-"`
 }
 
 type DSortConf struct {
@@ -692,6 +722,25 @@ func (c *CloudConf) validateConf(provider string) (err error) {
 			break
 		}
 		conf = aisConf
+	case ProviderHDFS:
+		var hdfsConf CloudConfHDFS
+		if err := jsoniter.Unmarshal(b, &hdfsConf); err != nil {
+			return fmt.Errorf("invalid cloud specification: %v", err)
+		}
+		if hdfsConf.NameNode == "" {
+			hdfsConf.NameNode = DefaultHDFSNameNode
+		}
+		if _, err := url.ParseRequestURI(hdfsConf.NameNode); err != nil {
+			return fmt.Errorf("invalid HDFS namenode URL %q: %v", hdfsConf.NameNode, err)
+		}
+		if hdfsConf.Root == "" {
+			hdfsConf.Root = "/"
+		}
+		if !strings.HasPrefix(hdfsConf.Root, "/") {
+			return fmt.Errorf("HDFS root directory %q must be an absolute path", hdfsConf.Root)
+		}
+		c.Ns = NsGlobal
+		conf = hdfsConf
 	case ProviderAmazon, ProviderGoogle, ProviderAzure:
 		c.Ns = NsGlobal
 	default:
@@ -807,6 +856,11 @@ func (c *ECConf) Validate(_ *Config) error {
 		return fmt.Errorf("invalid ec.parity_slices: %d (expected value in range [%d, %d])",
 			c.ParitySlices, MinSliceCount, MaxSliceCount)
 	}
+	// every local group must contain at least one data slice
+	if c.LocalGroups < 0 || c.LocalGroups > c.DataSlices {
+		return fmt.Errorf("invalid ec.local_groups: %d (expected value in range [0, %d])",
+			c.LocalGroups, c.DataSlices)
+	}
 	if c.BatchSize == 0 {
 		c.BatchSize = 64
 	}
@@ -825,13 +879,104 @@ func (c *ECConf) ValidateAsProps(args *ValidationArgs) error {
 	}
 	if required := c.RequiredEncodeTargets(); args.TargetCnt < required {
 		return fmt.Errorf(
-			"erasure coding requires %d targets to use %d data and %d parity slices "+
+			"erasure coding requires %d targets to use %d data, %d parity, and %d local parity slices "+
 				"(the cluster has only %d targets)",
-			required, c.DataSlices, c.ParitySlices, args.TargetCnt)
+			required, c.DataSlices, c.ParitySlices, c.LocalGroups, args.TargetCnt)
+	}
+	return nil
+}
+
+func (c *RetentionConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Mode != RetentionGovernance && c.Mode != RetentionCompliance {
+		return fmt.Errorf("invalid retention.mode: %q (expected one of [%s, %s])",
+			c.Mode, RetentionGovernance, RetentionCompliance)
+	}
+	if period, err := time.ParseDuration(c.Period); err != nil || period < 0 {
+		return fmt.Errorf("invalid retention.period: %q (expected non-negative duration)", c.Period)
+	}
+	return nil
+}
+
+func (c *LifecycleConf) ValidateAsProps(_ *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.Rules) == 0 {
+		return errors.New("lifecycle.rules: at least one rule is required")
+	}
+	for i, rule := range c.Rules {
+		if rule.ExpireDays < 0 || rule.EvictDays < 0 {
+			return fmt.Errorf("lifecycle.rules[%d]: number of days must be non-negative", i)
+		}
+		if rule.ExpireDays == 0 && rule.EvictDays == 0 && !rule.DeleteNoncurrent {
+			return fmt.Errorf("lifecycle.rules[%d]: no action specified", i)
+		}
 	}
 	return nil
 }
 
+func (c *BckReplicationConf) ValidateAsProps(args *ValidationArgs) error {
+	if !c.Enabled {
+		return nil
+	}
+	if args.Provider != ProviderAIS {
+		return fmt.Errorf("replication is supported only by %q buckets", ProviderAIS)
+	}
+	if c.Cluster == "" {
+		return errors.New("replication.cluster: UUID or alias of the remote AIS cluster is required")
+	}
+	return nil
+}
+
+func (c *ExtraProps) ValidateAsProps(args *ValidationArgs) error {
+	if args.Provider != ProviderHTTP && (c.OrigURLBck != "" || c.Manifest != "") {
+		return fmt.Errorf("extra.original_url and extra.manifest are supported only by %q buckets", ProviderHTTP)
+	}
+	if args.Provider != ProviderFS && c.Path != "" {
+		return fmt.Errorf("extra.path is supported only by %q buckets", ProviderFS)
+	}
+	switch args.Provider {
+	case ProviderHTTP:
+		u, err := url.Parse(c.OrigURLBck)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("extra.original_url: invalid HTTP(S) URL %q", c.OrigURLBck)
+		}
+	case ProviderFS:
+		if !filepath.IsAbs(c.Path) {
+			return fmt.Errorf("extra.path: %q is not an absolute path", c.Path)
+		}
+		// NOTE: symlinks get resolved and checked by the targets (see ais/cloud/fs.go)
+		if conf := &GCO.Get().FSBuckets; !conf.Allows(filepath.Clean(c.Path)) {
+			return fmt.Errorf("extra.path: %q is not under any of the allowed roots %v (fs_buckets.roots)",
+				c.Path, conf.Roots)
+		}
+	}
+	return nil
+}
+
+func (c *FSBucketsConf) Validate(_ *Config) error {
+	for i, root := range c.Roots {
+		if !filepath.IsAbs(root) {
+			return fmt.Errorf("invalid fs_buckets.roots: %q is not an absolute path", root)
+		}
+		c.Roots[i] = filepath.Clean(root)
+	}
+	return nil
+}
+
+// Allows returns true if the (clean, absolute) path is one of the roots or is located under one
+func (c *FSBucketsConf) Allows(path string) bool {
+	for _, root := range c.Roots {
+		if path == root || strings.HasPrefix(path, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *TimeoutConf) Validate(_ *Config) (err error) {
 	if c.MaxKeepalive, err = time.ParseDuration(c.MaxKeepaliveStr); err != nil {
 		return fmt.Errorf("invalid timeout.max_keepalive format %s, err %v", c.MaxKeepaliveStr, err)
@@ -964,6 +1109,20 @@ func (c *DownloaderConf) Validate(_ *Config) (err error) {
 	if c.Timeout, err = time.ParseDuration(c.TimeoutStr); err != nil {
 		return fmt.Errorf("invalid downloader.timeout %s", c.TimeoutStr)
 	}
+	if c.Retries < 0 {
+		return fmt.Errorf("invalid downloader.retries %d (expected non-negative)", c.Retries)
+	}
+	return nil
+}
+
+func (c *ScrubConf) Validate(_ *Config) (err error) {
+	if c.IntervalStr == "" {
+		c.Interval = 0
+		return nil
+	}
+	if c.Interval, err = time.ParseDuration(c.IntervalStr); err != nil || c.Interval < 0 {
+		return fmt.Errorf("invalid scrub.interval %s (expected non-negative duration)", c.IntervalStr)
+	}
 	return nil
 }
 
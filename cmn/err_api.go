@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 )
 
 ///////////////////////////////////////////////////////
@@ -67,6 +68,11 @@ type (
 		details string
 		cause   error
 	}
+	ObjRetainedError struct {
+		name        string // object's name
+		retainUntil int64  // unix nano
+		legalHold   bool
+	}
 )
 
 func _errBucket(msg, node string) string {
@@ -169,6 +175,22 @@ func (e ObjMetaErr) Error() string {
 
 func NewObjMetaErr(name string, err error) ObjMetaErr { return ObjMetaErr{name: name, err: err} }
 
+func (e ObjRetainedError) Error() string {
+	if e.legalHold {
+		return fmt.Sprintf("object %s is under legal hold", e.name)
+	}
+	return fmt.Sprintf("object %s is retained until %s", e.name, time.Unix(0, e.retainUntil).UTC().Format(time.RFC3339))
+}
+
+func NewObjRetainedError(name string, retainUntil int64, legalHold bool) ObjRetainedError {
+	return ObjRetainedError{name: name, retainUntil: retainUntil, legalHold: legalHold}
+}
+
+func IsErrObjRetained(err error) bool {
+	_, ok := err.(ObjRetainedError)
+	return ok
+}
+
 func NewAbortedError(what string) AbortedError {
 	return AbortedError{
 		what:    what,
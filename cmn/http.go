@@ -29,6 +29,14 @@ var (
 	jsonAPI jsoniter.API
 )
 
+type ctxKey string
+
+// CtxOriginHeader is a context key of http.Header which is added to the
+// requests to HTTP origin of ht:// bucket (eg. credentials of the download).
+const CtxOriginHeader ctxKey = "originHeader"
+
+const maxOriginRedirects = 10 // same as the default policy of http.Client
+
 type (
 	// Error structure for HTTP errors
 	HTTPError struct {
@@ -360,3 +368,21 @@ func MakeHeaderAuthnToken(token string) string {
 func IsHTTPS(urlPath string) bool {
 	return strings.HasPrefix(urlPath, "https://")
 }
+
+// StripOriginHeader is the redirect policy (http.Client.CheckRedirect) of the
+// clients sending the headers given in the request's context (see CtxOriginHeader).
+// The headers are meant for the origin only and are not forwarded when the
+// request is redirected to another host (http.Client itself drops only the
+// standard sensitive headers, and only when leaving the origin's domain).
+func StripOriginHeader(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxOriginRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxOriginRedirects)
+	}
+	header, ok := req.Context().Value(CtxOriginHeader).(http.Header)
+	if ok && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+		for key := range header {
+			req.Header.Del(key)
+		}
+	}
+	return nil
+}
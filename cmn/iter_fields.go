@@ -9,6 +9,8 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+
+	jsoniter "github.com/json-iterator/go"
 )
 
 const (
@@ -92,6 +94,15 @@ func (f *field) SetValue(src interface{}, force ...bool) error {
 				return err
 			}
 			dst.SetFloat(n)
+		case reflect.Slice:
+			// slices (e.g. list of rules) are represented as JSON
+			if s == "" {
+				dst.Set(reflect.Zero(dst.Type()))
+				break
+			}
+			if err := jsoniter.Unmarshal([]byte(s), dst.Addr().Interface()); err != nil {
+				return fmt.Errorf("invalid value of %q (expected JSON list): %v", f.name, err)
+			}
 		case reflect.Ptr:
 			dst.Set(reflect.New(dst.Type().Elem())) // set pointer to default value
 			dst = dst.Elem()                        // dereference pointer
@@ -0,0 +1,58 @@
+// Package cmn provides common low-level types and utilities for all aistore projects
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package cmn
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+)
+
+// NewSecretCipher returns AES-256-GCM cipher with the key derived from the
+// given secret (e.g., `auth.secret` of the cluster configuration). The purpose
+// separates the keys that are derived from the same secret for different data.
+func NewSecretCipher(secret, purpose string) (cipher.AEAD, error) {
+	if secret == "" {
+		return nil, errors.New("secret is empty")
+	}
+	key := sha256.Sum256([]byte(purpose + "\x00" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// SealSecret encrypts the plaintext and returns base64-encoded nonce followed
+// by the ciphertext.
+func SealSecret(aead cipher.AEAD, plaintext string) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenSecret decrypts the value returned by SealSecret.
+func OpenSecret(aead cipher.AEAD, sealed string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	nonceSize := aead.NonceSize()
+	if len(b) < nonceSize {
+		return "", errors.New("ciphertext too short")
+	}
+	plaintext, err := aead.Open(nil, b[:nonceSize], b[nonceSize:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -5,6 +5,10 @@
 package tests
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/NVIDIA/aistore/api"
 	"github.com/NVIDIA/aistore/cmn"
 	. "github.com/onsi/ginkgo"
@@ -179,4 +183,94 @@ var _ = Describe("API", func() {
 			),
 		)
 	})
+
+	Describe("SelectMsg", func() {
+		DescribeTable("should roll up object names into directories",
+			func(prefix, delimiter, objName, expectedDir string, expectedOK bool) {
+				msg := &cmn.SelectMsg{Prefix: prefix, Delimiter: delimiter}
+				dir, ok := msg.DirName(objName)
+				Expect(ok).To(Equal(expectedOK))
+				Expect(dir).To(Equal(expectedDir))
+			},
+			Entry("no delimiter", "", "", "a/b/c", "", false),
+			Entry("no prefix", "", "/", "a/b/c", "a/", true),
+			Entry("object at the top level", "", "/", "abc", "", false),
+			Entry("prefix is a directory", "a/", "/", "a/b/c", "a/b/", true),
+			Entry("object inside prefix directory", "a/", "/", "a/b", "", false),
+			Entry("prefix is not a directory", "a/b", "/", "a/bcd/e", "a/bcd/", true),
+			Entry("object does not match prefix", "x/", "/", "a/b/c", "", false),
+			Entry("multi-character delimiter", "", "--", "a--b--c", "a--", true),
+		)
+
+		DescribeTable("should skip objects returned in the previous pages",
+			func(marker, delimiter, objName string, expected bool) {
+				msg := &cmn.SelectMsg{PageMarker: marker, Delimiter: delimiter}
+				Expect(msg.SkipMarked(objName)).To(Equal(expected))
+			},
+			Entry("no marker", "", "/", "a/b", false),
+			Entry("object before marker", "b", "", "a", true),
+			Entry("object after marker", "b", "", "c", false),
+			Entry("object inside directory marker", "a/", "/", "a/b/c", true),
+			Entry("object after directory marker", "a/", "/", "b", false),
+			Entry("directory marker without delimiter", "a/", "", "a/b/c", false),
+		)
+	})
+
+	Describe("CustomMD", func() {
+		It("should convert user-defined metadata to and from headers", func() {
+			md := cmn.SimpleKVs{"author": "john", "source": "s3://bck/obj"}
+			header := http.Header{}
+			cmn.CustomMDToHeader(md, header, cmn.HeaderObjCustomMD)
+			header.Set(cmn.HeaderObjVersion, "1")
+
+			parsed, err := cmn.CustomMDFromHeader(header, cmn.HeaderObjCustomMD)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(md))
+		})
+
+		It("should lowercase keys and ignore unrelated headers", func() {
+			header := http.Header{}
+			header.Set("X-Amz-Meta-Author", "john")
+			header.Set("X-Amz-Version-Id", "1")
+
+			parsed, err := cmn.CustomMDFromHeader(header, "x-amz-meta-")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parsed).To(Equal(cmn.SimpleKVs{"author": "john"}))
+		})
+
+		It("should fail if metadata is too large", func() {
+			header := http.Header{}
+			header.Set(cmn.HeaderObjCustomMD+"key", strings.Repeat("x", cmn.MaxCustomMDSize))
+			_, err := cmn.CustomMDFromHeader(header, cmn.HeaderObjCustomMD)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if metadata has too many entries", func() {
+			header := http.Header{}
+			for i := 0; i < 680; i++ {
+				header.Set(fmt.Sprintf("%s%02x", cmn.HeaderObjCustomMD, i), "v")
+			}
+			_, err := cmn.CustomMDFromHeader(header, cmn.HeaderObjCustomMD)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should account for encoding overhead", func() {
+			// 60 entries: 1560 bytes of keys and values, 2520 bytes encoded
+			md := make(cmn.SimpleKVs, 60)
+			for i := 0; i < 60; i++ {
+				md[fmt.Sprintf("key-%02d", i)] = strings.Repeat("x", 20)
+			}
+			Expect(cmn.ValidateCustomMD(md)).To(HaveOccurred())
+			for i := 0; i < 12; i++ {
+				delete(md, fmt.Sprintf("key-%02d", i))
+			}
+			Expect(cmn.ValidateCustomMD(md)).NotTo(HaveOccurred())
+		})
+
+		It("should fail if metadata contains separators", func() {
+			for _, md := range []cmn.SimpleKVs{{"author": "jo\x00hn"}, {"author": "jo\xe3/\xbdhn"}, {"auth\x00or": "john"}} {
+				Expect(cmn.ValidateCustomMD(md)).To(HaveOccurred())
+			}
+		})
+	})
 })
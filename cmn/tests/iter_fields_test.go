@@ -67,6 +67,7 @@ var _ = Describe("IterFields", func() {
 					"ec.enabled":       true,
 					"ec.parity_slices": 1024,
 					"ec.data_slices":   0,
+					"ec.local_groups":  0,
 					"ec.batch_size":    32,
 					"ec.objsize_limit": int64(0),
 					"ec.compression":   "",
@@ -87,6 +88,22 @@ var _ = Describe("IterFields", func() {
 					"lru.dont_evict_time":   "",
 					"lru.capacity_upd_time": "",
 
+					"replication.enabled": false,
+					"replication.cluster": "",
+					"replication.bucket":  "",
+
+					"retention.enabled": false,
+					"retention.mode":    "",
+					"retention.period":  "",
+
+					"lifecycle.enabled": false,
+					"lifecycle.rules":   []cmn.LifecycleRule(nil),
+
+					"extra.original_url": "",
+					"extra.manifest":     "",
+
+					"extra.path": "",
+
 					"access":  uint64(0),
 					"created": int64(0),
 				},
@@ -116,6 +133,7 @@ var _ = Describe("IterFields", func() {
 					"ec.enabled":       api.Bool(true),
 					"ec.parity_slices": api.Int(1024),
 					"ec.data_slices":   (*int)(nil),
+					"ec.local_groups":  (*int)(nil),
 					"ec.objsize_limit": (*int64)(nil),
 					"ec.compression":   (*string)(nil),
 
@@ -133,6 +151,22 @@ var _ = Describe("IterFields", func() {
 					"lru.highwm":       (*int64)(nil),
 					"lru.out_of_space": (*int64)(nil),
 
+					"replication.enabled": (*bool)(nil),
+					"replication.cluster": (*string)(nil),
+					"replication.bucket":  (*string)(nil),
+
+					"retention.enabled": (*bool)(nil),
+					"retention.mode":    (*string)(nil),
+					"retention.period":  (*string)(nil),
+
+					"lifecycle.enabled": (*bool)(nil),
+					"lifecycle.rules":   (*[]cmn.LifecycleRule)(nil),
+
+					"extra.original_url": (*string)(nil),
+					"extra.manifest":     (*string)(nil),
+
+					"extra.path": (*string)(nil),
+
 					"access": api.Uint64(1024),
 				},
 			),
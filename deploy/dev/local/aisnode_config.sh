@@ -15,6 +15,7 @@ cat > $AIS_CONF_FILE <<EOL
 		"objsize_limit": ${OBJ_SIZE_LIMIT:-262144},
 		"data_slices":   ${DATA_SLICES:-1},
 		"parity_slices": ${PARITY_SLICES:-1},
+		"local_groups":  ${LOCAL_GROUPS:-0},
 		"compression":   "${COMPRESSION:-never}",
 		"enabled":       ${EC_ENABLED:-false},
 		"batch_size":    ${EC_BATCH_SIZE:-64}
@@ -141,7 +142,14 @@ cat > $AIS_CONF_FILE <<EOL
 		"timeout_factor": 3
 	},
 	"downloader": {
-		"timeout": "1h"
+		"timeout": "1h",
+		"retries": 10
+	},
+	"fs_buckets": {
+		"roots": []
+	},
+	"scrub": {
+		"interval": "${SCRUB_INTERVAL:-0}"
 	},
 	"distributed_sort": {
 		"duplicated_records":    "ignore",
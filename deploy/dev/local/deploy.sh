@@ -145,6 +145,7 @@ echo " 0: No 3rd party Cloud"
 echo " 1: Amazon S3"
 echo " 2: Google Cloud Storage"
 echo " 3: Azure Cloud"
+echo " 4: HDFS"
 read -r cld_provider
 is_number ${cld_provider}
 
@@ -156,8 +157,10 @@ elif [[ ${cld_provider} -eq 2 ]]; then
   AIS_CLD_PROVIDER="gcp"
 elif [[ ${cld_provider} -eq 3 ]]; then
   AIS_CLD_PROVIDER="azure"
+elif [[ ${cld_provider} -eq 4 ]]; then
+  AIS_CLD_PROVIDER="hdfs"
 else
-  printError "${cld_provider} is not a valid entry - expecting 0, 1, 2, or 3"
+  printError "${cld_provider} is not a valid entry - expecting 0, 1, 2, 3, or 4"
 fi
 
 if ! AIS_CLD_PROVIDER=${AIS_CLD_PROVIDER} make --no-print-directory -C ${AISTORE_DIR} node; then
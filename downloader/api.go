@@ -5,8 +5,12 @@
 package downloader
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
 	"path"
 	"regexp"
@@ -15,6 +19,7 @@ import (
 	"time"
 
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 )
 
 type (
@@ -35,6 +40,11 @@ type (
 		Aborted       bool      `json:"aborted"`
 		StartedTime   time.Time `json:"started_time"`
 		FinishedTime  time.Time `json:"finished_time"`
+
+		// sync job only
+		Sync    bool      `json:"sync,omitempty"`
+		LastRun time.Time `json:"last_run,omitempty"` // start of the last (or current) run
+		NextRun time.Time `json:"next_run,omitempty"` // zero if no more runs are scheduled
 	}
 
 	DlJobInfos []DlJobInfo
@@ -65,6 +75,13 @@ func (j *DlJobInfo) Aggregate(rhs DlJobInfo) {
 			j.FinishedTime = rhs.FinishedTime
 		}
 	}
+	j.Sync = j.Sync || rhs.Sync
+	if j.LastRun.Before(rhs.LastRun) {
+		j.LastRun = rhs.LastRun
+	}
+	if cmn.IsTimeZero(j.NextRun) || (!cmn.IsTimeZero(rhs.NextRun) && rhs.NextRun.Before(j.NextRun)) {
+		j.NextRun = rhs.NextRun
+	}
 }
 
 func (j DlJobInfo) JobFinished() bool {
@@ -144,11 +161,77 @@ type DlLimits struct {
 	BytesPerHour int `json:"bytes_per_hour"`
 }
 
+// DlCredentials authenticate the requests to the source of the download:
+// either basic authentication or bearer token.
+type DlCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
+}
+
+func (c *DlCredentials) IsEmpty() bool { return c.Username == "" && c.Password == "" && c.Token == "" }
+
+// dlSourceAuth contains the custom headers and credentials of the job. It is
+// sent to the cluster in the request header (see: DlBase.AsHeader) and never
+// returned by the cluster.
+type dlSourceAuth struct {
+	Headers     map[string]string `json:"headers,omitempty"`
+	Credentials DlCredentials     `json:"credentials,omitempty"`
+}
+
+func (a *dlSourceAuth) isEmpty() bool { return len(a.Headers) == 0 && a.Credentials.IsEmpty() }
+
+func (a *dlSourceAuth) validate() error {
+	for key, value := range a.Headers {
+		if key == "" || strings.ContainsAny(key, " \t\r\n:") || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("invalid request header %q", key)
+		}
+		if http.CanonicalHeaderKey(key) == cmn.HeaderAuthorization && !a.Credentials.IsEmpty() {
+			return fmt.Errorf("%q header cannot be used together with credentials", cmn.HeaderAuthorization)
+		}
+	}
+	creds := a.Credentials
+	if creds.Password != "" && creds.Username == "" {
+		return errors.New("password requires username")
+	}
+	if creds.Token != "" && creds.Username != "" {
+		return errors.New("username and token cannot be used together")
+	}
+	return nil
+}
+
+// header returns the headers to be added to the requests to the source.
+func (a *dlSourceAuth) header() http.Header {
+	if a == nil {
+		return nil
+	}
+	header := make(http.Header, len(a.Headers)+1)
+	for key, value := range a.Headers {
+		header.Set(key, value)
+	}
+	if a.Credentials.Token != "" {
+		header.Set(cmn.HeaderAuthorization, cmn.HeaderBearer+" "+a.Credentials.Token)
+	} else if a.Credentials.Username != "" {
+		userPass := a.Credentials.Username + ":" + a.Credentials.Password
+		header.Set(cmn.HeaderAuthorization, "Basic "+base64.StdEncoding.EncodeToString([]byte(userPass)))
+	}
+	return header
+}
+
 type DlBase struct {
 	Description string   `json:"description"`
 	Bck         cmn.Bck  `json:"bck"`
 	Timeout     string   `json:"timeout"`
 	Limits      DlLimits `json:"limits"`
+
+	// Extract the members of the downloaded archives (.tar, .tar.gz, .tgz,
+	// .zip) and store them as separate objects instead of the archives.
+	Extract bool `json:"extract"`
+
+	// Custom request headers (eg. API key, signed cookies) and credentials
+	// used to access the source. Sent in the request header, never returned.
+	Headers     map[string]string `json:"-"`
+	Credentials DlCredentials     `json:"-"`
 }
 
 func (b *DlBase) InitWithQuery(query url.Values) {
@@ -164,6 +247,7 @@ func (b *DlBase) InitWithQuery(query url.Values) {
 	b.Limits.Connections = int(x)
 	x, _ = cmn.S2B(query.Get(cmn.URLParamLimitBytesPerHour))
 	b.Limits.BytesPerHour = int(x)
+	b.Extract, _ = cmn.ParseBool(query.Get(cmn.URLParamExtract))
 }
 
 func (b *DlBase) AsQuery() url.Values {
@@ -189,13 +273,65 @@ func (b *DlBase) AsQuery() url.Values {
 	if b.Limits.BytesPerHour > 0 {
 		query.Add(cmn.URLParamLimitBytesPerHour, strconv.FormatInt(int64(b.Limits.BytesPerHour), 10))
 	}
+	if b.Extract {
+		query.Add(cmn.URLParamExtract, "true")
+	}
 	return query
 }
 
+// InitWithHeader reads the custom headers and credentials of the job.
+func (b *DlBase) InitWithHeader(header http.Header) error {
+	value := header.Get(cmn.HeaderDlSourceAuth)
+	if value == "" {
+		return nil
+	}
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("invalid %q header: %v", cmn.HeaderDlSourceAuth, err)
+	}
+	auth := dlSourceAuth{}
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return fmt.Errorf("invalid %q header: %v", cmn.HeaderDlSourceAuth, err)
+	}
+	if err := auth.validate(); err != nil {
+		return err
+	}
+	b.Headers, b.Credentials = auth.Headers, auth.Credentials
+	return nil
+}
+
+// AsHeader returns the request header with custom headers and credentials of
+// the job, nil if there are none. They are not sent in the query so that they
+// do not appear in URLs (and logs).
+func (b *DlBase) AsHeader() http.Header {
+	auth := b.sourceAuth()
+	if auth == nil {
+		return nil
+	}
+	data, err := json.Marshal(auth)
+	cmn.AssertNoErr(err)
+	header := make(http.Header, 1)
+	header.Set(cmn.HeaderDlSourceAuth, base64.StdEncoding.EncodeToString(data))
+	return header
+}
+
+func (b *DlBase) sourceAuth() *dlSourceAuth {
+	auth := &dlSourceAuth{Headers: b.Headers, Credentials: b.Credentials}
+	if auth.isEmpty() {
+		return nil
+	}
+	return auth
+}
+
 func (b *DlBase) Validate() error {
 	if b.Bck.Name == "" {
 		return fmt.Errorf("missing the %q", cmn.URLParamBucket)
 	}
+	if auth := b.sourceAuth(); auth != nil {
+		if err := auth.validate(); err != nil {
+			return err
+		}
+	}
 	if b.Timeout != "" {
 		if _, err := time.ParseDuration(b.Timeout); err != nil {
 			return fmt.Errorf("failed to parse timeout field: %v", err)
@@ -233,6 +369,42 @@ func (b *DlSingleObj) Validate() error {
 	return nil
 }
 
+// DlManifestObj is a single entry of the multi download manifest. Downloaded
+// content is verified against the size and all the checksums (hex encoded)
+// which are provided.
+type DlManifestObj struct {
+	ObjName string `json:"object_name"`
+	Link    string `json:"link"`
+	Size    int64  `json:"size"`
+	MD5     string `json:"md5"`
+	SHA256  string `json:"sha256"`
+	CRC32C  string `json:"crc32c"`
+}
+
+func (e *DlManifestObj) Validate() error {
+	if e.Link == "" {
+		return fmt.Errorf("missing the %q in manifest entry (object: %q)", cmn.URLParamLink, e.ObjName)
+	}
+	if e.ObjName == "" {
+		objName := path.Base(e.Link)
+		if objName == "." || objName == "/" {
+			return fmt.Errorf("can not extract a valid `object name` from the provided download link: %q", e.Link)
+		}
+		e.ObjName = objName
+	}
+	if e.Size < 0 {
+		return fmt.Errorf("manifest entry %q: size must be non-negative (got: %d)", e.ObjName, e.Size)
+	}
+	for _, cksum := range []struct{ ty, value string }{
+		{cmn.ChecksumMD5, e.MD5}, {cmn.ChecksumSHA256, e.SHA256}, {cmn.ChecksumCRC32C, e.CRC32C},
+	} {
+		if _, err := hex.DecodeString(cksum.value); err != nil {
+			return fmt.Errorf("manifest entry %q: invalid %s checksum %q", e.ObjName, cksum.ty, cksum.value)
+		}
+	}
+	return nil
+}
+
 // Internal status/delete request body
 type DlAdminBody struct {
 	ID    string `json:"id"`
@@ -434,6 +606,30 @@ func (b *DlMultiBody) ExtractPayload(objectsPayload interface{}) (cmn.SimpleKVs,
 	return objects, nil
 }
 
+// IsManifest returns true if the payload is an array of manifest entries
+// (objects) rather than an array of links.
+func (b *DlMultiBody) IsManifest(objectsPayload interface{}) bool {
+	list, ok := objectsPayload.([]interface{})
+	if !ok || len(list) == 0 {
+		return false
+	}
+	_, ok = list[0].(map[string]interface{})
+	return ok
+}
+
+func (b *DlMultiBody) ExtractManifest(body []byte) ([]DlManifestObj, error) {
+	var manifest []DlManifestObj
+	if err := jsoniter.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("JSON body should be array of manifest entries: %v", err)
+	}
+	for i := range manifest {
+		if err := manifest[i].Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
 func (b *DlMultiBody) Describe() string {
 	return fmt.Sprintf("multi-download -> %s", b.Bck)
 }
@@ -442,33 +638,87 @@ func (b *DlMultiBody) String() string {
 	return fmt.Sprintf("bucket: %q", b.Bck)
 }
 
+// DlSync turns the cloud download into a sync job which is re-run either with
+// a fixed period or according to a cron-like schedule. Each run downloads only
+// the objects which are new or have changed since the previous one.
+type DlSync struct {
+	Interval string `json:"interval"` // fixed period between the runs, eg. "6h"
+	Schedule string `json:"schedule"` // cron expression: "minute hour day-of-month month day-of-week"
+	Delete   bool   `json:"delete"`   // evict cached objects which have been removed from the cloud bucket
+}
+
+func (s *DlSync) IsScheduled() bool { return s.Interval != "" || s.Schedule != "" }
+
+func (s *DlSync) Validate() error {
+	if !s.IsScheduled() {
+		return nil
+	}
+	if s.Interval != "" && s.Schedule != "" {
+		return fmt.Errorf("%q and %q cannot be used together", cmn.URLParamSyncInterval, cmn.URLParamSyncSchedule)
+	}
+	_, err := parseSchedule(*s)
+	return err
+}
+
+func (s *DlSync) String() string {
+	var sb strings.Builder
+	if s.Interval != "" {
+		sb.WriteString("every " + s.Interval)
+	} else if s.Schedule != "" {
+		sb.WriteString("at \"" + s.Schedule + "\"")
+	}
+	if s.Delete {
+		if sb.Len() > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("delete removed")
+	}
+	return sb.String()
+}
+
 // Cloud request
 type DlCloudBody struct {
 	DlBase
 	Prefix string `json:"prefix"`
 	Suffix string `json:"suffix"`
+	Sync   DlSync `json:"sync"`
 }
 
 func (b *DlCloudBody) InitWithQuery(query url.Values) {
 	b.DlBase.InitWithQuery(query)
 	b.Prefix = query.Get(cmn.URLParamPrefix)
 	b.Suffix = query.Get(cmn.URLParamSuffix)
+	b.Sync.Interval = query.Get(cmn.URLParamSyncInterval)
+	b.Sync.Schedule = query.Get(cmn.URLParamSyncSchedule)
+	b.Sync.Delete, _ = cmn.ParseBool(query.Get(cmn.URLParamSyncDelete))
 }
 
 func (b *DlCloudBody) Validate() error {
 	if err := b.DlBase.Validate(); err != nil {
 		return err
 	}
-	return nil
+	return b.Sync.Validate()
 }
 
 func (b *DlCloudBody) AsQuery() url.Values {
 	query := b.DlBase.AsQuery()
 	query.Add(cmn.URLParamPrefix, b.Prefix)
 	query.Add(cmn.URLParamSuffix, b.Suffix)
+	if b.Sync.Interval != "" {
+		query.Add(cmn.URLParamSyncInterval, b.Sync.Interval)
+	}
+	if b.Sync.Schedule != "" {
+		query.Add(cmn.URLParamSyncSchedule, b.Sync.Schedule)
+	}
+	if b.Sync.Delete {
+		query.Add(cmn.URLParamSyncDelete, "true")
+	}
 	return query
 }
 
 func (b *DlCloudBody) Describe() string {
+	if b.Sync.IsScheduled() {
+		return fmt.Sprintf("cloud sync (%s) -> %s", b.Sync.String(), b.Bck)
+	}
 	return fmt.Sprintf("cloud prefetch -> %s", b.Bck)
 }
@@ -0,0 +1,103 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Archives (.tar, .tar.gz, .tgz, .zip) downloaded with the `extract` option
+// are not stored as objects. The archive is written to a work file which is
+// then read with the dSort's extract creators (see: extract.ExtractMembers)
+// and each member of the archive is stored as a separate object named
+// `<prefix>/<member-path>`, where prefix is the name of the archive without
+// its extension, on the target the member belongs to. The checksum of each
+// member is computed, according to the bucket's checksum config, when the
+// member is stored.
+
+var errExtract = errors.New("failed to extract archive")
+
+func checkArchive(objName string) error {
+	if extract.ArchiveExt(objName) == "" {
+		return fmt.Errorf("%q is not an archive (expected .tar, .tar.gz, .tgz or .zip), cannot extract", objName)
+	}
+	return nil
+}
+
+// memberObjName returns the name of the object for the member of the archive.
+// The members which would end up outside of the archive's prefix are rejected.
+func memberObjName(archiveName, member string) (string, error) {
+	name := path.Clean(member)
+	if path.IsAbs(member) || name == "." || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("invalid archive member name %q", member)
+	}
+	return strings.TrimSuffix(archiveName, extract.ArchiveExt(archiveName)) + "/" + name, nil
+}
+
+func (t *singleObjectTask) extractArchive(lom *cluster.LOM, workFQN string) error {
+	fh, err := os.Open(workFQN)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+	finfo, err := fh.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := io.NewSectionReader(fh, 0, finfo.Size())
+	_, cnt, err := extract.ExtractMembers(t.parent.t, lom, r, func(name string, r cmn.ReadSizer) error {
+		objName, err := memberObjName(lom.ObjName, name)
+		if err != nil {
+			return err
+		}
+		return t.putMember(objName, r)
+	})
+	if err != nil {
+		return fmt.Errorf("%w %q: %v", errExtract, lom.ObjName, err)
+	}
+	if glog.V(4) {
+		glog.Infof("%v: extracted %d members", t, cnt)
+	}
+	return nil
+}
+
+// putMember stores the member of the archive on the target it belongs to:
+// the archive is downloaded by a single target but its members are distributed
+// across the cluster.
+func (t *singleObjectTask) putMember(objName string, r cmn.ReadSizer) error {
+	lom := &cluster.LOM{T: t.parent.t, ObjName: objName}
+	if err := lom.Init(t.job.Bck()); err != nil {
+		return err
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), t.parent.t.GetSowner().Get())
+	if err != nil {
+		return err
+	}
+	if si.ID() != t.parent.t.Snode().ID() {
+		return t.parent.t.PutObjectToTarget(lom, r, r.Size(), si)
+	}
+	lom.SetAtimeUnix(t.started.Load().UnixNano())
+	return t.parent.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       ioutil.NopCloser(r),
+		WorkFQN:      fs.CSM.GenContentParsedFQN(lom.ParsedFQN, fs.WorkfileType, fs.WorkfilePut),
+		RecvType:     cluster.ColdGet,
+		Started:      t.started.Load(),
+		WithFinalize: true,
+	})
+}
@@ -5,13 +5,16 @@
 package downloader
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/sdomino/scribble"
 )
 
@@ -19,6 +22,11 @@ const (
 	persistDownloaderJobsPath = "downloader_jobs.db" // base name to persist downloader jobs' file
 	downloaderErrors          = "errors"
 	downloaderTasks           = "tasks"
+	downloaderSyncJobs        = "sync"
+
+	// credentials of the persisted jobs are encrypted with the key derived from
+	// the cluster secret (`auth.secret`), see: cmn.NewSecretCipher
+	credentialsPurpose = "ais downloader credentials"
 
 	// Number of errors stored in memory. When the number of errors exceeds
 	// this number, then all errors will be flushed to disk
@@ -182,5 +190,84 @@ func (db *downloaderDB) delete(id string) {
 	db.mtx.Lock()
 	db.driver.Delete(downloaderErrors, id)
 	db.driver.Delete(downloaderTasks, id)
+	delete(db.errCache, id)
+	delete(db.taskInfoCache, id)
+	db.mtx.Unlock()
+}
+
+// persistSyncJob persists the sync job, its credentials encrypted.
+func (db *downloaderDB) persistSyncJob(sj *syncJob) (err error) {
+	sj.Auth = ""
+	if sj.auth != nil {
+		data, err := json.Marshal(sj.auth)
+		if err != nil {
+			return err
+		}
+		if sj.Auth, err = sealCredentials(string(data)); err != nil {
+			return err
+		}
+	}
+	db.mtx.Lock()
+	defer db.mtx.Unlock()
+	return db.driver.Write(downloaderSyncJobs, sj.ID, sj)
+}
+
+func (db *downloaderDB) deleteSyncJob(id string) {
+	db.mtx.Lock()
+	db.driver.Delete(downloaderSyncJobs, id)
 	db.mtx.Unlock()
 }
+
+func (db *downloaderDB) syncJobs() ([]*syncJob, error) {
+	db.mtx.RLock()
+	defer db.mtx.RUnlock()
+
+	records, err := db.driver.ReadAll(downloaderSyncJobs)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	jobs := make([]*syncJob, 0, len(records))
+	for _, record := range records {
+		sj := &syncJob{}
+		if err := jsoniter.Unmarshal(record, sj); err != nil {
+			glog.Error(err)
+			continue
+		}
+		if sj.Auth != "" {
+			plaintext, err := openCredentials(sj.Auth)
+			if err == nil {
+				sj.auth = &dlSourceAuth{}
+				err = json.Unmarshal([]byte(plaintext), sj.auth)
+			}
+			if err != nil {
+				glog.Errorf("download sync job %s: failed to decrypt credentials, err: %v", sj.ID, err)
+				continue
+			}
+		}
+		jobs = append(jobs, sj)
+	}
+	return jobs, nil
+}
+
+// sealCredentials encrypts the credentials of the job so that they can be
+// persisted. The key is derived from the cluster secret which is never stored
+// next to the downloader's DB.
+func sealCredentials(plaintext string) (string, error) {
+	aead, err := cmn.NewSecretCipher(cmn.GCO.Get().Auth.Secret, credentialsPurpose)
+	if err != nil {
+		return "", fmt.Errorf("cannot encrypt credentials (auth.secret must be configured): %v", err)
+	}
+	return cmn.SealSecret(aead, plaintext)
+}
+
+// openCredentials decrypts the credentials encrypted by sealCredentials.
+func openCredentials(sealed string) (string, error) {
+	aead, err := cmn.NewSecretCipher(cmn.GCO.Get().Auth.Secret, credentialsPurpose)
+	if err != nil {
+		return "", err
+	}
+	return cmn.OpenSecret(aead, sealed)
+}
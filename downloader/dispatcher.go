@@ -218,7 +218,7 @@ func (d *dispatcher) createTasksLom(job DlJob, obj dlObj) (*cluster.LOM, error)
 		return nil, err
 	}
 	if err == nil {
-		equal, err := compareObjects(obj, lom)
+		equal, err := compareObjects(obj, lom, job.sourceAuth())
 		if err != nil {
 			return nil, err
 		}
@@ -315,6 +315,9 @@ func (d *dispatcher) dispatchRemove(req *request) {
 		return
 	}
 
+	if dlSyncer != nil {
+		dlSyncer.remove(req.id)
+	}
 	dlStore.delJob(req.id)
 	req.writeResp(nil)
 }
@@ -331,6 +334,9 @@ func (d *dispatcher) dispatchAbort(req *request) {
 		j.abortJob(req.id)
 	}
 
+	if dlSyncer != nil {
+		dlSyncer.remove(req.id)
+	}
 	err = dlStore.setAborted(req.id)
 	cmn.AssertNoErr(err) // Everything should be okay since getReqFromDB
 	req.writeResp(nil)
@@ -118,13 +118,18 @@ var (
 	// arbitrary server. The downloader chooses the correct client by
 	// server's URL. Certification check is disabled always for now and
 	// does not depend on cluster settings.
-	httpClient  = &http.Client{}
+	httpClient  = &http.Client{CheckRedirect: cmn.StripOriginHeader}
 	httpsClient = cmn.NewClient(cmn.TransportArgs{
 		UseHTTPS:   true,
 		SkipVerify: true,
 	})
 )
 
+func init() {
+	// custom headers and credentials of the job are not forwarded to another host
+	httpsClient.CheckRedirect = cmn.StripOriginHeader
+}
+
 // public types
 type (
 	// Downloader implements the fs.PathRunner and XactDemand interface. When
@@ -312,6 +317,9 @@ func (d *Downloader) Download(dJob DlJob) (resp interface{}, err error, statusCo
 
 	select {
 	case d.downloadCh <- dJob:
+		if cj, ok := dJob.(*cloudBucketDlJob); ok && cj.sync != nil {
+			dlSyncer.add(cj.sync)
+		}
 		return nil, nil, http.StatusOK
 	default:
 		return "downloader job queue is full", nil, http.StatusTooManyRequests
@@ -87,12 +87,39 @@ func (is *infoStore) setJob(id string, job DlJob) {
 		Description: job.Description(),
 		StartedTime: time.Now(),
 	}
+	if cj, ok := job.(*cloudBucketDlJob); ok && cj.sync != nil {
+		// Each run of the sync job replaces the previous one (errors and tasks included).
+		sj := dlSyncer.snapshot(cj.sync)
+		jInfo.Sync = true
+		jInfo.StartedTime = sj.Started
+		jInfo.LastRun = sj.LastRun
+		jInfo.NextRun.Store(sj.NextRun)
+		is.downloaderDB.delete(id)
+	}
 
 	is.Lock()
 	is.jobInfo[id] = jInfo
 	is.Unlock()
 }
 
+// setSyncJob restores the info of the sync job which is waiting for its next run.
+func (is *infoStore) setSyncJob(sj *syncJob) {
+	jInfo := &downloadJobInfo{
+		ID:          sj.ID,
+		Description: sj.Description,
+		StartedTime: sj.Started,
+		Sync:        true,
+		LastRun:     sj.LastRun,
+	}
+	jInfo.AllDispatched.Store(true)
+	jInfo.FinishedTime.Store(sj.LastRun)
+	jInfo.NextRun.Store(sj.NextRun)
+
+	is.Lock()
+	is.jobInfo[sj.ID] = jInfo
+	is.Unlock()
+}
+
 func (is *infoStore) incFinished(id string) error {
 	jInfo, err := is.getJob(id)
 	if err != nil {
@@ -163,6 +190,9 @@ func (is *infoStore) housekeep() time.Duration {
 
 	is.Lock()
 	for id, jInfo := range is.jobInfo {
+		if jInfo.Sync && !jInfo.Aborted.Load() && !jInfo.NextRun.Load().IsZero() {
+			continue // waiting for the next run
+		}
 		if time.Since(jInfo.FinishedTime.Load()) > interval {
 			is.delJob(id)
 		}
@@ -6,6 +6,7 @@ package downloader
 
 import (
 	"context"
+	"net/http"
 	"path"
 	"strings"
 	"sync"
@@ -15,6 +16,7 @@ import (
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
 )
 
 const sliceDownloadBatchSize = 1000
@@ -30,6 +32,8 @@ type (
 		objName   string
 		link      string
 		fromCloud bool
+		manifest  *DlManifestObj // expected size and checksums (manifest download only)
+		remote    *remoteObjInfo // attributes of the cloud object (cloud download only)
 	}
 
 	DlJob interface {
@@ -46,6 +50,8 @@ type (
 		genNext() (objs []dlObj, ok bool)
 
 		throttler() *throttler
+		sourceAuth() *dlSourceAuth
+		extractArchives() bool
 
 		cleanup()
 	}
@@ -56,6 +62,8 @@ type (
 		timeout     time.Duration
 		description string
 		t           *throttler
+		auth        *dlSourceAuth // custom headers and credentials, nil if none
+		extract     bool          // store the members of the archives instead of the archives
 	}
 
 	sliceDlJob struct {
@@ -85,6 +93,10 @@ type (
 		suffix     string
 		mtx        sync.Mutex
 		pagesCnt   int
+
+		sync       *syncJob      // set if the job is (a run of) sync job
+		listed     cmn.StringSet // names of listed objects, only when removed objects are to be evicted
+		listFailed bool          // listing has not completed
 	}
 
 	downloadJobInfo struct {
@@ -101,6 +113,10 @@ type (
 
 		StartedTime  time.Time   `json:"started_time"`
 		FinishedTime atomic.Time `json:"finished_time"`
+
+		Sync    bool        `json:"sync"`
+		LastRun time.Time   `json:"last_run"`
+		NextRun atomic.Time `json:"next_run"`
 	}
 )
 
@@ -117,7 +133,10 @@ func (j *baseDlJob) cleanup() {
 	j.throttler().stop()
 }
 
-func newBaseDlJob(id string, bck *cluster.Bck, timeout, desc string, limits DlLimits) *baseDlJob {
+func (j *baseDlJob) sourceAuth() *dlSourceAuth { return j.auth }
+func (j *baseDlJob) extractArchives() bool     { return j.extract }
+
+func newBaseDlJob(id string, bck *cluster.Bck, timeout, desc string, limits DlLimits, auth *dlSourceAuth) *baseDlJob {
 	t, _ := time.ParseDuration(timeout)
 	return &baseDlJob{
 		id:          id,
@@ -125,6 +144,7 @@ func newBaseDlJob(id string, bck *cluster.Bck, timeout, desc string, limits DlLi
 		timeout:     t,
 		description: desc,
 		t:           newThrottler(limits),
+		auth:        auth,
 	}
 }
 
@@ -167,6 +187,7 @@ func (j *cloudBucketDlJob) genNext() (objs []dlObj, ok bool) {
 		// this makes genNext return ok = false in the next
 		// loop iteration
 		j.objs, j.pageMarker = []dlObj{}, ""
+		j.listFailed = true
 	}
 
 	return readyToDownloadObjs, true
@@ -189,7 +210,7 @@ func (j *cloudBucketDlJob) getNextObjs() error {
 		msg := &cmn.SelectMsg{
 			Prefix:     j.prefix,
 			PageMarker: j.pageMarker,
-			Fast:       true,
+			Props:      strings.Join([]string{cmn.GetPropsSize, cmn.GetPropsVersion, cmn.GetPropsChecksum}, ","),
 		}
 
 		bckList, err, _ := j.t.Cloud(j.bck).ListObjects(j.ctx, j.bck, msg)
@@ -209,6 +230,10 @@ func (j *cloudBucketDlJob) getNextObjs() error {
 				}
 				return err
 			}
+			job.remote = cloudObjInfo(entry)
+			if j.listed != nil {
+				j.listed.Add(job.objName)
+			}
 			j.objs = append(j.objs, job)
 		}
 		if j.pageMarker == "" || len(j.objs) != 0 {
@@ -261,7 +286,10 @@ func (j *rangeDlJob) getNextObjs() error {
 	return nil
 }
 
-func newCloudBucketDlJob(ctx context.Context, t cluster.Target, base *baseDlJob, prefix, suffix string) (*cloudBucketDlJob, error) {
+func newCloudBucketDlJob(ctx context.Context, t cluster.Target, base *baseDlJob, prefix, suffix string, deleteRemoved bool) (*cloudBucketDlJob, error) {
+	if base.auth != nil {
+		ctx = context.WithValue(ctx, cmn.CtxOriginHeader, base.auth.header())
+	}
 	job := &cloudBucketDlJob{
 		baseDlJob:  *base,
 		pageMarker: "",
@@ -270,11 +298,83 @@ func newCloudBucketDlJob(ctx context.Context, t cluster.Target, base *baseDlJob,
 		prefix:     prefix,
 		suffix:     suffix,
 	}
+	if deleteRemoved {
+		job.listed = make(cmn.StringSet)
+	}
 
 	err := job.getNextObjs()
 	return job, err
 }
 
+func (j *cloudBucketDlJob) cleanup() {
+	// Evict only when the whole bucket has been listed, otherwise we could
+	// evict objects which still exist in the cloud.
+	if j.listed != nil && !j.listFailed {
+		if jInfo, err := dlStore.getJob(j.ID()); err == nil && !jInfo.Aborted.Load() {
+			j.evictRemoved()
+		}
+	}
+	j.baseDlJob.cleanup()
+}
+
+// evictRemoved evicts cached objects (matching prefix and suffix of the job)
+// which have not been listed, ie. have been removed from the cloud bucket.
+func (j *cloudBucketDlJob) evictRemoved() {
+	var (
+		evicted           int
+		availablePaths, _ = fs.Mountpaths.Get()
+	)
+	for _, mpathInfo := range availablePaths {
+		opts := &fs.Options{
+			Mpath: mpathInfo,
+			Bck:   j.Bck(),
+			CTs:   []string{fs.ObjectType},
+			Callback: func(fqn string, de fs.DirEntry) error {
+				if de.IsDir() {
+					return nil
+				}
+				lom := &cluster.LOM{T: j.t, FQN: fqn}
+				if err := lom.Init(j.Bck()); err != nil {
+					return nil
+				}
+				if !strings.HasPrefix(lom.ObjName, j.prefix) || !strings.HasSuffix(lom.ObjName, j.suffix) ||
+					j.listed.Contains(lom.ObjName) {
+					return nil
+				}
+				if j.evictObject(lom) {
+					evicted++
+				}
+				return nil
+			},
+		}
+		if err := fs.Walk(opts); err != nil {
+			glog.Errorf("%s: failed to traverse %s, err: %v", j.ID(), mpathInfo, err)
+		}
+	}
+	if evicted > 0 {
+		glog.Infof("%s: evicted %d object(s) removed from %s", j.ID(), evicted, j.Bck())
+	}
+}
+
+// evictObject evicts the object via the target's delete path which takes care
+// of the object's copies and EC slices as well.
+func (j *cloudBucketDlJob) evictObject(lom *cluster.LOM) bool {
+	if err := lom.Load(false); err != nil {
+		return false
+	}
+	if lom.IsCopy() || !lom.IsHRW() {
+		return false
+	}
+	if err, errCode := j.t.DeleteObject(j.ctx, lom, true /*evict*/); err != nil {
+		// not found (already removed) or forbidden (retained, WORM)
+		if errCode != http.StatusNotFound && errCode != http.StatusForbidden {
+			glog.Errorf("%s: failed to evict, err: %v", lom, err)
+		}
+		return false
+	}
+	return true
+}
+
 func countObjects(t cluster.Target, pt cmn.ParsedTemplate, dir string, bck *cluster.Bck) (cnt int, err error) {
 	var (
 		smap = t.GetSowner().Get()
@@ -329,5 +429,8 @@ func (d *downloadJobInfo) ToDlJobInfo() DlJobInfo {
 		Aborted:       d.Aborted.Load(),
 		StartedTime:   d.StartedTime,
 		FinishedTime:  d.FinishedTime.Load(),
+		Sync:          d.Sync,
+		LastRun:       d.LastRun,
+		NextRun:       d.NextRun.Load(),
 	}
 }
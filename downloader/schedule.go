@@ -0,0 +1,165 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	minSyncInterval = time.Minute
+	maxScheduleSpan = 5 * 366 * 24 * time.Hour // cron expression which does not fire within the span is invalid
+)
+
+type (
+	// schedule determines the time of the next run of the sync job.
+	schedule interface {
+		next(after time.Time) time.Time
+	}
+
+	// intervalSchedule runs the job with a fixed period.
+	intervalSchedule time.Duration
+
+	// cronSchedule runs the job at the times matching the cron expression:
+	// "minute hour day-of-month month day-of-week". Each of the fields is
+	// either `*` or a comma separated list of values, ranges (`a-b`) and
+	// steps (`*/n`, `a-b/n`). Day of the week is 0-6 (Sunday is 0). As in
+	// cron, if both day of the month and day of the week are restricted,
+	// the day matches when either of them does; a field that starts with
+	// `*` (including `*/n`) is not considered restricted.
+	cronSchedule struct {
+		minute, hour, dom, month, dow uint64 // bitsets of matching values
+		domAny, dowAny                bool
+	}
+)
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+func parseSchedule(sync DlSync) (schedule, error) {
+	if sync.Interval != "" {
+		interval, err := time.ParseDuration(sync.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync interval %q: %v", sync.Interval, err)
+		}
+		if interval < minSyncInterval {
+			return nil, fmt.Errorf("sync interval %v is too short (min: %v)", interval, minSyncInterval)
+		}
+		return intervalSchedule(interval), nil
+	}
+	return parseCron(sync.Schedule)
+}
+
+func (s intervalSchedule) next(after time.Time) time.Time { return after.Add(time.Duration(s)) }
+
+func parseCron(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid sync schedule %q: expected %d fields (minute hour day-of-month month day-of-week)",
+			expr, len(cronFields))
+	}
+	var (
+		s    = &cronSchedule{}
+		sets = []*uint64{&s.minute, &s.hour, &s.dom, &s.month, &s.dow}
+	)
+	for i, field := range fields {
+		set, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sync schedule %q, %s: %v", expr, cronFields[i].name, err)
+		}
+		*sets[i] = set
+	}
+	s.domAny, s.dowAny = strings.HasPrefix(fields[2], "*"), strings.HasPrefix(fields[4], "*")
+	if now := time.Now(); s.next(now).IsZero() {
+		return nil, fmt.Errorf("sync schedule %q never fires", expr)
+	}
+	return s, nil
+}
+
+func parseCronField(field string, min, max int) (set uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		var (
+			lo, hi = min, max
+			step   = 1
+		)
+		if idx := strings.IndexByte(part, '/'); idx >= 0 {
+			if step, err = strconv.Atoi(part[idx+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:idx]
+		}
+		switch {
+		case part == "*":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			if lo, err = strconv.Atoi(part); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			if step == 1 {
+				hi = lo
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("%q out of range [%d, %d]", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	var (
+		dom = s.dom&(1<<uint(t.Day())) != 0
+		dow = s.dow&(1<<uint(t.Weekday())) != 0
+	)
+	if !s.domAny && !s.dowAny {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// next returns the first matching time (with minute precision) after the
+// given time or zero time if there is none within maxScheduleSpan.
+func (s *cronSchedule) next(after time.Time) time.Time {
+	var (
+		t     = after.Truncate(time.Minute).Add(time.Minute)
+		limit = after.Add(maxScheduleSpan)
+	)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.matchDay(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
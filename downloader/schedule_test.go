@@ -0,0 +1,64 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Wednesday
+	after := time.Date(2020, time.April, 15, 10, 30, 20, 0, time.UTC)
+
+	var tests = []struct {
+		sync     DlSync
+		expected time.Time
+	}{
+		{DlSync{Interval: "90m"}, after.Add(90 * time.Minute)},
+		{DlSync{Schedule: "* * * * *"}, time.Date(2020, time.April, 15, 10, 31, 0, 0, time.UTC)},
+		{DlSync{Schedule: "*/15 * * * *"}, time.Date(2020, time.April, 15, 10, 45, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 2 * * *"}, time.Date(2020, time.April, 16, 2, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "30 10,22 * * *"}, time.Date(2020, time.April, 15, 22, 30, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 9-17/4 * * *"}, time.Date(2020, time.April, 15, 13, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 0 * * 0"}, time.Date(2020, time.April, 19, 0, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 0 1 * *"}, time.Date(2020, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 0 1 * 5"}, time.Date(2020, time.April, 17, 0, 0, 0, 0, time.UTC)}, // day of month OR day of week
+		// step is not a restriction: day of month AND day of week
+		{DlSync{Schedule: "0 0 */2 * 1"}, time.Date(2020, time.April, 27, 0, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 0 1 * */2"}, time.Date(2020, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{DlSync{Schedule: "0 0 29 2 *"}, time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		s, err := parseSchedule(test.sync)
+		tassert.CheckFatal(t, err)
+		actual := s.next(after)
+		tassert.Errorf(t, actual.Equal(test.expected), "%v: expected next run %v, got: %v", test.sync, test.expected, actual)
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	var tests = []DlSync{
+		{Interval: "10s"},
+		{Interval: "abc"},
+		{Schedule: "* * * *"},
+		{Schedule: "60 * * * *"},
+		{Schedule: "* 24 * * *"},
+		{Schedule: "* * 0 * *"},
+		{Schedule: "* * * 13 *"},
+		{Schedule: "* * * * 7"},
+		{Schedule: "5-1 * * * *"},
+		{Schedule: "*/0 * * * *"},
+		{Schedule: "a * * * *"},
+		{Schedule: "0 0 31 2 *"}, // never fires
+		{Interval: "1h", Schedule: "* * * * *"},
+	}
+	for _, sync := range tests {
+		err := sync.Validate()
+		tassert.Errorf(t, err != nil, "expected %v to be invalid", sync)
+	}
+}
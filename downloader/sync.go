@@ -0,0 +1,233 @@
+// Package downloader implements functionality to download resources into AIS cluster from external source.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package downloader
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/housekeep/hk"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Sync job is a cloud download which is re-run periodically. Each run is
+// a regular cloud bucket download job (with the same ID as the sync job) that
+// re-lists the cloud bucket and downloads only the objects which are new or
+// have changed since the previous run (see: compareObjects). Optionally, the
+// run evicts cached objects which have been removed from the cloud bucket.
+//
+// Each target schedules the runs independently, using the housekeeper. Sync
+// jobs are persisted in the downloader's DB so that they are resumed when the
+// target restarts. Sync job is stopped by aborting or removing it.
+
+const (
+	syncRetryInterval = time.Minute // when the run cannot be started (eg. cluster not started yet)
+	syncHKPrefix      = "downloader-sync-"
+)
+
+type (
+	// XactRegistry is used to (re)start the downloader when it is time to run
+	// the sync job.
+	XactRegistry interface {
+		RenewDownloader(t cluster.Target, statsT stats.Tracker) (*Downloader, error)
+	}
+
+	// syncJob is persisted in the downloader's DB.
+	syncJob struct {
+		ID          string    `json:"id"`
+		Description string    `json:"description"`
+		Bck         cmn.Bck   `json:"bck"`
+		Prefix      string    `json:"prefix"`
+		Suffix      string    `json:"suffix"`
+		Timeout     string    `json:"timeout"`
+		Limits      DlLimits  `json:"limits"` // limits of the target
+		Sync        DlSync    `json:"sync"`
+		Started     time.Time `json:"started"`
+		LastRun     time.Time `json:"last_run"`
+		NextRun     time.Time `json:"next_run"`
+		Auth        string    `json:"auth,omitempty"` // encrypted `auth`, see: downloaderDB.persistSyncJob
+
+		auth *dlSourceAuth
+	}
+
+	syncer struct {
+		mtx    sync.Mutex
+		t      cluster.Target
+		statsT stats.Tracker
+		reg    XactRegistry
+		jobs   map[string]*syncJob
+	}
+)
+
+var dlSyncer *syncer
+
+// InitSync enables sync jobs and resumes the ones which have been persisted.
+func InitSync(t cluster.Target, statsT stats.Tracker, reg XactRegistry) {
+	initInfoStore()
+	dlSyncer = &syncer{
+		t:      t,
+		statsT: statsT,
+		reg:    reg,
+		jobs:   make(map[string]*syncJob),
+	}
+	jobs, err := dlStore.syncJobs()
+	if err != nil {
+		glog.Errorf("failed to load download sync jobs, err: %v", err)
+		return
+	}
+	for _, sj := range jobs {
+		dlStore.setSyncJob(sj)
+		dlSyncer.schedule(sj, cmn.MaxDuration(time.Until(sj.NextRun), syncRetryInterval))
+		glog.Infof("resumed download sync job %s (next run: %v)", sj.ID, sj.NextRun)
+	}
+}
+
+func newSyncJob(id string, base *DlBase, body *DlCloudBody) *syncJob {
+	now := time.Now()
+	sj := &syncJob{
+		ID:          id,
+		Description: base.Description,
+		Bck:         base.Bck,
+		Prefix:      body.Prefix,
+		Suffix:      body.Suffix,
+		Timeout:     base.Timeout,
+		Limits:      base.Limits,
+		Sync:        body.Sync,
+		Started:     now,
+		LastRun:     now,
+		auth:        base.sourceAuth(),
+	}
+	sj.NextRun = sj.next(now)
+	return sj
+}
+
+func (sj *syncJob) next(after time.Time) time.Time {
+	s, err := parseSchedule(sj.Sync)
+	cmn.AssertNoErr(err) // validated when the job was started
+	return s.next(after)
+}
+
+func (sj *syncJob) newRun(t cluster.Target) (*cloudBucketDlJob, error) {
+	bck := cluster.NewBckEmbed(sj.Bck)
+	if err := bck.Init(t.GetBowner(), t.Snode()); err != nil {
+		return nil, err
+	}
+	base := newBaseDlJob(sj.ID, bck, sj.Timeout, sj.Description, sj.Limits, sj.auth)
+	job, err := newCloudBucketDlJob(context.Background(), t, base, sj.Prefix, sj.Suffix, sj.Sync.Delete)
+	if err != nil {
+		return nil, err
+	}
+	job.sync = sj
+	return job, nil
+}
+
+// add starts scheduling the runs of the job (its first run has just started),
+// noop if the job is already scheduled.
+func (s *syncer) add(sj *syncJob) {
+	s.mtx.Lock()
+	_, exists := s.jobs[sj.ID]
+	s.mtx.Unlock()
+	if exists {
+		return
+	}
+	s.persist(sj)
+	s.schedule(sj, time.Until(sj.NextRun))
+}
+
+func (s *syncer) snapshot(sj *syncJob) syncJob {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return *sj
+}
+
+func (s *syncer) persist(sj *syncJob) {
+	snapshot := s.snapshot(sj)
+	if err := dlStore.persistSyncJob(&snapshot); err != nil {
+		glog.Errorf("failed to persist download sync job %s, err: %v", sj.ID, err)
+	}
+}
+
+func (s *syncer) schedule(sj *syncJob, after time.Duration) {
+	s.mtx.Lock()
+	s.jobs[sj.ID] = sj
+	s.mtx.Unlock()
+	hk.Housekeeper.Register(syncHKPrefix+sj.ID, func() time.Duration { return s.run(sj.ID) }, after)
+}
+
+// remove stops scheduling the runs of the job, noop if the job is not a sync job.
+func (s *syncer) remove(id string) {
+	s.mtx.Lock()
+	_, ok := s.jobs[id]
+	delete(s.jobs, id)
+	s.mtx.Unlock()
+	if !ok {
+		return
+	}
+	hk.Housekeeper.Unregister(syncHKPrefix + id)
+	dlStore.deleteSyncJob(id)
+	if jInfo, err := dlStore.getJob(id); err == nil {
+		jInfo.NextRun.Store(time.Time{})
+	}
+}
+
+// run is called by the housekeeper, it starts the run of the job in the
+// background and returns the interval until the next one.
+func (s *syncer) run(id string) time.Duration {
+	s.mtx.Lock()
+	sj, ok := s.jobs[id]
+	s.mtx.Unlock()
+	if !ok {
+		return hk.DayInterval // removed, unregistering
+	}
+	if !s.t.ClusterStarted() {
+		return syncRetryInterval
+	}
+
+	var (
+		now  = time.Now()
+		next = sj.next(now)
+	)
+	s.mtx.Lock()
+	sj.NextRun = next
+	s.mtx.Unlock()
+
+	jInfo, err := dlStore.getJob(id)
+	if err == nil && jInfo.FinishedTime.Load().IsZero() {
+		glog.Warningf("download sync job %s: previous run is still in progress, skipping", id)
+		jInfo.NextRun.Store(next)
+		s.persist(sj)
+	} else {
+		go s.startRun(sj, now)
+	}
+	return cmn.MaxDuration(time.Until(next), syncRetryInterval)
+}
+
+func (s *syncer) startRun(sj *syncJob, started time.Time) {
+	d, err := s.reg.RenewDownloader(s.t, s.statsT)
+	if err != nil {
+		glog.Errorf("download sync job %s: failed to start the run, err: %v", sj.ID, err)
+		return
+	}
+	job, err := sj.newRun(s.t)
+	if err != nil {
+		glog.Errorf("download sync job %s: failed to start the run, err: %v", sj.ID, err)
+		dlStore.persistError(sj.ID, "", err.Error())
+		return
+	}
+	s.mtx.Lock()
+	sj.LastRun = started
+	s.mtx.Unlock()
+	s.persist(sj)
+	if glog.V(4) {
+		glog.Infof("download sync job %s: starting the run", sj.ID)
+	}
+	if resp, _, statusCode := d.Download(job); statusCode >= 400 {
+		glog.Errorf("download sync job %s: failed to start the run: %v", sj.ID, resp)
+	}
+}
@@ -22,7 +22,7 @@ import (
 )
 
 const (
-	retryCnt         = 10              // number of retries to external resource
+	retryCnt         = 10              // default number of retries to external resource
 	reqTimeoutFactor = 1.2             // newTimeout = prevTimeout * reqTimeoutFactor
 	headReqTimeout   = 5 * time.Second // timeout for HEAD request to get the Content-Length
 	internalErrorMsg = "internal server error"
@@ -109,7 +109,7 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	ctx, cancel := context.WithTimeout(t.downloadCtx, timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.obj.link, nil)
+	req, err := newSourceRequest(ctx, http.MethodGet, t.obj.link, t.job.sourceAuth())
 	if err != nil {
 		return err
 	}
@@ -130,9 +130,27 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 		return fmt.Errorf("request failed with %d status code (%s)", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
 
+	var (
+		r     io.ReadCloser = resp.Body
+		roi                 = getRemoteObjInfo(t.obj.link, resp)
+		cksum               = roi.cksum
+	)
+	if t.obj.manifest != nil {
+		// Verify the content against the manifest while it is being streamed.
+		// The object's checksum (of the bucket's type) is computed from the
+		// same, verified content - unless given by the manifest already.
+		r = newManifestReader(r, t.obj.manifest)
+		if mcksum := t.obj.manifest.cksum(lom.CksumConf().Type); mcksum != nil {
+			cksum = mcksum
+		}
+		if roi.size <= 0 {
+			roi.size = t.obj.manifest.Size
+		}
+	}
+
 	// Create a custom reader to monitor progress every time we read from response body stream
-	var r io.ReadCloser = &progressReader{
-		r: resp.Body,
+	r = &progressReader{
+		r: r,
 		reporter: func(n int64) {
 			t.currentSize.Add(n)
 		},
@@ -140,15 +158,31 @@ func (t *singleObjectTask) tryDownloadLocal(lom *cluster.LOM, timeout time.Durat
 	// Wrap around throttler reader (noop if throttling is disabled)
 	r = t.job.throttler().wrapReader(ctx, r)
 
-	roi := getRemoteObjInfo(t.obj.link, resp)
 	t.setTotalSize(roi)
 
+	if t.job.extractArchives() {
+		// Only the members of the archive are stored (see: extractArchive).
+		err = t.parent.t.PutObject(cluster.PutObjectParams{
+			LOM:      lom,
+			Reader:   r,
+			WorkFQN:  workFQN,
+			RecvType: cluster.ColdGet,
+			Cksum:    cksum,
+			Started:  t.started.Load(),
+		})
+		if err != nil {
+			return err
+		}
+		defer cmn.RemoveFile(workFQN)
+		return t.extractArchive(lom, workFQN)
+	}
+
 	err = t.parent.t.PutObject(cluster.PutObjectParams{
 		LOM:          lom,
 		Reader:       r,
 		WorkFQN:      workFQN,
 		RecvType:     cluster.ColdGet,
-		Cksum:        roi.cksum,
+		Cksum:        cksum,
 		Version:      roi.version,
 		Started:      t.started.Load(),
 		WithFinalize: true,
@@ -166,8 +200,17 @@ func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 	var (
 		httpErr = &cmn.HTTPError{}
 		timeout = t.initialTimeout()
+		retries = cmn.GCO.Get().Downloader.Retries
 	)
-	for i := 0; i < retryCnt; i++ {
+	if retries == 0 {
+		retries = retryCnt
+	}
+	if t.job.extractArchives() {
+		if err := checkArchive(lom.ObjName); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < retries; i++ {
 		err = t.tryDownloadLocal(lom, timeout)
 		if err == nil {
 			return nil
@@ -175,19 +218,24 @@ func (t *singleObjectTask) downloadLocal(lom *cluster.LOM) (err error) {
 			// Download was canceled or stopped, so just return.
 			return err
 		} else if errors.Is(err, context.DeadlineExceeded) {
-			glog.Warningf("%s [retries: %d/%d]: context exceeded with timeout (%v), increasing and retrying...", t, i, retryCnt, timeout)
+			glog.Warningf("%s [retries: %d/%d]: context exceeded with timeout (%v), increasing and retrying...", t, i, retries, timeout)
 			timeout = time.Duration(float64(timeout) * reqTimeoutFactor)
 		} else if errors.As(err, &httpErr) {
-			glog.Warningf("%s [retries: %d/%d]: failed to perform request: %v (code: %d)", t, i, retryCnt, err, httpErr.Status)
+			glog.Warningf("%s [retries: %d/%d]: failed to perform request: %v (code: %d)", t, i, retries, err, httpErr.Status)
 			if _, exists := terminalStatuses[httpErr.Status]; exists {
 				// Nothing we can do...
 				return err
 			}
 			// Otherwise retry...
+		} else if errors.Is(err, errExtract) {
+			// The archive has been downloaded, retrying will not help.
+			return err
+		} else if errors.Is(err, errManifestMismatch) {
+			glog.Warningf("%s [retries: %d/%d]: %v, retrying...", t, i, retries, err)
 		} else if cmn.IsErrConnectionReset(err) || cmn.IsErrConnectionRefused(err) {
-			glog.Warningf("%s [retries: %d/%d]: connection failed with (%v), retrying...", t, i, retryCnt, err)
+			glog.Warningf("%s [retries: %d/%d]: connection failed with (%v), retrying...", t, i, retries, err)
 		} else {
-			glog.Warningf("%s [retries: %d/%d]: unexpected error (%v), retrying...", t, i, retryCnt, err)
+			glog.Warningf("%s [retries: %d/%d]: unexpected error (%v), retrying...", t, i, retries, err)
 		}
 
 		t.reset()
@@ -209,6 +257,9 @@ func (t *singleObjectTask) reset() {
 func (t *singleObjectTask) downloadCloud(lom *cluster.LOM) error {
 	ctx, cancel := context.WithTimeout(t.downloadCtx, t.initialTimeout())
 	defer cancel()
+	if auth := t.job.sourceAuth(); auth != nil {
+		ctx = context.WithValue(ctx, cmn.CtxOriginHeader, auth.header())
+	}
 	err, _ := t.parent.t.GetCold(ctx, lom, true /* prefetch */)
 	return err
 }
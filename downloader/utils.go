@@ -6,19 +6,30 @@ package downloader
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
+	"hash"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
+	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
 	jsoniter "github.com/json-iterator/go"
 )
 
+// xattrDlCksum caches the checksum computed by computeCksum.
+const xattrDlCksum = "user.ais.dl-cksum"
+
 var (
 	errInvalidTarget = errors.New("invalid target")
 )
@@ -44,6 +55,29 @@ func buildDlObjs(t cluster.Target, bck *cluster.Bck, objects cmn.SimpleKVs) ([]d
 	return objs, nil
 }
 
+// buildManifestDlObjs returns list of manifest objects that must be downloaded by target.
+func buildManifestDlObjs(t cluster.Target, bck *cluster.Bck, manifest []DlManifestObj) ([]dlObj, error) {
+	var (
+		smap = t.GetSowner().Get()
+		sid  = t.Snode().ID()
+	)
+
+	objs := make([]dlObj, 0, len(manifest))
+	for i := range manifest {
+		entry := &manifest[i]
+		job, err := jobForObject(smap, sid, bck, entry.ObjName, entry.Link)
+		if err != nil {
+			if err == errInvalidTarget {
+				continue
+			}
+			return nil, err
+		}
+		job.manifest = entry
+		objs = append(objs, job)
+	}
+	return objs, nil
+}
+
 func jobForObject(smap *cluster.Smap, sid string, bck *cluster.Bck, objName, link string) (dlObj, error) {
 	objName, err := normalizeObjName(objName)
 	if err != nil {
@@ -92,8 +126,9 @@ func ParseStartDownloadRequest(ctx context.Context, r *http.Request, id string,
 
 	var (
 		// link -> objName
-		objects cmn.SimpleKVs
-		query   = r.URL.Query()
+		objects  cmn.SimpleKVs
+		manifest []DlManifestObj
+		query    = r.URL.Query()
 
 		payload       = &DlBase{}
 		singlePayload = &DlSingleBody{}
@@ -107,6 +142,9 @@ func ParseStartDownloadRequest(ctx context.Context, r *http.Request, id string,
 	)
 
 	payload.InitWithQuery(query)
+	if err := payload.InitWithHeader(r.Header); err != nil {
+		return nil, err
+	}
 
 	singlePayload.InitWithQuery(query)
 	rangePayload.InitWithQuery(query)
@@ -147,13 +185,20 @@ func ParseStartDownloadRequest(ctx context.Context, r *http.Request, id string,
 		if err := jsoniter.Unmarshal(b, &objectsPayload); err != nil {
 			return nil, err
 		}
-		if objects, err = multiPayload.ExtractPayload(objectsPayload); err != nil {
+		if multiPayload.IsManifest(objectsPayload) {
+			if manifest, err = multiPayload.ExtractManifest(b); err != nil {
+				return nil, err
+			}
+		} else if objects, err = multiPayload.ExtractPayload(objectsPayload); err != nil {
 			return nil, err
 		}
 		description = multiPayload.Describe()
 		dlType = dlTypeMulti
 	} else if err = cloudPayload.Validate(); err == nil {
+		description = cloudPayload.Describe()
 		dlType = dlTypeCloud
+	} else if cloudPayload.Sync.IsScheduled() {
+		return nil, err
 	} else {
 		return nil, errors.New("input does not match any of the supported formats (single, range, multi, cloud)")
 	}
@@ -168,13 +213,32 @@ func ParseStartDownloadRequest(ctx context.Context, r *http.Request, id string,
 		payload.Limits.BytesPerHour /= t.GetSowner().Get().CountTargets()
 	}
 
-	baseJob := newBaseDlJob(id, bck, payload.Timeout, payload.Description, payload.Limits)
+	baseJob := newBaseDlJob(id, bck, payload.Timeout, payload.Description, payload.Limits, payload.sourceAuth())
+	baseJob.extract = payload.Extract
 	switch dlType {
 	case dlTypeCloud:
 		if !bck.IsCloud() {
 			return nil, errors.New("bucket download requires a cloud bucket")
 		}
-		return newCloudBucketDlJob(ctx, t, baseJob, cloudPayload.Prefix, cloudPayload.Suffix)
+		if baseJob.extract {
+			return nil, errors.New("extracting archives is not supported for bucket download")
+		}
+		if baseJob.auth != nil && bck.Provider != cmn.ProviderHTTP {
+			return nil, fmt.Errorf("custom headers and credentials are supported only for %q buckets", cmn.ProviderHTTP)
+		}
+		job, err := newCloudBucketDlJob(ctx, t, baseJob, cloudPayload.Prefix, cloudPayload.Suffix, cloudPayload.Sync.Delete)
+		if err != nil || !cloudPayload.Sync.IsScheduled() {
+			return job, err
+		}
+		if dlSyncer == nil {
+			return nil, errors.New("sync jobs are not supported")
+		}
+		if baseJob.auth != nil && cmn.GCO.Get().Auth.Secret == "" {
+			// see sealCredentials
+			return nil, errors.New("sync job with headers or credentials requires auth.secret to be configured")
+		}
+		job.sync = newSyncJob(id, payload, cloudPayload)
+		return job, nil
 	case dlTypeRange:
 		if !bck.IsAIS() {
 			return nil, errors.New("range download requires ais bucket")
@@ -184,7 +248,12 @@ func ParseStartDownloadRequest(ctx context.Context, r *http.Request, id string,
 		if !bck.IsAIS() {
 			return nil, errors.New("regular download requires ais bucket")
 		}
-		objs, err := buildDlObjs(t, bck, objects)
+		var objs []dlObj
+		if manifest != nil {
+			objs, err = buildManifestDlObjs(t, bck, manifest)
+		} else {
+			objs, err = buildDlObjs(t, bck, objects)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -263,10 +332,29 @@ func getChecksumFromGoogleFormat(hs []string) string {
 	return ""
 }
 
-func headLink(link string) (*http.Response, error) {
+// newSourceRequest creates the request to the source with custom headers and
+// credentials of the job. The headers are also put in the context so that they
+// are not forwarded when the request is redirected to another host (see
+// cmn.StripOriginHeader).
+func newSourceRequest(ctx context.Context, method, link string, auth *dlSourceAuth) (*http.Request, error) {
+	header := auth.header()
+	if header != nil {
+		ctx = context.WithValue(ctx, cmn.CtxOriginHeader, header)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, link, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	return req, nil
+}
+
+func headLink(link string, auth *dlSourceAuth) (*http.Response, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), headReqTimeout)
 	defer cancel()
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, link, nil)
+	req, err := newSourceRequest(ctx, http.MethodHead, link, auth)
 	if err != nil {
 		return nil, err
 	}
@@ -278,22 +366,46 @@ func headLink(link string) (*http.Response, error) {
 	return resp, nil
 }
 
-func compareObjects(obj dlObj, lom *cluster.LOM) (equal bool, err error) {
-	resp, err := headLink(obj.link)
-	if err != nil {
-		return false, err
+// Get object info from the cloud bucket listing entry
+func cloudObjInfo(entry *cmn.BucketEntry) *remoteObjInfo {
+	roi := &remoteObjInfo{size: entry.Size, version: entry.Version}
+	// All cloud providers list MD5 (AWS ETag) - except multipart uploads.
+	if entry.Checksum != "" && !strings.Contains(entry.Checksum, s3CksumHeaderIllegalChar) {
+		roi.cksum = cmn.NewCksum(cmn.ChecksumMD5, entry.Checksum)
+	}
+	return roi
+}
+
+func compareObjects(obj dlObj, lom *cluster.LOM, auth *dlSourceAuth) (equal bool, err error) {
+	if obj.manifest != nil {
+		return compareManifest(obj.manifest, lom)
+	}
+	var roi remoteObjInfo
+	if obj.fromCloud {
+		if obj.remote == nil {
+			return false, nil
+		}
+		roi = *obj.remote
+	} else {
+		resp, err := headLink(obj.link, auth)
+		if err != nil {
+			return false, err
+		}
+		roi = getRemoteObjInfo(obj.link, resp)
 	}
-	if resp.ContentLength != 0 && resp.ContentLength != lom.Size() {
+	if roi.size != 0 && roi.size != lom.Size() {
 		return false, nil
 	}
-	roi := getRemoteObjInfo(obj.link, resp)
 	if roi.version != "" {
 		if roi.version != lom.Version() {
 			return false, nil
 		}
 	}
 	if roi.cksum != nil {
-		computedCksum, err := lom.ComputeCksum(roi.cksum.Type())
+		if cksum := lom.Cksum(); cksum != nil && cksum.Type() == roi.cksum.Type() {
+			return cksum.Equal(roi.cksum), nil
+		}
+		computedCksum, err := computeCksum(lom, roi.cksum.Type())
 		if err != nil || !computedCksum.Equal(roi.cksum) {
 			return false, err
 		}
@@ -301,3 +413,127 @@ func compareObjects(obj dlObj, lom *cluster.LOM) (equal bool, err error) {
 	// Cannot prove that the objects are different so assume they are equal.
 	return true, nil
 }
+
+// computeCksum computes the checksum of the object of the type which differs
+// from the bucket's one (e.g., MD5 listed by the cloud). The checksum is cached
+// in the object's xattr so that it is not computed again by the next sync run.
+// The cached value is valid only for the same size and modification time.
+func computeCksum(lom *cluster.LOM, cksumType string) (*cmn.Cksum, error) {
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("%s:%d:%d:", cksumType, finfo.Size(), finfo.ModTime().UnixNano())
+	if b, err := fs.GetXattr(lom.FQN, xattrDlCksum); err == nil && strings.HasPrefix(string(b), prefix) {
+		return cmn.NewCksum(cksumType, strings.TrimPrefix(string(b), prefix)), nil
+	}
+	cksumHash, err := lom.ComputeCksum(cksumType)
+	if err != nil {
+		return nil, err
+	}
+	cksum := cksumHash.Clone()
+	if err := fs.SetXattr(lom.FQN, xattrDlCksum, []byte(prefix+cksum.Value())); err != nil {
+		glog.Warningf("%s: failed to cache %s checksum, err: %v", lom, cksumType, err)
+	}
+	return cksum, nil
+}
+
+// compareManifest verifies the existing object against its manifest entry.
+func compareManifest(entry *DlManifestObj, lom *cluster.LOM) (equal bool, err error) {
+	if entry.Size > 0 && entry.Size != lom.Size() {
+		return false, nil
+	}
+	file, err := os.Open(lom.FQN)
+	if err != nil {
+		return false, err
+	}
+	_, err = io.Copy(ioutil.Discard, newManifestReader(file, entry))
+	if errors.Is(err, errManifestMismatch) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+//
+// Manifest verification
+//
+
+var errManifestMismatch = errors.New("downloaded content does not match the manifest")
+
+type (
+	// manifestReader computes the checksums of the content while it is being
+	// streamed and verifies them, together with the size, against the manifest
+	// entry once the whole content has been read. On mismatch, the error is
+	// returned instead of io.EOF so that the object is never finalized.
+	manifestReader struct {
+		r      io.ReadCloser
+		entry  *DlManifestObj
+		size   int64
+		hashes []manifestHash
+	}
+
+	manifestHash struct {
+		ty       string
+		expected string
+		h        hash.Hash
+	}
+)
+
+func newManifestReader(r io.ReadCloser, entry *DlManifestObj) *manifestReader {
+	mr := &manifestReader{r: r, entry: entry}
+	if entry.MD5 != "" {
+		mr.hashes = append(mr.hashes, manifestHash{cmn.ChecksumMD5, entry.MD5, md5.New()})
+	}
+	if entry.SHA256 != "" {
+		mr.hashes = append(mr.hashes, manifestHash{cmn.ChecksumSHA256, entry.SHA256, sha256.New()})
+	}
+	if entry.CRC32C != "" {
+		mr.hashes = append(mr.hashes, manifestHash{cmn.ChecksumCRC32C, entry.CRC32C, cmn.NewCRC32C()})
+	}
+	return mr
+}
+
+func (mr *manifestReader) Read(p []byte) (n int, err error) {
+	n, err = mr.r.Read(p)
+	mr.size += int64(n)
+	for _, mh := range mr.hashes {
+		mh.h.Write(p[:n])
+	}
+	if mr.entry.Size > 0 && mr.size > mr.entry.Size {
+		return n, fmt.Errorf("%w: size exceeds %d", errManifestMismatch, mr.entry.Size)
+	}
+	if err == io.EOF {
+		if verr := mr.verify(); verr != nil {
+			return n, verr
+		}
+	}
+	return
+}
+
+func (mr *manifestReader) verify() error {
+	if mr.entry.Size > 0 && mr.size != mr.entry.Size {
+		return fmt.Errorf("%w: size %d, expected %d", errManifestMismatch, mr.size, mr.entry.Size)
+	}
+	for _, mh := range mr.hashes {
+		if computed := hex.EncodeToString(mh.h.Sum(nil)); !strings.EqualFold(computed, mh.expected) {
+			return fmt.Errorf("%w: %s checksum %s, expected %s", errManifestMismatch, mh.ty, computed, mh.expected)
+		}
+	}
+	return nil
+}
+
+func (mr *manifestReader) Close() error { return mr.r.Close() }
+
+// cksum returns the manifest checksum of the given type (if provided).
+func (e *DlManifestObj) cksum(ty string) *cmn.Cksum {
+	switch ty {
+	case cmn.ChecksumMD5:
+		return cmn.NewCksum(ty, strings.ToLower(e.MD5))
+	case cmn.ChecksumSHA256:
+		return cmn.NewCksum(ty, strings.ToLower(e.SHA256))
+	case cmn.ChecksumCRC32C:
+		return cmn.NewCksum(ty, strings.ToLower(e.CRC32C))
+	default:
+		return nil
+	}
+}
@@ -5,13 +5,22 @@
 package downloader
 
 import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/tutils/tassert"
 )
 
@@ -61,28 +70,110 @@ func TestCompareObject(t *testing.T) {
 	lom.SetVersion("version")
 	modifyFirstCharacter(t, lom.FQN, 'a')
 
-	equal, err := compareObjects(obj, lom)
+	equal, err := compareObjects(obj, lom, nil)
 	tassert.CheckFatal(t, err)
 	tassert.Errorf(t, !equal, "expected the objects not to be equal")
 
 	// Check that objects are still not equal after size update
 	lom.SetSize(65)
-	equal, err = compareObjects(obj, lom)
+	equal, err = compareObjects(obj, lom, nil)
 	tassert.CheckFatal(t, err)
 	tassert.Errorf(t, !equal, "expected the objects not to be equal")
 
 	// Check that objects are still not equal after version update
 	lom.SetVersion("1503349750687573")
-	equal, err = compareObjects(obj, lom)
+	equal, err = compareObjects(obj, lom, nil)
 	tassert.CheckFatal(t, err)
 	tassert.Errorf(t, !equal, "expected the objects not to be equal")
 
 	modifyFirstCharacter(t, lom.FQN, 'f')
-	equal, err = compareObjects(obj, lom)
+	equal, err = compareObjects(obj, lom, nil)
 	tassert.CheckFatal(t, err)
 	tassert.Errorf(t, equal, "expected the objects to be equal")
 }
 
+func TestCompareObjectCachedCksum(t *testing.T) {
+	const content = "cached checksum content"
+	f, err := ioutil.TempFile("", "")
+	tassert.CheckFatal(t, err)
+	defer os.Remove(f.Name())
+	_, err = f.WriteString(content)
+	f.Close()
+	tassert.CheckFatal(t, err)
+
+	var (
+		sum = md5.Sum([]byte(content))
+		obj = dlObj{
+			fromCloud: true,
+			remote:    &remoteObjInfo{size: int64(len(content)), cksum: cmn.NewCksum(cmn.ChecksumMD5, hex.EncodeToString(sum[:]))},
+		}
+		lom = &cluster.LOM{T: cluster.NewTargetMock(nil), FQN: f.Name()}
+	)
+	lom.SetSize(int64(len(content)))
+
+	equal, err := compareObjects(obj, lom, nil)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, equal, "expected the objects to be equal")
+
+	b, err := fs.GetXattr(lom.FQN, xattrDlCksum)
+	if err != nil {
+		t.Skipf("xattrs are not supported: %v", err)
+	}
+	tassert.Errorf(t, strings.HasSuffix(string(b), hex.EncodeToString(sum[:])), "unexpected cached checksum %q", b)
+
+	// The cached checksum is used instead of computing it again.
+	prefix := strings.TrimSuffix(string(b), hex.EncodeToString(sum[:]))
+	tassert.CheckFatal(t, fs.SetXattr(lom.FQN, xattrDlCksum, []byte(prefix+"0123")))
+	equal, err = compareObjects(obj, lom, nil)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !equal, "expected the cached checksum to be used")
+}
+
+func TestManifestReader(t *testing.T) {
+	const content = "manifest verified content"
+	var (
+		md5Sum    = md5.Sum([]byte(content))
+		sha256Sum = sha256.Sum256([]byte(content))
+		crc       = cmn.NewCRC32C()
+	)
+	crc.Write([]byte(content))
+
+	entry := DlManifestObj{
+		Link:   "http://example.com/dir/obj.txt",
+		Size:   int64(len(content)),
+		MD5:    hex.EncodeToString(md5Sum[:]),
+		SHA256: strings.ToUpper(hex.EncodeToString(sha256Sum[:])),
+		CRC32C: hex.EncodeToString(crc.Sum(nil)),
+	}
+	tassert.CheckFatal(t, entry.Validate())
+	tassert.Errorf(t, entry.ObjName == "obj.txt", "expected object name from link, got: %q", entry.ObjName)
+
+	read := func(entry DlManifestObj, content string) error {
+		_, err := io.Copy(ioutil.Discard, newManifestReader(ioutil.NopCloser(strings.NewReader(content)), &entry))
+		return err
+	}
+	tassert.CheckFatal(t, read(entry, content))
+
+	corrupted := "manifest verified c0ntent"
+	err := read(entry, corrupted)
+	tassert.Errorf(t, errors.Is(err, errManifestMismatch), "expected checksum mismatch, got: %v", err)
+	err = read(entry, content+"!")
+	tassert.Errorf(t, errors.Is(err, errManifestMismatch), "expected size mismatch, got: %v", err)
+
+	// Only the checksums provided by the manifest are verified.
+	sha256Only := DlManifestObj{Link: entry.Link, SHA256: entry.SHA256}
+	tassert.CheckFatal(t, read(sha256Only, content))
+	err = read(sha256Only, corrupted)
+	tassert.Errorf(t, errors.Is(err, errManifestMismatch), "expected checksum mismatch, got: %v", err)
+
+	tassert.Errorf(t, entry.cksum(cmn.ChecksumMD5).Value() == entry.MD5, "unexpected md5 checksum")
+	tassert.Errorf(t, strings.EqualFold(entry.cksum(cmn.ChecksumSHA256).Value(), entry.SHA256), "unexpected sha256 checksum")
+	tassert.Errorf(t, entry.cksum(cmn.ChecksumXXHash) == nil, "expected no xxhash checksum")
+
+	invalid := DlManifestObj{Link: entry.Link, MD5: "not-hex"}
+	tassert.Errorf(t, invalid.Validate() != nil, "expected invalid checksum error")
+}
+
 func downloadObject(link string) (string, error) {
 	resp, err := http.Get(link)
 	if err != nil {
@@ -107,3 +198,121 @@ func modifyFirstCharacter(t *testing.T, path string, c byte) {
 	_, err = f.WriteAt([]byte{c}, 0)
 	tassert.CheckFatal(t, err)
 }
+
+func TestSourceAuth(t *testing.T) {
+	base := DlBase{
+		Headers:     map[string]string{"x-api-key": "secret", "Cookie": "session=abc"},
+		Credentials: DlCredentials{Username: "user", Password: "pass"},
+	}
+	header := base.AsHeader()
+	tassert.Fatalf(t, header.Get(cmn.HeaderDlSourceAuth) != "", "expected %q header", cmn.HeaderDlSourceAuth)
+	tassert.Fatalf(t, !strings.Contains(header.Get(cmn.HeaderDlSourceAuth), "secret"), "credentials should be encoded")
+
+	received := DlBase{}
+	tassert.CheckFatal(t, received.InitWithHeader(header))
+	req, err := newSourceRequest(context.Background(), http.MethodGet, "http://example.com", received.sourceAuth())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, req.Header.Get("X-Api-Key") == "secret", "expected custom header, got: %v", req.Header)
+	tassert.Errorf(t, req.Header.Get("Cookie") == "session=abc", "expected cookie, got: %v", req.Header)
+	user, pass, ok := req.BasicAuth()
+	tassert.Errorf(t, ok && user == "user" && pass == "pass", "expected basic auth, got: %v", req.Header)
+
+	received = DlBase{}
+	tassert.CheckFatal(t, received.InitWithHeader(http.Header{}))
+	tassert.Errorf(t, received.AsHeader() == nil, "expected no header")
+	req, err = newSourceRequest(context.Background(), http.MethodGet, "http://example.com", received.sourceAuth())
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, len(req.Header) == 0, "expected no headers, got: %v", req.Header)
+
+	token := DlBase{Credentials: DlCredentials{Token: "abc"}}
+	tassert.Errorf(t, token.sourceAuth().header().Get(cmn.HeaderAuthorization) == "Bearer abc", "expected bearer token")
+
+	for _, invalid := range []DlBase{
+		{Credentials: DlCredentials{Password: "pass"}},
+		{Credentials: DlCredentials{Username: "user", Token: "abc"}},
+		{Headers: map[string]string{"Authorization": "Basic abc"}, Credentials: DlCredentials{Token: "abc"}},
+		{Headers: map[string]string{"bad key": "value"}},
+		{Headers: map[string]string{"key": "value\r\nInjected: header"}},
+	} {
+		invalid.Bck.Name = "bucket"
+		tassert.Errorf(t, invalid.Validate() != nil, "expected %+v to be invalid", invalid.sourceAuth())
+	}
+}
+
+func TestSealCredentials(t *testing.T) {
+	config := cmn.GCO.BeginUpdate()
+	prevSecret := config.Auth.Secret
+	config.Auth.Secret = "cluster-secret"
+	cmn.GCO.CommitUpdate(config)
+	defer func() {
+		config := cmn.GCO.BeginUpdate()
+		config.Auth.Secret = prevSecret
+		cmn.GCO.CommitUpdate(config)
+	}()
+
+	plaintext := `{"credentials":{"token":"secret"}}`
+	sealed, err := sealCredentials(plaintext)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, !strings.Contains(sealed, "secret"), "expected encrypted data")
+
+	opened, err := openCredentials(sealed)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, opened == plaintext, "expected %q, got: %q", plaintext, opened)
+
+	// The key is derived from the cluster secret.
+	config = cmn.GCO.BeginUpdate()
+	config.Auth.Secret = "other-secret"
+	cmn.GCO.CommitUpdate(config)
+	_, err = openCredentials(sealed)
+	tassert.Errorf(t, err != nil, "expected credentials sealed with other secret to be rejected")
+
+	config = cmn.GCO.BeginUpdate()
+	config.Auth.Secret = ""
+	cmn.GCO.CommitUpdate(config)
+	_, err = sealCredentials(plaintext)
+	tassert.Errorf(t, err != nil, "expected error when the secret is not configured")
+}
+
+func TestSourceHeaderRedirect(t *testing.T) {
+	var received http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer other.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.Redirect(w, r, other.URL+"/obj", http.StatusFound)
+	}))
+	defer origin.Close()
+
+	auth := &dlSourceAuth{Headers: map[string]string{"x-api-key": "secret"}}
+	resp, err := headLink(origin.URL+"/obj", auth)
+	tassert.CheckFatal(t, err)
+	tassert.Errorf(t, resp.StatusCode == http.StatusOK, "expected 200, got: %d", resp.StatusCode)
+	tassert.Fatalf(t, received != nil, "expected request to be redirected")
+	tassert.Errorf(t, received.Get("x-api-key") == "", "expected custom header not to be forwarded to another host")
+}
+
+func TestMemberObjName(t *testing.T) {
+	var tests = []struct {
+		archive, member, expected string
+	}{
+		{"shard.tar", "a.jpg", "shard/a.jpg"},
+		{"dir/shard.tar.gz", "./sub/a.jpg", "dir/shard/sub/a.jpg"},
+		{"shard.tgz", "sub//b/../a.jpg", "shard/sub/a.jpg"},
+		{"shard.zip", "a/b/c", "shard/a/b/c"},
+	}
+	for _, test := range tests {
+		objName, err := memberObjName(test.archive, test.member)
+		tassert.CheckFatal(t, err)
+		tassert.Errorf(t, objName == test.expected, "%s/%s: expected %q, got: %q", test.archive, test.member, test.expected, objName)
+	}
+
+	for _, member := range []string{"/etc/passwd", "../a.jpg", "a/../../b", ".", ""} {
+		_, err := memberObjName("shard.tar", member)
+		tassert.Errorf(t, err != nil, "expected member %q to be rejected", member)
+	}
+}
@@ -0,0 +1,252 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package dsort
+
+import (
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/OneOfOne/xxhash"
+)
+
+// Each target checkpoints the progress of the dSort job in the persisted
+// managers' DB. When the job gets aborted (by the user, on error, or because
+// a target has left the cluster) the checkpoints allow to resume the job under
+// the same UUID: output shards which have already been created (and whose
+// records did not change) are not created again - only the remaining ranges
+// of records are re-planned across the current targets.
+//
+// Additionally, once the extraction phase finishes, the target persists the
+// records of the input shards it has extracted (see extractedShard). The resumed
+// job reuses them and extracts again only the input shards which have changed
+// or which the target did not own before (e.g., shards of the target that has
+// left the cluster).
+
+const (
+	checkpointsCollection = "checkpoints"
+	extractedCollection   = "extracted"
+	checkpointInterval    = 10 * time.Second // how often, at most, the progress is persisted
+)
+
+// phases of the dSort job as recorded in the checkpoint
+const (
+	PhaseExtraction   = "extraction"
+	PhaseDistribution = "distribution"
+	PhaseCreation     = "creation"
+)
+
+type (
+	// CreatedShard identifies the output shard which has been created.
+	CreatedShard struct {
+		Name   string  `json:"n"`
+		Bck    cmn.Bck `json:"b"`
+		Digest uint64  `json:"d"` // see shardDigest
+	}
+
+	// Checkpoint describes the progress of the dSort job on a single target.
+	Checkpoint struct {
+		ManagerUUID        string             `json:"manager_uuid"`
+		DaemonID           string             `json:"daemon_id"`
+		Spec               *ParsedRequestSpec `json:"spec"`
+		Phase              string             `json:"phase"`
+		SmapVersion        int64              `json:"smap_version"`
+		RecordsDistributed bool               `json:"records_distributed"` // records have been sent (merged)
+		CreatedShards      []CreatedShard     `json:"created_shards"`
+		Updated            time.Time          `json:"updated"`
+	}
+
+	// resumeMsg is sent by the proxy to every target to initialize the resumed job.
+	resumeMsg struct {
+		Spec          *ParsedRequestSpec `json:"spec"`
+		CreatedShards []CreatedShard     `json:"created_shards"` // union of all the targets' checkpoints
+	}
+
+	// extractedShard describes the input shard extracted by the target along
+	// with its records (stored by offset in the shard). The records are reused
+	// by the resumed job as long as the shard has not changed.
+	extractedShard struct {
+		Size    int64             `json:"size,string"`
+		Cksum   string            `json:"cksum,omitempty"`
+		Version string            `json:"version,omitempty"`
+		Records []*extract.Record `json:"records"`
+	}
+
+	checkpointer struct {
+		mu    sync.Mutex
+		ckpt  Checkpoint
+		saved time.Time
+	}
+)
+
+// shardDigest identifies the content of the output shard regardless of which
+// targets its records have been extracted by.
+func shardDigest(s *extract.Shard) uint64 {
+	h := xxhash.New64()
+	for _, r := range s.Records.All() {
+		h.WriteString(r.Name)
+		for _, obj := range r.Objects {
+			h.WriteString(obj.Extension)
+		}
+	}
+	return h.Sum64()
+}
+
+// uname corresponds to shardUname of the created shard.
+func (c *CreatedShard) uname() string { return cluster.NewBckEmbed(c.Bck).MakeUname(c.Name) }
+
+func (m *Manager) initCheckpoint(created []CreatedShard) {
+	cp := &m.checkpoint
+	cp.mu.Lock()
+	cp.ckpt = Checkpoint{
+		ManagerUUID:   m.ManagerUUID,
+		DaemonID:      m.ctx.node.DaemonID,
+		Spec:          m.rs,
+		Phase:         PhaseExtraction,
+		SmapVersion:   m.smap.Version,
+		CreatedShards: created,
+	}
+	cp.mu.Unlock()
+	m.saveCheckpoint(true)
+}
+
+// saveCheckpoint persists the checkpoint, unless it has been persisted recently
+// (and it is not forced to).
+func (m *Manager) saveCheckpoint(force bool) {
+	cp := &m.checkpoint
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.ckpt.ManagerUUID == "" || (!force && time.Since(cp.saved) < checkpointInterval) {
+		return
+	}
+	cp.ckpt.Updated = time.Now()
+	if err := Managers.saveCheckpoint(&cp.ckpt); err != nil {
+		glog.Errorf("%s: failed to save checkpoint, err: %v", m.ManagerUUID, err)
+		return
+	}
+	cp.saved = cp.ckpt.Updated
+}
+
+func (m *Manager) updateCheckpoint(update func(ckpt *Checkpoint), force bool) {
+	m.checkpoint.mu.Lock()
+	update(&m.checkpoint.ckpt)
+	m.checkpoint.mu.Unlock()
+	m.saveCheckpoint(force)
+}
+
+// skipCreatedShards filters out the output shards which have already been
+// created by the previous run of the resumed job and which still exist.
+// Returns the remaining shards and the number of records' objects (per target)
+// that are not going to be sent since they belong to the skipped shards.
+func (m *Manager) skipCreatedShards(shards []*extract.Shard) ([]*extract.Shard, map[string]int64) {
+	if len(m.resumed) == 0 {
+		return shards, nil
+	}
+	var (
+		remaining = shards[:0]
+		skipped   = make(map[string]int64, m.smap.CountTargets())
+		cnt       int
+	)
+	for _, s := range shards {
+		if digest, ok := m.resumed[shardUname(s)]; !ok || digest != shardDigest(s) || !m.createdShardExists(s) {
+			remaining = append(remaining, s)
+			continue
+		}
+		for _, r := range s.Records.All() {
+			skipped[r.DaemonID] += int64(len(r.Objects))
+		}
+		cnt++
+	}
+	glog.Infof("%s: skipping %d output shards created before the job was resumed", m.ManagerUUID, cnt)
+	return remaining, skipped
+}
+
+// createdShardExists checks that the output shard created before the job was
+// resumed exists on the target it belongs to according to the current Smap.
+// The shard is recorded by the target which created it, but the target which
+// received the shard might have left the cluster since.
+func (m *Manager) createdShardExists(s *extract.Shard) bool {
+	lom := &cluster.LOM{T: m.ctx.t, ObjName: s.Name}
+	if err := lom.Init(s.Bck); err != nil {
+		return false
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), m.smap)
+	if err != nil {
+		return false
+	}
+	if si.DaemonID == m.ctx.node.DaemonID {
+		return lom.Load(false) == nil
+	}
+	return m.ctx.t.LookupRemoteSingle(lom, si)
+}
+
+func newExtractedShard(lom *cluster.LOM) *extractedShard {
+	es := &extractedShard{Size: lom.Size(), Version: lom.Version()}
+	if cksum := lom.Cksum(); cksum != nil {
+		es.Cksum = cksum.String()
+	}
+	return es
+}
+
+// matches returns true if the input shard has not changed since its records
+// have been extracted.
+func (es *extractedShard) matches(lom *cluster.LOM) bool {
+	other := newExtractedShard(lom)
+	return es.Size == other.Size && es.Cksum == other.Cksum && es.Version == other.Version
+}
+
+// reuseExtractedShard inserts the records of the input shard extracted before
+// the job was resumed, instead of extracting the shard again.
+func (m *Manager) reuseExtractedShard(shardName string, es *extractedShard, metrics *LocalExtraction) {
+	var objCnt int64
+	for _, r := range es.Records {
+		r.DaemonID = m.ctx.node.DaemonID
+		objCnt += int64(len(r.Objects))
+	}
+	m.recManager.Records.Insert(es.Records...)
+	m.extracted.Store(shardName, es)
+
+	metrics.Lock()
+	metrics.ExtractedRecordCnt += objCnt
+	metrics.ExtractedCnt++
+	metrics.ReusedCnt++
+	metrics.Unlock()
+}
+
+// saveExtractedShards persists the records of all the input shards extracted
+// by the target so that the resumed job does not need to extract them again.
+// The records are persisted only if they can be read directly from the input
+// shard - otherwise (e.g., compressed shards) their contents are lost with the job.
+func (m *Manager) saveExtractedShards() {
+	if !m.extractCreator.SupportsOffset() {
+		return
+	}
+	var (
+		records = m.recManager.OffsetRecords()
+		shards  = make(map[string]*extractedShard)
+	)
+	m.extracted.Range(func(key, value interface{}) bool {
+		shardName := key.(string)
+		es := *value.(*extractedShard)
+		es.Records = records[shardName]
+		for _, r := range es.Records {
+			for _, obj := range r.Objects {
+				if obj.ObjectFileType != fs.ObjectType {
+					return true
+				}
+			}
+		}
+		shards[shardName] = &es
+		return true
+	})
+	if err := Managers.saveExtractedShards(m.ManagerUUID, shards); err != nil {
+		glog.Errorf("%s: failed to save extracted shards, err: %v", m.ManagerUUID, err)
+	}
+}
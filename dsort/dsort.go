@@ -29,6 +29,7 @@ import (
 	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/dsort/filetype"
 	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/transport"
 	"github.com/OneOfOne/xxhash"
 	jsoniter "github.com/json-iterator/go"
@@ -45,7 +46,7 @@ type (
 		postExtraction()
 		postRecordDistribution()
 		createShardsLocally() (err error)
-		preShardCreation(shardName string, mpathInfo *fs.MountpathInfo) error
+		preShardCreation(shardUname string, mpathInfo *fs.MountpathInfo) error
 		postShardCreation(mpathInfo *fs.MountpathInfo)
 		cleanup()
 		finalCleanup() error
@@ -65,6 +66,9 @@ var js = jsoniter.ConfigFastest
 
 func (m *Manager) start() (err error) {
 	defer func() {
+		// NOTE: must be persisted before cleanup (see finalCleanup)
+		m.saveCheckpoint(true)
+
 		m.lock()
 		m.setInProgressTo(false)
 		m.unlock()
@@ -89,6 +93,7 @@ func (m *Manager) start() (err error) {
 	if err := m.extractLocalShards(); err != nil {
 		return err
 	}
+	m.updateCheckpoint(func(ckpt *Checkpoint) { ckpt.Phase = PhaseDistribution }, true)
 
 	s := binary.BigEndian.Uint64(m.rs.TargetOrderSalt)
 	targetOrder := randomTargetOrder(s, m.smap.Tmap)
@@ -100,22 +105,14 @@ func (m *Manager) start() (err error) {
 	if err != nil {
 		return err
 	}
+	m.updateCheckpoint(func(ckpt *Checkpoint) {
+		ckpt.Phase = PhaseCreation
+		ckpt.RecordsDistributed = true
+	}, true)
 
 	// Phase 3. - run only by the final target
 	if curTargetIsFinal {
-		shardSize := m.rs.OutputShardSize
-		if m.extractCreator.UsingCompression() {
-			// By making the assumption that the input content is reasonably
-			// uniform across all shards, the output shard size required (such
-			// that each gzip compressed output shard will have a size close to
-			// rs.ShardSizeBytes) can be estimated.
-			avgCompressRatio := m.avgCompressionRatio()
-			shardSize = int64(float64(m.rs.OutputShardSize) / avgCompressRatio)
-			glog.V(4).Infof("estimated output shard size required before gzip compression: %d", shardSize)
-		}
-
-		// Phase 3.
-		if err := m.distributeShardRecords(shardSize); err != nil {
+		if err := m.distributeShardRecords(); err != nil {
 			return err
 		}
 	}
@@ -164,7 +161,10 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 		if si.DaemonID != m.ctx.node.DaemonID {
 			return nil
 		}
-		if err = lom.Load(false); err != nil {
+		if err = lom.Load(false); err != nil && cmn.IsErrObjNought(err) && m.restoreInputShard(lom) == nil {
+			err = nil
+		}
+		if err != nil {
 			if cmn.IsErrObjNought(err) {
 				msg := fmt.Sprintf("shard %q does not exist (is missing)", shardName)
 				return m.react(m.rs.MissingShards, msg)
@@ -172,6 +172,11 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 			return err
 		}
 
+		if es, ok := m.extracted.reusable[shardName]; ok && es.matches(lom) {
+			m.reuseExtractedShard(shardName, es, metrics)
+			return nil
+		}
+
 		phaseInfo.adjuster.acquireSema(lom.ParsedFQN.MpathInfo)
 		if m.aborted() {
 			phaseInfo.adjuster.releaseSema(lom.ParsedFQN.MpathInfo)
@@ -221,6 +226,7 @@ func (m *Manager) extractShard(name string, metrics *LocalExtraction) func() err
 			return errors.Errorf("error in ExtractShard, file: %s, err: %v", f.Name(), err)
 		}
 		f.Close()
+		m.extracted.Store(shardName, newExtractedShard(lom))
 
 		metrics.Lock()
 		metrics.ExtractedRecordCnt += int64(extractedCount)
@@ -300,8 +306,13 @@ ExtractAllShards:
 	// We will no longer reserve any memory
 	m.dsorter.postExtraction()
 
+	droppedRecords, droppedObjects := m.recManager.FilterRecords()
+	m.saveExtractedShards()
+
 	metrics.Lock()
-	totalExtractedCount := metrics.ExtractedRecordCnt
+	totalExtractedCount := metrics.ExtractedRecordCnt - droppedObjects
+	metrics.DroppedRecordCnt = droppedRecords
+	metrics.KeptRecordCnt = int64(m.recManager.Records.Len())
 	metrics.Unlock()
 	m.incrementRef(totalExtractedCount)
 	return nil
@@ -314,13 +325,11 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 
 		// object related variables
 		shardName = s.Name
-		bucket    = m.rs.OutputBucket
-		provider  = m.rs.OutputProvider
 
 		errCh = make(chan error, 2)
 	)
 	lom := &cluster.LOM{T: m.ctx.t, ObjName: shardName}
-	if err = lom.Init(cmn.Bck{Name: bucket, Provider: provider}); err != nil {
+	if err = lom.Init(s.Bck); err != nil {
 		return
 	}
 	lom.SetAtimeUnix(time.Now().UnixNano())
@@ -333,7 +342,7 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	default:
 	}
 
-	if err := m.dsorter.preShardCreation(s.Name, lom.ParsedFQN.MpathInfo); err != nil {
+	if err := m.dsorter.preShardCreation(shardUname(s), lom.ParsedFQN.MpathInfo); err != nil {
 		return err
 	}
 	defer m.dsorter.postShardCreation(lom.ParsedFQN.MpathInfo)
@@ -346,9 +355,10 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	beforeCreation := time.Now()
 
 	var (
-		wg   = &sync.WaitGroup{}
-		r, w = io.Pipe()
-		n    int64
+		wg    = &sync.WaitGroup{}
+		r, w  = io.Pipe()
+		n     int64
+		index *memsys.SGL // index of the output shard (if requested)
 	)
 	wg.Add(1)
 	go func() {
@@ -371,7 +381,13 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 		wg.Done()
 	}()
 
-	_, err = m.extractCreator.CreateShard(s, w, loadContent)
+	if ic, ok := m.extractCreator.(extract.IndexCreator); ok && m.rs.OutputIndex && !m.rs.DryRun {
+		index = m.ctx.t.GetMMSA().NewSGL(0)
+		defer index.Free()
+		_, err = ic.CreateShardWithIndex(s, w, index, loadContent)
+	} else {
+		_, err = m.extractCreator.CreateShard(s, w, loadContent)
+	}
 	w.CloseWithError(err)
 	if err != nil {
 		r.CloseWithError(err)
@@ -407,48 +423,21 @@ func (m *Manager) createShard(s *extract.Shard) (err error) {
 	// if we have an extra copy of the object local to this target, we
 	// optimize for performance by not removing the object now.
 	if si.DaemonID != m.ctx.node.DaemonID && !m.rs.DryRun {
-		lom.Lock(false)
-		defer lom.Unlock(false)
-
-		file, err := cmn.NewFileHandle(lom.FQN)
-		if err != nil {
+		if err := m.sendCreatedShard(lom, si); err != nil {
 			return err
 		}
+	}
 
-		if lom.Size() <= 0 {
-			goto exit
-		}
-
-		cksumType, cksumValue := lom.Cksum().Get()
-		hdr := transport.Header{
-			Bck:     lom.Bck().Bck,
-			ObjName: shardName,
-			ObjAttrs: transport.ObjectAttrs{
-				Size:       lom.Size(),
-				CksumType:  cksumType,
-				CksumValue: cksumValue,
-			},
-		}
-
-		// Make send synchronous
-		streamWg := &sync.WaitGroup{}
-		errCh := make(chan error, 1)
-		cb := func(_ transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
-			errCh <- err
-			streamWg.Done()
-		}
-		streamWg.Add(1)
-		err = m.streams.shards.Send(transport.Obj{Hdr: hdr, Callback: cb}, file, si)
-		if err != nil {
-			return err
-		}
-		streamWg.Wait()
-		if err := <-errCh; err != nil {
+	if index != nil {
+		if err := m.putShardIndex(s.Bck, shardName+extract.IndexExtension, index); err != nil {
 			return err
 		}
 	}
 
-exit:
+	m.updateCheckpoint(func(ckpt *Checkpoint) {
+		ckpt.CreatedShards = append(ckpt.CreatedShards, CreatedShard{Name: s.Name, Bck: s.Bck, Digest: shardDigest(s)})
+	}, false)
+
 	metrics.Lock()
 	metrics.CreatedCnt++
 	if si.DaemonID != m.ctx.node.DaemonID {
@@ -464,6 +453,82 @@ exit:
 	return nil
 }
 
+// shardUname uniquely identifies the output shard across all output buckets.
+func shardUname(s *extract.Shard) string {
+	return cluster.NewBckEmbed(s.Bck).MakeUname(s.Name)
+}
+
+// sendCreatedShard sends the shard (or its index) created by this target to
+// the target it belongs to according to HRW.
+func (m *Manager) sendCreatedShard(lom *cluster.LOM, si *cluster.Snode) error {
+	lom.Lock(false)
+	defer lom.Unlock(false)
+
+	file, err := cmn.NewFileHandle(lom.FQN)
+	if err != nil {
+		return err
+	}
+
+	if lom.Size() <= 0 {
+		return nil
+	}
+
+	cksumType, cksumValue := lom.Cksum().Get()
+	hdr := transport.Header{
+		Bck:     lom.Bck().Bck,
+		ObjName: lom.ObjName,
+		ObjAttrs: transport.ObjectAttrs{
+			Size:       lom.Size(),
+			CksumType:  cksumType,
+			CksumValue: cksumValue,
+		},
+	}
+
+	// Make send synchronous
+	streamWg := &sync.WaitGroup{}
+	errCh := make(chan error, 1)
+	cb := func(_ transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
+		errCh <- err
+		streamWg.Done()
+	}
+	streamWg.Add(1)
+	err = m.streams.shards.Send(transport.Obj{Hdr: hdr, Callback: cb}, file, si)
+	if err != nil {
+		return err
+	}
+	streamWg.Wait()
+	return <-errCh
+}
+
+// putShardIndex puts the index of the created output shard next to the shard.
+func (m *Manager) putShardIndex(bck cmn.Bck, name string, index *memsys.SGL) error {
+	lom := &cluster.LOM{T: m.ctx.t, ObjName: name}
+	if err := lom.Init(bck); err != nil {
+		return err
+	}
+	lom.SetAtimeUnix(time.Now().UnixNano())
+	workFQN := fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.DSortWorkfileType, filetype.WorkfileCreateShard)
+	err := m.ctx.t.PutObject(cluster.PutObjectParams{
+		LOM:          lom,
+		Reader:       memsys.NewReader(index),
+		WorkFQN:      workFQN,
+		RecvType:     cluster.WarmGet,
+		Started:      time.Now(),
+		WithFinalize: true,
+	})
+	if err != nil {
+		return err
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), m.smap)
+	if err != nil {
+		return err
+	}
+	if si.DaemonID != m.ctx.node.DaemonID {
+		return m.sendCreatedShard(lom, si)
+	}
+	return nil
+}
+
 // participateInRecordDistribution coordinates the distributed merging and
 // sorting of each target's SortedRecords based on the order defined by
 // targetOrder. It returns a bool, currentTargetIsFinal, which is true iff the
@@ -584,11 +649,28 @@ func (m *Manager) participateInRecordDistribution(targetOrder cluster.Nodes) (cu
 	return true, err
 }
 
-func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, error) {
+// maxShardSize returns the maximal size of the output shard before it gets
+// compressed (if the output shards are compressed at all).
+func (m *Manager) maxShardSize(outputShardSize int64) int64 {
+	if !m.extractCreator.UsingCompression() || m.rs.OutputExtension != m.rs.Extension {
+		return outputShardSize
+	}
+	// By making the assumption that the input content is reasonably
+	// uniform across all shards, the output shard size required (such
+	// that each gzip compressed output shard will have a size close to
+	// rs.ShardSizeBytes) can be estimated.
+	avgCompressRatio := m.avgCompressionRatio()
+	shardSize := int64(float64(outputShardSize) / avgCompressRatio)
+	glog.V(4).Infof("estimated output shard size required before gzip compression: %d", shardSize)
+	return shardSize
+}
+
+func (m *Manager) generateShardsWithTemplate(records *extract.Records, template *cmn.ParsedTemplate, bck cmn.Bck,
+	maxSize int64) ([]*extract.Shard, error) {
 	var (
-		n               = m.recManager.Records.Len()
-		names           = m.rs.OutputFormat.Template.Iter()
-		shardCount      = m.rs.OutputFormat.Template.Count()
+		n               = records.Len()
+		names           = template.Iter()
+		shardCount      = template.Count()
 		start           int
 		curShardSize    int64
 		shards          = make([]*extract.Shard, 0)
@@ -601,7 +683,7 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 		maxSize = int64(math.Ceil(float64(m.totalUncompressedSize()) / float64(shardCount)))
 	}
 
-	for i, r := range m.recManager.Records.All() {
+	for i, r := range records.All() {
 		numLocalRecords[r.DaemonID]++
 		curShardSize += r.TotalSize() + m.extractCreator.MetadataSize()*int64(len(r.Objects))
 		if curShardSize < maxSize && i < n-1 {
@@ -614,11 +696,12 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 			return nil, errors.Errorf("number of shards to be created exceeds expected number of shards (%d)", shardCount)
 		}
 		shard := &extract.Shard{
-			Name: name + m.rs.Extension,
+			Name: name + m.rs.OutputExtension,
+			Bck:  bck,
 		}
 
 		shard.Size = curShardSize
-		shard.Records = m.recManager.Records.Slice(start, i+1)
+		shard.Records = records.Slice(start, i+1)
 		shards = append(shards, shard)
 
 		start = i + 1
@@ -633,56 +716,19 @@ func (m *Manager) generateShardsWithTemplate(maxSize int64) ([]*extract.Shard, e
 
 func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shard, error) {
 	var (
-		shards         = make([]*extract.Shard, 0)
-		externalKeyMap = make(map[string]string)
-		shardsBuilder  = make(map[string][]*extract.Shard)
+		shards        = make([]*extract.Shard, 0)
+		shardsBuilder = make(map[string][]*extract.Shard)
+		bck           = m.rs.outputBck()
 	)
 
 	if maxSize <= 0 {
 		return nil, errors.New("invalid max size of shard was specified when using external key map")
 	}
 
-	req, err := http.NewRequest(http.MethodGet, m.rs.OrderFileURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := m.client.Do(req)
+	externalKeyMap, err := m.fetchExternalKeyMap(1)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// TODO: handle very large files > GB - in case the file is very big we
-	// need to save file to the disk and operate on the file directly rather
-	// than keeping everything in memory.
-	var (
-		lineReader = bufio.NewReader(resp.Body)
-	)
-	for idx := 0; ; idx++ {
-		l, _, err := lineReader.ReadLine()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		line := strings.TrimSpace(string(l))
-		if line == "" {
-			continue
-		}
-
-		parts := strings.Split(line, m.rs.OrderFileSep)
-		if len(parts) != 2 {
-			msg := fmt.Sprintf("malformed line (%d) in external key map: %s", idx, line)
-			if err := m.react(m.rs.EKMMalformedLine, msg); err != nil {
-				return nil, err
-			}
-		}
-
-		recordKey, shardNameFmt := parts[0], parts[1]
-		externalKeyMap[recordKey] = shardNameFmt
-	}
 
 	for _, r := range m.recManager.Records.All() {
 		key := fmt.Sprintf("%v", r.Key)
@@ -700,6 +746,7 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 		if shardCount == 0 || shards[shardCount-1].Size > maxSize {
 			shard := &extract.Shard{
 				Name:    fmt.Sprintf(shardNameFmt, shardCount),
+				Bck:     bck,
 				Size:    recordSize,
 				Records: extract.NewRecords(1),
 			}
@@ -720,6 +767,54 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 	return shards, nil
 }
 
+// fetchExternalKeyMap fetches the order file (external key map) and maps each
+// record key (first column) to the value in the given column.
+func (m *Manager) fetchExternalKeyMap(column int) (map[string]string, error) {
+	externalKeyMap := make(map[string]string)
+	req, err := http.NewRequest(http.MethodGet, m.rs.OrderFileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// TODO: handle very large files > GB - in case the file is very big we
+	// need to save file to the disk and operate on the file directly rather
+	// than keeping everything in memory.
+	var (
+		lineReader = bufio.NewReader(resp.Body)
+	)
+	for idx := 0; ; idx++ {
+		l, _, err := lineReader.ReadLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line := strings.TrimSpace(string(l))
+		if line == "" {
+			continue
+		}
+
+		parts := strings.Split(line, m.rs.OrderFileSep)
+		if len(parts) <= column {
+			msg := fmt.Sprintf("malformed line (%d) in external key map: %s", idx, line)
+			if err := m.react(m.rs.EKMMalformedLine, msg); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		externalKeyMap[parts[0]] = parts[column]
+	}
+	return externalKeyMap, nil
+}
+
 // distributeShardRecords creates Shard structs in the order of
 // dsortManager.Records corresponding to a maximum size maxSize. Each Shard is
 // sent in an HTTP request to the appropriate target to create the actual file
@@ -735,10 +830,11 @@ func (m *Manager) generateShardsWithOrderingFile(maxSize int64) ([]*extract.Shar
 //      The appropriate target is determined firstly by locality (i.e. the target with the most local records)
 //      and secondly (if there is a tie), by least load (i.e. the target with the least number of shard creation requests
 //      sent to it already).
-func (m *Manager) distributeShardRecords(maxSize int64) error {
+func (m *Manager) distributeShardRecords() error {
 	var (
-		shards []*extract.Shard
-		err    error
+		shards  []*extract.Shard
+		dropped map[string]int64 // objects which are not sent since their records do not belong to any shard
+		err     error
 
 		wg             = &sync.WaitGroup{}
 		shardsToTarget = make(map[*cluster.Snode][]*extract.Shard, m.smap.CountTargets())
@@ -752,15 +848,25 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 		sendOrder[d.DaemonID] = make(map[string]*extract.Shard, 100)
 	}
 
-	if m.rs.OrderFileURL != "" {
-		shards, err = m.generateShardsWithOrderingFile(maxSize)
+	if m.rs.Split != nil {
+		shards, dropped, err = m.generateShardsWithSplit()
+	} else if m.rs.OrderFileURL != "" {
+		shards, err = m.generateShardsWithOrderingFile(m.maxShardSize(m.rs.OutputShardSize))
 	} else {
-		shards, err = m.generateShardsWithTemplate(maxSize)
+		shards, err = m.generateShardsWithTemplate(m.recManager.Records, &m.rs.OutputFormat.Template,
+			m.rs.outputBck(), m.maxShardSize(m.rs.OutputShardSize))
 	}
 
 	if err != nil {
 		return err
 	}
+	shards, skipped := m.skipCreatedShards(shards)
+	for daemonID, cnt := range dropped {
+		if skipped == nil {
+			skipped = make(map[string]int64, m.smap.CountTargets())
+		}
+		skipped[daemonID] += cnt
+	}
 
 	// TODO: Following heuristic doesn't seem to be working correctly in
 	// all cases. When there is not much shards at each disk (like 1-5)
@@ -780,13 +886,22 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 	// 	// target.
 	// }
 
-	bck := cluster.NewBck(m.rs.OutputBucket, m.rs.OutputProvider, cmn.NsGlobal)
-	if err := bck.Init(m.ctx.bmdOwner, m.ctx.t.Snode()); err != nil {
-		return err
-	}
-
+	bcks := make(map[cmn.Bck]cmn.Bck, 1)
 	for _, s := range shards {
-		si, err := cluster.HrwTarget(bck.MakeUname(s.Name), m.smap)
+		// NOTE: shards must refer to initialized buckets (e.g. with resolved
+		// provider) so that all targets agree on their HRW targets.
+		initBck, ok := bcks[s.Bck]
+		if !ok {
+			bck := cluster.NewBckEmbed(s.Bck)
+			if err := bck.Init(m.ctx.bmdOwner, m.ctx.t.Snode()); err != nil {
+				return err
+			}
+			initBck = bck.Bck
+			bcks[s.Bck] = initBck
+		}
+		s.Bck = initBck
+
+		si, err := cluster.HrwTarget(shardUname(s), m.smap)
 		if err != nil {
 			return err
 		}
@@ -798,6 +913,7 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 			if !ok {
 				shard = &extract.Shard{
 					Name:    s.Name,
+					Bck:     s.Bck,
 					Records: extract.NewRecords(100),
 				}
 				singleSendOrder[record.DaemonID] = shard
@@ -806,7 +922,7 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 		}
 
 		for daemonID, shard := range singleSendOrder {
-			sendOrder[daemonID][shard.Name] = shard
+			sendOrder[daemonID][shardUname(shard)] = shard
 		}
 	}
 
@@ -818,8 +934,9 @@ func (m *Manager) distributeShardRecords(maxSize int64) error {
 			defer wg.Done()
 
 			body, err := js.Marshal(creationPhaseMetadata{
-				Shards:    s,
-				SendOrder: order,
+				Shards:         s,
+				SendOrder:      order,
+				SkippedObjects: skipped[si.DaemonID],
 			})
 			if err != nil {
 				errCh <- err
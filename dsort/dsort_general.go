@@ -274,7 +274,7 @@ CreateAllShards:
 	return group.Wait()
 }
 
-func (ds *dsorterGeneral) preShardCreation(shardName string, mpathInfo *fs.MountpathInfo) error {
+func (ds *dsorterGeneral) preShardCreation(_ string, mpathInfo *fs.MountpathInfo) error {
 	ds.creationPhase.adjuster.acquireSema(mpathInfo)
 	return nil
 }
@@ -316,6 +316,9 @@ func (ds *dsorterGeneral) loadContent() extract.LoadContentFunc {
 			switch obj.StoreType {
 			case extract.OffsetStoreType:
 				f, err := os.Open(fullContentPath) // TODO: it should be open always
+				if os.IsNotExist(err) && ds.m.restoreInputShardByName(obj.ContentPath) == nil {
+					f, err = os.Open(fullContentPath)
+				}
 				if err != nil {
 					return written, errors.WithMessage(err, "(offset) open local content failed")
 				}
@@ -541,6 +544,9 @@ func (ds *dsorterGeneral) makeRecvRequestFunc() transport.Receive {
 		switch req.RecordObj.StoreType {
 		case extract.OffsetStoreType:
 			f, err := cmn.NewFileHandle(fullContentPath)
+			if os.IsNotExist(err) && ds.m.restoreInputShardByName(req.RecordObj.ContentPath) == nil {
+				f, err = cmn.NewFileHandle(fullContentPath)
+			}
 			if err != nil {
 				errHandler(err, respHdr, fromNode)
 				return
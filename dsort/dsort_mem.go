@@ -11,6 +11,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 	"unsafe"
@@ -296,15 +297,15 @@ func (ds *dsorterMem) finalCleanup() error {
 
 func (ds *dsorterMem) postRecordDistribution() {}
 
-func (ds *dsorterMem) preShardCreation(shardName string, mpathInfo *fs.MountpathInfo) error {
+func (ds *dsorterMem) preShardCreation(shardUname string, mpathInfo *fs.MountpathInfo) error {
 	bsi := &buildingShardInfo{
-		shardName: shardName,
+		shardUname: shardUname,
 	}
 	opaque := bsi.NewPack(ds.m.ctx.t.GetSmallMMSA())
 	if err := ds.streams.builder.Send(transport.Obj{Hdr: transport.Header{Opaque: opaque}}, nil); err != nil {
 		return err
 	}
-	ds.creationPhase.requestedShards <- shardName // we also need to inform ourselves
+	ds.creationPhase.requestedShards <- shardUname // we also need to inform ourselves
 	ds.creationPhase.adjuster.write.acquireSema(mpathInfo)
 	return nil
 }
@@ -372,8 +373,8 @@ func (ds *dsorterMem) createShardsLocally() (err error) {
 			}
 
 			select {
-			case shardName := <-ds.creationPhase.requestedShards:
-				shard, ok := phaseInfo.metadata.SendOrder[shardName]
+			case uname := <-ds.creationPhase.requestedShards:
+				shard, ok := phaseInfo.metadata.SendOrder[uname]
 				if !ok {
 					break
 				}
@@ -383,11 +384,7 @@ func (ds *dsorterMem) createShardsLocally() (err error) {
 					return func() error {
 						defer ds.creationPhase.adjuster.read.releaseGoroutineSema()
 
-						bck := cluster.NewBck(ds.m.rs.OutputBucket, ds.m.rs.OutputProvider, cmn.NsGlobal)
-						if err := bck.Init(ds.m.ctx.bmdOwner, ds.m.ctx.t.Snode()); err != nil {
-							return err
-						}
-						toNode, err := cluster.HrwTarget(bck.MakeUname(shard.Name), ds.m.ctx.smapOwner.Get())
+						toNode, err := cluster.HrwTarget(shardUname(shard), ds.m.smap)
 						if err != nil {
 							return err
 						}
@@ -403,7 +400,7 @@ func (ds *dsorterMem) createShardsLocally() (err error) {
 					}
 				}(shard))
 
-				delete(phaseInfo.metadata.SendOrder, shardName)
+				delete(phaseInfo.metadata.SendOrder, uname)
 			case <-ds.m.listenAborted():
 				stopCh.Close()
 				group.Wait()
@@ -532,6 +529,9 @@ func (ds *dsorterMem) sendRecordObj(rec *extract.Record, obj *extract.RecordObj,
 	switch obj.StoreType {
 	case extract.OffsetStoreType:
 		f, err := cmn.NewFileHandle(fullContentPath)
+		if os.IsNotExist(err) && ds.m.restoreInputShardByName(obj.ContentPath) == nil {
+			f, err = cmn.NewFileHandle(fullContentPath)
+		}
 		if err != nil {
 			return err
 		}
@@ -589,7 +589,7 @@ func (ds *dsorterMem) makeRecvRequestFunc() transport.Receive {
 			return
 		}
 
-		ds.creationPhase.requestedShards <- req.shardName
+		ds.creationPhase.requestedShards <- req.shardUname
 	}
 }
 
@@ -0,0 +1,174 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/OneOfOne/xxhash"
+	"github.com/pkg/errors"
+)
+
+// Records can be dropped while resharding. Filter drops the records which
+// miss any of the required extensions or whose content (of the object with
+// given extension) is excluded. Sample keeps a deterministic (for given seed)
+// subset of the records: whether a record is kept depends only on its name
+// and the seed, so the records are sampled the same way on every run.
+//
+// Both are evaluated by the RecordManager during extraction: the objects of
+// sampled out (or already excluded) records are not even stored, the remaining
+// records are dropped once the extraction finishes (see FilterRecords).
+
+const samplePrecision = 1 << 32
+
+type (
+	FilterSpec struct {
+		// drop records which miss any of the extensions
+		RequiredExtensions []string `json:"required_extensions" yaml:"required_extensions"`
+		// extension of the object whose content is matched against Exclude and ExcludeRegex
+		Extension string `json:"extension" yaml:"extension"`
+		// drop records whose content (with surrounding whitespaces trimmed) equals any of the values
+		Exclude []string `json:"exclude" yaml:"exclude"`
+		// drop records whose content matches the regex
+		ExcludeRegex string `json:"exclude_regex" yaml:"exclude_regex"`
+	}
+
+	SampleSpec struct {
+		Percentage float64 `json:"percentage" yaml:"percentage"` // percentage of the records to keep: (0, 100]
+		Seed       string  `json:"seed" yaml:"seed"`             // seed of the sampling, should be int
+	}
+
+	// RecordFilter evaluates FilterSpec and SampleSpec.
+	RecordFilter struct {
+		required  []string
+		ext       string
+		exclude   cmn.StringSet
+		excludeRe *regexp.Regexp
+
+		sample    bool
+		seed      uint64
+		threshold uint64 // record is kept when hash of its name is below the threshold
+
+		excluded       sync.Map     // unique names of the records which are dropped
+		droppedObjects atomic.Int64 // objects which have not been stored since their records are dropped
+	}
+)
+
+func (f *FilterSpec) IsEmpty() bool {
+	return len(f.RequiredExtensions) == 0 && f.Extension == "" && len(f.Exclude) == 0 && f.ExcludeRegex == ""
+}
+
+func (f *FilterSpec) Validate() error {
+	if f.IsEmpty() {
+		return nil
+	}
+	for _, ext := range f.RequiredExtensions {
+		if ext == "" || ext[0] != '.' {
+			return fmt.Errorf("invalid required extension %q, should be in format: .ext", ext)
+		}
+	}
+	if f.Extension == "" {
+		if len(f.Exclude) > 0 || f.ExcludeRegex != "" {
+			return errors.New("filter extension is required to exclude records by content")
+		}
+		return nil
+	}
+	if f.Extension[0] != '.' {
+		return fmt.Errorf("invalid filter extension %q, should be in format: .ext", f.Extension)
+	}
+	if len(f.Exclude) == 0 && f.ExcludeRegex == "" {
+		return errors.New("filter extension requires either exclude or exclude regex")
+	}
+	if _, err := regexp.Compile(f.ExcludeRegex); err != nil {
+		return errors.Wrapf(err, "invalid exclude regex %q", f.ExcludeRegex)
+	}
+	return nil
+}
+
+func (s *SampleSpec) IsEmpty() bool { return s.Percentage == 0 }
+
+func (s *SampleSpec) Validate() error {
+	if s.IsEmpty() {
+		return nil
+	}
+	if s.Percentage < 0 || s.Percentage > 100 {
+		return fmt.Errorf("invalid sample percentage %v, should be in range (0, 100]", s.Percentage)
+	}
+	if s.Seed != "" {
+		if _, err := strconv.ParseInt(s.Seed, 10, 64); err != nil {
+			return fmt.Errorf("invalid sample seed %q, should be int", s.Seed)
+		}
+	}
+	return nil
+}
+
+// NewRecordFilter returns nil if neither filter nor sample is specified. Both
+// specs are expected to be validated.
+func NewRecordFilter(filter FilterSpec, sample SampleSpec) *RecordFilter {
+	if filter.IsEmpty() && sample.IsEmpty() {
+		return nil
+	}
+	f := &RecordFilter{
+		required: filter.RequiredExtensions,
+		ext:      filter.Extension,
+		exclude:  make(cmn.StringSet, len(filter.Exclude)),
+	}
+	for _, value := range filter.Exclude {
+		f.exclude.Add(value)
+	}
+	if filter.ExcludeRegex != "" {
+		f.excludeRe = regexp.MustCompile(filter.ExcludeRegex)
+	}
+	if !sample.IsEmpty() {
+		seed, _ := strconv.ParseInt(sample.Seed, 10, 64)
+		f.sample = true
+		f.seed = uint64(seed)
+		f.threshold = uint64(sample.Percentage / 100 * samplePrecision)
+	}
+	return f
+}
+
+// dropped returns true if the record has been already dropped or is sampled out.
+func (f *RecordFilter) dropped(recordUniqueName string) bool {
+	if _, ok := f.excluded.Load(recordUniqueName); ok {
+		return true
+	}
+	if f.sample && xxhash.ChecksumString64S(recordUniqueName, f.seed)%samplePrecision >= f.threshold {
+		f.excluded.Store(recordUniqueName, struct{}{})
+		return true
+	}
+	return false
+}
+
+func (f *RecordFilter) needContent(ext string) bool {
+	return f.ext != "" && f.ext == ext
+}
+
+// checkContent drops the record if the content of its object is excluded.
+func (f *RecordFilter) checkContent(recordUniqueName string, content *bytes.Buffer) {
+	value := strings.TrimSpace(content.String())
+	if f.exclude.Contains(value) || (f.excludeRe != nil && f.excludeRe.MatchString(value)) {
+		f.excluded.Store(recordUniqueName, struct{}{})
+	}
+}
+
+func (f *RecordFilter) keep(r *Record) bool {
+	if _, ok := f.excluded.Load(r.Name); ok {
+		return false
+	}
+	for _, ext := range f.required {
+		if !r.exists(ext) {
+			return false
+		}
+	}
+	return true
+}
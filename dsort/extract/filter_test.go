@@ -0,0 +1,96 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RecordFilter", func() {
+	newRecordManager := func(filter *RecordFilter) *RecordManager {
+		keyExtractor, err := NewNameKeyExtractor()
+		Expect(err).NotTo(HaveOccurred())
+		t := cluster.NewTargetMock(cluster.NewBaseBownerMock())
+		return NewRecordManager(t, "target", "bucket", cmn.ProviderAIS, cmn.ExtTar, NewTarExtractCreator(t),
+			keyExtractor, filter, func(string) error { return nil })
+	}
+
+	extractRecord := func(rm *RecordManager, recordName, content string) {
+		_, err := rm.ExtractRecordWithBuffer(extractRecordArgs{
+			shardName:     "shard.tar",
+			recordName:    recordName,
+			r:             cmn.NewSizedReader(strings.NewReader(content), int64(len(content))),
+			extractMethod: ExtractToMem,
+			buf:           make([]byte, 32*cmn.KiB),
+		})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should drop records missing extension or with excluded content", func() {
+		filter := FilterSpec{RequiredExtensions: []string{".jpg"}, Extension: ".cls", Exclude: []string{"3"}, ExcludeRegex: "^9"}
+		Expect(filter.Validate()).NotTo(HaveOccurred())
+		rm := newRecordManager(NewRecordFilter(filter, SampleSpec{}))
+		defer rm.Cleanup()
+
+		extractRecord(rm, "a.cls", "1")
+		extractRecord(rm, "a.jpg", "image")
+		extractRecord(rm, "b.cls", "3\n") // excluded after the image has been stored
+		extractRecord(rm, "b.jpg", "image")
+		extractRecord(rm, "c.jpg", "image")
+		extractRecord(rm, "c.cls", "99") // excluded before the image has been stored
+		extractRecord(rm, "d.cls", "2")  // missing required extension
+
+		droppedRecords, droppedObjects := rm.FilterRecords()
+		Expect(droppedRecords).To(BeEquivalentTo(3))
+		Expect(droppedObjects).To(BeEquivalentTo(5))
+		Expect(rm.Records.Len()).To(Equal(1))
+		Expect(rm.Records.All()[0].Name).To(Equal("shard|a"))
+		Expect(rm.Records.objectCount()).To(Equal(2))
+
+		contents := 0
+		rm.RecordContents().Range(func(_, _ interface{}) bool {
+			contents++
+			return true
+		})
+		Expect(contents).To(Equal(2))
+	})
+
+	It("should sample records deterministically", func() {
+		const total = 2000
+		sample := SampleSpec{Percentage: 10, Seed: "1234"}
+		Expect(sample.Validate()).NotTo(HaveOccurred())
+
+		var kept [2][]string
+		for i := range kept {
+			rm := newRecordManager(NewRecordFilter(FilterSpec{}, sample))
+			for j := 0; j < total; j++ {
+				extractRecord(rm, fmt.Sprintf("%05d.txt", j), "")
+			}
+			droppedRecords, droppedObjects := rm.FilterRecords()
+			Expect(droppedRecords).To(Equal(droppedObjects))
+			Expect(droppedRecords + int64(rm.Records.Len())).To(BeEquivalentTo(total))
+			for _, r := range rm.Records.All() {
+				kept[i] = append(kept[i], r.Name)
+			}
+			rm.Cleanup()
+		}
+		Expect(len(kept[0])).To(BeNumerically("~", total/10, total/40))
+		Expect(kept[0]).To(Equal(kept[1]))
+	})
+
+	It("should validate specs", func() {
+		Expect((&FilterSpec{RequiredExtensions: []string{"jpg"}}).Validate()).To(HaveOccurred())
+		Expect((&FilterSpec{Extension: ".cls"}).Validate()).To(HaveOccurred())
+		Expect((&FilterSpec{Extension: ".cls", ExcludeRegex: "("}).Validate()).To(HaveOccurred())
+		Expect((&SampleSpec{Percentage: 101}).Validate()).To(HaveOccurred())
+		Expect((&SampleSpec{Percentage: 50, Seed: "abc"}).Validate()).To(HaveOccurred())
+	})
+})
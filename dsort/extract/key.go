@@ -11,9 +11,13 @@ import (
 	"hash"
 	"io"
 	"io/ioutil"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/NVIDIA/aistore/cmn"
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 )
 
@@ -23,16 +27,38 @@ const (
 	FormatTypeString = "string"
 )
 
+// kinds of the keys which can be extracted from the record (see KeySpec)
+const (
+	KeyKindName    = "name"    // name of the record
+	KeyKindContent = "content" // content of the record object with given extension
+	KeyKindJSON    = "json"    // value under JSONPath in the record object with given extension
+	KeyKindRegex   = "regex"   // capture group of the regex matched against the name of the record
+)
+
 var (
 	supportedFormatTypes = []string{FormatTypeInt, FormatTypeFloat, FormatTypeString}
+	supportedKeyKinds    = []string{KeyKindName, KeyKindContent, KeyKindJSON, KeyKindRegex}
 
 	errInvalidAlgorithmFormatTypes = fmt.Errorf("invalid algorithm format type provided, shoule be one of: %+v", supportedFormatTypes)
+	errInvalidKeyKind              = fmt.Errorf("invalid key kind provided, should be one of: %+v", supportedKeyKinds)
+	errInvalidKeyExtension         = errors.New("invalid key extension provided, should be in format: .ext")
 )
 
 type (
+	// KeySpec describes the key (or a part of the composite key) which is
+	// extracted from each record and determines the sorting order.
+	KeySpec struct {
+		Kind       string `json:"kind" yaml:"kind"`
+		Extension  string `json:"extension" yaml:"extension"`     // Kind: content, json
+		JSONPath   string `json:"json_path" yaml:"json_path"`     // Kind: json, eg. "$.annotations[0].label"
+		Regex      string `json:"regex" yaml:"regex"`             // Kind: regex, eg. "^class-([0-9]+)/"
+		FormatType string `json:"format_type" yaml:"format_type"` // Kind: content, json, regex (default: string)
+	}
+
 	SingleKeyExtractor struct {
-		name string
-		buf  *bytes.Buffer
+		name  string
+		buf   *bytes.Buffer
+		parts []*SingleKeyExtractor // used by composite key extractor
 	}
 
 	KeyExtractor interface {
@@ -51,6 +77,22 @@ type (
 		ty  string // type of key extracted, supported: supportedFormatTypes
 		ext string // extension of object record whose content will be read
 	}
+	jsonKeyExtractor struct {
+		ty   string
+		ext  string        // extension of object record whose content will be decoded
+		path []interface{} // parsed JSONPath: field names (string) and indices (int)
+	}
+	regexKeyExtractor struct {
+		ty string
+		re *regexp.Regexp
+	}
+	// compositeKeyExtractor extracts the key composed of the keys extracted
+	// by each of the extractors: primary, secondary, etc. Each record object
+	// yields the partial key - parts which could not be extracted from the
+	// object are nil and are filled in when the objects are merged (see Record).
+	compositeKeyExtractor struct {
+		extractors []KeyExtractor
+	}
 )
 
 func NewMD5KeyExtractor() (KeyExtractor, error) {
@@ -108,8 +150,11 @@ func (ke *contentKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{},
 		return nil, err
 	}
 
-	key := string(b)
-	switch ke.ty {
+	return parseKey(string(b), ke.ty)
+}
+
+func parseKey(key, ty string) (interface{}, error) {
+	switch ty {
 	case FormatTypeInt:
 		return strconv.ParseInt(key, 10, 64)
 	case FormatTypeFloat:
@@ -117,8 +162,244 @@ func (ke *contentKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{},
 	case FormatTypeString:
 		return key, nil
 	default:
-		return nil, errors.Errorf("not implemented extractor type: %s", ke.ty)
+		return nil, errors.Errorf("not implemented extractor type: %s", ty)
+	}
+}
+
+func newJSONKeyExtractor(ty, ext, path string) (KeyExtractor, error) {
+	parsedPath, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonKeyExtractor{ty: ty, ext: ext, path: parsedPath}, nil
+}
+
+func (ke *jsonKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+	if ke.ext != ext {
+		return r, nil, false
+	}
+
+	buf := &bytes.Buffer{}
+	tee := cmn.NewSizedReader(io.TeeReader(r, buf), r.Size())
+	return tee, &SingleKeyExtractor{name: name, buf: buf}, true
+}
+
+func (ke *jsonKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	if ske == nil { // is not valid to be read
+		return nil, nil
+	}
+
+	var v interface{}
+	err := jsoniter.NewDecoder(ske.buf).Decode(&v)
+	ske.buf = nil
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %q", ske.name)
+	}
+	for _, elem := range ke.path {
+		switch e := elem.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return nil, errors.Errorf("%q: failed to get %q field of %T", ske.name, e, v)
+			}
+			if v, ok = obj[e]; !ok {
+				return nil, errors.Errorf("%q: %q field does not exist", ske.name, e)
+			}
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok || e >= len(arr) {
+				return nil, errors.Errorf("%q: failed to get element %d of %T", ske.name, e, v)
+			}
+			v = arr[e]
+		}
+	}
+
+	switch value := v.(type) {
+	case string:
+		return parseKey(value, ke.ty)
+	case float64:
+		switch ke.ty {
+		case FormatTypeInt:
+			return int64(value), nil
+		case FormatTypeFloat:
+			return value, nil
+		default:
+			return strconv.FormatFloat(value, 'f', -1, 64), nil
+		}
+	case bool:
+		return parseKey(strconv.FormatBool(value), ke.ty)
+	default:
+		return nil, errors.Errorf("%q: value under %q is not a scalar (%T)", ske.name, ke.path, v)
+	}
+}
+
+// parseJSONPath parses the subset of JSONPath which selects a single value:
+// "$.field.nested[0]['other field']" (the leading "$" is optional).
+func parseJSONPath(path string) ([]interface{}, error) {
+	var (
+		parsed []interface{}
+		p      = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	)
+	if p != "" && p[0] != '.' && p[0] != '[' {
+		p = "." + p
+	}
+	for p != "" {
+		switch p[0] {
+		case '.':
+			end := strings.IndexAny(p[1:], ".[") + 1
+			if end == 0 {
+				end = len(p)
+			}
+			if end == 1 {
+				return nil, errors.Errorf("invalid JSONPath %q: empty field name", path)
+			}
+			parsed = append(parsed, p[1:end])
+			p = p[end:]
+		case '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, errors.Errorf("invalid JSONPath %q: missing ']'", path)
+			}
+			elem := p[1:end]
+			if len(elem) >= 2 && (elem[0] == '\'' || elem[0] == '"') && elem[len(elem)-1] == elem[0] {
+				parsed = append(parsed, elem[1:len(elem)-1])
+			} else if idx, err := strconv.Atoi(elem); err == nil && idx >= 0 {
+				parsed = append(parsed, idx)
+			} else {
+				return nil, errors.Errorf("invalid JSONPath %q: invalid subscript %q", path, elem)
+			}
+			p = p[end+1:]
+		default:
+			return nil, errors.Errorf("invalid JSONPath %q", path)
+		}
+	}
+	if len(parsed) == 0 {
+		return nil, errors.Errorf("invalid JSONPath %q: path is empty", path)
+	}
+	return parsed, nil
+}
+
+func newRegexKeyExtractor(ty, expr string) (KeyExtractor, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &regexKeyExtractor{ty: ty, re: re}, nil
+}
+
+func (ke *regexKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+	return r, &SingleKeyExtractor{name: name}, false
+}
+
+// ExtractKey matches the regex against the name of the record (without
+// extension) - the first capture group (or the whole match) is the key.
+func (ke *regexKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	name := strings.TrimSuffix(ske.name, filepath.Ext(ske.name))
+	match := ke.re.FindStringSubmatch(name)
+	if match == nil {
+		return nil, errors.Errorf("record name %q does not match %q", name, ke.re)
+	}
+	key := match[0]
+	if len(match) > 1 {
+		key = match[1]
+	}
+	return parseKey(key, ke.ty)
+}
+
+// NewCompositeKeyExtractor creates the extractor of the key composed of the
+// keys described by specs: records are sorted by the first key, then by the
+// second one, and so on.
+func NewCompositeKeyExtractor(specs []KeySpec) (KeyExtractor, error) {
+	ke := &compositeKeyExtractor{extractors: make([]KeyExtractor, 0, len(specs))}
+	for _, spec := range specs {
+		extractor, err := NewKeyExtractor(spec)
+		if err != nil {
+			return nil, err
+		}
+		ke.extractors = append(ke.extractors, extractor)
+	}
+	return ke, nil
+}
+
+func (ke *compositeKeyExtractor) PrepareExtractor(name string, r cmn.ReadSizer, ext string) (cmn.ReadSizer, *SingleKeyExtractor, bool) {
+	var (
+		needRead bool
+		ske      = &SingleKeyExtractor{name: name, parts: make([]*SingleKeyExtractor, len(ke.extractors))}
+	)
+	for i, extractor := range ke.extractors {
+		var need bool
+		r, ske.parts[i], need = extractor.PrepareExtractor(name, r, ext)
+		needRead = needRead || need
+	}
+	return r, ske, needRead
+}
+
+func (ke *compositeKeyExtractor) ExtractKey(ske *SingleKeyExtractor) (interface{}, error) {
+	var (
+		extracted bool
+		key       = make([]interface{}, len(ke.extractors))
+	)
+	for i, extractor := range ke.extractors {
+		part, err := extractor.ExtractKey(ske.parts[i])
+		if err != nil {
+			return nil, err
+		}
+		key[i] = part
+		extracted = extracted || part != nil
+	}
+	if !extracted {
+		return nil, nil
+	}
+	return key, nil
+}
+
+// NewKeyExtractor creates the extractor of the key described by the spec.
+func NewKeyExtractor(spec KeySpec) (KeyExtractor, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	switch spec.Kind {
+	case KeyKindName:
+		return NewNameKeyExtractor()
+	case KeyKindContent:
+		return NewContentKeyExtractor(spec.FormatType, spec.Extension)
+	case KeyKindJSON:
+		return newJSONKeyExtractor(spec.FormatType, spec.Extension, spec.JSONPath)
+	case KeyKindRegex:
+		return newRegexKeyExtractor(spec.FormatType, spec.Regex)
+	default:
+		return nil, errInvalidKeyKind
+	}
+}
+
+// Validate checks the spec and sets the default values.
+func (spec *KeySpec) Validate() error {
+	if !cmn.StringInSlice(spec.Kind, supportedKeyKinds) {
+		return errInvalidKeyKind
 	}
+	if spec.FormatType == "" || spec.Kind == KeyKindName {
+		spec.FormatType = FormatTypeString
+	}
+	if err := ValidateAlgorithmFormatType(spec.FormatType); err != nil {
+		return err
+	}
+	switch spec.Kind {
+	case KeyKindContent, KeyKindJSON:
+		spec.Extension = strings.TrimSpace(spec.Extension)
+		if spec.Extension == "" || spec.Extension[0] != '.' { // extension should begin with dot: .json
+			return errInvalidKeyExtension
+		}
+		if spec.Kind == KeyKindJSON {
+			if _, err := parseJSONPath(spec.JSONPath); err != nil {
+				return err
+			}
+		}
+	case KeyKindRegex:
+		if _, err := regexp.Compile(spec.Regex); err != nil {
+			return errors.Wrapf(err, "invalid regex %q", spec.Regex)
+		}
+	}
+	return nil
 }
 
 func ValidateAlgorithmFormatType(ty string) error {
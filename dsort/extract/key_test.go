@@ -0,0 +1,124 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"io/ioutil"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cmn"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("KeyExtractor", func() {
+	extractKey := func(ke KeyExtractor, name, ext, content string) (interface{}, error) {
+		r, ske, needRead := ke.PrepareExtractor(name, cmn.NewSizedReader(strings.NewReader(content), int64(len(content))), ext)
+		if needRead {
+			_, err := ioutil.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+		}
+		return ke.ExtractKey(ske)
+	}
+
+	Context("json", func() {
+		const content = `{"label": {"id": 7, "name": "cat"}, "boxes": [{"area": 0.5}, {"area": 1.5}]}`
+
+		DescribeTable("should extract key",
+			func(path, formatType string, expected interface{}) {
+				ke, err := NewKeyExtractor(KeySpec{Kind: KeyKindJSON, Extension: ".json", JSONPath: path, FormatType: formatType})
+				Expect(err).NotTo(HaveOccurred())
+				key, err := extractKey(ke, "sample.json", ".json", content)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(key).To(Equal(expected))
+			},
+			Entry("int", "$.label.id", FormatTypeInt, int64(7)),
+			Entry("string", "label.name", FormatTypeString, "cat"),
+			Entry("number as string", "$.label.id", "", "7"),
+			Entry("array element", "$.boxes[1].area", FormatTypeFloat, 1.5),
+			Entry("quoted field", "$['label']['name']", FormatTypeString, "cat"),
+		)
+
+		It("should skip objects with different extension", func() {
+			ke, err := NewKeyExtractor(KeySpec{Kind: KeyKindJSON, Extension: ".json", JSONPath: "$.label.id"})
+			Expect(err).NotTo(HaveOccurred())
+			key, err := extractKey(ke, "sample.jpg", ".jpg", "not a json")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(BeNil())
+		})
+
+		It("should fail when path does not exist", func() {
+			ke, err := NewKeyExtractor(KeySpec{Kind: KeyKindJSON, Extension: ".json", JSONPath: "$.boxes[2].area"})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = extractKey(ke, "sample.json", ".json", content)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on invalid path", func() {
+			for _, path := range []string{"", "$", "$.label..id", "$.boxes[x]", "$.boxes[0"} {
+				_, err := NewKeyExtractor(KeySpec{Kind: KeyKindJSON, Extension: ".json", JSONPath: path})
+				Expect(err).To(HaveOccurred(), path)
+			}
+		})
+	})
+
+	Context("regex", func() {
+		It("should extract capture group from record name", func() {
+			ke, err := NewKeyExtractor(KeySpec{Kind: KeyKindRegex, Regex: `class-(\d+)/`, FormatType: FormatTypeInt})
+			Expect(err).NotTo(HaveOccurred())
+			key, err := extractKey(ke, "train/class-12/sample.jpg", ".jpg", "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(int64(12)))
+
+			_, err = extractKey(ke, "train/sample.jpg", ".jpg", "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on invalid regex", func() {
+			_, err := NewKeyExtractor(KeySpec{Kind: KeyKindRegex, Regex: `class-(\d+`})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("composite", func() {
+		It("should extract, merge and compare composite keys", func() {
+			ke, err := NewCompositeKeyExtractor([]KeySpec{
+				{Kind: KeyKindJSON, Extension: ".json", JSONPath: "label", FormatType: FormatTypeInt},
+				{Kind: KeyKindRegex, Regex: `-(\d+)$`, FormatType: FormatTypeInt},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			records := NewRecords(3)
+			for _, sample := range []struct{ name, label string }{{"a-10", "2"}, {"b-2", "1"}, {"c-1", "2"}} {
+				for _, ext := range []string{".jpg", ".json"} {
+					key, err := extractKey(ke, sample.name+ext, ext, `{"label": `+sample.label+`}`)
+					Expect(err).NotTo(HaveOccurred())
+					records.Insert(&Record{Key: key, Name: sample.name, Objects: []*RecordObj{{Extension: ext}}})
+				}
+			}
+			Expect(records.Len()).To(Equal(3))
+			Expect(records.All()[0].Key).To(Equal([]interface{}{int64(2), int64(10)}))
+
+			formatTypes := []string{FormatTypeInt, FormatTypeInt}
+			less, err := records.LessComposite(1, 0, formatTypes) // label 1 < label 2
+			Expect(err).NotTo(HaveOccurred())
+			Expect(less).To(BeTrue())
+			less, err = records.LessComposite(2, 0, formatTypes) // same label, 1 < 10
+			Expect(err).NotTo(HaveOccurred())
+			Expect(less).To(BeTrue())
+			less, err = records.LessComposite(0, 0, formatTypes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(less).To(BeFalse())
+		})
+
+		It("should fail when part of the key is missing", func() {
+			records := NewRecords(2)
+			records.Insert(&Record{Key: []interface{}{"a", nil}, Name: "a"}, &Record{Key: []interface{}{"b", "c"}, Name: "b"})
+			_, err := records.LessComposite(0, 1, []string{FormatTypeString, FormatTypeString})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -80,6 +80,7 @@ type (
 
 		extractCreator  ExtractCreator
 		keyExtractor    KeyExtractor
+		filter          *RecordFilter // nil if records are not filtered nor sampled
 		contents        *sync.Map
 		extractionPaths *sync.Map // Keys correspond to all paths to record contents on disk.
 
@@ -91,7 +92,7 @@ type (
 )
 
 func NewRecordManager(t cluster.Target, daemonID, bucket, provider, extension string, extractCreator ExtractCreator,
-	keyExtractor KeyExtractor, onDuplicatedRecords func(string) error) *RecordManager {
+	keyExtractor KeyExtractor, filter *RecordFilter, onDuplicatedRecords func(string) error) *RecordManager {
 	return &RecordManager{
 		Records: NewRecords(1000),
 
@@ -104,6 +105,7 @@ func NewRecordManager(t cluster.Target, daemonID, bucket, provider, extension st
 
 		extractCreator:  extractCreator,
 		keyExtractor:    keyExtractor,
+		filter:          filter,
 		contents:        &sync.Map{},
 		extractionPaths: &sync.Map{},
 	}
@@ -120,6 +122,18 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 		recordUniqueName = rm.genRecordUniqueName(args.shardName, args.recordName)
 	)
 
+	// Objects of the dropped records are not stored at all (but the content
+	// still needs to be written when it is required by the caller).
+	if rm.filter != nil && rm.filter.dropped(recordUniqueName) {
+		if args.extractMethod.Has(ExtractToWriter) {
+			if _, err = io.CopyBuffer(args.w, args.r, args.buf); err != nil {
+				return 0, errors.WithStack(err)
+			}
+		}
+		rm.filter.droppedObjects.Inc()
+		return 0, nil
+	}
+
 	// If the content already exists we should skip it but set error (caller
 	// needs to handle it properly).
 	if rm.Records.Exists(recordUniqueName, ext) {
@@ -138,6 +152,12 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 	}
 
 	r, ske, needRead := rm.keyExtractor.PrepareExtractor(args.recordName, args.r, ext)
+	var filterContent *bytes.Buffer
+	if rm.filter != nil && rm.filter.needContent(ext) {
+		filterContent = &bytes.Buffer{}
+		r = cmn.NewSizedReader(io.TeeReader(r, filterContent), r.Size())
+		needRead = true
+	}
 	if args.extractMethod.Has(ExtractToMem) {
 		mdSize = int64(len(args.metadata))
 		storeType = SGLStoreType
@@ -193,6 +213,10 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 		cmn.AssertMsg(false, fmt.Sprintf("%d %d", args.extractMethod, args.extractMethod&ExtractToDisk))
 	}
 
+	if filterContent != nil {
+		rm.filter.checkContent(recordUniqueName, filterContent)
+	}
+
 	var key interface{}
 	if key, err = rm.keyExtractor.ExtractKey(ske); err != nil {
 		return size, errors.WithStack(err)
@@ -218,6 +242,45 @@ func (rm *RecordManager) ExtractRecordWithBuffer(args extractRecordArgs) (size i
 	return size, nil
 }
 
+// FilterRecords drops the records which have been excluded during extraction
+// or miss any of the required extensions, and frees their contents. Returns
+// the number of dropped records and the number of dropped objects (including
+// the ones which have not been stored).
+func (rm *RecordManager) FilterRecords() (droppedRecords, droppedObjects int64) {
+	if rm.filter == nil {
+		return 0, 0
+	}
+	rm.filter.excluded.Range(func(_, _ interface{}) bool {
+		droppedRecords++
+		return true
+	})
+	dropped := rm.Records.Filter(rm.filter.keep)
+	for _, record := range dropped {
+		if _, ok := rm.filter.excluded.Load(record.Name); !ok {
+			droppedRecords++ // dropped due to missing extension
+		}
+		for _, obj := range record.Objects {
+			switch obj.StoreType {
+			case SGLStoreType:
+				fullContentPath := rm.FullContentPath(obj)
+				if v, ok := rm.contents.Load(fullContentPath); ok {
+					v.(*memsys.SGL).Free()
+					rm.contents.Delete(fullContentPath)
+				}
+			case DiskStoreType:
+				fullContentPath := rm.FullContentPath(obj)
+				if err := os.Remove(fullContentPath); err != nil && !os.IsNotExist(err) {
+					glog.Errorf("failed to remove content of the dropped record %q, err: %v", record.Name, err)
+				}
+				rm.extractionPaths.Delete(fullContentPath)
+			}
+		}
+		droppedObjects += int64(len(record.Objects))
+	}
+	droppedObjects += rm.filter.droppedObjects.Load()
+	return
+}
+
 func (rm *RecordManager) EnqueueRecords(records *Records) {
 	rm.enqueued.mu.Lock()
 	rm.enqueued.records = append(rm.enqueued.records, records)
@@ -359,6 +422,39 @@ func (rm *RecordManager) ChangeStoreType(fullContentPath, newStoreType string, v
 	return
 }
 
+// OffsetRecords returns copies of the records grouped by the shard they have
+// been extracted from. In the copies all the objects are stored by offset in
+// the shard (see ChangeStoreType) so they remain valid after the contents are
+// freed. Requires extractor which supports offsets.
+func (rm *RecordManager) OffsetRecords() map[string][]*Record {
+	cmn.Assert(rm.extractCreator.SupportsOffset())
+
+	rm.Records.RLock()
+	defer rm.Records.RUnlock()
+
+	shards := make(map[string][]*Record)
+	for _, record := range rm.Records.arr {
+		shardName, _ := rm.parseRecordUniqueName(record.Name)
+		r := &Record{
+			Key:      record.Key,
+			Name:     record.Name,
+			DaemonID: record.DaemonID,
+			Objects:  make([]*RecordObj, 0, len(record.Objects)),
+		}
+		for _, obj := range record.Objects {
+			o := *obj
+			if o.StoreType != OffsetStoreType {
+				o.ContentPath = shardName
+				o.MetadataSize = rm.extractCreator.MetadataSize()
+				o.StoreType = OffsetStoreType
+			}
+			r.Objects = append(r.Objects, &o)
+		}
+		shards[shardName] = append(shards[shardName], r)
+	}
+	return shards
+}
+
 func (rm *RecordManager) RecordContents() *sync.Map {
 	return rm.contents
 }
@@ -0,0 +1,85 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/filetype"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Archives can be also unpacked without resharding (eg. by the downloader):
+// ExtractMembers reads the archive with the same extract creators that dSort
+// uses but, instead of storing the records, it passes each member (regular
+// file) of the archive to the callback.
+
+type (
+	// MemberFunc is called for each member of the archive with its name (path
+	// within the archive) and content.
+	MemberFunc func(name string, r cmn.ReadSizer) error
+
+	memberExtractor struct {
+		f MemberFunc
+	}
+)
+
+// interface guard
+var _ RecordExtractor = &memberExtractor{}
+
+var archiveExts = []string{cmn.ExtTarTgz, cmn.ExtTgz, cmn.ExtTar, cmn.ExtZip}
+
+// ArchiveExt returns the extension of the archive (one of: .tar, .tar.gz, .tgz,
+// .zip) or empty string if the name is not the name of supported archive.
+func ArchiveExt(name string) string {
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(name, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+func (e *memberExtractor) ExtractRecordWithBuffer(args extractRecordArgs) (int64, error) {
+	r := args.r
+	if args.extractMethod.Has(ExtractToWriter) {
+		// The content is required by the creator as well (eg. .tar.gz is
+		// decompressed to .tar while being extracted).
+		r = cmn.NewSizedReader(io.TeeReader(args.r, args.w), args.r.Size())
+	}
+	if err := e.f(args.recordName, r); err != nil {
+		return 0, err
+	}
+	// Make sure that the whole member has been read, even if the callback did not.
+	if _, err := io.CopyBuffer(ioutil.Discard, r, args.buf); err != nil {
+		return 0, err
+	}
+	return r.Size(), nil
+}
+
+// ExtractMembers reads the archive (type of which is determined by the
+// extension of the object name) and calls f for each of its members. Returns
+// the total size and the number of the members.
+func ExtractMembers(t cluster.Target, lom *cluster.LOM, r *io.SectionReader, f MemberFunc) (size int64, cnt int, err error) {
+	var ec ExtractCreator
+	switch ArchiveExt(lom.ObjName) {
+	case cmn.ExtTar:
+		ec = NewTarExtractCreator(t)
+	case cmn.ExtTarTgz, cmn.ExtTgz:
+		ec = NewTargzExtractCreator(t)
+		// .tar.gz is decompressed next to the archive, see: targzExtractCreator.ExtractShard
+		defer cmn.RemoveFile(fs.CSM.GenContentParsedFQN(lom.ParsedFQN, filetype.DSortFileType, ""))
+	case cmn.ExtZip:
+		ec = NewZipExtractCreator(t)
+	default:
+		return 0, 0, fmt.Errorf("%q is not an archive, expected one of: %v", lom.ObjName, archiveExts)
+	}
+	return ec.ExtractShard(lom, r, &memberExtractor{f: f}, false /*toDisk*/)
+}
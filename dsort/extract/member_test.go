@@ -0,0 +1,121 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExtractMembers", func() {
+	members := map[string]string{
+		"a.txt":         "first member",
+		"dir/b.jpg":     "second member",
+		"dir/sub/c.cls": "",
+	}
+
+	createTar := func() []byte {
+		var (
+			buf bytes.Buffer
+			tw  = tar.NewWriter(&buf)
+		)
+		Expect(tw.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755})).To(Succeed())
+		for name, content := range members {
+			err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(content))})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = tw.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(tw.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	createZip := func() []byte {
+		var (
+			buf bytes.Buffer
+			zw  = zip.NewWriter(&buf)
+		)
+		for name, content := range members {
+			w, err := zw.Create(name)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = w.Write([]byte(content))
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(zw.Close()).To(Succeed())
+		return buf.Bytes()
+	}
+
+	extractMembers := func(objName string, archive []byte) (map[string]string, error) {
+		var (
+			t         = cluster.NewTargetMock(cluster.NewBaseBownerMock())
+			lom       = &cluster.LOM{T: t, ObjName: objName, ParsedFQN: fs.ParsedFQN{ObjName: objName}}
+			extracted = make(map[string]string)
+		)
+		size, cnt, err := ExtractMembers(t, lom, io.NewSectionReader(bytes.NewReader(archive), 0, int64(len(archive))),
+			func(name string, r cmn.ReadSizer) error {
+				b, err := ioutil.ReadAll(r)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(b).To(HaveLen(int(r.Size())))
+				extracted[name] = string(b)
+				return nil
+			},
+		)
+		if err == nil {
+			Expect(cnt).To(Equal(len(extracted)))
+			var total int64
+			for _, content := range extracted {
+				total += int64(len(content))
+			}
+			Expect(size).To(Equal(total))
+		}
+		return extracted, err
+	}
+
+	It("should pass each member of tar archive", func() {
+		extracted, err := extractMembers("dir/shard.tar", createTar())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal(members))
+	})
+
+	It("should pass each member of zip archive", func() {
+		extracted, err := extractMembers("shard.zip", createZip())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(extracted).To(Equal(members))
+	})
+
+	It("should return error of the callback", func() {
+		var (
+			t   = cluster.NewTargetMock(cluster.NewBaseBownerMock())
+			lom = &cluster.LOM{T: t, ObjName: "shard.tar", ParsedFQN: fs.ParsedFQN{ObjName: "shard.tar"}}
+			tb  = createTar()
+		)
+		_, _, err := ExtractMembers(t, lom, io.NewSectionReader(bytes.NewReader(tb), 0, int64(len(tb))),
+			func(string, cmn.ReadSizer) error { return io.ErrUnexpectedEOF },
+		)
+		Expect(err).To(Equal(io.ErrUnexpectedEOF))
+	})
+
+	It("should fail for object which is not an archive", func() {
+		_, err := extractMembers("shard.txt", createTar())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should recognize archive extensions", func() {
+		Expect(ArchiveExt("a/b.tar")).To(Equal(cmn.ExtTar))
+		Expect(ArchiveExt("a/b.tar.gz")).To(Equal(cmn.ExtTarTgz))
+		Expect(ArchiveExt("b.tgz")).To(Equal(cmn.ExtTgz))
+		Expect(ArchiveExt("b.zip")).To(Equal(cmn.ExtZip))
+		Expect(ArchiveExt("b.tar.bz2")).To(BeEmpty())
+	})
+})
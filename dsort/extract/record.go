@@ -80,6 +80,15 @@ func (r *Record) mergeObjects(other *Record) {
 	cmn.Assert(r.Name == other.Name)
 	if r.Key == nil && other.Key != nil {
 		r.Key = other.Key
+	} else if lhs, ok := r.Key.([]interface{}); ok {
+		// Composite key: fill in the parts extracted from other objects.
+		if rhs, ok := other.Key.([]interface{}); ok {
+			for i := 0; i < len(lhs) && i < len(rhs); i++ {
+				if lhs[i] == nil {
+					lhs[i] = rhs[i]
+				}
+			}
+		}
 	}
 	r.Objects = append(r.Objects, other.Objects...)
 }
@@ -182,6 +191,24 @@ func (r *Records) Exists(name, ext string) (exists bool) {
 	return
 }
 
+// Filter removes the records for which keep returns false and returns them.
+func (r *Records) Filter(keep func(*Record) bool) (removed []*Record) {
+	r.Lock()
+	kept := r.arr[:0]
+	for _, record := range r.arr {
+		if keep(record) {
+			kept = append(kept, record)
+			continue
+		}
+		removed = append(removed, record)
+		delete(r.m, record.Name)
+		r.totalObjectCount -= len(record.Objects)
+	}
+	r.arr = kept
+	r.Unlock()
+	return
+}
+
 func (r *Records) merge(records *Records) {
 	r.Insert(records.arr...)
 }
@@ -209,13 +236,39 @@ func (r *Records) Less(i, j int, formatType string) (bool, error) {
 	} else if rhs == nil {
 		return false, errors.Errorf("key is missing for %q", r.arr[j].Name)
 	}
+	return lessKey(lhs, rhs, formatType), nil
+}
 
+// LessComposite compares composite keys (see compositeKeyExtractor) part by
+// part, each part is interpreted with corresponding format type.
+func (r *Records) LessComposite(i, j int, formatTypes []string) (bool, error) {
+	lhs, _ := r.arr[i].Key.([]interface{})
+	rhs, _ := r.arr[j].Key.([]interface{})
+	for k := range formatTypes {
+		if k >= len(lhs) || lhs[k] == nil {
+			return false, errors.Errorf("key (part %d) is missing for %q", k, r.arr[i].Name)
+		} else if k >= len(rhs) || rhs[k] == nil {
+			return false, errors.Errorf("key (part %d) is missing for %q", k, r.arr[j].Name)
+		}
+	}
+	for k, formatType := range formatTypes {
+		if lessKey(lhs[k], rhs[k], formatType) {
+			return true, nil
+		}
+		if lessKey(rhs[k], lhs[k], formatType) {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+func lessKey(lhs, rhs interface{}, formatType string) bool {
 	switch formatType {
 	case FormatTypeInt:
 		ilhs, lok := lhs.(int64)
 		irhs, rok := rhs.(int64)
 		if lok && rok {
-			return ilhs < irhs, nil
+			return ilhs < irhs
 		}
 
 		// One side was parsed as float64 - javascript does not support
@@ -227,15 +280,15 @@ func (r *Records) Less(i, j int, formatType string) (bool, error) {
 			irhs = int64(rhs.(float64))
 		}
 
-		return ilhs < irhs, nil
+		return ilhs < irhs
 	case FormatTypeFloat:
-		return lhs.(float64) < rhs.(float64), nil
+		return lhs.(float64) < rhs.(float64)
 	case FormatTypeString:
-		return lhs.(string) < rhs.(string), nil
+		return lhs.(string) < rhs.(string)
 	}
 
-	cmn.AssertFmt(false, lhs, rhs, r.arr[i], r.arr[j])
-	return false, nil
+	cmn.AssertFmt(false, lhs, rhs)
+	return false
 }
 
 func (r *Records) objectCount() int {
@@ -4,6 +4,8 @@
  */
 package extract
 
+import "github.com/NVIDIA/aistore/cmn"
+
 // Shard represents the metadata required to construct a single shard (aka an archive file).
 type Shard struct {
 	// Size is total size of shard to be created.
@@ -12,4 +14,6 @@ type Shard struct {
 	Records *Records `json:"r"`
 	// Name determines the output name of the shard.
 	Name string `json:"n"`
+	// Bck determines the bucket where the shard is created.
+	Bck cmn.Bck `json:"b"`
 }
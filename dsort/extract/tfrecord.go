@@ -0,0 +1,237 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/go-tfdata/tfdata/core"
+)
+
+// TFRecord output: each record (sample) of the input shards is converted into
+// a single tf.Example. Similarly to tar2tf, the record objects are keyed by
+// their extensions: by default every object becomes a bytes feature named
+// after its extension (without the leading dot), and the name of the record
+// is stored in the `__key__` feature (WebDataset convention).
+
+const (
+	TFFeatureBytes = "bytes"
+	TFFeatureInt64 = "int64"
+	TFFeatureFloat = "float"
+
+	// TFKeyFeature is the name of the feature holding the record name.
+	TFKeyFeature = "__key__"
+	// IndexExtension is appended to the name of the shard to name its index.
+	IndexExtension = ".idx"
+)
+
+var (
+	// interface guard
+	_ ExtractCreator = &tfrecordExtractCreator{}
+	_ IndexCreator   = &tfrecordExtractCreator{}
+)
+
+type (
+	// TFFeature maps the record objects with the given extensions to
+	// the tf.Example feature. The first object matching any of the extensions
+	// is used. Int64 and float features are parsed from the object's content
+	// (whitespace separated list of numbers).
+	TFFeature struct {
+		Name       string   `json:"name" yaml:"name"`
+		Extensions []string `json:"extensions" yaml:"extensions"`
+		Type       string   `json:"type" yaml:"type"` // Default: TFFeatureBytes
+	}
+
+	// IndexCreator is implemented by the creators which are able to create
+	// an index of the output shard along with the shard itself.
+	IndexCreator interface {
+		CreateShardWithIndex(s *Shard, w, index io.Writer, loadContent LoadContentFunc) (int64, error)
+	}
+
+	// tfrecordExtractCreator extracts the shards with the internal (input
+	// format) creator and creates TFRecord shards.
+	tfrecordExtractCreator struct {
+		internal ExtractCreator
+		features []TFFeature
+	}
+
+	// skipWriter discards first `skip` bytes (metadata of the record object)
+	// and writes the rest to the buffer.
+	skipWriter struct {
+		buf  *bytes.Buffer
+		skip int64
+	}
+)
+
+func ValidateTFFeatures(features []TFFeature) error {
+	names := make(map[string]struct{}, len(features))
+	for _, f := range features {
+		if f.Name == "" || f.Name == TFKeyFeature {
+			return fmt.Errorf("invalid TFRecord feature name: %q", f.Name)
+		}
+		if _, ok := names[f.Name]; ok {
+			return fmt.Errorf("duplicated TFRecord feature: %q", f.Name)
+		}
+		names[f.Name] = struct{}{}
+		if len(f.Extensions) == 0 {
+			return fmt.Errorf("TFRecord feature %q does not map any extension", f.Name)
+		}
+		switch f.Type {
+		case "", TFFeatureBytes, TFFeatureInt64, TFFeatureFloat:
+		default:
+			return fmt.Errorf("invalid type of TFRecord feature %q: %q (expected one of: %s, %s, %s)",
+				f.Name, f.Type, TFFeatureBytes, TFFeatureInt64, TFFeatureFloat)
+		}
+	}
+	return nil
+}
+
+// NewTFRecordExtractCreator returns creator which extracts the shards using
+// the internal creator and creates TFRecord shards out of them. When features
+// are empty, the default mapping is used (see above).
+func NewTFRecordExtractCreator(internal ExtractCreator, features []TFFeature) ExtractCreator {
+	return &tfrecordExtractCreator{internal: internal, features: features}
+}
+
+func (w *skipWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if w.skip > 0 {
+		if int64(len(p)) <= w.skip {
+			w.skip -= int64(len(p))
+			return n, nil
+		}
+		p = p[w.skip:]
+		w.skip = 0
+	}
+	w.buf.Write(p)
+	return n, nil
+}
+
+func (t *tfrecordExtractCreator) ExtractShard(lom *cluster.LOM, r *io.SectionReader, extractor RecordExtractor,
+	toDisk bool) (int64, int, error) {
+	return t.internal.ExtractShard(lom, r, extractor, toDisk)
+}
+
+// CreateShard creates a new TFRecord shard locally based on the Shard.
+func (t *tfrecordExtractCreator) CreateShard(s *Shard, w io.Writer, loadContent LoadContentFunc) (int64, error) {
+	return t.CreateShardWithIndex(s, w, nil, loadContent)
+}
+
+// CreateShardWithIndex creates a new TFRecord shard and, if `index` is not nil,
+// writes its index: "<offset> <size>" line per each tf.Example (the format
+// used by DALI's TFRecord reader).
+func (t *tfrecordExtractCreator) CreateShardWithIndex(s *Shard, w, index io.Writer,
+	loadContent LoadContentFunc) (written int64, err error) {
+	tw := core.NewTFRecordWriter(w)
+	for _, rec := range s.Records.All() {
+		example, err := t.toExample(rec, loadContent)
+		if err != nil {
+			return written, err
+		}
+		n, err := tw.WriteExample(example)
+		if err != nil {
+			return written, err
+		}
+		if index != nil {
+			if _, err := fmt.Fprintf(index, "%d %d\n", written, n); err != nil {
+				return written, err
+			}
+		}
+		written += int64(n)
+	}
+	return written, nil
+}
+
+func (t *tfrecordExtractCreator) toExample(rec *Record, loadContent LoadContentFunc) (*core.TFExample, error) {
+	contents := make(map[string][]byte, len(rec.Objects))
+	for _, obj := range rec.Objects {
+		buf := bytes.NewBuffer(make([]byte, 0, obj.Size))
+		if _, err := loadContent(&skipWriter{buf: buf, skip: obj.MetadataSize}, rec, obj); err != nil {
+			return nil, err
+		}
+		contents[obj.Extension] = buf.Bytes()
+	}
+
+	example := core.NewTFExample()
+	example.AddBytes(TFKeyFeature, []byte(rec.Name))
+	if len(t.features) == 0 {
+		for _, obj := range rec.Objects {
+			example.AddBytes(strings.TrimPrefix(obj.Extension, "."), contents[obj.Extension])
+		}
+		return example, nil
+	}
+
+	for _, f := range t.features {
+		var (
+			content []byte
+			found   bool
+		)
+		for _, ext := range f.Extensions {
+			if !strings.HasPrefix(ext, ".") {
+				ext = "." + ext
+			}
+			if content, found = contents[ext]; found {
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("record %q does not contain any of the extensions %v required by %q feature",
+				rec.Name, f.Extensions, f.Name)
+		}
+		if err := addFeature(example, f, content); err != nil {
+			return nil, fmt.Errorf("record %q: %v", rec.Name, err)
+		}
+	}
+	return example, nil
+}
+
+func addFeature(example *core.TFExample, f TFFeature, content []byte) error {
+	switch f.Type {
+	case TFFeatureInt64:
+		fields := strings.Fields(string(content))
+		ints := make([]int64, 0, len(fields))
+		for _, field := range fields {
+			v, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q feature: %v", f.Name, err)
+			}
+			ints = append(ints, v)
+		}
+		example.AddInt64List(f.Name, ints)
+	case TFFeatureFloat:
+		fields := strings.Fields(string(content))
+		floats := make([]float32, 0, len(fields))
+		for _, field := range fields {
+			v, err := strconv.ParseFloat(field, 32)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q feature: %v", f.Name, err)
+			}
+			floats = append(floats, float32(v))
+		}
+		example.AddFloatList(f.Name, floats)
+	default:
+		example.AddBytes(f.Name, content)
+	}
+	return nil
+}
+
+func (t *tfrecordExtractCreator) UsingCompression() bool {
+	return t.internal.UsingCompression()
+}
+
+func (t *tfrecordExtractCreator) SupportsOffset() bool {
+	return t.internal.SupportsOffset()
+}
+
+// MetadataSize returns the size of the internal creator's metadata - the
+// metadata is extracted (and loaded) along with the record objects.
+func (t *tfrecordExtractCreator) MetadataSize() int64 {
+	return t.internal.MetadataSize()
+}
@@ -0,0 +1,124 @@
+// Package extract provides provides functions for working with compressed files
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package extract
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/NVIDIA/go-tfdata/tfdata/core"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TFRecord", func() {
+	const metadataSize = 16
+
+	var (
+		contents = map[string]string{
+			"sample1.jpg": "first image", "sample1.cls": "3",
+			"sample2.jpg": "second image", "sample2.cls": "5 7",
+		}
+		loadContent = func(w io.Writer, rec *Record, obj *RecordObj) (int64, error) {
+			n, err := w.Write(append(make([]byte, obj.MetadataSize), contents[rec.Name+obj.Extension]...))
+			return int64(n), err
+		}
+	)
+
+	newShard := func() *Shard {
+		records := NewRecords(2)
+		for _, name := range []string{"sample1", "sample2"} {
+			rec := &Record{Key: name, Name: name}
+			for _, ext := range []string{".jpg", ".cls"} {
+				rec.Objects = append(rec.Objects, &RecordObj{
+					StoreType:    SGLStoreType,
+					MetadataSize: metadataSize,
+					Size:         int64(len(contents[name+ext])),
+					Extension:    ext,
+				})
+			}
+			records.Insert(rec)
+		}
+		return &Shard{Name: "shard.tfrecord", Records: records}
+	}
+
+	readExamples := func(b []byte) []*core.TFExample {
+		examples, err := core.NewTFRecordReader(bytes.NewReader(b)).ReadAllExamples()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(examples).To(HaveLen(2))
+		return examples
+	}
+
+	It("should create shard with default features and index", func() {
+		var (
+			w, index = &bytes.Buffer{}, &bytes.Buffer{}
+			ec       = NewTFRecordExtractCreator(NewTarExtractCreator(nil), nil)
+		)
+		written, err := ec.(IndexCreator).CreateShardWithIndex(newShard(), w, index, loadContent)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(written).To(BeEquivalentTo(w.Len()))
+
+		examples := readExamples(w.Bytes())
+		Expect(string(examples[0].GetBytesList(TFKeyFeature))).To(Equal("sample1"))
+		Expect(string(examples[0].GetBytesList("jpg"))).To(Equal("first image"))
+		Expect(string(examples[1].GetBytesList("cls"))).To(Equal("5 7"))
+
+		// Every index entry must point to exactly one example.
+		var (
+			offset  int64
+			scanner = bufio.NewScanner(index)
+		)
+		for scanner.Scan() {
+			parts := strings.Fields(scanner.Text())
+			Expect(parts).To(HaveLen(2))
+			Expect(parts[0]).To(Equal(strconv.FormatInt(offset, 10)))
+			size, err := strconv.ParseInt(parts[1], 10, 64)
+			Expect(err).NotTo(HaveOccurred())
+			examples, err := core.NewTFRecordReader(bytes.NewReader(w.Bytes()[offset : offset+size])).ReadAllExamples()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(examples).To(HaveLen(1))
+			offset += size
+		}
+		Expect(offset).To(Equal(written))
+	})
+
+	It("should create shard with mapped features", func() {
+		features := []TFFeature{
+			{Name: "image", Extensions: []string{"png", "jpg"}},
+			{Name: "label", Extensions: []string{".cls"}, Type: TFFeatureInt64},
+		}
+		Expect(ValidateTFFeatures(features)).NotTo(HaveOccurred())
+
+		w := &bytes.Buffer{}
+		_, err := NewTFRecordExtractCreator(NewTarExtractCreator(nil), features).CreateShard(newShard(), w, loadContent)
+		Expect(err).NotTo(HaveOccurred())
+
+		examples := readExamples(w.Bytes())
+		Expect(string(examples[1].GetBytesList("image"))).To(Equal("second image"))
+		Expect(examples[0].GetInt64List("label")).To(Equal([]int64{3}))
+		Expect(examples[1].GetInt64List("label")).To(Equal([]int64{5, 7}))
+		Expect(examples[0].HasFeature("jpg")).To(BeFalse())
+	})
+
+	It("should fail when record misses mapped feature", func() {
+		features := []TFFeature{{Name: "label", Extensions: []string{"txt"}}}
+		_, err := NewTFRecordExtractCreator(NewTarExtractCreator(nil), features).CreateShard(newShard(), ioutil.Discard, loadContent)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should validate features", func() {
+		Expect(ValidateTFFeatures([]TFFeature{{Name: TFKeyFeature, Extensions: []string{"cls"}}})).To(HaveOccurred())
+		Expect(ValidateTFFeatures([]TFFeature{{Name: "label"}})).To(HaveOccurred())
+		Expect(ValidateTFFeatures([]TFFeature{{Name: "label", Extensions: []string{"cls"}, Type: "string"}})).To(HaveOccurred())
+		Expect(ValidateTFFeatures([]TFFeature{
+			{Name: "label", Extensions: []string{"cls"}},
+			{Name: "label", Extensions: []string{"txt"}},
+		})).To(HaveOccurred())
+	})
+})
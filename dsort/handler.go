@@ -45,7 +45,11 @@ func ProxySortHandler(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodPost:
-		proxyStartSortHandler(w, r)
+		if len(apiItems) == 1 && apiItems[0] == cmn.Resume {
+			proxyResumeSortHandler(w, r)
+		} else {
+			proxyStartSortHandler(w, r)
+		}
 	case http.MethodGet:
 		proxyGetHandler(w, r)
 	case http.MethodDelete:
@@ -76,6 +80,14 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	parsedRS.TargetOrderSalt = []byte(time.Now().Format("15:04:05.000000"))
+	if parsedRS.Algorithm.Kind == SortKindShuffle && parsedRS.Algorithm.Seed == "" {
+		// resumed job must shuffle the records the same way
+		parsedRS.Algorithm.Seed = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	if !parsedRS.Sample.IsEmpty() && parsedRS.Sample.Seed == "" {
+		// all targets (and resumed job) must sample the records the same way
+		parsedRS.Sample.Seed = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
 
 	// TODO: handle case when bucket was removed during dSort job - this should
 	// stop whole operation. Maybe some listeners as we have on smap change?
@@ -96,14 +108,16 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	bck = cluster.NewBck(parsedRS.OutputBucket, parsedRS.OutputProvider, cmn.NsGlobal)
-	if err = bck.Init(ctx.bmdOwner, nil); err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error())
-		return
-	}
-	if err = bck.Allow(cmn.AccessPUT); err != nil {
-		cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusForbidden)
-		return
+	for _, outputBck := range parsedRS.outputBcks() {
+		bck = cluster.NewBckEmbed(outputBck)
+		if err = bck.Init(ctx.bmdOwner, nil); err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error())
+			return
+		}
+		if err = bck.Allow(cmn.AccessPUT); err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusForbidden)
+			return
+		}
 	}
 
 	parsedRS.DSorterType, err = determineDSorterType(parsedRS)
@@ -120,6 +134,51 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	managerUUID := cmn.GenUserID()
+	if !startOnTargets(w, r, managerUUID, cmn.Init, b) {
+		return
+	}
+	w.Write([]byte(managerUUID))
+}
+
+// POST /v1/sort/resume?id=...
+func proxyResumeSortHandler(w http.ResponseWriter, r *http.Request) {
+	managerUUID := r.URL.Query().Get(cmn.URLParamID)
+	path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.Checkpoint, managerUUID)
+	responses := broadcast(http.MethodGet, path, nil, nil, ctx.smapOwner.Get().Tmap)
+
+	// Targets which have joined since the job was aborted (and those which
+	// have finished) do not have the checkpoint.
+	msg := resumeMsg{CreatedShards: make([]CreatedShard, 0)}
+	for _, resp := range responses {
+		if resp.statusCode == http.StatusNotFound {
+			continue
+		}
+		if resp.err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, resp.err.Error(), resp.statusCode)
+			return
+		}
+		ckpt := &Checkpoint{}
+		if err := js.Unmarshal(resp.res, ckpt); err != nil {
+			cmn.InvalidHandlerWithMsg(w, r, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		msg.Spec = ckpt.Spec
+		msg.CreatedShards = append(msg.CreatedShards, ckpt.CreatedShards...)
+	}
+	if msg.Spec == nil {
+		msg := fmt.Sprintf("%s job %q cannot be resumed: checkpoint not found", cmn.DSortName, managerUUID)
+		cmn.InvalidHandlerWithMsg(w, r, msg, http.StatusNotFound)
+		return
+	}
+
+	glog.Infof("[%s] resuming, %d output shards have been already created", managerUUID, len(msg.CreatedShards))
+	startOnTargets(w, r, managerUUID, cmn.Resume, cmn.MustMarshal(msg))
+}
+
+// startOnTargets initializes (or resumes) the job on all targets and then
+// starts it. Returns false if it failed - in which case the job is aborted and
+// the error is written to the response.
+func startOnTargets(w http.ResponseWriter, r *http.Request, managerUUID, initAction string, body []byte) bool {
 	checkResponses := func(responses []response) error {
 		for _, resp := range responses {
 			if resp.err == nil {
@@ -150,21 +209,17 @@ func proxyStartSortHandler(w http.ResponseWriter, r *http.Request) {
 	// given dSort job. Also bug where we could send abort (which triggers cleanup)
 	// to not yet initialized target.
 
-	glog.V(4).Infof("[%s] broadcasting init request to all targets", managerUUID)
-	path := cmn.URLPath(cmn.Version, cmn.Sort, cmn.Init, managerUUID)
-	responses := broadcast(http.MethodPost, path, nil, b, ctx.smapOwner.Get().Tmap)
+	glog.V(4).Infof("[%s] broadcasting %s request to all targets", managerUUID, initAction)
+	path := cmn.URLPath(cmn.Version, cmn.Sort, initAction, managerUUID)
+	responses := broadcast(http.MethodPost, path, nil, body, ctx.smapOwner.Get().Tmap)
 	if err := checkResponses(responses); err != nil {
-		return
+		return false
 	}
 
 	glog.V(4).Infof("[%s] broadcasting start request to all targets", managerUUID)
 	path = cmn.URLPath(cmn.Version, cmn.Sort, cmn.Start, managerUUID)
 	responses = broadcast(http.MethodPost, path, nil, nil, ctx.smapOwner.Get().Tmap)
-	if err := checkResponses(responses); err != nil {
-		return
-	}
-
-	w.Write([]byte(managerUUID))
+	return checkResponses(responses) == nil
 }
 
 // GET /v1/sort
@@ -380,6 +435,10 @@ func SortHandler(w http.ResponseWriter, r *http.Request) {
 	switch apiItems[0] {
 	case cmn.Init:
 		initSortHandler(w, r)
+	case cmn.Resume:
+		resumeSortHandler(w, r)
+	case cmn.Checkpoint:
+		checkpointHandler(w, r)
 	case cmn.Start:
 		startSortHandler(w, r)
 	case cmn.Records:
@@ -434,6 +493,88 @@ func initSortHandler(w http.ResponseWriter, r *http.Request) {
 		cmn.InvalidHandlerWithMsg(w, r, err.Error())
 		return
 	}
+	dsortManager.initCheckpoint(nil)
+}
+
+// resumeSortHandler is the handler called for the HTTP endpoint /v1/sort/resume.
+// Similarly to initSortHandler it initializes the dSort manager, but for the
+// job (with the same UUID) that has been aborted - see Checkpoint.
+func resumeSortHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkHTTPMethod(w, r, http.MethodPost) {
+		return
+	}
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, cmn.Sort, cmn.Resume)
+	if err != nil {
+		return
+	}
+	var msg resumeMsg
+	if err := js.NewDecoder(r.Body).Decode(&msg); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, fmt.Sprintf("could not unmarshal request body, err: %v", err))
+		return
+	}
+
+	// NOTE: removing the aborted job removes its persisted state as well.
+	managerUUID := apiItems[0]
+	extracted := Managers.extractedShards(managerUUID)
+
+	// The aborted job might be still cleaning up (in which case it cannot be
+	// removed yet).
+	deadline := time.Now().Add(cmn.GCO.Get().DSort.CallTimeout)
+	for {
+		if err = Managers.Remove(managerUUID); err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error())
+		return
+	}
+
+	dsortManager, err := Managers.Add(managerUUID)
+	if err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error())
+		return
+	}
+	defer dsortManager.unlock()
+	if err = dsortManager.init(msg.Spec); err != nil {
+		cmn.InvalidHandlerWithMsg(w, r, err.Error())
+		return
+	}
+	dsortManager.resumed = make(map[string]uint64, len(msg.CreatedShards))
+	for _, created := range msg.CreatedShards {
+		dsortManager.resumed[created.uname()] = created.Digest
+	}
+	dsortManager.extracted.reusable = extracted
+	dsortManager.initCheckpoint(msg.CreatedShards)
+}
+
+// checkpointHandler is the handler called for the HTTP endpoint /v1/sort/checkpoint.
+// A valid GET to this endpoint sends response with the persisted checkpoint.
+func checkpointHandler(w http.ResponseWriter, r *http.Request) {
+	if !checkHTTPMethod(w, r, http.MethodGet) {
+		return
+	}
+	apiItems, err := checkRESTItems(w, r, 1, cmn.Version, cmn.Sort, cmn.Checkpoint)
+	if err != nil {
+		return
+	}
+
+	managerUUID := apiItems[0]
+	if dsortManager, exists := Managers.Get(managerUUID); exists && !dsortManager.aborted() {
+		s := fmt.Sprintf("%s process %s still in progress and cannot be resumed", cmn.DSortName, managerUUID)
+		cmn.InvalidHandlerWithMsg(w, r, s)
+		return
+	}
+	ckpt, exists := Managers.Checkpoint(managerUUID)
+	if !exists {
+		s := fmt.Sprintf("invalid request: checkpoint of %s job %s does not exist", cmn.DSortName, managerUUID)
+		cmn.InvalidHandlerWithMsg(w, r, s, http.StatusNotFound)
+		return
+	}
+	if _, err := w.Write(cmn.MustMarshal(ckpt)); err != nil {
+		glog.Error(err)
+	}
 }
 
 // startSortHandler is the handler called for the HTTP endpoint /v1/sort/start.
@@ -536,6 +677,7 @@ func shardsHandler(managers *ManagerGroup) http.HandlerFunc {
 		}
 
 		dsortManager.creationPhase.metadata = tmpMetadata
+		dsortManager.decrementRef(tmpMetadata.SkippedObjects)
 		dsortManager.startShardCreation <- struct{}{}
 	}
 }
@@ -10,6 +10,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"sync"
 	"time"
@@ -64,11 +65,15 @@ type (
 
 	creationPhaseMetadata struct {
 		Shards    []*extract.Shard          `json:"shards"`
-		SendOrder map[string]*extract.Shard `json:"send_order"`
+		SendOrder map[string]*extract.Shard `json:"send_order"` // shard uname => shard
+		// number of local records' objects that belong to output shards
+		// created before the job was resumed, or do not belong to any output
+		// shard at all (and are not going to be sent)
+		SkippedObjects int64 `json:"skipped_objects,omitempty"`
 	}
 
 	buildingShardInfo struct {
-		shardName string
+		shardUname string // see shardUname
 	}
 
 	// progressState abstracts all information meta information about progress of
@@ -131,6 +136,13 @@ type (
 			mu sync.Mutex
 			m  map[string]struct{} // finished acks: daemonID -> ack
 		}
+		checkpoint checkpointer
+		resumed    map[string]uint64 // output shards created before the job was resumed: shard uname -> digest
+		joined     atomic.Bool       // new target(s) joined the cluster during the run
+		extracted  struct {
+			sync.Map                            // input shards extracted by this run: shard name -> *extractedShard
+			reusable map[string]*extractedShard // input shards extracted before the job was resumed
+		}
 
 		dsorter dsorter
 
@@ -370,6 +382,11 @@ func (m *Manager) finalCleanup() {
 
 	m.finishedAck.m = nil
 
+	// All targets have finished - the job will not be resumed
+	if !m.aborted() {
+		Managers.removeCheckpoint(m.ManagerUUID)
+	}
+
 	// Update clean state
 	m.state.cleaned = finallyCleanedState
 	m.state.cleanWait.Signal() // if there is another `finalCleanup` waiting it should be woken up to check the state and exit
@@ -436,6 +453,10 @@ func (m *Manager) setExtractCreator() (err error) {
 		keyExtractor, err = extract.NewContentKeyExtractor(m.rs.Algorithm.FormatType, m.rs.Algorithm.Extension)
 	case SortKindMD5:
 		keyExtractor, err = extract.NewMD5KeyExtractor()
+	case SortKindJSON, SortKindRegex:
+		keyExtractor, err = extract.NewKeyExtractor(m.rs.Algorithm.keySpec())
+	case SortKindComposite:
+		keyExtractor, err = extract.NewCompositeKeyExtractor(m.rs.Algorithm.Keys)
 	default:
 		keyExtractor, err = extract.NewNameKeyExtractor()
 	}
@@ -459,6 +480,9 @@ func (m *Manager) setExtractCreator() (err error) {
 	default:
 		cmn.AssertMsg(false, fmt.Sprintf("unknown extension %s", m.rs.Extension))
 	}
+	if m.rs.OutputExtension == cmn.ExtTFRecord {
+		extractCreator = extract.NewTFRecordExtractCreator(extractCreator, m.rs.TFFeatures)
+	}
 
 	if !m.rs.DryRun {
 		m.extractCreator = extractCreator
@@ -467,7 +491,7 @@ func (m *Manager) setExtractCreator() (err error) {
 	}
 
 	m.recManager = extract.NewRecordManager(m.ctx.t, m.ctx.node.DaemonID, m.rs.Bucket, m.rs.Provider,
-		m.rs.Extension, m.extractCreator, keyExtractor, onDuplicatedRecords)
+		m.rs.Extension, m.extractCreator, keyExtractor, extract.NewRecordFilter(m.rs.Filter, m.rs.Sample), onDuplicatedRecords)
 
 	return nil
 }
@@ -714,6 +738,15 @@ func (m *Manager) doWithAbort(reqArgs *cmn.ReqArgs) error {
 	return errors.WithStack(<-errCh)
 }
 
+// ListenSmapChanged handles cluster membership changes during the run. The job
+// is pinned to the targets it has been started with:
+//
+//   - when new target joins, the job continues - new target does not
+//     participate, and the input shards that rebalance migrates to it are
+//     restored on demand (see restoreInputShard);
+//   - when target leaves, the records it has extracted (and the shards it
+//     has created) are gone, so the job is aborted on all the remaining targets
+//     and then resumed from their checkpoints - see Checkpoint.
 func (m *Manager) ListenSmapChanged(ch chan int64) {
 	for {
 		newSmapVersion, ok := <-ch
@@ -729,17 +762,104 @@ func (m *Manager) ListenSmapChanged(ch chan int64) {
 		}
 
 		newSmap := m.ctx.smapOwner.Get()
-		if newSmap.CountTargets() != m.smap.CountTargets() {
-			// Currently adding new target as well as removing one is not
-			// supported during the run.
-			//
-			// TODO: dSort should survive adding new target. For now it is
-			// not possible as rebalance deletes moved object - dSort needs
-			// to use `GetObject` method instead of relaying on simple `os.Open`
-			err := errors.Errorf("number of target has changed during dSort run, aborting due to possible errors")
-			m.abort(err)
+		left := make([]string, 0)
+		for sid := range m.smap.Tmap {
+			if newSmap.GetTarget(sid) == nil {
+				left = append(left, sid)
+			}
+		}
+		if len(left) == 0 {
+			if newSmap.CountTargets() != m.smap.CountTargets() && !m.joined.Swap(true) {
+				glog.Infof("%s %s: new target(s) joined the cluster, continuing with %d targets",
+					cmn.DSortName, m.ManagerUUID, m.smap.CountTargets())
+			}
+			continue
+		}
+
+		if m.aborted() {
+			continue
+		}
+		err := errors.Errorf("target(s) %v left the cluster during %s run, the job will be resumed",
+			left, cmn.DSortName)
+		m.abort(err)
+		go m.requestResume(newSmap)
+	}
+}
+
+// requestResume asks the primary proxy to resume the job aborted due to
+// a target leaving the cluster. Only one (the lowest ID) of the remaining
+// targets does it.
+func (m *Manager) requestResume(smap *cluster.Smap) {
+	for sid := range m.smap.Tmap {
+		if smap.GetTarget(sid) != nil && sid < m.ctx.node.DaemonID {
+			return
 		}
 	}
+
+	// wait for the job to be cleaned up - resumed job reuses the UUID
+	deadline := time.Now().Add(m.callTimeout)
+	for {
+		if _, exists := Managers.Get(m.ManagerUUID); !exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			glog.Errorf("%s %s: timed out waiting for cleanup, the job must be resumed manually",
+				cmn.DSortName, m.ManagerUUID)
+			return
+		}
+		time.Sleep(time.Second)
+	}
+
+	reqArgs := cmn.ReqArgs{
+		Method: http.MethodPost,
+		Base:   smap.ProxySI.URL(cmn.NetworkIntraControl),
+		Path:   cmn.URLPath(cmn.Version, cmn.Sort, cmn.Resume),
+		Query:  url.Values{cmn.URLParamID: []string{m.ManagerUUID}},
+	}
+	config := cmn.GCO.Get()
+	client := cmn.NewClient(cmn.TransportArgs{
+		Timeout:    m.callTimeout,
+		UseHTTPS:   config.Net.HTTP.UseHTTPS,
+		SkipVerify: config.Net.HTTP.SkipVerify,
+	})
+	req, err := reqArgs.Req()
+	if err == nil {
+		var resp *http.Response
+		if resp, err = client.Do(req); err == nil {
+			if resp.StatusCode >= http.StatusBadRequest {
+				b, _ := ioutil.ReadAll(resp.Body)
+				err = errors.New(string(b))
+			}
+			resp.Body.Close()
+		}
+	}
+	if err != nil {
+		glog.Errorf("%s %s: failed to resume, err: %v", cmn.DSortName, m.ManagerUUID, err)
+		return
+	}
+	glog.Infof("%s %s has been resumed", cmn.DSortName, m.ManagerUUID)
+}
+
+// restoreInputShard brings back the input shard which has been migrated to
+// a new target by rebalance (the job stays with the original targets).
+func (m *Manager) restoreInputShard(lom *cluster.LOM) error {
+	if !m.joined.Load() || !lom.Bck().IsAIS() {
+		return os.ErrNotExist
+	}
+	if err := m.ctx.t.GetObject(ioutil.Discard, lom, time.Now()); err != nil {
+		return err
+	}
+	glog.Infof("%s %s: restored %s", cmn.DSortName, m.ManagerUUID, lom)
+	return lom.Load(false)
+}
+
+// restoreInputShardByName - see restoreInputShard
+func (m *Manager) restoreInputShardByName(shardName string) error {
+	lom := &cluster.LOM{T: m.ctx.t, ObjName: shardName}
+	if err := lom.Init(cmn.Bck{Name: m.rs.Bucket, Provider: m.rs.Provider}); err != nil {
+		return err
+	}
+	return m.restoreInputShard(lom)
 }
 
 func (m *Manager) String() string {
@@ -775,11 +895,11 @@ func (m *Manager) react(reaction, msg string) error {
 
 func (bsi *buildingShardInfo) Unpack(unpacker *cmn.ByteUnpack) error {
 	var err error
-	bsi.shardName, err = unpacker.ReadString()
+	bsi.shardUname, err = unpacker.ReadString()
 	return err
 }
-func (bsi *buildingShardInfo) Pack(packer *cmn.BytePack) { packer.WriteString(bsi.shardName) }
-func (bsi *buildingShardInfo) PackedSize() int           { return cmn.SizeofLen + len(bsi.shardName) }
+func (bsi *buildingShardInfo) Pack(packer *cmn.BytePack) { packer.WriteString(bsi.shardUname) }
+func (bsi *buildingShardInfo) PackedSize() int           { return cmn.SizeofLen + len(bsi.shardUname) }
 func (bsi *buildingShardInfo) NewPack(mm *memsys.MMSA) []byte {
 	var (
 		size   = bsi.PackedSize()
@@ -150,6 +150,8 @@ func (mg *ManagerGroup) Remove(managerUUID string) error {
 		return err
 	}
 	_ = db.Delete(managersCollection, managerUUID) // Delete only returns err when record does not exist, which should be ignored
+	_ = db.Delete(checkpointsCollection, managerUUID)
+	_ = db.Delete(extractedCollection, managerUUID)
 	return nil
 }
 
@@ -181,6 +183,87 @@ func (mg *ManagerGroup) persist(managerUUID string) {
 	delete(mg.managers, managerUUID)
 }
 
+// saveCheckpoint persists the checkpoint of the job - see Checkpoint.
+func (mg *ManagerGroup) saveCheckpoint(ckpt *Checkpoint) error {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	config := cmn.GCO.Get()
+	db, err := scribble.New(filepath.Join(config.Confdir, persistManagersPath), nil)
+	if err != nil {
+		return err
+	}
+	return db.Write(checkpointsCollection, ckpt.ManagerUUID, ckpt)
+}
+
+// Checkpoint returns persisted checkpoint of the job with given managerUUID.
+// Returns false if the checkpoint does not exist.
+func (mg *ManagerGroup) Checkpoint(managerUUID string) (*Checkpoint, bool) {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	config := cmn.GCO.Get()
+	db, err := scribble.New(filepath.Join(config.Confdir, persistManagersPath), nil)
+	if err != nil {
+		glog.Error(err)
+		return nil, false
+	}
+	ckpt := &Checkpoint{}
+	if err := db.Read(checkpointsCollection, managerUUID, ckpt); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Error(err)
+		}
+		return nil, false
+	}
+	return ckpt, true
+}
+
+// removeCheckpoint removes the checkpoint once the job has successfully finished.
+func (mg *ManagerGroup) removeCheckpoint(managerUUID string) {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	config := cmn.GCO.Get()
+	db, err := scribble.New(filepath.Join(config.Confdir, persistManagersPath), nil)
+	if err != nil {
+		glog.Error(err)
+		return
+	}
+	_ = db.Delete(checkpointsCollection, managerUUID) // the job might have never been checkpointed
+	_ = db.Delete(extractedCollection, managerUUID)
+}
+
+// saveExtractedShards persists the records of the input shards extracted by
+// the job - see extractedShard.
+func (mg *ManagerGroup) saveExtractedShards(managerUUID string, shards map[string]*extractedShard) error {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	config := cmn.GCO.Get()
+	db, err := scribble.New(filepath.Join(config.Confdir, persistManagersPath), nil)
+	if err != nil {
+		return err
+	}
+	return db.Write(extractedCollection, managerUUID, shards)
+}
+
+// extractedShards returns persisted records of the input shards extracted by
+// the job with given managerUUID (before it has been aborted).
+func (mg *ManagerGroup) extractedShards(managerUUID string) map[string]*extractedShard {
+	mg.mtx.Lock()
+	defer mg.mtx.Unlock()
+	config := cmn.GCO.Get()
+	db, err := scribble.New(filepath.Join(config.Confdir, persistManagersPath), nil)
+	if err != nil {
+		glog.Error(err)
+		return nil
+	}
+	var shards map[string]*extractedShard
+	if err := db.Read(extractedCollection, managerUUID, &shards); err != nil {
+		if !os.IsNotExist(err) {
+			glog.Error(err)
+		}
+		return nil
+	}
+	return shards
+}
+
 func (mg *ManagerGroup) AbortAll(err error) {
 	mg.mtx.Lock()
 	defer mg.mtx.Unlock()
@@ -227,5 +310,28 @@ func (mg *ManagerGroup) housekeep() time.Duration {
 		}
 	}
 
+	// Checkpoints of the jobs which have not been resumed for a long time
+	records, err = db.ReadAll(checkpointsCollection)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return regularInterval
+		}
+
+		glog.Error(err)
+		return retryInterval
+	}
+
+	for _, r := range records {
+		var ckpt Checkpoint
+		if err := jsoniter.Unmarshal(r, &ckpt); err != nil {
+			glog.Error(err)
+			return retryInterval
+		}
+		if time.Since(ckpt.Updated) > regularInterval {
+			_ = db.Delete(checkpointsCollection, ckpt.ManagerUUID)
+			_ = db.Delete(extractedCollection, ckpt.ManagerUUID)
+		}
+	}
+
 	return regularInterval
 }
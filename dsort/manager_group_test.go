@@ -11,7 +11,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/housekeep/hk"
 	. "github.com/onsi/ginkgo"
@@ -22,6 +24,16 @@ const (
 	testingConfigDir = "/tmp/ais_tests"
 )
 
+// lookupTargetMock reports objects as existing on the remote targets.
+type lookupTargetMock struct {
+	*cluster.TargetMock
+	exists map[string]bool
+}
+
+func (t *lookupTargetMock) LookupRemoteSingle(lom *cluster.LOM, _ *cluster.Snode) bool {
+	return t.exists[lom.ObjName]
+}
+
 var _ = Describe("ManagerGroup", func() {
 	var (
 		mgrp    *ManagerGroup
@@ -115,6 +127,119 @@ var _ = Describe("ManagerGroup", func() {
 		})
 	})
 
+	Context("checkpoint", func() {
+		ctx.smapOwner = newTestSmap("target")
+		ctx.node = ctx.smapOwner.Get().Tmap["target"]
+
+		newShard := func(name string, records ...string) *extract.Shard {
+			s := &extract.Shard{
+				Name:    name,
+				Bck:     cmn.Bck{Name: testBucket, Provider: cmn.ProviderAIS},
+				Records: extract.NewRecords(len(records)),
+			}
+			for _, r := range records {
+				s.Records.Insert(&extract.Record{
+					Name:     r,
+					DaemonID: "target",
+					Objects:  []*extract.RecordObj{{Extension: ".cls"}, {Extension: ".jpg"}},
+				})
+			}
+			return s
+		}
+
+		It("should persist checkpoint and remove it with the manager", func() {
+			m, err := mgrp.Add("uuid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(m.init(validRS)).ShouldNot(HaveOccurred())
+			m.initCheckpoint(nil)
+			m.unlock()
+			m.updateCheckpoint(func(ckpt *Checkpoint) {
+				ckpt.Phase = PhaseCreation
+				ckpt.CreatedShards = append(ckpt.CreatedShards, CreatedShard{Name: "shard-0.tar", Digest: 1})
+			}, true)
+
+			ckpt, exists := mgrp.Checkpoint("uuid")
+			Expect(exists).To(BeTrue())
+			Expect(ckpt.Phase).To(Equal(PhaseCreation))
+			Expect(ckpt.Spec.Extension).To(Equal(cmn.ExtTar))
+			Expect(ckpt.CreatedShards).To(Equal([]CreatedShard{{Name: "shard-0.tar", Digest: 1}}))
+
+			m.setInProgressTo(false)
+			mgrp.persist("uuid")
+			Expect(mgrp.Remove("uuid")).ShouldNot(HaveOccurred())
+			_, exists = mgrp.Checkpoint("uuid")
+			Expect(exists).To(BeFalse())
+		})
+
+		It("should skip only existing created shards with the same records", func() {
+			// Output shards belong to the other target which has them.
+			prevCtx := ctx
+			defer func() { ctx = prevCtx }()
+			bmdMock := cluster.NewBaseBownerMock()
+			bmdMock.Add(cluster.NewBck(testBucket, cmn.ProviderAIS, cmn.NsGlobal, &cmn.BucketProps{}))
+			ctx.t = &lookupTargetMock{
+				TargetMock: cluster.NewTargetMock(bmdMock),
+				exists:     map[string]bool{"shard-0.tar": true, "shard-1.tar": true},
+			}
+			ctx.smapOwner = newTestSmap("other")
+			ctx.node = &cluster.Snode{DaemonID: "target"}
+
+			m, err := mgrp.Add("uuid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(m.init(validRS)).ShouldNot(HaveOccurred())
+			m.unlock()
+
+			shards := []*extract.Shard{
+				newShard("shard-0.tar", "a", "b"),
+				newShard("shard-1.tar", "c", "d"),
+				newShard("shard-2.tar", "e", "f"),
+				newShard("shard-3.tar", "g", "h"),
+			}
+			m.resumed = map[string]uint64{
+				shardUname(shards[0]): shardDigest(newShard("shard-0.tar", "a", "b")),
+				shardUname(shards[1]): shardDigest(newShard("shard-1.tar", "c", "x")), // records have changed
+				shardUname(shards[3]): shardDigest(newShard("shard-3.tar", "g", "h")), // shard is gone
+			}
+			remaining, skipped := m.skipCreatedShards(shards)
+			Expect(remaining).To(HaveLen(3))
+			Expect(remaining[0].Name).To(Equal("shard-1.tar"))
+			Expect(remaining[1].Name).To(Equal("shard-2.tar"))
+			Expect(remaining[2].Name).To(Equal("shard-3.tar"))
+			Expect(skipped["target"]).To(Equal(int64(4)))
+		})
+
+		It("should persist extracted shards and remove them with the manager", func() {
+			m, err := mgrp.Add("uuid")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(m.init(validRS)).ShouldNot(HaveOccurred())
+			m.initCheckpoint(nil)
+			m.unlock()
+
+			shards := map[string]*extractedShard{
+				"shard-0.tar": {
+					Size:  1024,
+					Cksum: "(xxhash,01234)",
+					Records: []*extract.Record{{
+						Key:      "a",
+						Name:     "shard-0|a",
+						DaemonID: "target",
+						Objects: []*extract.RecordObj{{
+							ContentPath: "shard-0.tar", StoreType: extract.OffsetStoreType,
+							Offset: 512, MetadataSize: 512, Size: 100, Extension: ".cls",
+						}},
+					}},
+				},
+			}
+			Expect(mgrp.saveExtractedShards("uuid", shards)).ShouldNot(HaveOccurred())
+			Expect(mgrp.extractedShards("uuid")).To(Equal(shards))
+
+			m.setInProgressTo(false)
+			mgrp.persist("uuid")
+			Expect(mgrp.Remove("uuid")).ShouldNot(HaveOccurred())
+			Expect(mgrp.extractedShards("uuid")).To(BeNil())
+		})
+	})
+
 	Context("housekeep", func() {
 		persistManager := func(uuid string, finishedAgo time.Duration) {
 			m, err := mgrp.Add(uuid)
@@ -130,6 +130,9 @@ type LocalExtraction struct {
 	// ExtractedCnt describes number of extracted shards to given moment. At the
 	// end, number should be roughly equal to TotalCnt/#Targets.
 	ExtractedCnt int64 `json:"extracted_count,string"`
+	// ReusedCnt describes number of extracted shards whose records have been
+	// extracted before the job was resumed (and have not been extracted again).
+	ReusedCnt int64 `json:"reused_count,string"`
 	// ExtractedSize describes uncompressed size of extracted shards to given moment.
 	ExtractedSize int64 `json:"extracted_size,string"`
 	// ExtractedRecordCnt describes number of records extracted from all shards.
@@ -141,6 +144,12 @@ type LocalExtraction struct {
 	// ExtractedToDiskSize describes uncompressed size of extracted shards to disk
 	// to given moment.
 	ExtractedToDiskSize int64 `json:"extracted_to_disk_size,string"`
+	// DroppedRecordCnt describes number of records dropped by the filter or
+	// sampling (see RequestSpec).
+	DroppedRecordCnt int64 `json:"dropped_record_count,string"`
+	// KeptRecordCnt describes number of records kept after extraction (when
+	// records are filtered or sampled).
+	KeptRecordCnt int64 `json:"kept_record_count,string"`
 	// ShardExtractionStats describes time statistics about single shard extraction.
 	ShardExtractionStats *DetailedStats `json:"single_shard_stats,omitempty"`
 }
@@ -26,6 +26,9 @@ const (
 var (
 	errMissingBucket            = errors.New("missing field 'bucket'")
 	errInvalidExtension         = errors.New("extension must be one of '.tar', '.tar.gz', or '.tgz'")
+	errInvalidOutputExtension   = errors.New("output extension must be the same as extension or '.tfrecord'")
+	errInvalidOutputIndex       = errors.New("output index is supported only for '.tfrecord' output extension")
+	errInvalidTFFeatures        = errors.New("TFRecord features require '.tfrecord' output extension")
 	errNegOutputShardSize       = errors.New("output shard size must be > 0")
 	errNegativeConcurrencyLimit = fmt.Errorf("concurrency limit must be 0 (limits will be calculated) or > 0")
 
@@ -37,11 +40,15 @@ var (
 	errInvalidAlgorithmKind      = fmt.Errorf("invalid algorithm kind, should be one of: %+v", supportedAlgorithms)
 	errInvalidSeed               = errors.New("invalid seed provided, should be int")
 	errInvalidAlgorithmExtension = errors.New("invalid extension provided, should be in format: .ext")
+	errInvalidAlgorithmKeys      = errors.New("composite algorithm requires at least one key")
 )
 
 var (
 	// supportedExtensions is a list of supported extensions by dSort
 	supportedExtensions = []string{cmn.ExtTar, cmn.ExtTgz, cmn.ExtTarTgz, cmn.ExtZip}
+	// supportedOutputExtensions is a list of output extensions which can be
+	// created from any of the supportedExtensions
+	supportedOutputExtensions = []string{cmn.ExtTFRecord}
 )
 
 // TODO: maybe this struct should be composed of `type` and `template` where
@@ -92,6 +99,12 @@ type RequestSpec struct {
 	Provider string `json:"provider" yaml:"provider"`
 	// Default: "ais"
 	OutputProvider string `json:"output_provider" yaml:"output_provider"`
+	// Default: same as `extension` field
+	OutputExtension string `json:"output_extension" yaml:"output_extension"`
+	// Default: false (output extension: .tfrecord)
+	OutputIndex bool `json:"output_index" yaml:"output_index"`
+	// Default: every record object is mapped to the feature named after its extension (output extension: .tfrecord)
+	TFFeatures []extract.TFFeature `json:"tf_features" yaml:"tf_features"`
 	// Default: DefaultConcLimit
 	ExtractConcLimit int `json:"extract_concurrency_limit" yaml:"extract_concurrency_limit"`
 	// Default: DefaultConcLimit
@@ -100,6 +113,12 @@ type RequestSpec struct {
 	StreamMultiplier int `json:"stream_multiplier" yaml:"stream_multiplier"`
 	// Default: false
 	ExtendedMetrics bool `json:"extended_metrics" yaml:"extended_metrics"`
+	// Default: no records are dropped
+	Filter extract.FilterSpec `json:"filter" yaml:"filter"`
+	// Default: all records are kept
+	Sample extract.SampleSpec `json:"sample" yaml:"sample"`
+	// Default: all records are written to `output_bucket` (`output_format` and `output_shard_size` are not used otherwise)
+	Split SplitSpec `json:"split" yaml:"split"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -116,6 +135,9 @@ type ParsedRequestSpec struct {
 	Provider         string                `json:"provider"`
 	OutputProvider   string                `json:"output_provider"`
 	Extension        string                `json:"extension"`
+	OutputExtension  string                `json:"output_extension"`
+	OutputIndex      bool                  `json:"output_index"`
+	TFFeatures       []extract.TFFeature   `json:"tf_features"`
 	OutputShardSize  int64                 `json:"output_shard_size,string"`
 	InputFormat      *parsedInputTemplate  `json:"input_format"`
 	OutputFormat     *parsedOutputTemplate `json:"output_format"`
@@ -128,6 +150,9 @@ type ParsedRequestSpec struct {
 	CreateConcLimit  int                   `json:"create_concurrency_limit"`  // TODO: should be removed
 	StreamMultiplier int                   `json:"stream_multiplier"`         // TODO: should be removed
 	ExtendedMetrics  bool                  `json:"extended_metrics"`
+	Filter           extract.FilterSpec    `json:"filter"`
+	Sample           extract.SampleSpec    `json:"sample"`
+	Split            *ParsedSplitSpec      `json:"split,omitempty"`
 
 	// debug
 	DSorterType string `json:"dsorter_type"`
@@ -137,17 +162,27 @@ type ParsedRequestSpec struct {
 }
 
 type SortAlgorithm struct {
-	Kind string `json:"kind"`
+	Kind string `json:"kind" yaml:"kind"`
 
-	// Kind: alphanumeric, content
-	Decreasing bool `json:"decreasing"`
+	// Kind: alphanumeric, content, json, regex, composite
+	Decreasing bool `json:"decreasing" yaml:"decreasing"`
 
 	// Kind: shuffle
-	Seed string `json:"seed"` // seed provided to random generator
+	Seed string `json:"seed" yaml:"seed"` // seed provided to random generator
 
-	// Kind: content
-	Extension  string `json:"extension"`
-	FormatType string `json:"format_type"`
+	// Kind: content, json
+	Extension string `json:"extension" yaml:"extension"`
+	// Kind: content, json, regex
+	FormatType string `json:"format_type" yaml:"format_type"`
+
+	// Kind: json
+	JSONPath string `json:"json_path" yaml:"json_path"`
+
+	// Kind: regex
+	Regex string `json:"regex" yaml:"regex"`
+
+	// Kind: composite
+	Keys []extract.KeySpec `json:"keys" yaml:"keys"`
 }
 
 // Parse returns a non-nil error if a RequestSpec is invalid. When RequestSpec
@@ -186,13 +221,36 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		return nil, errInvalidExtension
 	}
 	parsedRS.Extension = rs.Extension
-
-	parsedRS.OutputShardSize, err = cmn.S2B(rs.OutputShardSize)
-	if err != nil {
-		return nil, err
+	parsedRS.OutputExtension = rs.OutputExtension
+	if parsedRS.OutputExtension == "" {
+		parsedRS.OutputExtension = parsedRS.Extension
+	}
+	if !validateOutputExtension(parsedRS.Extension, parsedRS.OutputExtension) {
+		return nil, errInvalidOutputExtension
+	}
+	if rs.OutputIndex && parsedRS.OutputExtension != cmn.ExtTFRecord {
+		return nil, errInvalidOutputIndex
+	}
+	parsedRS.OutputIndex = rs.OutputIndex
+	if len(rs.TFFeatures) > 0 {
+		if parsedRS.OutputExtension != cmn.ExtTFRecord {
+			return nil, errInvalidTFFeatures
+		}
+		if err := extract.ValidateTFFeatures(rs.TFFeatures); err != nil {
+			return nil, err
+		}
+		parsedRS.TFFeatures = rs.TFFeatures
 	}
-	if parsedRS.OutputShardSize <= 0 {
-		return nil, errNegOutputShardSize
+
+	// NOTE: with split, each of the outputs can specify its own shard size
+	if rs.Split.IsEmpty() || rs.OutputShardSize != "" {
+		parsedRS.OutputShardSize, err = cmn.S2B(rs.OutputShardSize)
+		if err != nil {
+			return nil, err
+		}
+		if parsedRS.OutputShardSize <= 0 {
+			return nil, errNegOutputShardSize
+		}
 	}
 
 	parsedRS.Algorithm, err = parseAlgorithm(rs.Algorithm)
@@ -203,8 +261,10 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	if empty, valid := validateOrderFileURL(rs.OrderFileURL); !valid {
 		return nil, errInvalidOrderParam
 	} else if empty {
-		if parsedRS.OutputFormat, err = parseOutputFormat(rs.OutputFormat); err != nil {
-			return nil, err
+		if rs.Split.IsEmpty() {
+			if parsedRS.OutputFormat, err = parseOutputFormat(rs.OutputFormat); err != nil {
+				return nil, err
+			}
 		}
 	} else { // valid and not empty
 		parsedRS.OrderFileURL = rs.OrderFileURL
@@ -215,6 +275,20 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 		}
 	}
 
+	if err := rs.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	parsedRS.Filter = rs.Filter
+	if err := rs.Sample.Validate(); err != nil {
+		return nil, err
+	}
+	parsedRS.Sample = rs.Sample
+	if !rs.Split.IsEmpty() {
+		if parsedRS.Split, err = parseSplit(rs.Split, parsedRS); err != nil {
+			return nil, err
+		}
+	}
+
 	if rs.MaxMemUsage == "" {
 		rs.MaxMemUsage = cfg.DefaultMaxMemUsage
 	}
@@ -262,11 +336,41 @@ func (rs *RequestSpec) Parse() (*ParsedRequestSpec, error) {
 	return parsedRS, nil
 }
 
+// outputBck returns the output bucket of the job (without split).
+func (rs *ParsedRequestSpec) outputBck() cmn.Bck {
+	return cmn.Bck{Name: rs.OutputBucket, Provider: rs.OutputProvider, Ns: cmn.NsGlobal}
+}
+
+// outputBcks returns all the buckets the output shards are created in.
+func (rs *ParsedRequestSpec) outputBcks() []cmn.Bck {
+	if rs.Split == nil {
+		return []cmn.Bck{rs.outputBck()}
+	}
+	var (
+		bcks = make([]cmn.Bck, 0, len(rs.Split.Outputs))
+		seen = make(map[cmn.Bck]struct{}, len(rs.Split.Outputs))
+	)
+	for _, out := range rs.Split.Outputs {
+		bck := out.bck()
+		if _, ok := seen[bck]; !ok {
+			seen[bck] = struct{}{}
+			bcks = append(bcks, bck)
+		}
+	}
+	return bcks
+}
+
 // validateExtension checks if extension is supported by dsort
 func validateExtension(ext string) bool {
 	return cmn.StringInSlice(ext, supportedExtensions)
 }
 
+// validateOutputExtension checks if shards with extension can be converted
+// into shards with output extension
+func validateOutputExtension(ext, outputExt string) bool {
+	return ext == outputExt || cmn.StringInSlice(outputExt, supportedOutputExtensions)
+}
+
 // parseInputFormat checks if input format was specified correctly
 func parseInputFormat(inputFormat string) (pit *parsedInputTemplate, err error) {
 	pit = &parsedInputTemplate{}
@@ -321,6 +425,24 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 		if err := extract.ValidateAlgorithmFormatType(algo.FormatType); err != nil {
 			return nil, err
 		}
+	} else if algo.Kind == SortKindJSON || algo.Kind == SortKindRegex {
+		spec := algo.keySpec()
+		if err := spec.Validate(); err != nil {
+			return nil, err
+		}
+		algo.Extension, algo.FormatType = spec.Extension, spec.FormatType
+	} else if algo.Kind == SortKindComposite {
+		if len(algo.Keys) == 0 {
+			return nil, errInvalidAlgorithmKeys
+		}
+		keys := make([]extract.KeySpec, len(algo.Keys))
+		copy(keys, algo.Keys)
+		for i := range keys {
+			if err := keys[i].Validate(); err != nil {
+				return nil, fmt.Errorf("invalid key %d: %v", i, err)
+			}
+		}
+		algo.Keys = keys
 	} else {
 		algo.FormatType = extract.FormatTypeString
 	}
@@ -328,6 +450,17 @@ func parseAlgorithm(algo SortAlgorithm) (parsedAlgo *SortAlgorithm, err error) {
 	return &algo, nil
 }
 
+// keySpec returns the spec of the key extracted by single-key algorithm kinds.
+func (algo *SortAlgorithm) keySpec() extract.KeySpec {
+	return extract.KeySpec{
+		Kind:       algo.Kind, // NOTE: SortKindJSON and SortKindRegex are the same as corresponding key kinds
+		Extension:  algo.Extension,
+		JSONPath:   algo.JSONPath,
+		Regex:      algo.Regex,
+		FormatType: algo.FormatType,
+	}
+}
+
 func validateOrderFileURL(orderURL string) (empty, valid bool) {
 	if orderURL == "" {
 		return true, true
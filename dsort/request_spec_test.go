@@ -9,6 +9,7 @@ package dsort
 
 import (
 	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
 	"github.com/NVIDIA/aistore/fs"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -42,6 +43,7 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.Provider).To(Equal(cmn.ProviderAIS))
 			Expect(parsed.OutputProvider).To(Equal(cmn.ProviderAIS))
 			Expect(parsed.Extension).To(Equal(cmn.ExtTar))
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtTar))
 
 			Expect(parsed.InputFormat.Template).To(Equal(cmn.ParsedTemplate{
 				Prefix: "prefix-",
@@ -155,6 +157,78 @@ var _ = Describe("RequestSpec", func() {
 			Expect(parsed.Extension).To(Equal(cmn.ExtZip))
 		})
 
+		It("should parse spec with split", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				OutputBucket:    "output",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				Split: SplitSpec{
+					Kind: SplitKindHash,
+					Seed: "42",
+					Outputs: []SplitOutput{
+						{Name: "train", Percentage: 90, OutputFormat: "train-{0..9}"},
+						{Name: "val", Percentage: 10, OutputBucket: "val", OutputFormat: "val-{0..9}", OutputShardSize: "1KB"},
+					},
+				},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.OutputFormat).To(BeNil())
+			Expect(parsed.Split.Seed).To(BeEquivalentTo(42))
+			Expect(parsed.Split.Outputs).To(HaveLen(2))
+			Expect(parsed.Split.Outputs[0].OutputBucket).To(Equal("output"))
+			Expect(parsed.Split.Outputs[0].OutputShardSize).To(BeEquivalentTo(10 * cmn.KiB))
+			Expect(parsed.Split.Outputs[1].OutputBucket).To(Equal("val"))
+			Expect(parsed.Split.Outputs[1].OutputShardSize).To(BeEquivalentTo(cmn.KiB))
+			Expect(parsed.outputBcks()).To(HaveLen(2))
+		})
+
+		It("should parse spec with .tfrecord output extension", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				OutputExtension: cmn.ExtTFRecord,
+				OutputIndex:     true,
+				TFFeatures:      []extract.TFFeature{{Name: "image", Extensions: []string{"jpg"}}},
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Extension).To(Equal(cmn.ExtTar))
+			Expect(parsed.OutputExtension).To(Equal(cmn.ExtTFRecord))
+			Expect(parsed.OutputIndex).To(BeTrue())
+			Expect(parsed.TFFeatures).To(HaveLen(1))
+		})
+
+		It("should parse spec with composite algorithm", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm: SortAlgorithm{
+					Kind: SortKindComposite,
+					Keys: []extract.KeySpec{
+						{Kind: extract.KeyKindJSON, Extension: ".json", JSONPath: "$.label.id", FormatType: extract.FormatTypeInt},
+						{Kind: extract.KeyKindRegex, Regex: "-([0-9]+)$"},
+					},
+				},
+			}
+			parsed, err := rs.Parse()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(parsed.Algorithm.Keys).To(HaveLen(2))
+			Expect(parsed.Algorithm.Keys[1].FormatType).To(Equal(extract.FormatTypeString))
+		})
+
 		It("should parse spec with @ syntax", func() {
 			rs := RequestSpec{
 				Bucket:          "test",
@@ -313,6 +387,96 @@ var _ = Describe("RequestSpec", func() {
 			Expect(err).To(Equal(errInvalidExtension))
 		})
 
+		It("should fail due to invalid output extension", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				OutputExtension: cmn.ExtZip,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidOutputExtension))
+		})
+
+		It("should fail due to output index requested for tar output", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				OutputIndex:     true,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+			Expect(err).To(Equal(errInvalidOutputIndex))
+		})
+
+		It("should fail due to invalid json algorithm", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindJSON, Extension: ".json", JSONPath: "$.label[x]"},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should fail due to invalid filter and sample", func() {
+			rs := RequestSpec{
+				Bucket:          "test",
+				Extension:       cmn.ExtTar,
+				InputFormat:     "prefix-{0010..0111}-suffix",
+				OutputFormat:    "prefix-{0010..0111}-suffix",
+				OutputShardSize: "10KB",
+				Algorithm:       SortAlgorithm{Kind: SortKindNone},
+				Filter:          extract.FilterSpec{Exclude: []string{"3"}},
+			}
+			_, err := rs.Parse()
+			Expect(err).Should(HaveOccurred())
+
+			rs.Filter = extract.FilterSpec{}
+			rs.Sample = extract.SampleSpec{Percentage: 150}
+			_, err = rs.Parse()
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should fail due to invalid split", func() {
+			rs := RequestSpec{
+				Bucket:      "test",
+				Extension:   cmn.ExtTar,
+				InputFormat: "prefix-{0010..0111}-suffix",
+				Algorithm:   SortAlgorithm{Kind: SortKindNone},
+				Split: SplitSpec{
+					Kind: SplitKindHash,
+					Outputs: []SplitOutput{
+						{Name: "train", Percentage: 80, OutputFormat: "train-{0..9}", OutputShardSize: "10KB"},
+						{Name: "val", Percentage: 10, OutputFormat: "val-{0..9}", OutputShardSize: "10KB"},
+					},
+				},
+			}
+			_, err := rs.Parse()
+			Expect(err).To(Equal(errInvalidSplitPercent))
+
+			rs.Split.Outputs[1].Percentage = 20
+			rs.Split.Outputs[1].OutputShardSize = ""
+			_, err = rs.Parse()
+			Expect(err).Should(HaveOccurred()) // missing shard size
+
+			rs.Split.Outputs[1].OutputShardSize = "10KB"
+			rs.Split.Kind = SplitKindOrderFile
+			_, err = rs.Parse()
+			Expect(err).To(Equal(errInvalidSplitOrderFile))
+		})
+
 		It("should fail due to invalid mem usage specification", func() {
 			rs := RequestSpec{
 				Bucket:          "test",
@@ -19,20 +19,25 @@ const (
 	SortKindAlphanumeric = "alphanumeric" // sort the records (decreasing or increasing)
 	SortKindNone         = "none"         // none, used for resharding
 	SortKindMD5          = "md5"
-	SortKindShuffle      = "shuffle" // shuffle randomly, can be used with seed to get reproducible results
-	SortKindContent      = "content" // sort by content of given file
+	SortKindShuffle      = "shuffle"   // shuffle randomly, can be used with seed to get reproducible results
+	SortKindContent      = "content"   // sort by content of given file
+	SortKindJSON         = "json"      // sort by value under JSONPath in given (JSON) file
+	SortKindRegex        = "regex"     // sort by capture group of the regex matched against record name
+	SortKindComposite    = "composite" // sort by multiple keys: primary, secondary, etc.
 )
 
 var (
-	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent, SortKindNone}
+	supportedAlgorithms = []string{sortKindEmpty, SortKindAlphanumeric, SortKindMD5, SortKindShuffle, SortKindContent,
+		SortKindJSON, SortKindRegex, SortKindComposite, SortKindNone}
 )
 
 type (
 	alphaByKey struct {
 		*extract.Records
-		decreasing bool
-		formatType string
-		err        error
+		decreasing  bool
+		formatType  string
+		formatTypes []string // composite key
+		err         error
 	}
 )
 
@@ -47,7 +52,10 @@ func (s *alphaByKey) Less(i, j int) bool {
 	)
 
 	if s.decreasing {
-		less, err = s.Records.Less(j, i, s.formatType)
+		i, j = j, i
+	}
+	if len(s.formatTypes) > 0 {
+		less, err = s.Records.LessComposite(i, j, s.formatTypes)
 	} else {
 		less, err = s.Records.Less(i, j, s.formatType)
 	}
@@ -77,7 +85,10 @@ func sortRecords(r *extract.Records, algo *SortAlgorithm) (err error) {
 			r.Swap(i, j)
 		}
 	} else {
-		keys := &alphaByKey{r, algo.Decreasing, algo.FormatType, nil}
+		keys := &alphaByKey{Records: r, decreasing: algo.Decreasing, formatType: algo.FormatType}
+		for _, key := range algo.Keys {
+			keys.formatTypes = append(keys.formatTypes, key.FormatType)
+		}
 		sort.Sort(keys)
 
 		if keys.err != nil {
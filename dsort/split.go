@@ -0,0 +1,279 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package dsort
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/OneOfOne/xxhash"
+)
+
+// Split routes the sorted records into multiple outputs (e.g. train,
+// validation and test datasets), each with its own bucket, template and shard
+// size. The records are routed either by the hash of their names (the outputs
+// get the requested percentages of the records, regardless of how many distinct
+// keys there are) or by the column of the order file which contains the name
+// of the output. Records keep their sorting order within each of the outputs.
+
+const (
+	SplitKindHash      = "hash"
+	SplitKindOrderFile = "order_file"
+
+	splitPrecision = 1 << 32
+)
+
+var (
+	supportedSplitKinds = []string{SplitKindHash, SplitKindOrderFile}
+
+	errInvalidSplitKind      = fmt.Errorf("invalid split kind, should be one of: %+v", supportedSplitKinds)
+	errInvalidSplitOutputs   = errors.New("split requires at least one output")
+	errInvalidSplitOrderFile = errors.New("split kind 'order_file' requires order file, other kinds cannot be used with order file")
+	errInvalidSplitPercent   = errors.New("split output percentages must be > 0 and sum up to 100")
+	errInvalidSplitColumn    = errors.New("split column must be > 0")
+)
+
+type (
+	SplitSpec struct {
+		Kind string `json:"kind" yaml:"kind"`
+		// Kind: hash; Default: "0"
+		Seed string `json:"seed" yaml:"seed"`
+		// Kind: order_file; column of the order file with the name of the output; Default: 1
+		Column int `json:"column" yaml:"column"`
+
+		Outputs []SplitOutput `json:"outputs" yaml:"outputs"`
+	}
+
+	SplitOutput struct {
+		// Required
+		Name         string `json:"name" yaml:"name"`
+		OutputFormat string `json:"output_format" yaml:"output_format"`
+
+		// Kind: hash
+		Percentage float64 `json:"percentage" yaml:"percentage"`
+		// Default: same as `output_bucket` of the job
+		OutputBucket string `json:"output_bucket" yaml:"output_bucket"`
+		// Default: same as `output_provider` of the job
+		OutputProvider string `json:"output_provider" yaml:"output_provider"`
+		// Default: same as `output_shard_size` of the job
+		OutputShardSize string `json:"output_shard_size" yaml:"output_shard_size"`
+	}
+
+	ParsedSplitSpec struct {
+		Kind    string               `json:"kind"`
+		Seed    uint64               `json:"seed,string"`
+		Column  int                  `json:"column"`
+		Outputs []*ParsedSplitOutput `json:"outputs"`
+	}
+
+	ParsedSplitOutput struct {
+		Name            string                `json:"name"`
+		Percentage      float64               `json:"percentage"`
+		OutputBucket    string                `json:"output_bucket"`
+		OutputProvider  string                `json:"output_provider"`
+		OutputFormat    *parsedOutputTemplate `json:"output_format"`
+		OutputShardSize int64                 `json:"output_shard_size,string"`
+	}
+)
+
+func (s *SplitSpec) IsEmpty() bool { return s.Kind == "" && len(s.Outputs) == 0 }
+
+// parseSplit validates the split and fills in the defaults of its outputs
+// from already parsed request spec.
+func parseSplit(split SplitSpec, parsedRS *ParsedRequestSpec) (*ParsedSplitSpec, error) {
+	if !cmn.StringInSlice(split.Kind, supportedSplitKinds) {
+		return nil, errInvalidSplitKind
+	}
+	if (split.Kind == SplitKindOrderFile) != (parsedRS.OrderFileURL != "") {
+		return nil, errInvalidSplitOrderFile
+	}
+	if len(split.Outputs) == 0 {
+		return nil, errInvalidSplitOutputs
+	}
+
+	parsedSplit := &ParsedSplitSpec{
+		Kind:    split.Kind,
+		Column:  split.Column,
+		Outputs: make([]*ParsedSplitOutput, 0, len(split.Outputs)),
+	}
+	if split.Seed != "" {
+		seed, err := strconv.ParseInt(split.Seed, 10, 64)
+		if err != nil {
+			return nil, errInvalidSeed
+		}
+		parsedSplit.Seed = uint64(seed)
+	}
+	if split.Kind == SplitKindOrderFile {
+		if split.Column < 0 {
+			return nil, errInvalidSplitColumn
+		}
+		if split.Column == 0 {
+			parsedSplit.Column = 1
+		}
+	}
+
+	var (
+		names = make(cmn.StringSet, len(split.Outputs))
+		total float64
+	)
+	for _, out := range split.Outputs {
+		if out.Name == "" || names.Contains(out.Name) {
+			return nil, fmt.Errorf("split output name %q is empty or duplicated", out.Name)
+		}
+		names.Add(out.Name)
+
+		parsedOut := &ParsedSplitOutput{
+			Name:            out.Name,
+			OutputBucket:    out.OutputBucket,
+			OutputProvider:  out.OutputProvider,
+			OutputShardSize: parsedRS.OutputShardSize,
+		}
+		if parsedOut.OutputBucket == "" {
+			parsedOut.OutputBucket = parsedRS.OutputBucket
+		}
+		if parsedOut.OutputProvider == "" {
+			parsedOut.OutputProvider = parsedRS.OutputProvider
+		}
+		var err error
+		if parsedOut.OutputFormat, err = parseOutputFormat(out.OutputFormat); err != nil {
+			return nil, fmt.Errorf("split output %q: %v", out.Name, err)
+		}
+		if out.OutputShardSize != "" {
+			if parsedOut.OutputShardSize, err = cmn.S2B(out.OutputShardSize); err != nil {
+				return nil, fmt.Errorf("split output %q: %v", out.Name, err)
+			}
+		}
+		if parsedOut.OutputShardSize <= 0 {
+			return nil, fmt.Errorf("split output %q: %v", out.Name, errNegOutputShardSize)
+		}
+		if split.Kind == SplitKindHash {
+			if out.Percentage <= 0 {
+				return nil, errInvalidSplitPercent
+			}
+			parsedOut.Percentage = out.Percentage
+			total += out.Percentage
+		}
+		parsedSplit.Outputs = append(parsedSplit.Outputs, parsedOut)
+	}
+	if split.Kind == SplitKindHash && math.Abs(total-100) > 1e-6 {
+		return nil, errInvalidSplitPercent
+	}
+	return parsedSplit, nil
+}
+
+func (out *ParsedSplitOutput) bck() cmn.Bck {
+	return cmn.Bck{Name: out.OutputBucket, Provider: out.OutputProvider, Ns: cmn.NsGlobal}
+}
+
+// splitRecords routes the records into the outputs of the split. Records which
+// do not belong to any output (missing in the order file) are returned separately.
+func (m *Manager) splitRecords() (outputs []*extract.Records, unassigned []*extract.Record, err error) {
+	var (
+		split   = m.rs.Split
+		records = m.recManager.Records.All()
+		route   func(r *extract.Record) int
+	)
+	outputs = make([]*extract.Records, len(split.Outputs))
+	for i := range outputs {
+		outputs[i] = extract.NewRecords(len(records) / len(outputs))
+	}
+
+	switch split.Kind {
+	case SplitKindHash:
+		// Each output is assigned a range of the hash values proportional to
+		// its percentage. The last output takes the rest (rounding errors).
+		thresholds := make([]uint64, len(split.Outputs))
+		var cumulative float64
+		for i, out := range split.Outputs {
+			cumulative += out.Percentage
+			thresholds[i] = uint64(cumulative / 100 * splitPrecision)
+		}
+		thresholds[len(thresholds)-1] = splitPrecision
+		// NOTE: the name (unlike the key) is unique - hashing the keys would
+		// not meet the percentages when there are only a few distinct keys.
+		route = func(r *extract.Record) int {
+			h := xxhash.ChecksumString64S(r.Name, split.Seed) % splitPrecision
+			for i, threshold := range thresholds {
+				if h < threshold {
+					return i
+				}
+			}
+			return len(thresholds) - 1
+		}
+	case SplitKindOrderFile:
+		externalKeyMap, err := m.fetchExternalKeyMap(split.Column)
+		if err != nil {
+			return nil, nil, err
+		}
+		indices := make(map[string]int, len(split.Outputs))
+		for i, out := range split.Outputs {
+			indices[out.Name] = i
+		}
+		route = func(r *extract.Record) int {
+			if idx, ok := indices[externalKeyMap[fmt.Sprintf("%v", r.Key)]]; ok {
+				return idx
+			}
+			return -1
+		}
+	default:
+		cmn.AssertMsg(false, split.Kind)
+	}
+
+	for _, r := range records {
+		idx := route(r)
+		if idx < 0 {
+			msg := fmt.Sprintf("extracted record %q which does not belong to any split output", r.Key)
+			if err := m.react(m.rs.EKMMissingKey, msg); err != nil {
+				return nil, nil, err
+			}
+			unassigned = append(unassigned, r)
+			continue
+		}
+		outputs[idx].Insert(r)
+	}
+	return outputs, unassigned, nil
+}
+
+// generateShardsWithSplit generates the output shards of all the split outputs.
+// Returns also the number of records' objects (per target) which are not going
+// to be sent since their records do not belong to any output.
+func (m *Manager) generateShardsWithSplit() ([]*extract.Shard, map[string]int64, error) {
+	outputs, unassigned, err := m.splitRecords()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		shards   = make([]*extract.Shard, 0)
+		dropped  = make(map[string]int64)
+		outCount int
+	)
+	for _, r := range unassigned {
+		dropped[r.DaemonID] += int64(len(r.Objects))
+	}
+	for i, out := range m.rs.Split.Outputs {
+		outShards, err := m.generateShardsWithTemplate(
+			outputs[i], &out.OutputFormat.Template, out.bck(), m.maxShardSize(out.OutputShardSize),
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("split output %q: %v", out.Name, err)
+		}
+		glog.Infof("%s: split output %q (bucket %q): %d records, %d shards",
+			m.ManagerUUID, out.Name, out.OutputBucket, outputs[i].Len(), len(outShards))
+		outCount += outputs[i].Len()
+		shards = append(shards, outShards...)
+	}
+	if len(unassigned) > 0 {
+		glog.Warningf("%s: %d records do not belong to any split output", m.ManagerUUID, len(unassigned))
+	}
+	cmn.Assert(outCount+len(unassigned) == m.recManager.Records.Len())
+	return shards, dropped, nil
+}
@@ -0,0 +1,155 @@
+// Package dsort provides distributed massively parallel resharding for very large datasets.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ *
+ */
+package dsort
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/dsort/extract"
+	"github.com/NVIDIA/aistore/fs"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split", func() {
+	const recordCnt = 1000
+
+	BeforeEach(func() {
+		ctx.smapOwner = newTestSmap("target")
+		ctx.node = ctx.smapOwner.Get().Tmap["target"]
+		fs.InitMountedFS()
+
+		config := cmn.GCO.BeginUpdate()
+		config.DSort.DefaultMaxMemUsage = "90%"
+		cmn.GCO.CommitUpdate(config)
+	})
+
+	newManager := func(rs RequestSpec) *Manager {
+		parsedRS, err := rs.Parse()
+		Expect(err).NotTo(HaveOccurred())
+		m := &Manager{ctx: dsortContext{t: cluster.NewTargetMock(cluster.NewBaseBownerMock())}}
+		Expect(m.init(parsedRS)).NotTo(HaveOccurred())
+		for i := 0; i < recordCnt; i++ {
+			name := fmt.Sprintf("record-%04d", i)
+			m.recManager.Records.Insert(&extract.Record{
+				Key:      name,
+				Name:     name,
+				DaemonID: "target",
+				Objects:  []*extract.RecordObj{{Extension: ".jpg", Size: 100}, {Extension: ".cls", Size: 1}},
+			})
+		}
+		return m
+	}
+
+	countRecords := func(shards []*extract.Shard) map[string]int {
+		counts := make(map[string]int)
+		for _, s := range shards {
+			counts[s.Bck.Name] += s.Records.Len()
+		}
+		return counts
+	}
+
+	It("should split records by hash of their names", func() {
+		rs := RequestSpec{
+			Bucket:      "test",
+			Extension:   cmn.ExtTar,
+			InputFormat: "input-{0..9}",
+			Algorithm:   SortAlgorithm{Kind: SortKindNone},
+			DSorterType: DSorterGeneralType,
+			Split: SplitSpec{
+				Kind: SplitKindHash,
+				Outputs: []SplitOutput{
+					{Name: "train", Percentage: 80, OutputBucket: "train", OutputFormat: "shard-{0..99}", OutputShardSize: "100KB"},
+					{Name: "val", Percentage: 10, OutputBucket: "val", OutputFormat: "shard-{0..99}", OutputShardSize: "20KB"},
+					{Name: "test", Percentage: 10, OutputBucket: "test", OutputFormat: "shard-{0..99}", OutputShardSize: "20KB"},
+				},
+			},
+		}
+		// Only a few distinct keys (e.g. class labels) must not skew the split.
+		lowCardinality := func(m *Manager) *Manager {
+			for i, r := range m.recManager.Records.All() {
+				r.Key = fmt.Sprintf("label-%d", i%2)
+			}
+			return m
+		}
+		shards, dropped, err := lowCardinality(newManager(rs)).generateShardsWithSplit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dropped).To(BeEmpty())
+
+		counts := countRecords(shards)
+		Expect(counts["train"] + counts["val"] + counts["test"]).To(Equal(recordCnt))
+		Expect(counts["train"]).To(BeNumerically("~", 800, 50))
+		Expect(counts["val"]).To(BeNumerically("~", 100, 35))
+		Expect(counts["test"]).To(BeNumerically("~", 100, 35))
+
+		// The same shard names are used in different buckets.
+		unames := make(cmn.StringSet, len(shards))
+		for _, s := range shards {
+			Expect(s.Name).To(HavePrefix("shard-"))
+			unames.Add(shardUname(s))
+		}
+		Expect(unames).To(HaveLen(len(shards)))
+
+		// Records are routed the same way on every run.
+		shards, _, err = lowCardinality(newManager(rs)).generateShardsWithSplit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(countRecords(shards)).To(Equal(counts))
+	})
+
+	It("should split records by order file column", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for i := 0; i < recordCnt; i++ {
+				split := "train"
+				if i%4 == 0 {
+					split = "val"
+				} else if i%4 == 1 {
+					split = "unknown"
+				}
+				fmt.Fprintf(w, "record-%04d\tignored\t%s\n", i, split)
+			}
+		}))
+		defer srv.Close()
+
+		rs := RequestSpec{
+			Bucket:          "test",
+			Extension:       cmn.ExtTar,
+			InputFormat:     "input-{0..9}",
+			OutputShardSize: "10KB",
+			OrderFileURL:    srv.URL,
+			Algorithm:       SortAlgorithm{Kind: SortKindNone},
+			DSorterType:     DSorterGeneralType,
+			Split: SplitSpec{
+				Kind:   SplitKindOrderFile,
+				Column: 2,
+				Outputs: []SplitOutput{
+					{Name: "train", OutputFormat: "train-{0..99}"},
+					{Name: "val", OutputFormat: "val-{0..99}"},
+				},
+			},
+		}
+		rs.DSortConf.EKMMissingKey = cmn.IgnoreReaction
+		shards, dropped, err := newManager(rs).generateShardsWithSplit()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dropped["target"]).To(BeEquivalentTo(recordCnt / 4 * 2)) // objects of the unknown split
+
+		var train, val int
+		for _, s := range shards {
+			Expect(s.Bck.Name).To(Equal("test"))
+			if strings.HasPrefix(s.Name, "val-") {
+				val += s.Records.Len()
+			} else {
+				train += s.Records.Len()
+			}
+		}
+		Expect(train).To(Equal(recordCnt / 2))
+		Expect(val).To(Equal(recordCnt / 4))
+	})
+})
@@ -0,0 +1,202 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// XactBckReencode walks the EC-encoded objects of the bucket and re-encodes
+// those whose EC metadata does not match the current EC configuration of the
+// bucket (the number of data, parity, and local parity slices, or the replication
+// size limit).
+// The object gets re-sliced (or replicated) by its main target which also
+// removes the slices, replicas, and metafiles that do not fit the new layout
+// from the other targets (see: putJogger.reencode).
+
+type (
+	XactBckReencode struct {
+		cmn.XactBase
+		cmn.MountpathXact
+		t      cluster.Target
+		doneCh chan struct{}
+		wg     *sync.WaitGroup // to wait for EC finishes all objects
+		stats  struct {
+			scanned, failed atomic.Int64
+		}
+	}
+	joggerBckReencode struct { // per mountpath
+		parent    *XactBckReencode
+		mpathInfo *fs.MountpathInfo
+		config    *cmn.Config
+		smap      *cluster.Smap
+		daemonID  string
+		throttle  fs.Throttle
+	}
+)
+
+func NewXactBckReencode(bck cmn.Bck, t cluster.Target) *XactBckReencode {
+	return &XactBckReencode{
+		XactBase: *cmn.NewXactBaseWithBucket("", cmn.ActECReencode, bck),
+		t:        t,
+		wg:       &sync.WaitGroup{},
+	}
+}
+
+func (r *XactBckReencode) afterReencode(lom *cluster.LOM, err error) {
+	if err == nil {
+		r.ObjectsInc()
+		r.BytesAdd(lom.Size())
+	} else {
+		r.stats.failed.Inc()
+		glog.Errorf("Failed to re-encode object %s/%s: %v", lom.BckName(), lom.ObjName, err)
+	}
+	r.wg.Done()
+}
+
+func (r *XactBckReencode) Run() error {
+	bck := cluster.NewBckEmbed(r.Bck())
+	if err := bck.Init(r.t.GetBowner(), r.t.Snode()); err != nil {
+		r.EndTime(time.Now())
+		return err
+	}
+	if !bck.Props.EC.Enabled {
+		r.EndTime(time.Now())
+		return fmt.Errorf("bucket %q does not have EC enabled", bck.Name)
+	}
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		config            = cmn.GCO.Get()
+		smap              = r.t.GetSowner().Get()
+	)
+	glog.Infoln(r.String())
+	r.doneCh = make(chan struct{}, len(availablePaths))
+	for _, mpathInfo := range availablePaths {
+		j := &joggerBckReencode{
+			parent:    r,
+			mpathInfo: mpathInfo,
+			config:    config,
+			smap:      smap,
+			daemonID:  r.t.Snode().ID(),
+			throttle:  fs.Throttle{Mpath: mpathInfo.Path, Config: config},
+		}
+		go j.jog()
+	}
+	for range availablePaths {
+		<-r.doneCh
+	}
+	if r.Aborted() {
+		// the pending objects are still processed by EC but no one waits for them
+		return fmt.Errorf("%s aborted, exiting", r)
+	}
+	r.wg.Wait()
+	r.EndTime(time.Now())
+	glog.Infof("%s: scanned %d, re-encoded %d, failed %d", r, r.stats.scanned.Load(), r.ObjectsCnt(),
+		r.stats.failed.Load())
+	return nil
+}
+
+func (r *XactBckReencode) Stop(error) { r.Abort() }
+
+// ScannedCnt returns the number of the EC-encoded objects checked so far.
+func (r *XactBckReencode) ScannedCnt() int64 { return r.stats.scanned.Load() }
+
+// FailedCnt returns the number of the objects that failed to get re-encoded.
+func (r *XactBckReencode) FailedCnt() int64 { return r.stats.failed.Load() }
+
+//
+// mpath jogger
+//
+
+func (j *joggerBckReencode) jog() {
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      j.parent.Bck(),
+		CTs:      []string{fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		if errors.As(err, &cmn.AbortedError{}) {
+			glog.Infof("%s: stopping traversal: %v", j.mpathInfo, err)
+		} else {
+			glog.Errorf("%s: failed to traverse, err: %v", j.mpathInfo, err)
+		}
+	}
+	j.parent.doneCh <- struct{}{}
+}
+
+func (j *joggerBckReencode) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.throttle.YieldTerm(j.parent); err != nil {
+		return err
+	}
+	lom := &cluster.LOM{T: j.parent.t, FQN: fqn}
+	if err := lom.Init(j.parent.Bck(), j.config); err != nil {
+		return nil
+	}
+	if err := lom.Load(); err != nil {
+		return nil
+	}
+	// a mirror of the object
+	if !lom.IsHRW() {
+		return nil
+	}
+	si, err := cluster.HrwTarget(lom.Uname(), j.smap)
+	if err != nil {
+		glog.Errorf("%s: %s", lom, err)
+		return nil
+	}
+	// an object replica - re-encoded by the main target
+	if j.daemonID != si.ID() {
+		return nil
+	}
+	mdFQN, _, err := cluster.HrwFQN(lom.Bck(), MetaType, lom.ObjName)
+	if err != nil {
+		glog.Warningf("metadata FQN generation failed %q: %v", fqn, err)
+		return nil
+	}
+	// not encoded yet (see: XactBckEncode)
+	md, err := LoadMetadata(mdFQN)
+	if err != nil {
+		return nil
+	}
+	j.parent.stats.scanned.Inc()
+	if !needsReencode(lom, md) {
+		return nil
+	}
+
+	j.parent.wg.Add(1)
+	if err := ECM.ReencodeObject(lom, j.parent.afterReencode); err != nil {
+		j.parent.wg.Done()
+		j.parent.stats.failed.Inc()
+		// something wrong with EC, interrupt file walk - it is critical
+		return fmt.Errorf("failed to re-encode object %q: %v", fqn, err)
+	}
+	return nil
+}
+
+// needsReencode returns true if the object's EC layout differs from the one
+// defined by the current EC configuration of its bucket.
+func needsReencode(lom *cluster.LOM, md *Metadata) bool {
+	conf := &lom.Bprops().EC
+	isCopy := IsECCopy(lom.Size(), conf)
+	if md.IsCopy != isCopy || md.Parity != conf.ParitySlices {
+		return true
+	}
+	// the number of data slices and local groups does not matter for replicas
+	return !isCopy && (md.Data != conf.DataSlices || md.LocalGroups != conf.LocalGroups)
+}
@@ -28,6 +28,7 @@ import (
 //		Enable: true|false    # enables or disables protection
 //		DataSlices: [1-32]    # the number of data slices
 //		ParitySlices: [1-32]  # the number of parity slices
+//		LocalGroups: 0        # the number of local parity groups (see lrc.go)
 //		ObjSizeLimit: 0       # replication versus erasure coding
 //
 // NOTE: replicating small object is cheaper than erasure encoding.
@@ -42,7 +43,9 @@ import (
 //
 // NOTE: All slices and replicas must be on the different targets. The target
 // list is calculated by HrwTargetList. The first target in the list is the
-// "main" target that keeps the full object, the others keep only slices/replicas
+// "main" target that keeps the full object, the others keep only slices/replicas.
+// If targets are labeled with failure domains (Snode.Zone), HrwTargetList
+// spreads the list across the domains
 //
 // NOTE: All slices must be of the same size. So, the last slice can be padded
 // with zeros. In most cases, padding results in the total size of data
@@ -62,6 +65,8 @@ import (
 //		size - size of the original object (required for correct restoration)
 //		data - the number of data slices (unused if the object was replicated)
 //		parity - the number of parity slices
+//		local_groups - the number of local parity slices (unused if replicated)
+//		groups - the local parity group of every data slice (unused if replicated)
 //		copy - whether the object was replicated or erasure encoded
 //		chk - original object checksum (used to choose the correct slices when
 //			restoring the object, sort of versioning)
@@ -113,9 +118,10 @@ const (
 	SliceType = "ec" // object slice prefix
 	MetaType  = "mt" // metafile prefix
 
-	ActSplit   = "split"
-	ActRestore = "restore"
-	ActDelete  = "delete"
+	ActSplit    = "split"
+	ActRestore  = "restore"
+	ActDelete   = "delete"
+	ActReencode = "reencode"
 
 	RespStreamName = "ec-resp"
 	ReqStreamName  = "ec-req"
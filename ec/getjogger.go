@@ -237,6 +237,7 @@ func (c *getJogger) restoreReplicatedFromMemory(req *Request, meta *Metadata, no
 		return err
 	}
 
+	req.LOM.SetCustomMD(meta.CustomMD)
 	if err := req.LOM.Persist(); err != nil {
 		writer.Free()
 		return err
@@ -302,6 +303,7 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 		return err
 	}
 
+	req.LOM.SetCustomMD(meta.CustomMD)
 	if err := req.LOM.Persist(); err != nil {
 		return err
 	}
@@ -327,17 +329,19 @@ func (c *getJogger) restoreReplicatedFromDisk(req *Request, meta *Metadata, node
 }
 
 // Main object is not found and it is clear that it was encoded. Request
-// all data and parity slices from targets in a cluster:
+// the data and parity slices (except the skipped ones) from targets in a cluster:
 // * req - original request
 // * meta - reconstructed metadata
 // * nodes - targets that responded with valid metadata, it does not make sense
 //    to request slice from the entire cluster
+// * skip - the slices that are not required for restoration (see unneededSlices)
 // Returns:
-// * []slice - a list of received slices in correct order (missing slices = nil)
+// * []slice - a list of received slices in correct order (missing and skipped slices = nil)
 // * map[int]string - a map of slice locations: SliceID <-> DaemonID
-func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string]*Metadata, toDisk bool) ([]*slice, map[int]string, error) {
+func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string]*Metadata,
+	skip map[int]bool, toDisk bool) ([]*slice, map[int]string, error) {
 	wgSlices := cmn.NewTimeoutGroup()
-	sliceCnt := meta.Data + meta.Parity
+	sliceCnt := meta.SliceCnt()
 	slices := make([]*slice, sliceCnt)
 	daemons := make([]string, 0, len(nodes)) // target to be requested for a slice
 	idToNode := make(map[int]string)         // which target what slice returned
@@ -347,6 +351,10 @@ func (c *getJogger) requestSlices(req *Request, meta *Metadata, nodes map[string
 			glog.Warningf("Node %s has invalid slice ID %d", k, v.SliceID)
 			continue
 		}
+		if skip[v.SliceID] {
+			idToNode[v.SliceID] = k
+			continue
+		}
 
 		if glog.V(4) {
 			glog.Infof("Slice %s/%s ID %d requesting from %s", req.LOM.Bck(), req.LOM.ObjName, v.SliceID, k)
@@ -473,13 +481,15 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	toDisk bool, buffer []byte) ([]*slice, error) {
 	var (
 		err       error
-		sliceCnt  = meta.Data + meta.Parity
+		sliceCnt  = meta.SliceCnt()
 		sliceSize = SliceSize(meta.Size, meta.Data)
 		readers   = make([]io.Reader, sliceCnt)
 		writers   = make([]io.Writer, sliceCnt)
 		restored  = make([]*slice, sliceCnt)
 		cksums    = make([]*cmn.CksumHash, sliceCnt)
 		conf      = req.LOM.CksumConf()
+		rsCnt     = meta.Data + meta.Parity // local parity slices are not a part of RS encoding
+		open      = func(i int) (io.ReadCloser, error) { return openSlice(slices[i], restored[i]) }
 	)
 	cksmWg := &sync.WaitGroup{}
 	cksmErrCh := make(chan int, sliceCnt)
@@ -487,6 +497,9 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	// allocate memory for reconstructed(missing) slices - EC requirement,
 	// and open existing slices for reading
 	for i, sl := range slices {
+		if sl == nil && idToNode[i+1] != "" {
+			continue // available but not requested (see requestSlices)
+		}
 		if sl != nil && sl.writer != nil {
 			sz := sl.n
 			if glog.V(4) {
@@ -551,8 +564,37 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 		readers[i] = nil
 	}
 
-	if err := stream.Reconstruct(readers, writers); err != nil {
-		return restored, err
+	// first, restore the data slices that can be restored from their local groups
+	if meta.LocalGroups > 0 {
+		repaired, err := RepairLocal(meta, sliceSize, open, writers)
+		if err != nil {
+			return restored, err
+		}
+		for _, i := range repaired {
+			if readers[i], err = openSlice(nil, restored[i]); err != nil {
+				return restored, err
+			}
+			writers[i] = nil
+		}
+		if glog.V(4) && len(repaired) > 0 {
+			glog.Infof("Restored %d slices of %s/%s from local parity", len(repaired), req.LOM.Bck(), req.LOM.ObjName)
+		}
+	}
+
+	// nothing to reconstruct if the data and parity slices are available (or not needed)
+	rsMissing := false
+	for _, w := range writers[:rsCnt] {
+		rsMissing = rsMissing || w != nil
+	}
+	if rsMissing {
+		if err := stream.Reconstruct(readers[:rsCnt], writers[:rsCnt]); err != nil {
+			return restored, err
+		}
+	}
+	if meta.LocalGroups > 0 {
+		if err := RebuildLocalParity(meta, sliceSize, open, writers); err != nil {
+			return restored, err
+		}
 	}
 
 	version := ""
@@ -570,29 +612,14 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 
 	srcReaders := make([]io.Reader, meta.Data)
 	for i := 0; i < meta.Data; i++ {
-		if slices[i] != nil && slices[i].writer != nil {
-			if sgl, ok := slices[i].writer.(*memsys.SGL); ok {
-				srcReaders[i] = memsys.NewReader(sgl)
-			} else if slices[i].workFQN != "" {
-				srcReaders[i], err = cmn.NewFileHandle(slices[i].workFQN)
-				if err != nil {
-					return restored, err
-				}
-			} else {
-				return restored, fmt.Errorf("invalid writer: %T", slices[i].writer)
-			}
-		} else {
-			if restored[i].workFQN != "" {
-				srcReaders[i], err = cmn.NewFileHandle(restored[i].workFQN)
-				if err != nil {
-					return restored, err
-				}
-			} else if sgl, ok := restored[i].obj.(*memsys.SGL); ok {
-				srcReaders[i] = memsys.NewReader(sgl)
-			} else {
-				return restored, fmt.Errorf("empty slice %d of %s/%s", i, req.LOM.Bck(), req.LOM.ObjName)
-			}
+		var r io.ReadCloser
+		if r, err = open(i); err != nil {
+			return restored, err
+		}
+		if r == nil {
+			return restored, fmt.Errorf("empty slice %d of %s/%s", i, req.LOM.Bck(), req.LOM.ObjName)
 		}
+		srcReaders[i] = r
 	}
 
 	src := io.MultiReader(srcReaders...)
@@ -607,6 +634,7 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	if version != "" {
 		req.LOM.SetVersion(version)
 	}
+	req.LOM.SetCustomMD(meta.CustomMD)
 	// recompute checksum of the full replica
 	var (
 		tmpFQN = fs.CSM.GenContentFQN(mainFQN, fs.WorkfileType, "ec")
@@ -619,6 +647,15 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 		return restored, err
 	}
 	<-c.diskCh
+	// the object checksum is stored in the metafile, if the checksum type has not changed since encoding
+	if meta.ObjCksum != "" && meta.ObjCksumType == conf.Type {
+		if expected := cmn.NewCksum(meta.ObjCksumType, meta.ObjCksum); !cksum.Equal(expected) {
+			if errRemove := cmn.RemoveFile(mainFQN); errRemove != nil {
+				glog.Errorf("Failed to remove %q: %v", mainFQN, errRemove)
+			}
+			return restored, cmn.NewBadDataCksumError(&cksum.Cksum, expected, req.LOM.String())
+		}
+	}
 	req.LOM.SetCksum(cksum.Clone())
 	// Persist called without a lock. It's not a problem, as the LOM should not be used as the object is missing
 	if err := req.LOM.Persist(); err != nil {
@@ -655,6 +692,30 @@ func (c *getJogger) restoreMainObj(req *Request, meta *Metadata, slices []*slice
 	return restored, nil
 }
 
+// opens a new reader of the slice: the restored one if the slice was missing
+// or damaged, the received one otherwise. Returns nil if there is neither.
+func openSlice(received, restored *slice) (io.ReadCloser, error) {
+	if restored != nil {
+		if restored.workFQN != "" {
+			return cmn.NewFileHandle(restored.workFQN)
+		}
+		if sgl, ok := restored.obj.(*memsys.SGL); ok {
+			return memsys.NewReader(sgl), nil
+		}
+		return nil, fmt.Errorf("invalid restored slice: %T", restored.obj)
+	}
+	if received == nil || received.writer == nil {
+		return nil, nil
+	}
+	if sgl, ok := received.writer.(*memsys.SGL); ok {
+		return memsys.NewReader(sgl), nil
+	}
+	if received.workFQN != "" {
+		return cmn.NewFileHandle(received.workFQN)
+	}
+	return nil, fmt.Errorf("invalid writer: %T", received.writer)
+}
+
 // *slices - slices to search through
 // *start - id which search should start from
 // Returns:
@@ -681,7 +742,7 @@ func getNextNonEmptySlice(slices []*slice, start int) (*slice, int) {
 // * slices - object slices reconstructed by `restoreMainObj`
 // * idToNode - a map of targets that already contain a slice (SliceID <-> target)
 func (c *getJogger) uploadRestoredSlices(req *Request, meta *Metadata, slices []*slice, idToNode map[int]string) {
-	sliceCnt := meta.Data + meta.Parity
+	sliceCnt := meta.SliceCnt()
 	nodeToID := make(map[string]int, len(idToNode))
 	// transpose SliceID <-> DaemonID map for faster lookup
 	for k, v := range idToNode {
@@ -743,7 +804,7 @@ func (c *getJogger) uploadRestoredSlices(req *Request, meta *Metadata, slices []
 
 		// clone the object's metadata and set the correct SliceID before sending
 		sliceMeta := *meta
-		sliceMeta.SliceID = idx + 1
+		sliceMeta.SliceID = nextIdx // the index of the found slice + 1
 		var reader cmn.ReadOpenCloser
 		if sl.workFQN != "" {
 			reader, _ = cmn.NewFileHandle(sl.workFQN)
@@ -764,13 +825,13 @@ func (c *getJogger) uploadRestoredSlices(req *Request, meta *Metadata, slices []
 		}
 
 		if glog.V(4) {
-			glog.Infof("Sending slice %d %s/%s to %s", sliceMeta.SliceID+1, req.LOM.Bck(), req.LOM.ObjName, tgt)
+			glog.Infof("Sending slice %d %s/%s to %s", sliceMeta.SliceID, req.LOM.Bck(), req.LOM.ObjName, tgt)
 		}
 		if sl.cksum != nil {
 			sliceMeta.CksumType, sliceMeta.CksumValue = sl.cksum.Get()
 		}
 		if err := c.parent.writeRemote([]string{tgt}, req.LOM, dataSrc, cb); err != nil {
-			glog.Errorf("Failed to send slice %d of %s/%s to %s", idx+1, req.LOM.Bck(), req.LOM.ObjName, tgt)
+			glog.Errorf("Failed to send slice %d of %s/%s to %s", sliceMeta.SliceID, req.LOM.Bck(), req.LOM.ObjName, tgt)
 		}
 
 		idx = nextIdx
@@ -804,8 +865,14 @@ func (c *getJogger) restoreEncoded(req *Request, meta *Metadata, nodes map[strin
 		}
 	}
 
-	// download all slices from the targets that have sent metadata
-	slices, idToNode, err := c.requestSlices(req, meta, nodes, toDisk)
+	// download the slices from the targets that have sent metadata: all of
+	// them unless the missing data slices can be restored from local parity
+	available := make(map[int]bool, len(nodes))
+	for _, md := range nodes {
+		available[md.SliceID] = true
+	}
+	skip := unneededSlices(meta, available)
+	slices, idToNode, err := c.requestSlices(req, meta, nodes, skip, toDisk)
 	if err != nil {
 		freeWriters()
 		return err
@@ -813,6 +880,19 @@ func (c *getJogger) restoreEncoded(req *Request, meta *Metadata, nodes map[strin
 
 	// restore and save locally the main replica
 	restored, err := c.restoreMainObj(req, meta, slices, idToNode, toDisk, buffer)
+	if err != nil && len(skip) > 0 {
+		// some of the received slices are damaged - retry with all slices
+		glog.Warningf("Failed to restore %s/%s without parity slices, retrying with all: %v",
+			req.LOM.Bck(), req.LOM.ObjName, err)
+		freeWriters()
+		freeSlices(restored)
+		freeSlices(slices)
+		if slices, idToNode, err = c.requestSlices(req, meta, nodes, nil, toDisk); err != nil {
+			freeWriters()
+			return err
+		}
+		restored, err = c.restoreMainObj(req, meta, slices, idToNode, toDisk, buffer)
+	}
 	if err != nil {
 		glog.Errorf("Failed to restore main object %s/%s: %v", req.LOM.Bck(), req.LOM.ObjName, err)
 		freeWriters()
@@ -0,0 +1,315 @@
+// Package ec provides erasure coding (EC) based data protection for AIStore.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package ec
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/memsys"
+)
+
+// Local parity groups (LRC-style local reconstruction codes).
+//
+// With `LocalGroups` > 0 the data slices are split into `LocalGroups` groups
+// by the failure domains (zones) of the targets they are sent to: the data
+// slices stored in the same zone get into the same group as long as possible
+// (see zoneGroups). The group of every data slice is stored in the metadata.
+// Every group gets one extra local parity slice - XOR of the data slices of
+// the group. Local parity slices have IDs following the data and parity ones:
+// `Data+Parity+1` to `Data+Parity+LocalGroups`.
+//
+// A single missing data slice of a group is restored from the rest of the
+// group and its local parity, i.e., by reading (mostly within the zone) the
+// group instead of Data slices as Reed-Solomon decoding requires. Losses that
+// local parity cannot repair fall back to Reed-Solomon reconstruction.
+
+// SliceCnt returns the total number of slices of the encoded object: data,
+// parity, and local parity ones.
+func (md *Metadata) SliceCnt() int {
+	return md.Data + md.Parity + md.LocalGroups
+}
+
+// returns the local group of every data slice; the metadata without groups
+// assigns the data slice `i` (0-based) to the group `i % LocalGroups`
+func (md *Metadata) dataGroups() []int {
+	if len(md.Groups) == md.Data {
+		return md.Groups
+	}
+	groups := make([]int, md.Data)
+	for i := range groups {
+		groups[i] = i % md.LocalGroups
+	}
+	return groups
+}
+
+// zoneGroups splits the data slices into `cnt` local groups: the slices are
+// ordered by the zones of their targets and the ordered list is cut into `cnt`
+// groups of (nearly) equal size. Returns the group of every data slice.
+// * targets - the target of every data slice
+func zoneGroups(targets cluster.Nodes, cnt int) []int {
+	var (
+		zones = make(map[string]int, len(targets))
+		order = make([]int, len(targets))
+	)
+	for i, si := range targets {
+		if _, ok := zones[si.Zone]; !ok {
+			zones[si.Zone] = len(zones)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return zones[targets[order[i]].Zone] < zones[targets[order[j]].Zone]
+	})
+	groups := make([]int, len(targets))
+	for pos, i := range order {
+		groups[i] = pos * cnt / len(targets)
+	}
+	return groups
+}
+
+// returns the indices (0-based) of the data slices of the local group
+func groupMembers(group int, groups []int) []int {
+	members := make([]int, 0, len(groups))
+	for i, g := range groups {
+		if g == group {
+			members = append(members, i)
+		}
+	}
+	return members
+}
+
+// unneededSlices returns the slices (by SliceID) that are not required to
+// restore the object when every missing data slice is the only missing data
+// slice of its group and the group's local parity is available: the parity
+// slices and the local parity slices of the complete groups. Returns nil if
+// Reed-Solomon reconstruction is required.
+// * available - SliceIDs of the available slices
+func unneededSlices(md *Metadata, available map[int]bool) map[int]bool {
+	var (
+		missing = make([]int, md.LocalGroups)
+		groups  []int
+	)
+	if md.LocalGroups > 0 {
+		groups = md.dataGroups()
+	}
+	for i := 0; i < md.Data; i++ {
+		if available[i+1] {
+			continue
+		}
+		if md.LocalGroups == 0 {
+			return nil
+		}
+		missing[groups[i]]++
+	}
+	skip := make(map[int]bool, md.Parity+md.LocalGroups)
+	for id := md.Data + 1; id <= md.Data+md.Parity; id++ {
+		skip[id] = true
+	}
+	for g, cnt := range missing {
+		lpID := md.Data + md.Parity + g + 1
+		switch {
+		case cnt == 0:
+			skip[lpID] = true
+		case cnt > 1 || !available[lpID]:
+			return nil
+		}
+	}
+	return skip
+}
+
+// writes XOR of `size` bytes read from every reader to the writer
+func xorSlices(w io.Writer, size int64, readers ...io.Reader) error {
+	var (
+		acc, accSlab = mm.Alloc(size)
+		buf, bufSlab = mm.Alloc(size)
+	)
+	defer func() {
+		accSlab.Free(acc)
+		bufSlab.Free(buf)
+	}()
+	for left := size; left > 0; {
+		n := int(cmn.MinI64(left, int64(len(acc))))
+		if _, err := io.ReadFull(readers[0], acc[:n]); err != nil {
+			return err
+		}
+		for _, r := range readers[1:] {
+			if _, err := io.ReadFull(r, buf[:n]); err != nil {
+				return err
+			}
+			for k := 0; k < n; k++ {
+				acc[k] ^= buf[k]
+			}
+		}
+		if _, err := w.Write(acc[:n]); err != nil {
+			return err
+		}
+		left -= int64(n)
+	}
+	return nil
+}
+
+// XORs the slices opened by `open` (indices are 0-based) into the writer
+func xorGroup(w io.Writer, size int64, idxs []int, open func(i int) (io.ReadCloser, error)) error {
+	readers := make([]io.Reader, 0, len(idxs))
+	for _, i := range idxs {
+		r, err := open(i)
+		if err == nil && r == nil {
+			err = fmt.Errorf("slice %d is missing", i+1)
+		}
+		if err != nil {
+			closeReaders(readers)
+			return err
+		}
+		readers = append(readers, r)
+	}
+	err := xorSlices(w, size, readers...)
+	closeReaders(readers)
+	return err
+}
+
+func closeReaders(readers []io.Reader) {
+	for _, r := range readers {
+		r.(io.Closer).Close()
+	}
+}
+
+// RepairLocal restores the data slices that are the only missing data slice
+// of their local group from the group's local parity and the rest of the group.
+// * open - opens a new reader of the i-th (0-based) available slice
+// * writers - writers for missing slices (nil for available ones)
+// Returns the indices of the restored data slices.
+func RepairLocal(md *Metadata, sliceSize int64, open func(i int) (io.ReadCloser, error),
+	writers []io.Writer) (repaired []int, err error) {
+	groups := md.dataGroups()
+	for g := 0; g < md.LocalGroups; g++ {
+		var (
+			lpIdx   = md.Data + md.Parity + g
+			members = groupMembers(g, groups)
+			missing = -1
+			srcs    = make([]int, 0, len(members))
+		)
+		if writers[lpIdx] != nil {
+			continue
+		}
+		for _, i := range members {
+			if writers[i] == nil {
+				srcs = append(srcs, i)
+				continue
+			}
+			if missing != -1 {
+				missing = -2 // more than one slice is missing
+				break
+			}
+			missing = i
+		}
+		if missing < 0 {
+			continue
+		}
+		srcs = append(srcs, lpIdx)
+		if err = xorGroup(writers[missing], sliceSize, srcs, open); err != nil {
+			return
+		}
+		repaired = append(repaired, missing)
+	}
+	return
+}
+
+// RebuildLocalParity computes the missing local parity slices from the data
+// slices (which must be all available or restored at this point).
+// * open - opens a new reader of the i-th (0-based) slice
+// * writers - writers for missing slices (nil for available ones)
+func RebuildLocalParity(md *Metadata, sliceSize int64, open func(i int) (io.ReadCloser, error),
+	writers []io.Writer) error {
+	groups := md.dataGroups()
+	for g := 0; g < md.LocalGroups; g++ {
+		w := writers[md.Data+md.Parity+g]
+		if w == nil {
+			continue
+		}
+		if err := xorGroup(w, sliceSize, groupMembers(g, groups), open); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewinds the data slice reader that has been read to the end
+func rewindReader(reader cmn.ReadOpenCloser) (err error) {
+	switch r := reader.(type) {
+	case *memsys.SliceReader:
+		_, err = r.Seek(0, io.SeekStart)
+	case *memsys.Reader:
+		_, err = r.Seek(0, io.SeekStart)
+	case *cmn.FileSectionHandle:
+		_, err = r.Open()
+	default:
+		cmn.AssertFmt(false, "unsupported reader type", reader)
+	}
+	return
+}
+
+// generateLocalParity calculates local parity slices of the object from its
+// data slices (see generateSlicesToMemory and generateSlicesToDisk).
+// Returns the list of local parity slices, one per local group.
+func generateLocalParity(lom *cluster.LOM, md *Metadata, dataSlices []*slice, sliceSize int64,
+	toDisk bool) ([]*slice, error) {
+	var (
+		conf   = lom.CksumConf()
+		groups = md.dataGroups()
+		slices = make([]*slice, 0, md.LocalGroups)
+	)
+	for g := 0; g < md.LocalGroups; g++ {
+		var (
+			members = groupMembers(g, groups)
+			readers = make([]io.Reader, 0, len(members))
+			file    *os.File
+			writer  io.Writer
+			sl      *slice
+			cksum   *cmn.CksumHash
+		)
+		for _, i := range members {
+			if err := rewindReader(dataSlices[i].reader); err != nil {
+				return slices, err
+			}
+			readers = append(readers, dataSlices[i].reader)
+		}
+		if toDisk {
+			workFQN := fs.CSM.GenContentFQN(lom.FQN, fs.WorkfileType, fmt.Sprintf("ec-write-lrc-%d", g))
+			f, err := lom.CreateFile(workFQN)
+			if err != nil {
+				return slices, err
+			}
+			file, writer = f, f
+			sl = &slice{workFQN: workFQN}
+		} else {
+			sgl := mm.NewSGL(cmn.MinI64(sliceSize, cmn.MiB))
+			writer = sgl
+			sl = &slice{obj: sgl}
+		}
+		slices = append(slices, sl)
+		if conf.Type != cmn.ChecksumNone {
+			cksum = cmn.NewCksumHash(conf.Type)
+			writer = cmn.NewWriterMulti(writer, cksum.H)
+		}
+		err := xorSlices(writer, sliceSize, readers...)
+		if file != nil {
+			file.Close()
+		}
+		if err != nil {
+			return slices, err
+		}
+		if cksum != nil {
+			cksum.Finalize()
+			sl.cksum = cksum.Clone()
+		}
+	}
+	return slices, nil
+}
@@ -279,6 +279,17 @@ func (mgr *Manager) recvResponse(w http.ResponseWriter, hdr transport.Header, ob
 // Encode the object. `wg` is optional - a caller passes WaitGroup when it
 // wants to be notified after the object is done
 func (mgr *Manager) EncodeObject(lom *cluster.LOM, cb ...cluster.OnFinishObj) error {
+	return mgr.encodeObject(lom, ActSplit, cb...)
+}
+
+// ReencodeObject encodes the already encoded object in accordance with the
+// current EC configuration of its bucket. Once the object is encoded, the slices
+// and replicas that do not fit the new layout get removed from the targets.
+func (mgr *Manager) ReencodeObject(lom *cluster.LOM, cb ...cluster.OnFinishObj) error {
+	return mgr.encodeObject(lom, ActReencode, cb...)
+}
+
+func (mgr *Manager) encodeObject(lom *cluster.LOM, act string, cb ...cluster.OnFinishObj) error {
 	if !lom.Bprops().EC.Enabled {
 		return ErrorECDisabled
 	}
@@ -306,8 +317,8 @@ func (mgr *Manager) EncodeObject(lom *cluster.LOM, cb ...cluster.OnFinishObj) er
 	}
 
 	req := &Request{
-		Action: ActSplit,
-		IsCopy: IsECCopy(lom.Size(), &lom.Bprops().EC),
+		Action: act,
+		IsCopy: isECCopy,
 		LOM:    lom,
 	}
 	if len(cb) != 0 {
@@ -15,15 +15,19 @@ import (
 
 // Metadata - EC information stored in metafiles for every encoded object
 type Metadata struct {
-	Size       int64  `json:"size"`                      // obj size (after EC'ing sum size of slices differs from the original)
-	ObjCksum   string `json:"obj_chk"`                   // checksum of the original object
-	ObjVersion string `json:"obj_version,omitempty"`     // object version
-	CksumType  string `json:"slice_ck_type,omitempty"`   // slice checksum type
-	CksumValue string `json:"slice_chk_value,omitempty"` // slice checksum of the slice if EC is used
-	Data       int    `json:"data"`                      // the number of data slices
-	Parity     int    `json:"parity"`                    // the number of parity slices
-	SliceID    int    `json:"sliceid,omitempty"`         // 0 for full replica, 1 to N for slices
-	IsCopy     bool   `json:"copy"`                      // object is replicated(true) or encoded(false)
+	Size         int64         `json:"size"`                      // obj size (after EC'ing sum size of slices differs from the original)
+	ObjCksum     string        `json:"obj_chk"`                   // checksum of the original object
+	ObjCksumType string        `json:"obj_ck_type,omitempty"`     // checksum type of the original object
+	ObjVersion   string        `json:"obj_version,omitempty"`     // object version
+	CksumType    string        `json:"slice_ck_type,omitempty"`   // slice checksum type
+	CksumValue   string        `json:"slice_chk_value,omitempty"` // slice checksum of the slice if EC is used
+	Data         int           `json:"data"`                      // the number of data slices
+	Parity       int           `json:"parity"`                    // the number of parity slices
+	LocalGroups  int           `json:"local_groups,omitempty"`    // the number of local parity groups (LRC)
+	Groups       []int         `json:"groups,omitempty"`          // local parity group of every data slice (LRC)
+	SliceID      int           `json:"sliceid,omitempty"`         // 0 for full replica, 1 to N for slices
+	IsCopy       bool          `json:"copy"`                      // object is replicated(true) or encoded(false)
+	CustomMD     cmn.SimpleKVs `json:"custom_md,omitempty"`       // user-defined metadata of the original object
 }
 
 var (
@@ -100,13 +104,53 @@ func (md *Metadata) Unpack(unpacker *cmn.ByteUnpack) (err error) {
 	if md.ObjCksum, err = unpacker.ReadString(); err != nil {
 		return
 	}
+	if md.ObjCksumType, err = unpacker.ReadString(); err != nil {
+		return
+	}
 	if md.ObjVersion, err = unpacker.ReadString(); err != nil {
 		return
 	}
 	if md.CksumType, err = unpacker.ReadString(); err != nil {
 		return
 	}
-	md.CksumValue, err = unpacker.ReadString()
+	if md.CksumValue, err = unpacker.ReadString(); err != nil {
+		return
+	}
+	if i, err = unpacker.ReadUint16(); err != nil {
+		return
+	}
+	if i > 0 {
+		md.CustomMD = make(cmn.SimpleKVs, i)
+	}
+	for ; i > 0; i-- {
+		var k, v string
+		if k, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		if v, err = unpacker.ReadString(); err != nil {
+			return
+		}
+		md.CustomMD[k] = v
+	}
+	// local parity groups are optional: the targets that do not support
+	// them do not pack the fields (see Pack)
+	if unpacker.Len() == 0 {
+		return
+	}
+	if i, err = unpacker.ReadUint16(); err != nil {
+		return
+	}
+	md.LocalGroups = int(i)
+	if i, err = unpacker.ReadUint16(); err != nil || i == 0 {
+		return
+	}
+	md.Groups = make([]int, i)
+	for k := range md.Groups {
+		if i, err = unpacker.ReadUint16(); err != nil {
+			return
+		}
+		md.Groups[k] = int(i)
+	}
 	return
 }
 
@@ -117,14 +161,32 @@ func (md *Metadata) Pack(packer *cmn.BytePack) {
 	packer.WriteUint16(uint16(md.SliceID))
 	packer.WriteBool(md.IsCopy)
 	packer.WriteString(md.ObjCksum)
+	packer.WriteString(md.ObjCksumType)
 	packer.WriteString(md.ObjVersion)
 	packer.WriteString(md.CksumType)
 	packer.WriteString(md.CksumValue)
+	packer.WriteUint16(uint16(len(md.CustomMD)))
+	for k, v := range md.CustomMD {
+		packer.WriteString(k)
+		packer.WriteString(v)
+	}
+	// must go last (see Unpack)
+	packer.WriteUint16(uint16(md.LocalGroups))
+	packer.WriteUint16(uint16(len(md.Groups)))
+	for _, g := range md.Groups {
+		packer.WriteUint16(uint16(g))
+	}
 }
 
-// int16 is sufficient to keep Data,Parity, and SliceID, so:
-//    int64 + 3*int16 + bool + 4 strings
+// int16 is sufficient to keep Data, Parity, SliceID, the number of custom
+// metadata entries, LocalGroups, and the groups of data slices, so:
+//    int64 + 6*int16 + bool + 5 strings + 2 strings per custom metadata entry +
+//    int16 per data slice
 func (md *Metadata) PackedSize() int {
-	return cmn.SizeofI64 + cmn.SizeofI16*3 + 1 + cmn.SizeofLen*4 +
-		len(md.ObjCksum) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue)
+	size := cmn.SizeofI64 + cmn.SizeofI16*(6+len(md.Groups)) + 1 + cmn.SizeofLen*5 +
+		len(md.ObjCksum) + len(md.ObjCksumType) + len(md.ObjVersion) + len(md.CksumType) + len(md.CksumValue)
+	for k, v := range md.CustomMD {
+		size += cmn.SizeofLen*2 + len(k) + len(v)
+	}
+	return size
 }
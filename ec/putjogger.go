@@ -81,6 +81,10 @@ func (c *putJogger) ec(req *Request) error {
 	case ActSplit:
 		err = c.encode(req)
 		c.parent.stats.updateEncodeTime(time.Since(req.tm), err != nil)
+	case ActReencode:
+		err = c.reencode(req)
+		act = "re-encoding"
+		c.parent.stats.updateEncodeTime(time.Since(req.tm), err != nil)
 	case ActDelete:
 		err = c.cleanup(req)
 		act = "cleaning up"
@@ -119,32 +123,47 @@ func (c *putJogger) encode(req *Request) error {
 		glog.Infof("Encoding %q...", req.LOM.FQN)
 	}
 	var (
-		cksumValue string
-		ecConf     = req.LOM.Bprops().EC
+		cksumType, cksumValue string
+		ecConf                = req.LOM.Bprops().EC
 	)
 	if req.LOM.Cksum() != nil {
-		_, cksumValue = req.LOM.Cksum().Get()
+		cksumType, cksumValue = req.LOM.Cksum().Get()
 	}
 	meta := &Metadata{
-		Size:     req.LOM.Size(),
-		Data:     ecConf.DataSlices,
-		Parity:   ecConf.ParitySlices,
-		IsCopy:   req.IsCopy,
-		ObjCksum: cksumValue,
+		Size:         req.LOM.Size(),
+		Data:         ecConf.DataSlices,
+		Parity:       ecConf.ParitySlices,
+		IsCopy:       req.IsCopy,
+		ObjCksum:     cksumValue,
+		ObjCksumType: cksumType,
+		CustomMD:     req.LOM.CustomMD(),
+	}
+
+	if !req.IsCopy {
+		meta.LocalGroups = ecConf.LocalGroups
 	}
 
 	// calculate the number of targets required to encode the object
 	// For replicated: ParitySlices + original object
-	// For encoded: ParitySlices + DataSlices + original object
+	// For encoded: ParitySlices + DataSlices + LocalGroups + original object
 	reqTargets := ecConf.ParitySlices + 1
 	if !req.IsCopy {
-		reqTargets += ecConf.DataSlices
+		reqTargets += ecConf.DataSlices + ecConf.LocalGroups
 	}
-	targetCnt := len(c.parent.smap.Get().Tmap)
+	smap := c.parent.smap.Get()
+	targetCnt := len(smap.Tmap)
 	if targetCnt < reqTargets {
 		return fmt.Errorf("object %s/%s requires %d targets to encode, only %d found",
 			req.LOM.Bck(), req.LOM.ObjName, reqTargets, targetCnt)
 	}
+	if meta.LocalGroups > 0 {
+		// group the data slices by the zones of their targets (see sendSlices)
+		targets, err := cluster.HrwTargetList(req.LOM.Uname(), smap, reqTargets)
+		if err != nil {
+			return err
+		}
+		meta.Groups = zoneGroups(targets[1:meta.Data+1], meta.LocalGroups)
+	}
 
 	// Save metadata before encoding the object
 	metaFQN, _, err := cluster.HrwFQN(req.LOM.Bck(), MetaType, req.LOM.ObjName)
@@ -164,6 +183,7 @@ func (c *putJogger) encode(req *Request) error {
 	if meta.IsCopy {
 		if err := c.createCopies(req, meta); err != nil {
 			c.cleanup(req)
+			return err
 		}
 		return nil
 	}
@@ -172,6 +192,7 @@ func (c *putJogger) encode(req *Request) error {
 	if slices, err := c.sendSlices(req, meta); err != nil {
 		c.freeSGL(slices)
 		c.cleanup(req)
+		return err
 	}
 	return nil
 }
@@ -211,6 +232,65 @@ func (c *putJogger) cleanup(req *Request) error {
 	return c.parent.reqBundle.Send(transport.Obj{Hdr: hdr, Callback: c.ctSendCallback}, nil)
 }
 
+// re-encodes the object: the new slices and replicas overwrite the old ones
+// and only then the old ones stored by the targets that are not a part of the
+// new layout get removed - a failed re-encoding does not leave the object with
+// fewer slices than before
+func (c *putJogger) reencode(req *Request) error {
+	smap := c.parent.smap.Get()
+	if err := c.encode(req); err != nil {
+		return err
+	}
+	if err := c.cleanupOrphans(req, smap); err != nil {
+		glog.Errorf("Failed to cleanup orphaned slices of %s/%s: %v", req.LOM.Bck(), req.LOM.ObjName, err)
+	}
+	return nil
+}
+
+// Sends delete requests to all the targets that do not get a slice or replica
+// in the new layout. The old layout is not used to find the orphans: the
+// cluster map might have changed since the object was encoded. Excluding the
+// new layout (computed with both the cluster map that was used for encoding
+// and the current one) makes sure that a delete request never races with the
+// new slice sent to the same target.
+func (c *putJogger) cleanupOrphans(req *Request, encSmap *cluster.Smap) error {
+	var (
+		ecConf = req.LOM.Bprops().EC
+		smap   = c.parent.smap.Get()
+		newCnt = ecConf.ParitySlices + 1
+		layout = make(map[string]struct{}, newCnt)
+	)
+	if !req.IsCopy {
+		newCnt += ecConf.DataSlices + ecConf.LocalGroups
+	}
+	for _, sm := range []*cluster.Smap{encSmap, smap} {
+		targets, err := cluster.HrwTargetList(req.LOM.Uname(), sm, newCnt)
+		if err != nil {
+			return err
+		}
+		for _, si := range targets {
+			layout[si.ID()] = struct{}{}
+		}
+	}
+	daemonIDs := make([]string, 0, len(smap.Tmap))
+	for id := range smap.Tmap {
+		if _, ok := layout[id]; !ok {
+			daemonIDs = append(daemonIDs, id)
+		}
+	}
+	if len(daemonIDs) == 0 {
+		return nil
+	}
+	mm := c.parent.t.GetSmallMMSA()
+	request := c.parent.newIntraReq(reqDel, nil).NewPack(mm)
+	hdr := transport.Header{
+		Bck:     req.LOM.Bck().Bck,
+		ObjName: req.LOM.ObjName,
+		Opaque:  request,
+	}
+	return c.parent.sendByDaemonID(daemonIDs, hdr, nil, c.ctSendCallback, true)
+}
+
 // Sends object replicas to targets that must have replicas after the client
 // uploads the main replica
 func (c *putJogger) createCopies(req *Request, metadata *Metadata) error {
@@ -495,10 +575,10 @@ func generateSlicesToDisk(lom *cluster.LOM, dataSlices, paritySlices int) (cmn.R
 // * list of all slices, sent to targets
 func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 	ecConf := req.LOM.Bprops().EC
-	totalCnt := ecConf.ParitySlices + ecConf.DataSlices
+	totalCnt := meta.SliceCnt()
 
 	// totalCnt+1: first node gets the full object, other totalCnt nodes
-	// gets a slice each
+	// gets a slice each (data, parity, and local parity ones)
 	targets, err := cluster.HrwTargetList(req.LOM.Uname(), c.parent.smap.Get(), totalCnt+1)
 	if err != nil {
 		return nil, err
@@ -515,6 +595,13 @@ func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 		objReader, slices, err = generateSlicesToMemory(req.LOM, ecConf.DataSlices, ecConf.ParitySlices)
 	}
 
+	sliceSize := SliceSize(req.LOM.Size(), ecConf.DataSlices)
+	if err == nil && meta.LocalGroups > 0 {
+		var localParity []*slice
+		localParity, err = generateLocalParity(req.LOM, meta, slices[:ecConf.DataSlices], sliceSize, c.toDisk)
+		slices = append(slices, localParity...)
+	}
+
 	if err != nil {
 		freeObject(objReader)
 		c.freeSGL(slices)
@@ -524,7 +611,6 @@ func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 	wg := sync.WaitGroup{}
 	ch := make(chan error, totalCnt)
 	mainObj := &slice{refCnt: *atomic.NewInt32(int32(ecConf.DataSlices)), obj: objReader}
-	sliceSize := SliceSize(req.LOM.Size(), ecConf.DataSlices)
 
 	// transfer a slice to remote target
 	// If the slice is data one - no immediate cleanup is required because this
@@ -550,16 +636,7 @@ func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 		)
 		if slices[i].reader != nil {
 			reader = slices[i].reader
-			switch r := reader.(type) {
-			case *memsys.SliceReader:
-				_, err = r.Seek(0, io.SeekStart)
-			case *memsys.Reader:
-				_, err = r.Seek(0, io.SeekStart)
-			case *cmn.FileSectionHandle:
-				_, err = r.Open()
-			default:
-				cmn.AssertFmt(false, "unsupported reader type", reader)
-			}
+			err = rewindReader(reader)
 		} else {
 			if sgl, ok := slices[i].obj.(*memsys.SGL); ok {
 				reader = memsys.NewReader(sgl)
@@ -613,11 +690,11 @@ func (c *putJogger) sendSlices(req *Request, meta *Metadata) ([]*slice, error) {
 		if ecConf.DataSlices > 1 {
 			s = "s"
 		}
-		glog.Errorf("Error while copying %d slice%s (with parity=%d) for %q: %v",
-			ecConf.DataSlices, s, ecConf.ParitySlices, req.LOM.FQN, err)
+		glog.Errorf("Error while copying %d slice%s (with parity=%d, local=%d) for %q: %v",
+			ecConf.DataSlices, s, ecConf.ParitySlices, meta.LocalGroups, req.LOM.FQN, err)
 	} else if glog.V(4) {
-		glog.Infof("EC created %d slices (with %d parity) for %q: %v",
-			ecConf.DataSlices, ecConf.ParitySlices, req.LOM.FQN, err)
+		glog.Infof("EC created %d slices (with %d parity, %d local) for %q: %v",
+			ecConf.DataSlices, ecConf.ParitySlices, meta.LocalGroups, req.LOM.FQN, err)
 	}
 
 	return slices, nil
@@ -100,7 +100,7 @@ func (r *XactPut) Run() (err error) {
 		case req := <-r.ecCh:
 			lastAction = time.Now()
 			switch req.Action {
-			case ActSplit:
+			case ActSplit, ActReencode:
 				r.stats.updateEncode(req.LOM.Size())
 			case ActDelete:
 				r.stats.updateDelete()
@@ -210,7 +210,7 @@ func (r *XactPut) dispatchRequest(req *Request) {
 		return
 	}
 
-	if req.Action == ActDelete || req.Action == ActSplit {
+	if req.Action == ActDelete || req.Action == ActSplit || req.Action == ActReencode {
 		r.IncPending()
 		jogger, ok := r.putJoggers[req.LOM.ParsedFQN.MpathInfo.Path]
 		cmn.AssertMsg(ok, "Invalid mountpath given in EC request")
@@ -214,6 +214,8 @@ func (r *XactRespond) DispatchResp(iReq intraReq, bck *cluster.Bck, objName stri
 			bdir = mi.MakePathBck(lom.Bck().Bck)
 		}
 
+		r.removeStaleCT(bck, objName, iReq.isSlice)
+
 		// save slice/object
 		tmpFQN := fs.CSM.GenContentFQN(objFQN, fs.WorkfileType, "ec")
 		buf, slab := mm.Alloc()
@@ -223,6 +225,10 @@ func (r *XactRespond) DispatchResp(iReq intraReq, bck *cluster.Bck, objName stri
 			lom.SetVersion(objAttrs.Version)
 			lom.SetAtimeUnix(objAttrs.Atime)
 			lom.SetSize(objAttrs.Size)
+			lom.SetCustomMD(objAttrs.CustomMD)
+			lom.SetRetainUntil(objAttrs.RetainUntil)
+			lom.SetLegalHold(objAttrs.LegalHold)
+			lom.SetCtimeUnix(objAttrs.Ctime)
 			if objAttrs.CksumType != "" {
 				lom.SetCksum(cmn.NewCksum(objAttrs.CksumType, objAttrs.CksumValue))
 			}
@@ -251,6 +257,31 @@ func (r *XactRespond) DispatchResp(iReq intraReq, bck *cluster.Bck, objName stri
 	}
 }
 
+// The object may have been re-encoded (see ActReencode) from replicas into
+// slices or vice versa: removes the replica (slice) stored by this target
+// before saving the received slice (replica).
+func (r *XactRespond) removeStaleCT(bck *cluster.Bck, objName string, isSlice bool) {
+	metaFQN, _, err := cluster.HrwFQN(bck, MetaType, objName)
+	if err != nil {
+		return
+	}
+	md, err := LoadMetadata(metaFQN)
+	if err != nil || md.IsCopy != isSlice {
+		return
+	}
+	staleType := SliceType
+	if isSlice {
+		staleType = fs.ObjectType
+	}
+	staleFQN, _, err := cluster.HrwFQN(bck, staleType, objName)
+	if err != nil {
+		return
+	}
+	if err := os.Remove(staleFQN); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("Failed to remove stale %q: %v", staleFQN, err)
+	}
+}
+
 func (r *XactRespond) Stop(error) { r.Abort() }
 
 func (r *XactRespond) stop() {
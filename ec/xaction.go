@@ -140,6 +140,7 @@ func (r *xactECBase) newIntraReq(act intraReqType, meta *Metadata) *intraReq {
 
 func (r *xactECBase) newSliceResponse(md *Metadata, attrs *transport.ObjectAttrs, fqn string) (reader cmn.ReadOpenCloser, err error) {
 	attrs.Version = md.ObjVersion
+	attrs.CustomMD = md.CustomMD
 	attrs.CksumType = md.CksumType
 	attrs.CksumValue = md.CksumValue
 
@@ -381,9 +382,13 @@ func (r *xactECBase) writeRemote(daemonIDs []string, lom *cluster.LOM, src *data
 	mm := r.t.GetSmallMMSA()
 	putData := req.NewPack(mm)
 	objAttrs := transport.ObjectAttrs{
-		Size:    src.size,
-		Version: lom.Version(),
-		Atime:   lom.AtimeUnix(),
+		Size:        src.size,
+		Version:     lom.Version(),
+		Atime:       lom.AtimeUnix(),
+		CustomMD:    lom.CustomMD(),
+		RetainUntil: lom.RetainUntil(),
+		LegalHold:   lom.LegalHold(),
+		Ctime:       lom.CtimeUnix(),
 	}
 	if src.metadata != nil && src.metadata.SliceID != 0 {
 		// for a slice read everything from slice's metadata
@@ -25,6 +25,7 @@ const (
 	WorkfileColdget = "cold"   // object GET: coldget
 	WorkfilePut     = "put"    // object PUT
 	WorkfileAppend  = "append" // object APPEND
+	WorkfileMpt     = "mpt"    // S3 multipart upload part
 	WorkfileFSHC    = "fshc"   // FSHC test file
 )
 
@@ -0,0 +1,58 @@
+// Package fs provides mountpath and FQN abstractions and methods to resolve/map stored content
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package fs
+
+import (
+	"runtime"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+)
+
+const ThrottleNumObjects = 16 // unit of self-throttling
+
+type (
+	// Throttle self-throttles a mountpath jogger that walks (and processes) the objects:
+	// every ThrottleNumObjects objects it checks the utilization of the mountpath and
+	// sleeps if the latter is busy; otherwise, it yields the processor.
+	Throttle struct {
+		Mpath  string
+		Config *cmn.Config
+		Strict bool // sleep longer when busy and also above the low watermark (e.g., scrubbing)
+		num    int64
+	}
+	// the xaction that runs the jogger
+	abortable interface {
+		Aborted() bool
+		String() string
+	}
+)
+
+// YieldTerm is called prior to processing the next object; returns error if the
+// xaction has been aborted.
+func (t *Throttle) YieldTerm(xact abortable) error {
+	if xact.Aborted() {
+		return cmn.NewAbortedError(xact.String())
+	}
+	t.num++
+	if (t.num % ThrottleNumObjects) != 0 {
+		runtime.Gosched()
+		return nil
+	}
+	var (
+		curr = Mountpaths.GetMpathUtil(t.Mpath, time.Now())
+		conf = &t.Config.Disk
+	)
+	switch {
+	case curr >= conf.DiskUtilHighWM && t.Strict:
+		time.Sleep(cmn.ThrottleSleepAvg)
+	case curr >= conf.DiskUtilHighWM, curr > conf.DiskUtilLowWM && t.Strict:
+		time.Sleep(cmn.ThrottleSleepMin)
+	}
+	return nil
+}
+
+// Num returns the number of objects walked so far.
+func (t *Throttle) Num() int64 { return t.num }
@@ -32,6 +32,7 @@ require (
 	github.com/urfave/cli v1.22.4
 	github.com/valyala/fasthttp v1.11.0
 	github.com/vbauerster/mpb/v4 v4.10.1
+	github.com/zeebo/blake3 v0.2.3
 	golang.org/x/image v0.0.0-20200119044424-58c23975cae1 // indirect
 	golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
 	golang.org/x/sys v0.0.0-20191120155948-bd437916bb0e
@@ -0,0 +1,226 @@
+// Package lifecycle evaluates per-bucket lifecycle rules: expires (deletes) old objects
+// and evicts cached cloud objects that are either not accessed or not current.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// ============================================= Summary ===========================================
+//
+// Lifecycle rules are configured on a per-bucket basis (see cmn.LifecycleConf) and get
+// evaluated by the lifecycle xaction. The xaction runs periodically (daily) on each storage
+// target for each bucket that has lifecycle enabled; it can be also started and stopped via API.
+//
+// The xaction runs a jogger per mountpath. Each jogger walks the bucket's objects and
+// applies the rules that match object names:
+//   - expire: delete objects created more than `expire_days` ago; for cloud buckets,
+//     the objects get deleted from the Cloud as well;
+//   - evict: remove cached cloud objects that were not accessed for `evict_days`;
+//   - noncurrent: remove cached cloud objects whose versions differ from the Cloud ones.
+//
+// Object creation time is stored in its metadata and is preserved when the object gets
+// migrated (rebalance, resilver, EC restore), so that the migration does not postpone
+// the expiration. Objects get removed via the target's delete path, which also cleans up
+// their EC slices and replicas. Objects under retention (WORM) are never removed.
+//
+// ============================================= Summary ===========================================
+
+const day = 24 * time.Hour
+
+type (
+	Xaction struct {
+		cmn.XactBase
+		cmn.MountpathXact
+		t      cluster.Target
+		ctx    context.Context
+		doneCh chan struct{}
+		stats  struct {
+			expired, evicted, noncurrent counter
+		}
+	}
+	counter struct {
+		cnt, size atomic.Int64
+	}
+	jogger struct { // one per mountpath
+		parent    *Xaction
+		mpathInfo *fs.MountpathInfo
+		config    *cmn.Config
+		throttle  fs.Throttle
+	}
+)
+
+func NewXaction(t cluster.Target, bck cmn.Bck) *Xaction {
+	return &Xaction{
+		XactBase: *cmn.NewXactBaseWithBucket("", cmn.ActLifecycle, bck),
+		t:        t,
+		ctx:      context.Background(),
+	}
+}
+
+func (r *Xaction) Run() {
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		config            = cmn.GCO.Get()
+	)
+	glog.Infoln(r.String())
+	r.doneCh = make(chan struct{}, len(availablePaths))
+	for _, mpathInfo := range availablePaths {
+		j := &jogger{
+			parent:    r,
+			mpathInfo: mpathInfo,
+			config:    config,
+			throttle:  fs.Throttle{Mpath: mpathInfo.Path, Config: config},
+		}
+		go j.jog()
+	}
+	for range availablePaths {
+		<-r.doneCh
+	}
+	if !r.Finished() { // not aborted
+		r.EndTime(time.Now())
+	}
+	glog.Infof("%s: expired %d, evicted %d, noncurrent %d", r, r.stats.expired.cnt.Load(),
+		r.stats.evicted.cnt.Load(), r.stats.noncurrent.cnt.Load())
+}
+
+func (r *Xaction) Stats() *stats.LifecycleStats {
+	s := &stats.LifecycleStats{BaseXactStats: *stats.NewXactStats(r)}
+	s.Ext.ExpiredCount, s.Ext.ExpiredSize = r.stats.expired.cnt.Load(), r.stats.expired.size.Load()
+	s.Ext.EvictedCount, s.Ext.EvictedSize = r.stats.evicted.cnt.Load(), r.stats.evicted.size.Load()
+	s.Ext.NoncurrentCount, s.Ext.NoncurrentSize = r.stats.noncurrent.cnt.Load(), r.stats.noncurrent.size.Load()
+	return s
+}
+
+//
+// mpath jogger
+//
+
+func (j *jogger) jog() {
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      j.parent.Bck(),
+		CTs:      []string{fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		if errors.As(err, &cmn.AbortedError{}) {
+			glog.Infof("%s: stopping traversal: %v", j.mpathInfo, err)
+		} else {
+			glog.Errorf("%s: failed to traverse, err: %v", j.mpathInfo, err)
+		}
+	}
+	j.parent.doneCh <- struct{}{}
+}
+
+func (j *jogger) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.throttle.YieldTerm(j.parent); err != nil {
+		return err
+	}
+	lom := &cluster.LOM{T: j.parent.t, FQN: fqn}
+	if err := lom.Init(j.parent.Bck(), j.config); err != nil {
+		return nil
+	}
+	conf := &lom.Bprops().Lifecycle
+	if !conf.Enabled {
+		return nil
+	}
+	rules := conf.Match(lom.ObjName)
+	if len(rules) == 0 {
+		return nil
+	}
+	if err := lom.Load(false); err != nil {
+		return nil
+	}
+	if lom.IsCopy() || !lom.IsHRW() {
+		return nil
+	}
+	if err := lom.AllowModify(false /*bypass*/); err != nil {
+		return nil // retained (WORM)
+	}
+	if cntr, expire := j.evaluate(lom, rules); cntr != nil {
+		j.remove(lom, cntr, expire)
+	}
+	return nil
+}
+
+// returns the counter of the action to take, if any
+func (j *jogger) evaluate(lom *cluster.LOM, rules []cmn.LifecycleRule) (cntr *counter, expire bool) {
+	var (
+		now    = time.Now()
+		remote = lom.Bck().IsRemote()
+	)
+	for _, rule := range rules {
+		if rule.ExpireDays > 0 {
+			if ctime, err := creationTime(lom); err == nil && now.Sub(ctime) > time.Duration(rule.ExpireDays)*day {
+				return &j.parent.stats.expired, true
+			}
+		}
+		if !remote {
+			continue
+		}
+		if rule.EvictDays > 0 && now.Sub(lom.Atime()) > time.Duration(rule.EvictDays)*day {
+			return &j.parent.stats.evicted, false
+		}
+		if rule.DeleteNoncurrent && lom.Version() != "" {
+			vchanged, err, errCode := j.parent.t.CheckCloudVersion(j.parent.ctx, lom)
+			if vchanged || errCode == http.StatusNotFound {
+				return &j.parent.stats.noncurrent, false
+			}
+			if err != nil {
+				glog.Warning(err)
+			}
+		}
+	}
+	return nil, false
+}
+
+// creation time is stored in the object's metadata; objects written prior to that
+// fall back to the file's mtime
+func creationTime(lom *cluster.LOM) (time.Time, error) {
+	if ctime := lom.CtimeUnix(); ctime != 0 {
+		return time.Unix(0, ctime), nil
+	}
+	finfo, err := os.Stat(lom.FQN)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return finfo.ModTime(), nil
+}
+
+// goes through the target's delete path that (in addition to removing the object)
+// enforces retention and cleans up EC slices, replicas, and cross-cluster replicas
+func (j *jogger) remove(lom *cluster.LOM, cntr *counter, expire bool) {
+	size := lom.Size()
+	if err, errCode := j.parent.t.DeleteObject(j.parent.ctx, lom, !expire /*evict*/); err != nil {
+		if errCode != http.StatusNotFound && !os.IsNotExist(err) {
+			glog.Errorf("%s: failed to remove, err: %v", lom, err)
+		}
+		return
+	}
+	if glog.FastV(4, glog.SmoduleAIS) {
+		glog.Infof("%s: removed (expired: %t)", lom, expire)
+	}
+	cntr.cnt.Inc()
+	cntr.size.Add(size)
+	j.parent.ObjectsInc()
+	j.parent.BytesAdd(size)
+}
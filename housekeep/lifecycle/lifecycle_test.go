@@ -0,0 +1,116 @@
+// Package lifecycle evaluates per-bucket lifecycle rules: expires (deletes) old objects
+// and evicts cached cloud objects that are either not accessed or not current.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package lifecycle
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+const (
+	basePath   = "/tmp/lifecycle-tests"
+	bucketName = "lifecycle-bck"
+	fileSize   = cmn.KiB
+)
+
+// deletes objects the way the target does, minus EC and replication cleanup
+type targetMock struct {
+	*cluster.TargetMock
+}
+
+func (*targetMock) DeleteObject(_ context.Context, lom *cluster.LOM, _ bool) (error, int) {
+	lom.Lock(true)
+	defer lom.Unlock(true)
+	if err := lom.Load(false); err != nil {
+		return err, http.StatusNotFound
+	}
+	if err := lom.AllowModify(false); err != nil {
+		return err, http.StatusForbidden
+	}
+	return lom.Remove(), 0
+}
+
+// age < 0: creation time is not stored (object written by an older version)
+func saveFile(t *testing.T, tMock cluster.Target, fqn string, age, mtimeAge time.Duration, retainUntil int64) {
+	buf := make([]byte, fileSize)
+	_, err := cmn.SaveReader(fqn, rand.Reader, buf, cmn.ChecksumNone, fileSize, "")
+	tassert.CheckFatal(t, err)
+	lom := &cluster.LOM{T: tMock, FQN: fqn}
+	tassert.CheckFatal(t, lom.Init(cmn.Bck{}))
+	lom.SetSize(fileSize)
+	lom.SetRetainUntil(retainUntil)
+	if age >= 0 {
+		lom.SetCtimeUnix(time.Now().Add(-age).UnixNano())
+	}
+	tassert.CheckFatal(t, lom.Persist())
+	mtime := time.Now().Add(-mtimeAge)
+	tassert.CheckFatal(t, os.Chtimes(fqn, mtime, mtime))
+}
+
+func TestLifecycleExpire(t *testing.T) {
+	cluster.InitTarget()
+	tassert.CheckFatal(t, cmn.CreateDir(basePath))
+	defer os.RemoveAll(basePath)
+	fs.InitMountedFS()
+	fs.Mountpaths.DisableFsIDCheck()
+	tassert.CheckFatal(t, fs.Mountpaths.Add(basePath))
+	defer fs.Mountpaths.Remove(basePath)
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+	var (
+		bmdMock = cluster.NewBaseBownerMock()
+		tMock   = &targetMock{cluster.NewTargetMock(bmdMock)}
+		bck     = cmn.Bck{Name: bucketName, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		props   = &cmn.BucketProps{
+			Cksum:       cmn.CksumConf{Type: cmn.ChecksumNone},
+			Retention:   cmn.RetentionConf{Enabled: true, Mode: cmn.RetentionGovernance, Period: "0s"},
+			Lifecycle:   cmn.LifecycleConf{Enabled: true, Rules: []cmn.LifecycleRule{{Prefix: "tmp/", ExpireDays: 1}}},
+			AccessAttrs: cmn.AllAccess(),
+		}
+		week     = 7 * 24 * time.Hour
+		retained = time.Now().Add(time.Hour).UnixNano()
+	)
+	bmdMock.Add(cluster.NewBck(bucketName, cmn.ProviderAIS, cmn.NsGlobal, props))
+	mpaths, _ := fs.Mountpaths.Get()
+	dir := mpaths[basePath].MakePathCT(bck, fs.ObjectType)
+
+	files := map[string]bool{ // name => expected to remain
+		"tmp/old":      false,
+		"tmp/new":      true,
+		"tmp/retained": true,
+		"tmp/migrated": false, // mtime gets reset by migration
+		"tmp/legacy":   false, // no creation time: mtime is used
+		"keep/old":     true,
+	}
+	saveFile(t, tMock, filepath.Join(dir, "tmp/old"), week, week, 0)
+	saveFile(t, tMock, filepath.Join(dir, "tmp/new"), 0, 0, 0)
+	saveFile(t, tMock, filepath.Join(dir, "tmp/retained"), week, week, retained)
+	saveFile(t, tMock, filepath.Join(dir, "tmp/migrated"), week, 0, 0)
+	saveFile(t, tMock, filepath.Join(dir, "tmp/legacy"), -1, week, 0)
+	saveFile(t, tMock, filepath.Join(dir, "keep/old"), week, week, 0)
+
+	xact := NewXaction(tMock, bck)
+	xact.Run()
+
+	for name, remain := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		tassert.Errorf(t, (err == nil) == remain, "%s: expected to remain %t, err: %v", name, remain, err)
+	}
+	s := xact.Stats()
+	tassert.Errorf(t, s.Ext.ExpiredCount == 3 && s.Ext.ExpiredSize == 3*fileSize, "unexpected stats: %+v", s.Ext)
+	tassert.Errorf(t, s.Finished() && !s.Aborted(), "expected finished xaction")
+}
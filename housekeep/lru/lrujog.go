@@ -120,6 +120,9 @@ func (lctx *lruCtx) walk(fqn string, de fs.DirEntry) error {
 	if err = lom.Bck().Allow(cmn.AccessObjDELETE); err != nil {
 		return nil
 	}
+	if err = lom.AllowModify(false /*bypass*/); err != nil {
+		return nil // retained (WORM)
+	}
 
 	// Partial optimization: do nothing if the heap's curSize >= totalSize and
 	// the file is more recent then the the heap's newest.
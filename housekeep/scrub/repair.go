@@ -0,0 +1,212 @@
+// Package scrub verifies the integrity of stored objects, their copies and EC slices,
+// and repairs the corrupted ones from local mirrors, EC slices, or the Cloud.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+)
+
+// Sources of the restored content (see: RepairObject)
+const (
+	RepairedFromMirror = "mirror"
+	RepairedFromEC     = "ec"
+	RepairedFromCloud  = "cloud"
+)
+
+// RepairObject restores the content of the corrupted object (at its default
+// location) from a healthy local copy or, if there is none, from EC slices or
+// replicas stored by other targets or, for Cloud buckets, by fetching the object
+// from the Cloud. Returns the source of the restored content.
+// NOTE: the object must not be locked by the caller.
+func RepairObject(t cluster.Target, lom *cluster.LOM) (source string, err error) {
+	buf, slab := t.GetMMSA().Alloc()
+	defer slab.Free(buf)
+
+	lom.Lock(true)
+	source, err = repairLocal(lom, buf)
+	if err == nil {
+		// the other copies may be corrupted as well
+		if _, _, errCopies := repairCopies(lom, buf); errCopies != nil {
+			glog.Errorf("%s: failed to repair copies, err: %v", lom, errCopies)
+		}
+	}
+	lom.Unlock(true)
+	if err == nil || !lom.Bck().IsRemote() {
+		return
+	}
+	if errCold, _ := t.GetCold(context.Background(), lom, true /*prefetch*/); errCold != nil {
+		return "", fmt.Errorf("%v; %v", err, errCold)
+	}
+	return RepairedFromCloud, nil
+}
+
+// NOTE: the object must be exclusively locked by the caller.
+func repairLocal(lom *cluster.LOM, buf []byte) (source string, err error) {
+	var expected *cmn.Cksum // checksum of the original content, if known
+	lom.Uncache()
+	if err := lom.Load(false); err == nil {
+		expected = lom.Cksum()
+	}
+
+	// 1. local copies (including the ones that are not in the object's metadata)
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, mpathInfo := range availablePaths {
+		fqn := fs.CSM.FQN(mpathInfo, lom.Bck().Bck, fs.ObjectType, lom.ObjName)
+		if fqn == lom.FQN {
+			continue
+		}
+		if err := fs.Access(fqn); err != nil {
+			continue
+		}
+		src := lom.Clone(fqn)
+		if err := src.Init(lom.Bck().Bck, lom.Config()); err != nil {
+			continue
+		}
+		if err := src.Load(false); err != nil || src.Cksum() == nil {
+			continue
+		}
+		if expected != nil && !src.Cksum().Equal(expected) {
+			continue // different version
+		}
+		if err := verifyFile(src, fqn); err != nil {
+			glog.Errorf("%s: copy %s: %v", lom, fqn, err)
+			continue
+		}
+		if _, err := src.CopyObject(lom.FQN, buf); err != nil {
+			glog.Errorf("%s: failed to restore from copy %s, err: %v", lom, fqn, err)
+			continue
+		}
+		lom.Uncache()
+		if err := lom.Load(false); err != nil {
+			return "", err
+		}
+		return RepairedFromMirror, nil
+	}
+
+	// 2. EC
+	if ec.ECM == nil || !lom.ECEnabled() {
+		return "", fmt.Errorf("%s: no healthy copies to restore from", lom)
+	}
+	if err = ec.ECM.RestoreObject(lom); err != nil {
+		return "", fmt.Errorf("%s: no healthy copies to restore from, failed to EC-restore: %v", lom, err)
+	}
+	lom.Uncache()
+	if err = lom.Load(false); err != nil {
+		return "", err
+	}
+	if expected != nil {
+		if cksum, err := lom.ComputeCksum(expected.Type()); err != nil || !cksum.Equal(expected) {
+			return "", fmt.Errorf("%s: EC-restored content does not match the original checksum %s", lom, expected)
+		}
+	}
+	return RepairedFromEC, nil
+}
+
+// repairCopies restores the missing and corrupted copies of the (healthy) object
+// and, for the mirrored buckets, the configured number of copies. Returns the number
+// of copies that failed verification and the number of (re)created copies.
+// NOTE: the object must be exclusively locked by the caller.
+func repairCopies(lom *cluster.LOM, buf []byte) (bad, restored int, err error) {
+	lom.Uncache()
+	if err = lom.Load(false); err != nil {
+		return
+	}
+	if lom.Cksum() == nil {
+		return
+	}
+	badFQNs := make([]string, 0, lom.NumCopies())
+	for copyFQN := range lom.GetCopies() {
+		if copyFQN != lom.FQN && verifyFile(lom, copyFQN) != nil {
+			badFQNs = append(badFQNs, copyFQN)
+		}
+	}
+	bad = len(badFQNs)
+	if err = verifyFile(lom, lom.FQN); err != nil {
+		return
+	}
+	lom.CloneCopiesMd()
+	for _, copyFQN := range badFQNs {
+		if _, err = lom.CopyObject(copyFQN, buf); err != nil {
+			return
+		}
+		restored++
+	}
+	conf := lom.MirrorConf()
+	if !conf.Enabled {
+		return
+	}
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, mpathInfo := range availablePaths {
+		if lom.NumCopies() >= int(conf.Copies) {
+			break
+		}
+		copyFQN := fs.CSM.FQN(mpathInfo, lom.Bck().Bck, fs.ObjectType, lom.ObjName)
+		if _, ok := lom.GetCopies()[copyFQN]; ok || copyFQN == lom.FQN {
+			continue
+		}
+		if _, err = lom.CopyObject(copyFQN, buf); err != nil {
+			return
+		}
+		restored++
+	}
+	lom.Uncache()
+	return
+}
+
+// verifyFile computes the checksum of the object's (or its copy's) content and
+// compares it with the object's checksum.
+func verifyFile(lom *cluster.LOM, fqn string) error {
+	var (
+		expected = lom.Cksum()
+		clone    = lom.Clone(fqn)
+	)
+	cksum, err := clone.ComputeCksum(expected.Type())
+	if err != nil {
+		return err
+	}
+	if !cksum.Equal(expected) {
+		return cmn.NewBadDataCksumError(&cksum.Cksum, expected, fqn)
+	}
+	return nil
+}
+
+func verifySlice(fqn string, expected *cmn.Cksum, buf []byte) (int64, error) {
+	file, err := os.Open(fqn)
+	if err != nil {
+		return 0, err
+	}
+	n, cksum, err := cmn.CopyAndChecksum(ioutil.Discard, file, buf, expected.Type())
+	file.Close()
+	if err != nil {
+		return 0, err
+	}
+	if !cksum.Equal(expected) {
+		return 0, cmn.NewBadDataCksumError(&cksum.Cksum, expected, fqn)
+	}
+	return n, nil
+}
+
+// isCorrupted returns true if the object cannot be loaded or validated because of
+// its damaged content or metadata (rather than, eg., being removed in the meantime).
+func isCorrupted(err error) bool {
+	if cmn.IsObjNotExist(err) || cmn.IsErrBucketNought(err) {
+		return false
+	}
+	if errors.As(err, &cmn.ObjDefunctErr{}) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,482 @@
+// Package scrub verifies the integrity of stored objects, their copies and EC slices,
+// and repairs the corrupted ones from local mirrors, EC slices, or the Cloud.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// ============================================= Summary ===========================================
+//
+// The scrubber is a global xaction that re-reads all the data stored by the target and
+// verifies it: objects and their local copies against the checksums stored with the objects'
+// metadata, and EC slices against the checksums stored in their EC metafiles.
+//
+// The xaction runs a jogger per mountpath. Each jogger traverses the mountpath's buckets
+// in the sorted order and, for each:
+//   - corrupted object: restores it from a healthy local copy, from EC slices (or replicas)
+//     on other targets or, for Cloud buckets, by fetching it from the Cloud (see RepairObject);
+//   - corrupted or missing copy: restores it from the (healthy) object; for the mirrored
+//     buckets also restores the configured number of copies;
+//   - corrupted EC slice: removes the slice along with its metafile so that it is not used
+//     to restore the object - the slice gets recreated when the object is restored or encoded.
+//
+// The joggers throttle themselves based on the utilization of their mountpaths and
+// periodically checkpoint the last verified file into the mountpath's marker, so that
+// the aborted (or interrupted by restart) scrubbing resumes from where it has stopped.
+// Upon completing the traversal, the jogger records the time next to the marker - the
+// target uses it to schedule the next run (see LastRun).
+//
+// ============================================= Summary ===========================================
+
+const (
+	markerName         = ".scrub_marker"
+	lastRunName        = ".scrub_last_run"
+	checkpointInterval = 30 * time.Second
+)
+
+type (
+	Xaction struct {
+		cmn.XactBase
+		cmn.MountpathXact
+		t      cluster.Target
+		doneCh chan struct{}
+		stats  struct {
+			slices, badObjs, badCopies, badSlices atomic.Int64
+			mirror, ec, cloud, copies, unrepaired atomic.Int64
+		}
+	}
+	jogger struct { // one per mountpath
+		parent     *Xaction
+		mpathInfo  *fs.MountpathInfo
+		config     *cmn.Config
+		buf        []byte
+		resume     string    // files up to (and including) this one have been verified by the previous run
+		last       string    // the last verified file
+		checkpoint time.Time // when the last verified file has been persisted
+		throttle   fs.Throttle
+	}
+	root struct {
+		dir, contentType string
+	}
+)
+
+func NewXaction(id string, t cluster.Target) *Xaction {
+	return &Xaction{XactBase: *cmn.NewXactBase(cmn.XactBaseID(id), cmn.ActScrub), t: t}
+}
+
+func (r *Xaction) Run() {
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		config            = cmn.GCO.Get()
+	)
+	glog.Infoln(r.String())
+	r.doneCh = make(chan struct{}, len(availablePaths))
+	for _, mpathInfo := range availablePaths {
+		j := &jogger{
+			parent:    r,
+			mpathInfo: mpathInfo,
+			config:    config,
+			throttle:  fs.Throttle{Mpath: mpathInfo.Path, Config: config, Strict: true},
+		}
+		go j.jog()
+	}
+	for range availablePaths {
+		<-r.doneCh
+	}
+	if !r.Finished() { // not aborted
+		r.EndTime(time.Now())
+	}
+	glog.Infof("%s: verified %d objects and %d EC slices, corrupted: %d objects (%d unrepaired), %d copies, %d slices",
+		r, r.ObjectsCnt(), r.stats.slices.Load(), r.stats.badObjs.Load(), r.stats.unrepaired.Load(),
+		r.stats.badCopies.Load(), r.stats.badSlices.Load())
+}
+
+func (r *Xaction) Stats() *stats.ScrubStats {
+	s := &stats.ScrubStats{BaseXactStats: *stats.NewXactStats(r)}
+	s.Ext.SliceCount = r.stats.slices.Load()
+	s.Ext.BadObjCount, s.Ext.BadCopyCount = r.stats.badObjs.Load(), r.stats.badCopies.Load()
+	s.Ext.BadSliceCount = r.stats.badSlices.Load()
+	s.Ext.RepairedMirror, s.Ext.RepairedEC = r.stats.mirror.Load(), r.stats.ec.Load()
+	s.Ext.RepairedCloud, s.Ext.RepairedCopies = r.stats.cloud.Load(), r.stats.copies.Load()
+	s.Ext.Unrepaired = r.stats.unrepaired.Load()
+	return s
+}
+
+// MarkerExists returns true if the scrubbing has been interrupted and should be resumed.
+func MarkerExists() bool {
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, mpathInfo := range availablePaths {
+		if err := fs.Access(markerPath(mpathInfo)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LastRun returns the time the last complete scrubbing of all (available) mountpaths
+// has finished - zero if any of the mountpaths has not been completely scrubbed yet.
+func LastRun() (last time.Time) {
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, mpathInfo := range availablePaths {
+		finished := loadLastRun(mpathInfo)
+		if finished.IsZero() {
+			return time.Time{}
+		}
+		if last.IsZero() || finished.Before(last) {
+			last = finished
+		}
+	}
+	return
+}
+
+//
+// mpath jogger
+//
+
+func (j *jogger) jog() {
+	defer func() { j.parent.doneCh <- struct{}{} }()
+
+	j.resume = loadMarker(j.mpathInfo)
+	if j.resume != "" {
+		glog.Infof("%s: resuming scrub after %q", j.mpathInfo, j.resume)
+	}
+	buf, slab := j.parent.t.GetMMSA().Alloc()
+	j.buf = buf
+	defer slab.Free(buf)
+	j.checkpoint = time.Now()
+
+	for _, rt := range j.roots() {
+		callback := j.walkObj
+		if rt.contentType == ec.SliceType {
+			callback = j.walkSlice
+		}
+		opts := &fs.Options{Dir: rt.dir, Callback: callback, Sorted: true}
+		if err := fs.Walk(opts); err != nil {
+			if errors.As(err, &cmn.AbortedError{}) {
+				glog.Infof("%s: stopping traversal: %v", j.mpathInfo, err)
+				j.saveMarker()
+				return
+			}
+			glog.Errorf("%s: failed to traverse %s, err: %v", j.mpathInfo, rt.dir, err)
+		}
+	}
+	if err := os.Remove(markerPath(j.mpathInfo)); err != nil && !os.IsNotExist(err) {
+		glog.Errorf("%s: failed to remove scrub marker, err: %v", j.mpathInfo, err)
+	}
+	saveLastRun(j.mpathInfo, time.Now())
+}
+
+// roots returns the directories of objects and EC slices of all buckets in the
+// order of the (sorted) traversal.
+func (j *jogger) roots() []root {
+	roots := make([]root, 0, 16)
+	j.parent.t.GetBowner().Get().Range(nil, nil, func(bck *cluster.Bck) bool {
+		for _, ct := range []string{fs.ObjectType, ec.SliceType} {
+			roots = append(roots, root{dir: j.mpathInfo.MakePathCT(bck.Bck, ct), contentType: ct})
+		}
+		return false
+	})
+	sort.Slice(roots, func(i, k int) bool { return fqnLess(roots[i].dir, roots[k].dir) })
+	return roots
+}
+
+// skip returns true for the files (and directories) that have been already
+// verified by the previous run.
+func (j *jogger) skip(fqn string, de fs.DirEntry) (skip bool, err error) {
+	if j.resume == "" {
+		return
+	}
+	if de.IsDir() {
+		if fqnLess(fqn, j.resume) && !strings.HasPrefix(j.resume, fqn+"/") {
+			err = filepath.SkipDir
+		}
+		return
+	}
+	if !fqnLess(j.resume, fqn) {
+		return true, nil
+	}
+	j.resume = "" // passed the checkpoint
+	return
+}
+
+func (j *jogger) walkObj(fqn string, de fs.DirEntry) error {
+	if skip, err := j.skip(fqn, de); skip || err != nil || de.IsDir() {
+		return err
+	}
+	if err := j.throttle.YieldTerm(j.parent); err != nil {
+		return err
+	}
+	lom := &cluster.LOM{T: j.parent.t, FQN: fqn}
+	if err := lom.Init(cmn.Bck{}, j.config); err == nil && lom.CksumConf().Type != cmn.ChecksumNone {
+		if lom.IsHRW() {
+			j.scrubObj(lom)
+		} else {
+			j.scrubCopy(lom)
+		}
+	}
+	j.done(fqn)
+	return nil
+}
+
+func (j *jogger) walkSlice(fqn string, de fs.DirEntry) error {
+	if skip, err := j.skip(fqn, de); skip || err != nil || de.IsDir() {
+		return err
+	}
+	if err := j.throttle.YieldTerm(j.parent); err != nil {
+		return err
+	}
+	j.scrubSlice(fqn)
+	j.done(fqn)
+	return nil
+}
+
+func (j *jogger) scrubObj(lom *cluster.LOM) {
+	r := j.parent
+	lom.Lock(false)
+	err := lom.Load(false)
+	if err == nil {
+		err = lom.ValidateMetaChecksum()
+	}
+	if err == nil {
+		err = lom.ValidateContentChecksum()
+	}
+	lom.Unlock(false)
+	if err == nil {
+		r.ObjectsInc()
+		r.BytesAdd(lom.Size())
+		if j.missingCopies(lom) {
+			j.repairCopies(lom)
+		}
+		return
+	}
+	if !isCorrupted(err) {
+		return
+	}
+	glog.Errorf("%s: %v", r, err)
+	r.stats.badObjs.Inc()
+	source, err := RepairObject(r.t, lom)
+	if err != nil {
+		glog.Errorf("%s: failed to repair %s, err: %v", r, lom, err)
+		r.stats.unrepaired.Inc()
+		return
+	}
+	glog.Infof("%s: repaired %s from %s", r, lom, source)
+	switch source {
+	case RepairedFromMirror:
+		r.stats.mirror.Inc()
+	case RepairedFromEC:
+		r.stats.ec.Inc()
+	case RepairedFromCloud:
+		r.stats.cloud.Inc()
+	}
+	r.ObjectsInc()
+	r.BytesAdd(lom.Size())
+}
+
+// scrubCopy verifies the copy of the object against the object's checksum.
+func (j *jogger) scrubCopy(cp *cluster.LOM) {
+	r := j.parent
+	lom := &cluster.LOM{T: r.t, ObjName: cp.ObjName}
+	if err := lom.Init(cp.Bck().Bck, j.config); err != nil {
+		return
+	}
+	lom.Lock(false)
+	err := lom.Load(false)
+	if err != nil {
+		lom.Unlock(false)
+		return // missing objects are restored by the resilver or GET
+	}
+	if _, ok := lom.GetCopies()[cp.FQN]; !ok || lom.Cksum() == nil {
+		lom.Unlock(false)
+		return // not a copy (eg. misplaced) or nothing to verify against
+	}
+	err = verifyFile(lom, cp.FQN)
+	lom.Unlock(false)
+	if err == nil {
+		r.ObjectsInc()
+		r.BytesAdd(lom.Size())
+		return
+	}
+	if os.IsNotExist(err) {
+		return
+	}
+	glog.Errorf("%s: copy %s: %v", r, cp.FQN, err)
+	j.repairCopies(lom)
+}
+
+func (j *jogger) missingCopies(lom *cluster.LOM) bool {
+	if conf := lom.MirrorConf(); conf.Enabled && lom.NumCopies() < int(conf.Copies) {
+		return true
+	}
+	for copyFQN := range lom.GetCopies() {
+		if copyFQN == lom.FQN {
+			continue
+		}
+		if err := fs.Access(copyFQN); os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
+
+func (j *jogger) repairCopies(lom *cluster.LOM) {
+	r := j.parent
+	lom.Lock(true)
+	bad, restored, err := repairCopies(lom, j.buf)
+	lom.Unlock(true)
+	r.stats.badCopies.Add(int64(bad))
+	r.stats.copies.Add(int64(restored))
+	if err != nil {
+		glog.Errorf("%s: failed to repair copies of %s, err: %v", r, lom, err)
+	}
+}
+
+// scrubSlice verifies the EC slice against the checksum stored in its metafile.
+func (j *jogger) scrubSlice(fqn string) {
+	r := j.parent
+	parsedFQN, err := fs.Mountpaths.ParseFQN(fqn)
+	if err != nil {
+		return
+	}
+	metaFQN := fs.CSM.GenContentParsedFQN(parsedFQN, ec.MetaType, "")
+	for retry := true; ; retry = false {
+		md, err := ec.LoadMetadata(metaFQN)
+		if err != nil || md.CksumType == "" || md.CksumType == cmn.ChecksumNone {
+			return // being written, orphaned, or without checksum
+		}
+		size, err := verifySlice(fqn, cmn.NewCksum(md.CksumType, md.CksumValue), j.buf)
+		if err == nil {
+			r.stats.slices.Inc()
+			r.BytesAdd(size)
+			return
+		}
+		if os.IsNotExist(err) {
+			return
+		}
+		if !retry {
+			glog.Errorf("%s: slice %s: %v", r, fqn, err)
+			break
+		}
+		// the slice may have been replaced in the meantime - reload the metafile and check again
+	}
+	r.stats.badSlices.Inc()
+	if err := cmn.RemoveFile(fqn); err != nil {
+		glog.Errorf("%s: failed to remove corrupted slice %s, err: %v", r, fqn, err)
+		return
+	}
+	if err := cmn.RemoveFile(metaFQN); err != nil {
+		glog.Errorf("%s: failed to remove metafile %s, err: %v", r, metaFQN, err)
+	}
+}
+
+func (j *jogger) done(fqn string) {
+	j.last = fqn
+	if (j.throttle.Num()%fs.ThrottleNumObjects) == 0 && time.Since(j.checkpoint) > checkpointInterval {
+		j.saveMarker()
+	}
+}
+
+//
+// checkpoint
+//
+
+func markerPath(mpathInfo *fs.MountpathInfo) string { return filepath.Join(mpathInfo.Path, markerName) }
+
+func loadMarker(mpathInfo *fs.MountpathInfo) string {
+	b, err := ioutil.ReadFile(markerPath(mpathInfo))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("%s: failed to read scrub marker, err: %v", mpathInfo, err)
+		}
+		return ""
+	}
+	return string(b)
+}
+
+func (j *jogger) saveMarker() {
+	j.checkpoint = time.Now()
+	if j.last == "" {
+		return
+	}
+	var (
+		path    = markerPath(j.mpathInfo)
+		tmpPath = path + ".tmp"
+		err     = ioutil.WriteFile(tmpPath, []byte(j.last), 0644)
+	)
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		glog.Errorf("%s: failed to save scrub marker, err: %v", j.mpathInfo, err)
+	}
+}
+
+func lastRunPath(mpathInfo *fs.MountpathInfo) string {
+	return filepath.Join(mpathInfo.Path, lastRunName)
+}
+
+func loadLastRun(mpathInfo *fs.MountpathInfo) time.Time {
+	b, err := ioutil.ReadFile(lastRunPath(mpathInfo))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.Errorf("%s: failed to read scrub last run, err: %v", mpathInfo, err)
+		}
+		return time.Time{}
+	}
+	finished, err := time.Parse(time.RFC3339, string(b))
+	if err != nil {
+		glog.Errorf("%s: invalid scrub last run %q, err: %v", mpathInfo, b, err)
+		return time.Time{}
+	}
+	return finished
+}
+
+func saveLastRun(mpathInfo *fs.MountpathInfo, finished time.Time) {
+	var (
+		path    = lastRunPath(mpathInfo)
+		tmpPath = path + ".tmp"
+		err     = ioutil.WriteFile(tmpPath, []byte(finished.UTC().Format(time.RFC3339)), 0644)
+	)
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		glog.Errorf("%s: failed to save scrub last run, err: %v", mpathInfo, err)
+	}
+}
+
+// fqnLess compares FQNs in the order of the sorted traversal, that is,
+// path element by path element.
+func fqnLess(a, b string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if a[i] == filepath.Separator {
+			return true
+		}
+		if b[i] == filepath.Separator {
+			return false
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
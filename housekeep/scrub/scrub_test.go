@@ -0,0 +1,203 @@
+// Package scrub verifies the integrity of stored objects, their copies and EC slices,
+// and repairs the corrupted ones from local mirrors, EC slices, or the Cloud.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package scrub
+
+import (
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+const (
+	basePath   = "/tmp/scrub-tests"
+	bucketName = "scrub-bck"
+	fileSize   = cmn.KiB
+)
+
+func TestScrubFqnLess(t *testing.T) {
+	// the order of the sorted traversal: "/" precedes any other character
+	fqns := []string{"/mp/b/z", "/mp/a-b/x", "/mp/a/b/c", "/mp/a/b", "/mp/a.txt", "/mp/a/c"}
+	sort.Slice(fqns, func(i, j int) bool { return fqnLess(fqns[i], fqns[j]) })
+	expected := []string{"/mp/a/b", "/mp/a/b/c", "/mp/a/c", "/mp/a-b/x", "/mp/a.txt", "/mp/b/z"}
+	for i := range expected {
+		tassert.Errorf(t, fqns[i] == expected[i], "expected %v, got %v", expected, fqns)
+	}
+}
+
+func TestScrubSkip(t *testing.T) {
+	j := &jogger{resume: "/mp/a/c/obj2"}
+	tests := []struct {
+		fqn     string
+		dir     bool
+		skip    bool
+		skipDir bool
+	}{
+		{fqn: "/mp/a/b", dir: true, skipDir: true},
+		{fqn: "/mp/a/c", dir: true},
+		{fqn: "/mp/a/c/obj1", skip: true},
+		{fqn: "/mp/a/c/obj2", skip: true},
+		{fqn: "/mp/a/c/obj3"},
+		{fqn: "/mp/a/c/obj0"}, // resume point already passed
+	}
+	for _, test := range tests {
+		skip, err := j.skip(test.fqn, &dirEntry{dir: test.dir})
+		tassert.Errorf(t, skip == test.skip, "%s: expected skip %t", test.fqn, test.skip)
+		tassert.Errorf(t, (err == filepath.SkipDir) == test.skipDir, "%s: expected skip dir %t", test.fqn, test.skipDir)
+	}
+}
+
+type dirEntry struct{ dir bool }
+
+func (de *dirEntry) IsDir() bool { return de.dir }
+
+func saveObject(t *testing.T, tMock cluster.Target, bck cmn.Bck, objName string) *cluster.LOM {
+	buf := make([]byte, fileSize)
+	lom := &cluster.LOM{T: tMock, ObjName: objName}
+	tassert.CheckFatal(t, lom.Init(bck))
+	cksum, err := cmn.SaveReader(lom.FQN, rand.Reader, buf, cmn.ChecksumXXHash, fileSize, "")
+	tassert.CheckFatal(t, err)
+	lom.SetSize(fileSize)
+	lom.SetCksum(cksum.Clone())
+	tassert.CheckFatal(t, lom.Persist())
+	return lom
+}
+
+func corruptFile(t *testing.T, fqn string) {
+	buf := make([]byte, fileSize)
+	_, err := rand.Read(buf)
+	tassert.CheckFatal(t, err)
+	tassert.CheckFatal(t, ioutil.WriteFile(fqn, buf, 0644))
+}
+
+func TestScrubRepair(t *testing.T) {
+	cluster.InitTarget()
+	tassert.CheckFatal(t, cmn.CreateDir(basePath))
+	defer os.RemoveAll(basePath)
+	config := cmn.GCO.BeginUpdate()
+	config.TestFSP.Count = 1 // mountpaths share the disk
+	cmn.GCO.CommitUpdate(config)
+	fs.InitMountedFS()
+	fs.Mountpaths.DisableFsIDCheck()
+	mpaths := []string{filepath.Join(basePath, "mp1"), filepath.Join(basePath, "mp2")}
+	for _, mpath := range mpaths {
+		tassert.CheckFatal(t, cmn.CreateDir(mpath))
+		tassert.CheckFatal(t, fs.Mountpaths.Add(mpath))
+		defer fs.Mountpaths.Remove(mpath)
+	}
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+	var (
+		bmdMock = cluster.NewBaseBownerMock()
+		tMock   = cluster.NewTargetMock(bmdMock)
+		bck     = cmn.Bck{Name: bucketName, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		props   = &cmn.BucketProps{
+			Cksum:       cmn.CksumConf{Type: cmn.ChecksumXXHash},
+			Mirror:      cmn.MirrorConf{Enabled: true, Copies: 2},
+			AccessAttrs: cmn.AllAccess(),
+		}
+		buf  = make([]byte, fileSize)
+		loms = make([]*cluster.LOM, 0, 4)
+	)
+	bmdMock.Add(cluster.NewBck(bucketName, cmn.ProviderAIS, cmn.NsGlobal, props))
+	availablePaths, _ := fs.Mountpaths.Get()
+	for _, objName := range []string{"corrupted-obj", "corrupted-copy", "missing-copy"} {
+		lom := saveObject(t, tMock, bck, objName)
+		for _, mpathInfo := range availablePaths {
+			if mpathInfo != lom.ParsedFQN.MpathInfo {
+				_, err := lom.CopyObject(fs.CSM.FQN(mpathInfo, bck, fs.ObjectType, objName), buf)
+				tassert.CheckFatal(t, err)
+			}
+		}
+		tassert.Fatalf(t, lom.NumCopies() == 2, "%s: expected 2 copies, got %d", lom, lom.NumCopies())
+		loms = append(loms, lom)
+	}
+	// not yet mirrored - the added copy must not be counted as bad one
+	loms = append(loms, saveObject(t, tMock, bck, "single-copy"))
+	copyFQN := func(lom *cluster.LOM) string {
+		for fqn := range lom.GetCopies() {
+			if fqn != lom.FQN {
+				return fqn
+			}
+		}
+		return ""
+	}
+	corruptFile(t, loms[0].FQN)
+	corruptFile(t, copyFQN(loms[1]))
+	tassert.CheckFatal(t, os.Remove(copyFQN(loms[2])))
+
+	xact := NewXaction("", tMock)
+	xact.Run()
+
+	for _, lom := range loms {
+		lom.Uncache()
+		tassert.CheckFatal(t, lom.Load(false))
+		tassert.Errorf(t, lom.NumCopies() == 2, "%s: expected 2 copies, got %d", lom, lom.NumCopies())
+		for fqn := range lom.GetCopies() {
+			tassert.Errorf(t, verifyFile(lom, fqn) == nil, "%s: expected healthy copy %s", lom, fqn)
+		}
+	}
+	s := xact.Stats()
+	tassert.Errorf(t, s.Ext.BadObjCount == 1 && s.Ext.RepairedMirror == 1 && s.Ext.Unrepaired == 0,
+		"unexpected object stats: %+v", s.Ext)
+	tassert.Errorf(t, s.Ext.BadCopyCount == 2 && s.Ext.RepairedCopies == 3, "unexpected copy stats: %+v", s.Ext)
+	tassert.Errorf(t, s.Finished() && !s.Aborted(), "expected finished xaction")
+	tassert.Errorf(t, !MarkerExists(), "expected no scrub marker")
+}
+
+func TestScrubResume(t *testing.T) {
+	cluster.InitTarget()
+	tassert.CheckFatal(t, cmn.CreateDir(basePath))
+	defer os.RemoveAll(basePath)
+	fs.InitMountedFS()
+	fs.Mountpaths.DisableFsIDCheck()
+	tassert.CheckFatal(t, fs.Mountpaths.Add(basePath))
+	defer fs.Mountpaths.Remove(basePath)
+	_ = fs.CSM.RegisterContentType(fs.ObjectType, &fs.ObjectContentResolver{})
+	_ = fs.CSM.RegisterContentType(fs.WorkfileType, &fs.WorkfileContentResolver{})
+
+	var (
+		bmdMock = cluster.NewBaseBownerMock()
+		tMock   = cluster.NewTargetMock(bmdMock)
+		bck     = cmn.Bck{Name: bucketName, Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		props   = &cmn.BucketProps{
+			Cksum:       cmn.CksumConf{Type: cmn.ChecksumXXHash},
+			AccessAttrs: cmn.AllAccess(),
+		}
+	)
+	bmdMock.Add(cluster.NewBck(bucketName, cmn.ProviderAIS, cmn.NsGlobal, props))
+	loms := make([]*cluster.LOM, 0, 4)
+	for _, objName := range []string{"a/obj1", "a/obj2", "b/obj1", "b/obj2"} {
+		loms = append(loms, saveObject(t, tMock, bck, objName))
+	}
+	// the previous run has stopped after verifying "a/obj2"
+	availablePaths, _ := fs.Mountpaths.Get()
+	j := &jogger{mpathInfo: availablePaths[basePath]}
+	j.last = loms[1].FQN
+	j.saveMarker()
+	tassert.Fatalf(t, MarkerExists(), "expected scrub marker")
+	tassert.Fatalf(t, LastRun().IsZero(), "expected no completed scrub run")
+	corruptFile(t, loms[0].FQN) // not verified again
+
+	started := time.Now().Add(-time.Second) // (last run is persisted with seconds precision)
+	xact := NewXaction("", tMock)
+	xact.Run()
+
+	s := xact.Stats()
+	tassert.Errorf(t, s.ObjCount() == 2 && s.Ext.BadObjCount == 0, "expected 2 objects verified, got: %d, %+v",
+		s.ObjCount(), s.Ext)
+	tassert.Errorf(t, !MarkerExists(), "expected no scrub marker")
+	tassert.Errorf(t, LastRun().After(started), "expected last run after %v, got %v", started, LastRun())
+}
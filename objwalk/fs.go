@@ -26,16 +26,18 @@ type (
 		markerDir    string
 		msg          *cmn.SelectMsg
 		lastFilePath string
+		lastDir      string // the last directory entry added when listing with a delimiter
 		bucket       string
 		fileCount    int
 		limit        int
 
-		needSize    bool
-		needAtime   bool
-		needCksum   bool
-		needVersion bool
-		needStatus  bool
-		needCopies  bool
+		needSize     bool
+		needAtime    bool
+		needCksum    bool
+		needVersion  bool
+		needStatus   bool
+		needCopies   bool
+		needCustomMD bool
 	}
 )
 
@@ -64,9 +66,38 @@ func (ci *allfinfos) processDir(fqn string) error {
 		return filepath.SkipDir
 	}
 
+	// When listing with "/" delimiter, the entire directory is skipped if
+	// it is rolled up into a directory entry that has been already added to
+	// the page or returned by the previous call.
+	if ci.msg.Delimiter == cmn.DirDelimiter {
+		dirName := ct.ObjName() + cmn.DirDelimiter
+		if ci.lastDir != "" && strings.HasPrefix(dirName, ci.lastDir) {
+			return filepath.SkipDir
+		}
+		if strings.HasSuffix(ci.marker, cmn.DirDelimiter) && strings.HasPrefix(dirName, ci.marker) {
+			return filepath.SkipDir
+		}
+	}
+
 	return nil
 }
 
+// Adds a directory entry to the list if the object rolls up into a directory
+// (when listing with a delimiter). Returns true if the object is rolled up.
+func (ci *allfinfos) lsDir(objName string) bool {
+	dir, ok := ci.msg.DirName(objName)
+	if !ok {
+		return false
+	}
+	if dir == ci.lastDir {
+		return true
+	}
+	ci.fileCount++
+	ci.lastDir = dir
+	ci.objs = append(ci.objs, &cmn.BucketEntry{Name: dir, Flags: cmn.ObjStatusOK | cmn.EntryIsDir})
+	return true
+}
+
 // Adds an info about cached object to the list if:
 //  - its name starts with prefix (if prefix is set)
 //  - it has not been already returned by previous page request
@@ -76,7 +107,10 @@ func (ci *allfinfos) lsObject(lom *cluster.LOM, objStatus uint16) error {
 	if ci.prefix != "" && !strings.HasPrefix(objName, ci.prefix) {
 		return nil
 	}
-	if ci.marker != "" && objName <= ci.marker {
+	if ci.msg.SkipMarked(objName) {
+		return nil
+	}
+	if ci.lsDir(objName) {
 		return nil
 	}
 
@@ -101,6 +135,9 @@ func (ci *allfinfos) lsObject(lom *cluster.LOM, objStatus uint16) error {
 	if ci.needCopies {
 		fileInfo.Copies = int16(lom.NumCopies())
 	}
+	if ci.needCustomMD {
+		fileInfo.CustomMD = lom.CustomMD()
+	}
 	fileInfo.Size = lom.Size()
 	ci.objs = append(ci.objs, fileInfo)
 	ci.lastFilePath = lom.FQN
@@ -129,7 +166,10 @@ func (ci *allfinfos) listwalkfFast(fqn string, de fs.DirEntry) error {
 	if ci.prefix != "" && !strings.HasPrefix(ct.ObjName(), ci.prefix) {
 		return nil
 	}
-	if ci.marker != "" && ct.ObjName() <= ci.marker {
+	if ci.msg.SkipMarked(ct.ObjName()) {
+		return nil
+	}
+	if ci.lsDir(ct.ObjName()) {
 		return nil
 	}
 	ci.fileCount++
@@ -64,12 +64,13 @@ func (w *Walk) newFileWalk(ctx context.Context, bucket string, msg *cmn.SelectMs
 		fileCount:    0,
 		limit:        cmn.DefaultListPageSize, // maximum number files to return
 
-		needSize:    msg.WantProp(cmn.GetPropsSize),
-		needAtime:   msg.WantProp(cmn.GetPropsAtime),
-		needCksum:   msg.WantProp(cmn.GetPropsChecksum),
-		needVersion: msg.WantProp(cmn.GetPropsVersion),
-		needStatus:  msg.WantProp(cmn.GetPropsStatus),
-		needCopies:  msg.WantProp(cmn.GetPropsCopies),
+		needSize:     msg.WantProp(cmn.GetPropsSize),
+		needAtime:    msg.WantProp(cmn.GetPropsAtime),
+		needCksum:    msg.WantProp(cmn.GetPropsChecksum),
+		needVersion:  msg.WantProp(cmn.GetPropsVersion),
+		needStatus:   msg.WantProp(cmn.GetPropsStatus),
+		needCopies:   msg.WantProp(cmn.GetPropsCopies),
+		needCustomMD: msg.WantProp(cmn.GetPropsCustom),
 	}
 
 	if msg.PageSize != 0 {
@@ -199,6 +200,7 @@ func (w *Walk) CloudObjPage() (*cmn.BucketList, error) {
 		needCksum   = w.msg.WantProp(cmn.GetPropsChecksum)
 		needVersion = w.msg.WantProp(cmn.GetPropsVersion)
 		needCopies  = w.msg.WantProp(cmn.GetPropsCopies)
+		needCustom  = w.msg.WantProp(cmn.GetPropsCustom)
 	)
 
 	for _, e := range bucketList.Entries {
@@ -237,6 +239,9 @@ func (w *Walk) CloudObjPage() (*cmn.BucketList, error) {
 		if needCopies {
 			e.Copies = int16(lom.NumCopies())
 		}
+		if needCustom {
+			e.CustomMD = lom.CustomMD()
+		}
 
 		if postCallback != nil {
 			postCallback(lom)
@@ -114,6 +114,7 @@ type (
 		SliceID      int16  `json:"sliceid,omitempty"`
 		DataSlices   int16  `json:"data"`
 		ParitySlices int16  `json:"parity"`
+		LocalGroups  int16  `json:"local,omitempty"`
 	}
 
 	ctList = map[string][]*rebCT // EC CTs grouped by a rule
@@ -137,6 +138,7 @@ type (
 		ready        atomic.Int32 // object state: objWaiting, objReceived, objDone
 		dataSlices   int16        // the number of data slices
 		paritySlices int16        // the number of parity slices
+		localGroups  int16        // the number of local parity slices
 		mainSliceID  int16        // sliceID on the main target
 		isECCopy     bool         // replicated or erasure coded
 		hasCT        bool         // local target has any obj's CT
@@ -237,7 +239,7 @@ func (so *rebObject) requiredCT() int {
 	if so.isECCopy {
 		return int(so.paritySlices + 1)
 	}
-	return int(so.dataSlices + so.paritySlices + 1)
+	return int(so.dataSlices + so.paritySlices + so.localGroups + 1)
 }
 
 // Returns how many CTs found across all targets
@@ -469,7 +471,9 @@ func (reb *Manager) sendFromDisk(ct *rebCT, target *cluster.Snode) error {
 	)
 	if lom != nil {
 		hdr.ObjAttrs.Atime = lom.AtimeUnix()
+		hdr.ObjAttrs.Ctime = lom.CtimeUnix()
 		hdr.ObjAttrs.Version = lom.Version()
+		hdr.ObjAttrs.CustomMD = lom.CustomMD()
 		if cksum := lom.Cksum(); cksum != nil {
 			hdr.ObjAttrs.CksumType, hdr.ObjAttrs.CksumValue = cksum.Get()
 		}
@@ -600,6 +604,8 @@ func (reb *Manager) saveCTToDisk(data io.Reader, req *pushReq, hdr transport.Hea
 		if hdr.ObjAttrs.Atime != 0 {
 			lom.SetAtimeUnix(hdr.ObjAttrs.Atime)
 		}
+		lom.SetCtimeUnix(hdr.ObjAttrs.Ctime)
+		lom.SetCustomMD(hdr.ObjAttrs.CustomMD)
 		bdir = mpath.MakePathBck(lom.Bck().Bck)
 		lom.Lock(true)
 		defer lom.Unlock(true)
@@ -1038,6 +1044,7 @@ func (reb *Manager) walkEC(fqn string, de fs.DirEntry) (err error) {
 		SliceID:      int16(md.SliceID),
 		DataSlices:   int16(md.Data),
 		ParitySlices: int16(md.Parity),
+		LocalGroups:  int16(md.LocalGroups),
 		realFQN:      fileFQN,
 		hrwFQN:       hrwFQN,
 		meta:         md,
@@ -1248,6 +1255,7 @@ func (reb *Manager) calcLocalProps(bck *cluster.Bck, obj *rebObject, smap *clust
 	obj.isECCopy = ec.IsECCopy(obj.objSize, ecConfig)
 	obj.dataSlices = mainSlice.DataSlices
 	obj.paritySlices = mainSlice.ParitySlices
+	obj.localGroups = mainSlice.LocalGroups
 	obj.sliceSize = ec.SliceSize(obj.objSize, int(obj.dataSlices))
 
 	ctReq := obj.requiredCT()
@@ -1278,7 +1286,7 @@ func (reb *Manager) calcLocalProps(bck *cluster.Bck, obj *rebObject, smap *clust
 		}
 	}
 	ctFound := obj.foundCT()
-	obj.hasAllSlices = ctCnt >= obj.dataSlices+obj.paritySlices
+	obj.hasAllSlices = ctCnt >= obj.dataSlices+obj.paritySlices+obj.localGroups
 
 	genCount := cmn.Max(ctReq, len(smap.Tmap))
 	obj.hrwTargets, err = cluster.HrwTargetList(bck.MakeUname(obj.objName), smap, genCount)
@@ -1361,8 +1369,10 @@ func (reb *Manager) shouldSendSlice(obj *rebObject) (hasSlice, shouldSend bool)
 	}
 	// First check if this target in the first 'dataSliceCount' slices.
 	// Skip the first target in list for it is the main one.
+	// Local parity slices are not enough to rebuild the object by themselves,
+	// so 'localGroups' more targets send their slices.
 	tgtIndex := reb.targetIndex(reb.t.Snode().ID(), obj)
-	shouldSend = tgtIndex >= 0 && tgtIndex < int(obj.dataSlices)
+	shouldSend = tgtIndex >= 0 && tgtIndex < int(obj.dataSlices+obj.localGroups)
 	hasSlice = obj.hasCT && !obj.isMain && !obj.isECCopy && !obj.fullObjFound
 	if hasSlice && (bool(glog.FastV(4, glog.SmoduleReb))) {
 		locSlice := obj.locCT[reb.t.Snode().ID()]
@@ -1858,12 +1868,14 @@ func (reb *Manager) releaseSGLs(md *rebArgs, objList []*rebObject) {
 func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err error) {
 	var (
 		meta     *ec.Metadata
-		sliceCnt = obj.dataSlices + obj.paritySlices
+		sliceCnt = obj.dataSlices + obj.paritySlices + obj.localGroups
+		rsCnt    = obj.dataSlices + obj.paritySlices // local parity slices are not a part of RS encoding
 		readers  = make([]io.Reader, sliceCnt)
 
 		// since io.Reader cannot be reopened, we need to have a copy for saving object
 		rereaders   = make([]io.Reader, sliceCnt)
 		writers     = make([]io.Writer, sliceCnt)
+		sgls        = make([]*memsys.SGL, sliceCnt) // received slices
 		slicesFound = int16(0)
 	)
 	if glog.FastV(4, glog.SmoduleReb) {
@@ -1880,6 +1892,7 @@ func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err
 		cmn.AssertMsg(sl.SliceID != 0 && readers[id] == nil, obj.uid)
 		readers[id] = memsys.NewReader(sl.sgl)
 		rereaders[id] = memsys.NewReader(sl.sgl)
+		sgls[id] = sl.sgl
 		slicesFound++
 		if meta == nil {
 			meta = sl.meta
@@ -1898,13 +1911,42 @@ func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err
 		writers[i] = obj.rebuildSGLs[i]
 	}
 
+	// first, restore the data slices that can be restored from their local
+	// groups; the rest is up to Reed-Solomon
+	rsWriters := append([]io.Writer(nil), writers[:rsCnt]...)
+	open := func(i int) (io.ReadCloser, error) {
+		if obj.rebuildSGLs[i] != nil {
+			return memsys.NewReader(obj.rebuildSGLs[i]), nil
+		}
+		if sgls[i] != nil {
+			return memsys.NewReader(sgls[i]), nil
+		}
+		return nil, nil
+	}
+	if obj.localGroups > 0 {
+		repaired, err := ec.RepairLocal(meta, obj.sliceSize, open, writers)
+		if err != nil {
+			return fmt.Errorf("failed to repair %q from local parity: %v", obj.objName, err)
+		}
+		for _, i := range repaired {
+			readers[i] = memsys.NewReader(obj.rebuildSGLs[i])
+			rereaders[i] = memsys.NewReader(obj.rebuildSGLs[i])
+			rsWriters[i] = nil
+		}
+	}
+
 	stream, err := reedsolomon.NewStreamC(int(obj.dataSlices), int(obj.paritySlices), true, true)
 	if err != nil {
 		return fmt.Errorf("failed to create initialize EC for %q: %v", obj.objName, err)
 	}
-	if err := stream.Reconstruct(readers, writers); err != nil {
+	if err := stream.Reconstruct(readers[:rsCnt], rsWriters); err != nil {
 		return fmt.Errorf("failed to build EC for %q: %v", obj.objName, err)
 	}
+	if obj.localGroups > 0 {
+		if err := ec.RebuildLocalParity(meta, obj.sliceSize, open, writers); err != nil {
+			return fmt.Errorf("failed to build local parity for %q: %v", obj.objName, err)
+		}
+	}
 
 	if glog.FastV(4, glog.SmoduleReb) {
 		glog.Infof("saving restored full object %s[%d]", obj.objName, obj.objSize)
@@ -1961,6 +2003,7 @@ func (reb *Manager) rebuildFromSlices(obj *rebObject, conf *cmn.CksumConf) (err
 			SliceID:      int16(sliceID),
 			DataSlices:   int16(ecMD.Data),
 			ParitySlices: int16(ecMD.Parity),
+			LocalGroups:  int16(ecMD.LocalGroups),
 			meta:         &sliceMD,
 		}
 
@@ -2045,9 +2088,10 @@ func (reb *Manager) rebuildAndSend(obj *rebObject) error {
 			ObjName: obj.objName,
 			ObjAttrs: transport.ObjectAttrs{
 				Size:       obj.objSize,
-				CksumType:  s.meta.CksumType,
+				CksumType:  s.meta.ObjCksumType,
 				CksumValue: s.meta.ObjCksum,
 				Version:    s.meta.ObjVersion,
+				CustomMD:   s.meta.CustomMD,
 			},
 		}
 		reb.saveCTToDisk(memsys.NewReader(s.sgl), req, hdr)
@@ -2132,10 +2176,16 @@ func (reb *Manager) updateRebuildInfo(obj *rebObject) {
 	if obj.ready.Load() != objWaiting {
 		return
 	}
-	cnt := 0
+	var (
+		cnt   = 0
+		rsCnt = 0 // data and parity slices only
+	)
 	for _, ct := range obj.locCT {
 		if ct.sgl != nil && ct.sgl.Size() != 0 {
 			cnt++
+			if ct.SliceID <= obj.dataSlices+obj.paritySlices {
+				rsCnt++
+			}
 		}
 	}
 	if cnt != 0 && !obj.isMain {
@@ -2144,7 +2194,7 @@ func (reb *Manager) updateRebuildInfo(obj *rebObject) {
 	} else if obj.isMain && obj.isECCopy && cnt != 0 {
 		obj.ready.Store(objReceived)
 		// TODO: add to ZIL  missing replicas
-	} else if cnt >= int(obj.dataSlices) {
+	} else if rsCnt >= int(obj.dataSlices) {
 		// if it is main target, it needs dataSlices slices to rebuild
 		obj.ready.CAS(objWaiting, objReceived)
 	}
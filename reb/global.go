@@ -677,11 +677,15 @@ func (rj *rebalanceJogger) send(lom *cluster.LOM, tsi *cluster.Snode, addAck boo
 			ObjName: lom.ObjName,
 			Opaque:  opaque,
 			ObjAttrs: transport.ObjectAttrs{
-				Size:       lom.Size(),
-				Atime:      lom.AtimeUnix(),
-				CksumType:  cksumType,
-				CksumValue: cksumValue,
-				Version:    lom.Version(),
+				Size:        lom.Size(),
+				Atime:       lom.AtimeUnix(),
+				CksumType:   cksumType,
+				CksumValue:  cksumValue,
+				Version:     lom.Version(),
+				CustomMD:    lom.CustomMD(),
+				RetainUntil: lom.RetainUntil(),
+				LegalHold:   lom.LegalHold(),
+				Ctime:       lom.CtimeUnix(),
 			},
 		}
 		o = transport.Obj{Hdr: hdr, Callback: rj.objSentCallback, CmplPtr: unsafe.Pointer(lom)}
@@ -421,6 +421,10 @@ func (reb *Manager) recvObjRegular(hdr transport.Header, smap *cluster.Smap, unp
 	}
 	lom.SetAtimeUnix(hdr.ObjAttrs.Atime)
 	lom.SetVersion(hdr.ObjAttrs.Version)
+	lom.SetCustomMD(hdr.ObjAttrs.CustomMD)
+	lom.SetRetainUntil(hdr.ObjAttrs.RetainUntil)
+	lom.SetLegalHold(hdr.ObjAttrs.LegalHold)
+	lom.SetCtimeUnix(hdr.ObjAttrs.Ctime)
 
 	if err := reb.t.PutObject(cluster.PutObjectParams{
 		LOM:          lom,
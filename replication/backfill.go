@@ -0,0 +1,239 @@
+// Package replication asynchronously replicates PUTs, DELETEs, and renames of the objects
+// of AIS buckets to the buckets of attached remote AIS clusters.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/atomic"
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/stats"
+)
+
+// Backfill is a one-shot xaction that brings the existing content of the bucket in
+// sync with the destination bucket at the remote cluster. It runs when replication
+// gets enabled (or its destination changes) and can be also started via API.
+//
+// Every target walks the objects it stores (one jogger per mountpath) and queues
+// the replication of those objects that are missing at the remote cluster or differ
+// in size or checksum. Objects that exist only at the remote cluster are left intact.
+//
+// Backfill is deferred (with backoff) while the remote cluster is unreachable, and fails
+// if the remote cluster is not attached, rejects access to the destination bucket, gets
+// unreachable in the middle of the walk, or the replication queue is full.
+
+type (
+	Backfill struct {
+		cmn.XactBase
+		cmn.MountpathXact
+		t      cluster.Target
+		doneCh chan struct{}
+		mtx    sync.Mutex
+		err    error // the first error that has stopped the joggers
+		stats  struct {
+			scanned, inSync atomic.Int64
+		}
+	}
+	jogger struct { // one per mountpath
+		parent    *Backfill
+		mpathInfo *fs.MountpathInfo
+		config    *cmn.Config
+		smap      *cluster.Smap
+		bp        api.BaseParams
+		dst       cmn.Bck
+		throttle  fs.Throttle
+	}
+)
+
+func NewBackfill(t cluster.Target, bck cmn.Bck) *Backfill {
+	return &Backfill{
+		XactBase: *cmn.NewXactBaseWithBucket("", cmn.ActReplBackfill, bck),
+		t:        t,
+	}
+}
+
+func (r *Backfill) Run() error {
+	bck := cluster.NewBckEmbed(r.Bck())
+	if err := bck.Init(r.t.GetBowner(), r.t.Snode()); err != nil {
+		r.EndTime(time.Now())
+		return err
+	}
+	conf := &bck.Props.Replication
+	if !conf.Enabled || mgr == nil {
+		r.EndTime(time.Now())
+		return fmt.Errorf("bucket %q does not have replication enabled", bck.Name)
+	}
+	dst := conf.DstBck(bck.Bck)
+	bp, err := r.waitRemote(conf.Cluster, dst)
+	if err != nil {
+		r.EndTime(time.Now())
+		glog.Errorf("%s: %v", r, err)
+		return err
+	}
+	var (
+		availablePaths, _ = fs.Mountpaths.Get()
+		config            = cmn.GCO.Get()
+		smap              = r.t.GetSowner().Get()
+	)
+	glog.Infoln(r.String())
+	r.doneCh = make(chan struct{}, len(availablePaths))
+	for _, mpathInfo := range availablePaths {
+		j := &jogger{
+			parent:    r,
+			mpathInfo: mpathInfo,
+			config:    config,
+			smap:      smap,
+			bp:        bp,
+			dst:       dst,
+			throttle:  fs.Throttle{Mpath: mpathInfo.Path, Config: config},
+		}
+		go j.jog()
+	}
+	for range availablePaths {
+		<-r.doneCh
+	}
+	if r.err != nil {
+		r.EndTime(time.Now())
+		glog.Errorf("%s: %v", r, r.err)
+		return r.err
+	}
+	if r.Aborted() {
+		return fmt.Errorf("%s aborted, exiting", r)
+	}
+	r.EndTime(time.Now())
+	glog.Infof("%s: scanned %d, in sync %d, queued %d", r, r.stats.scanned.Load(), r.stats.inSync.Load(),
+		r.ObjectsCnt())
+	return nil
+}
+
+func (r *Backfill) Stop(error) { r.Abort() }
+
+// waitRemote returns the API parameters of the remote cluster once the latter can be
+// reached; waits (with backoff) while it is offline
+func (r *Backfill) waitRemote(uuid string, dst cmn.Bck) (bp api.BaseParams, err error) {
+	var backoff time.Duration
+	for {
+		if bp, err = mgr.remote.BaseParams(uuid); err != nil {
+			return bp, fmt.Errorf("%w: %v", errNoRemote, err)
+		}
+		if _, err = api.HeadBucket(bp, dst); err == nil || !retriable(err) {
+			return
+		}
+		backoff = cmn.MinDuration(cmn.MaxDuration(2*backoff, retryMin), retryMax)
+		glog.Warningf("%s: remote cluster %q is unreachable, retrying in %v: %v", r, uuid, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-r.ChanAbort():
+			return bp, cmn.NewAbortedError(r.String())
+		}
+	}
+}
+
+// fail stops all joggers with the error (the first one wins)
+func (r *Backfill) fail(err error) {
+	r.mtx.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mtx.Unlock()
+	r.Abort()
+}
+
+func (r *Backfill) Stats() *stats.ReplBackfillStats {
+	s := &stats.ReplBackfillStats{BaseXactStats: *stats.NewXactStats(r)}
+	s.Ext.ScannedCount, s.Ext.InSyncCount = r.stats.scanned.Load(), r.stats.inSync.Load()
+	return s
+}
+
+//
+// mpath jogger
+//
+
+func (j *jogger) jog() {
+	opts := &fs.Options{
+		Mpath:    j.mpathInfo,
+		Bck:      j.parent.Bck(),
+		CTs:      []string{fs.ObjectType},
+		Callback: j.walk,
+		Sorted:   false,
+	}
+	if err := fs.Walk(opts); err != nil {
+		if errors.As(err, &cmn.AbortedError{}) || j.parent.Aborted() {
+			glog.Infof("%s: stopping traversal: %v", j.mpathInfo, err)
+		} else {
+			glog.Errorf("%s: failed to traverse, err: %v", j.mpathInfo, err)
+		}
+	}
+	j.parent.doneCh <- struct{}{}
+}
+
+func (j *jogger) walk(fqn string, de fs.DirEntry) error {
+	if de.IsDir() {
+		return nil
+	}
+	if err := j.throttle.YieldTerm(j.parent); err != nil {
+		return err
+	}
+	lom := &cluster.LOM{T: j.parent.t, FQN: fqn}
+	if err := lom.Init(j.parent.Bck(), j.config); err != nil {
+		return nil
+	}
+	if err := lom.Load(); err != nil {
+		return nil
+	}
+	// a mirror of the object
+	if !lom.IsHRW() {
+		return nil
+	}
+	// misplaced - replicated by the target that has originally stored it
+	if si, err := cluster.HrwTarget(lom.Uname(), j.smap); err != nil || si.ID() != j.parent.t.Snode().ID() {
+		return nil
+	}
+	j.parent.stats.scanned.Inc()
+	inSync, err := j.inSync(lom)
+	if err != nil {
+		j.parent.fail(fmt.Errorf("remote cluster is unreachable: %w", err))
+		return err
+	}
+	if inSync {
+		j.parent.stats.inSync.Inc()
+		return nil
+	}
+	if err := mgr.enqueue(newOp(OpPut, lom)); err != nil {
+		j.parent.fail(err)
+		return err
+	}
+	j.parent.ObjectsInc()
+	j.parent.BytesAdd(lom.Size())
+	return nil
+}
+
+// inSync returns true if the remote cluster stores the same object; returns error
+// if the remote cluster cannot be reached
+func (j *jogger) inSync(lom *cluster.LOM) (bool, error) {
+	props, err := api.HeadObject(j.bp, j.dst, lom.ObjName)
+	if err != nil {
+		if retriable(err) {
+			return false, err
+		}
+		return false, nil // not found, etc.
+	}
+	if props.Size != lom.Size() {
+		return false, nil
+	}
+	cksum := lom.Cksum()
+	if cksum == nil || cksum.Type() == cmn.ChecksumNone {
+		return true, nil
+	}
+	return props.Checksum.Type == cksum.Type() && props.Checksum.Value == cksum.Value(), nil
+}
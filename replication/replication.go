@@ -0,0 +1,632 @@
+// Package replication asynchronously replicates PUTs, DELETEs, and renames of the objects
+// of AIS buckets to the buckets of attached remote AIS clusters.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package replication
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/api"
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// ============================================= Summary ===========================================
+//
+// Replication is configured on a per-bucket basis (see cmn.ReplicationConf): the changes of
+// the bucket's objects get replicated to the destination bucket of an attached remote AIS
+// cluster (see ais/cloud/ais.go).
+//
+// Each target queues the operations - PUT, DELETE, and rename - on the objects it stores.
+// The queue is durable: the operations are appended to the on-disk journal (one JSON
+// record per line) and acknowledged in the journal once executed at the remote cluster.
+// The journal gets synced every `syncInterval` and compacted (rewritten with the pending
+// operations only) as it grows.
+//
+// The operations are queued per destination (remote cluster), and each destination has its
+// own worker that executes the operations in the order they were queued, so that an offline
+// or slow remote cluster does not hold back the others:
+//   - PUT sends the current content of the object; it is skipped if the object does not
+//     exist anymore or there is a later operation on the object in the queue;
+//   - DELETE of an object that does not exist at the remote cluster is a no-op;
+//   - rename falls back to PUT (of the new name) and DELETE (of the old one) if the remote
+//     cluster fails to rename the object, e.g. because it has not been replicated yet.
+// Operations queued before the bucket's destination changed are dropped.
+//
+// Failures that indicate that the remote cluster is offline or overloaded are retried with
+// exponential backoff (between `retryMin` and `retryMax`), up to `maxAttempts` times; other
+// failures - including the remote cluster that is not attached (anymore) - are counted
+// (see stats.ErrReplCount) and the operation is dropped. So are the operations that do not
+// fit into the queue (`maxPending`).
+//
+// The objects that existed before the replication was enabled, as well as those whose
+// operations were dropped, get replicated by the backfill xaction (see: Backfill).
+//
+// ============================================= Summary ===========================================
+
+const (
+	QueueFname = "replication.queue" // in the configuration directory
+
+	OpPut    = "put"
+	OpDelete = "delete"
+	OpRename = "rename"
+
+	compactMinRecords = 1000 // do not compact smaller journals
+	syncInterval      = time.Second
+	retryMin          = time.Second
+	retryMax          = time.Minute
+	maxAttempts       = 10      // per operation
+	maxPending        = 1 << 20 // pending operations (all destinations)
+)
+
+type (
+	// Remote provides access to the attached remote AIS clusters (see: cloud.AisCloudProvider)
+	Remote interface {
+		BaseParams(uuid string) (api.BaseParams, error)
+	}
+
+	// Op is a queued operation and, at the same time, the record of the journal
+	Op struct {
+		Seq     int64   `json:"seq,string"`
+		Kind    string  `json:"op"`
+		Bck     cmn.Bck `json:"bck"`
+		ObjName string  `json:"obj"`
+		NewName string  `json:"new,omitempty"`     // rename only
+		Cluster string  `json:"cluster,omitempty"` // destination when queued
+		Time    int64   `json:"time,string"`       // when queued (unix nanoseconds)
+
+		attempts int // failed (retriable) attempts so far
+	}
+	// journal record that acknowledges (removes) the operation
+	ack struct {
+		Seq  int64 `json:"seq,string"`
+		Done bool  `json:"done"`
+	}
+
+	Manager struct {
+		t          cluster.Target
+		remote     Remote
+		statsT     *stats.Trunner
+		mtx        sync.Mutex
+		path       string
+		file       *os.File          // journal (append-only)
+		queues     map[string]*queue // destination => pending operations
+		latest     map[string]int64  // uname => seq of the latest pending operation on the object
+		pending    int               // total number of pending operations
+		maxPending int
+		dropped    int64 // operations dropped since the last stats update
+		seq        int64
+		fileCnt    int  // number of records in the journal
+		dirty      bool // journal written but not synced yet
+		overflow   bool // the last operation did not fit into the queue
+		running    bool // workers started (see: run)
+		stopCh     *cmn.StopCh
+	}
+	// pending operations of a given destination (oldest first) and its worker's wakeup
+	queue struct {
+		ops    []*Op
+		workCh chan struct{}
+	}
+
+	// local failures (e.g., to read the object) are not retried
+	localError struct {
+		err error
+	}
+)
+
+// the replication manager of the target (see: Init)
+var mgr *Manager
+
+var (
+	errNoRemote = errors.New("remote cluster is not attached")
+	errFull     = errors.New("replication queue is full")
+)
+
+func (e *localError) Error() string { return e.err.Error() }
+
+func newManager(t cluster.Target, remote Remote, statsT *stats.Trunner) *Manager {
+	return &Manager{
+		t:          t,
+		remote:     remote,
+		statsT:     statsT,
+		queues:     make(map[string]*queue),
+		latest:     make(map[string]int64),
+		maxPending: maxPending,
+		stopCh:     cmn.NewStopCh(),
+	}
+}
+
+// Init loads the target's replication queue from the configuration directory and
+// starts executing the pending operations.
+func Init(t cluster.Target, remote Remote, statsT *stats.Trunner, confdir string) error {
+	m := newManager(t, remote, statsT)
+	err := m.load(filepath.Join(confdir, QueueFname))
+	mgr = m
+	go m.run()
+	return err
+}
+
+// Stop stops executing the operations and syncs the journal.
+func Stop() {
+	if mgr != nil {
+		mgr.stop()
+	}
+}
+
+// Put queues the replication of the object's content (no-op if the bucket is not replicated).
+func Put(lom *cluster.LOM) {
+	if enabled(lom) {
+		mgr.enqueue(newOp(OpPut, lom))
+	}
+}
+
+// Delete queues the deletion of the object at the remote cluster (no-op if the bucket
+// is not replicated).
+func Delete(lom *cluster.LOM) {
+	if enabled(lom) {
+		mgr.enqueue(newOp(OpDelete, lom))
+	}
+}
+
+// Rename queues the renaming of the object at the remote cluster (no-op if the bucket
+// is not replicated).
+func Rename(lom *cluster.LOM, objNameTo string) {
+	if enabled(lom) {
+		op := newOp(OpRename, lom)
+		op.NewName = objNameTo
+		mgr.enqueue(op)
+	}
+}
+
+func newOp(kind string, lom *cluster.LOM) *Op {
+	return &Op{Kind: kind, Bck: lom.Bck().Bck, ObjName: lom.ObjName, Cluster: lom.Bprops().Replication.Cluster}
+}
+
+func enabled(lom *cluster.LOM) bool {
+	return mgr != nil && lom.Bck().Bck.IsAIS() && lom.Bprops().Replication.Enabled
+}
+
+//
+// queue and journal
+//
+
+// load reads the journal and rewrites it with the pending operations only;
+// records that cannot be parsed (e.g. the last one, partially written before
+// the crash) are skipped.
+func (m *Manager) load(path string) error {
+	m.path = path
+	file, err := os.Open(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err == nil {
+		var (
+			ops     []*Op
+			acked   = make(map[int64]struct{})
+			scanner = bufio.NewScanner(file)
+		)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			op, rec := &Op{}, &ack{}
+			if err := jsoniter.Unmarshal(line, rec); err == nil && rec.Done {
+				acked[rec.Seq] = struct{}{}
+				continue
+			}
+			if err := jsoniter.Unmarshal(line, op); err != nil || op.Kind == "" {
+				glog.Warningf("replication queue %q: skipping invalid record %q", path, line)
+				continue
+			}
+			ops = append(ops, op)
+		}
+		file.Close()
+		if err := scanner.Err(); err != nil {
+			glog.Errorf("replication queue %q: %v", path, err)
+		}
+		for _, op := range ops {
+			m.seq = cmn.MaxI64(m.seq, op.Seq)
+			if _, ok := acked[op.Seq]; !ok {
+				m.push(op)
+			}
+		}
+		if m.pending > 0 {
+			glog.Infof("replication queue %q: %d pending operation(s)", path, m.pending)
+		}
+	}
+	return m.rewrite()
+}
+
+// enqueue returns errFull (and drops the operation) if the queue is full
+func (m *Manager) enqueue(op *Op) error {
+	m.mtx.Lock()
+	if m.pending >= m.maxPending {
+		overflow := m.overflow
+		m.overflow = true
+		m.dropped++
+		m.mtx.Unlock()
+		if !overflow {
+			glog.Errorf("%v (%d pending operations): dropping %s and subsequent operations - run backfill to resync",
+				errFull, m.maxPending, op)
+		}
+		return errFull
+	}
+	m.overflow = false
+	m.seq++
+	op.Seq, op.Time = m.seq, time.Now().UnixNano()
+	q := m.push(op)
+	m.write(op)
+	m.mtx.Unlock()
+
+	select {
+	case q.workCh <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// push adds the operation to its destination's queue, creating the latter (and starting
+// its worker) if need be.
+// NOTE: the caller must hold the lock (or have exclusive access)
+func (m *Manager) push(op *Op) *queue {
+	q, ok := m.queues[op.Cluster]
+	if !ok {
+		q = &queue{workCh: make(chan struct{}, 1)}
+		m.queues[op.Cluster] = q
+		if m.running {
+			go m.work(q)
+		}
+	}
+	q.ops = append(q.ops, op)
+	m.setLatest(op)
+	m.pending++
+	return q
+}
+
+// head returns the oldest pending operation of the queue
+func (m *Manager) head(q *queue) (op *Op) {
+	m.mtx.Lock()
+	if len(q.ops) > 0 {
+		op = q.ops[0]
+	}
+	m.mtx.Unlock()
+	return
+}
+
+// done removes the executed (or dropped) operation from the head of the queue
+func (m *Manager) done(q *queue, op *Op) {
+	m.mtx.Lock()
+	cmn.Assert(q.ops[0] == op)
+	q.ops[0] = nil
+	q.ops = q.ops[1:]
+	m.pending--
+	for _, uname := range op.unames() {
+		if m.latest[uname] == op.Seq {
+			delete(m.latest, uname)
+		}
+	}
+	if m.file != nil && m.fileCnt >= compactMinRecords && m.fileCnt > 2*m.pending {
+		if err := m.rewrite(); err != nil {
+			glog.Errorf("failed to compact replication queue %q: %v", m.path, err)
+		}
+	} else {
+		m.write(&ack{Seq: op.Seq, Done: true})
+	}
+	m.mtx.Unlock()
+}
+
+// superseded returns true if there is a later operation on the object in the queue
+func (m *Manager) superseded(op *Op) bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.latest[op.unames()[0]] != op.Seq
+}
+
+// backlog returns the number of the pending operations and the age of the oldest one
+func (m *Manager) backlog() (cnt int64, lag time.Duration) {
+	m.mtx.Lock()
+	cnt = int64(m.pending)
+	for _, q := range m.queues {
+		if len(q.ops) > 0 {
+			lag = cmn.MaxDuration(lag, time.Since(time.Unix(0, q.ops[0].Time)))
+		}
+	}
+	m.mtx.Unlock()
+	return
+}
+
+func (m *Manager) setLatest(op *Op) {
+	for _, uname := range op.unames() {
+		m.latest[uname] = op.Seq
+	}
+}
+
+// NOTE: the caller must hold the lock
+func (m *Manager) write(rec interface{}) {
+	if m.file == nil {
+		return
+	}
+	b := cmn.MustMarshal(rec)
+	if _, err := m.file.Write(append(b, '\n')); err != nil {
+		glog.Errorf("failed to write replication queue %q: %v", m.path, err)
+		return
+	}
+	m.fileCnt++
+	m.dirty = true
+}
+
+// rewrite replaces the journal with the pending operations.
+// NOTE: the caller must hold the lock (or have exclusive access)
+func (m *Manager) rewrite() error {
+	var (
+		buf     bytes.Buffer
+		tmpPath = m.path + ".tmp"
+	)
+	for _, q := range m.queues {
+		for _, op := range q.ops {
+			buf.Write(cmn.MustMarshal(op))
+			buf.WriteByte('\n')
+		}
+	}
+	if m.file != nil {
+		m.file.Close()
+		m.file = nil
+	}
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(buf.Bytes()); err == nil {
+		err = file.Sync()
+	}
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, m.path)
+	}
+	if err != nil {
+		return err
+	}
+	if m.file, err = os.OpenFile(m.path, os.O_APPEND|os.O_WRONLY, 0644); err != nil {
+		return err
+	}
+	m.fileCnt, m.dirty = m.pending, false
+	return nil
+}
+
+func (m *Manager) sync() {
+	m.mtx.Lock()
+	if m.file != nil && m.dirty {
+		if err := m.file.Sync(); err != nil {
+			glog.Errorf("failed to sync replication queue %q: %v", m.path, err)
+		}
+		m.dirty = false
+	}
+	m.mtx.Unlock()
+}
+
+func (m *Manager) stop() {
+	m.stopCh.Close()
+	m.sync()
+	m.mtx.Lock()
+	if m.file != nil {
+		m.file.Close()
+		m.file = nil
+	}
+	m.mtx.Unlock()
+}
+
+func (op *Op) unames() []string {
+	bck := cluster.NewBckEmbed(op.Bck)
+	if op.Kind == OpRename {
+		return []string{bck.MakeUname(op.ObjName), bck.MakeUname(op.NewName)}
+	}
+	return []string{bck.MakeUname(op.ObjName)}
+}
+
+func (op *Op) String() string {
+	if op.Kind == OpRename {
+		return fmt.Sprintf("%s %s/%s => %s", op.Kind, op.Bck, op.ObjName, op.NewName)
+	}
+	return fmt.Sprintf("%s %s/%s", op.Kind, op.Bck, op.ObjName)
+}
+
+//
+// worker
+//
+
+func (m *Manager) run() {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	m.mtx.Lock()
+	m.running = true
+	for _, q := range m.queues {
+		go m.work(q)
+	}
+	m.mtx.Unlock()
+	for {
+		select {
+		case <-ticker.C:
+			m.sync()
+			m.updateStats()
+		case <-m.stopCh.Listen():
+			return
+		}
+	}
+}
+
+// work executes the operations of the destination's queue
+func (m *Manager) work(q *queue) {
+	var (
+		backoff time.Duration
+		retryCh <-chan time.Time // non-nil: backing off
+	)
+	for {
+		op := m.head(q)
+		if op != nil && retryCh == nil {
+			size, err := m.do(op)
+			if err != nil && retriable(err) && op.attempts < maxAttempts-1 {
+				op.attempts++
+				backoff = cmn.MinDuration(cmn.MaxDuration(2*backoff, retryMin), retryMax)
+				glog.Warningf("replication: %s failed (attempt %d/%d), retrying in %v: %v",
+					op, op.attempts, maxAttempts, backoff, err)
+				retryCh = time.After(backoff)
+			} else {
+				m.done(q, op)
+				if err != nil {
+					glog.Errorf("replication: %s failed: %v", op, err)
+					m.statsT.Add(stats.ErrReplCount, 1)
+				} else {
+					m.statsT.AddMany(
+						stats.NamedVal64{Name: stats.ReplCount, Value: 1},
+						stats.NamedVal64{Name: stats.ReplSize, Value: size},
+					)
+				}
+				// keep backing off while the remote cluster stays offline
+				if err == nil || !retriable(err) {
+					backoff = 0
+				}
+				select {
+				case <-m.stopCh.Listen():
+					return
+				default:
+				}
+				continue
+			}
+		}
+		select {
+		case <-q.workCh:
+		case <-retryCh:
+			retryCh = nil
+		case <-m.stopCh.Listen():
+			return
+		}
+	}
+}
+
+func (m *Manager) updateStats() {
+	m.mtx.Lock()
+	dropped := m.dropped
+	m.dropped = 0
+	m.mtx.Unlock()
+	if dropped > 0 {
+		m.statsT.Add(stats.ErrReplCount, dropped)
+	}
+	m.statsT.UpdateReplication(m.backlog())
+}
+
+// do executes the operation at the remote cluster; returns the number of bytes sent
+func (m *Manager) do(op *Op) (size int64, err error) {
+	bck := cluster.NewBckEmbed(op.Bck)
+	if err := bck.Init(m.t.GetBowner(), m.t.Snode()); err != nil {
+		glog.Warningf("replication: %s skipped: %v", op, err)
+		return 0, nil // the bucket has been destroyed in the meantime
+	}
+	conf := &bck.Props.Replication
+	if !conf.Enabled {
+		return 0, nil
+	}
+	if op.Cluster != "" && op.Cluster != conf.Cluster {
+		glog.Warningf("replication: %s skipped: destination changed %q => %q", op, op.Cluster, conf.Cluster)
+		return 0, nil // backfill takes care of the new destination
+	}
+	bp, err := m.remote.BaseParams(conf.Cluster)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", errNoRemote, err)
+	}
+	dst := conf.DstBck(op.Bck)
+	switch op.Kind {
+	case OpPut:
+		if m.superseded(op) {
+			return 0, nil
+		}
+		return m.put(bp, bck, dst, op.ObjName)
+	case OpDelete:
+		err = deleteRemote(bp, dst, op.ObjName)
+	case OpRename:
+		if err = api.RenameObject(bp, dst, op.ObjName, op.NewName); err == nil || retriable(err) {
+			return 0, err
+		}
+		if size, err = m.put(bp, bck, dst, op.NewName); err == nil {
+			err = deleteRemote(bp, dst, op.ObjName)
+		}
+	default:
+		err = &localError{fmt.Errorf("unknown operation %q", op.Kind)}
+	}
+	return
+}
+
+func (m *Manager) put(bp api.BaseParams, bck *cluster.Bck, dst cmn.Bck, objName string) (size int64, err error) {
+	lom := &cluster.LOM{T: m.t, ObjName: objName}
+	if err = lom.Init(bck.Bck); err != nil {
+		return 0, &localError{err}
+	}
+	// The lock is held only to load the object and open its file: PUT replaces the file
+	// (renames the work file) and DELETE removes it, so that the open handle keeps reading
+	// the loaded content while the changes get replicated by the later operations.
+	lom.Lock(false)
+	if err = lom.Load(false); err != nil {
+		lom.Unlock(false)
+		if cmn.IsObjNotExist(err) {
+			return 0, nil // deleted or renamed in the meantime
+		}
+		return 0, &localError{err}
+	}
+	fh, err := cmn.NewFileHandle(lom.FQN)
+	lom.Unlock(false)
+	if err != nil {
+		return 0, &localError{err}
+	}
+	defer fh.Close()
+	args := api.PutObjectArgs{
+		BaseParams: bp,
+		Bck:        dst,
+		Object:     objName,
+		Cksum:      lom.Cksum(),
+		Reader:     fh,
+		Size:       uint64(lom.Size()),
+		CustomMD:   lom.CustomMD(),
+	}
+	if err = api.PutObject(args); err != nil {
+		return 0, err
+	}
+	return lom.Size(), nil
+}
+
+func deleteRemote(bp api.BaseParams, dst cmn.Bck, objName string) error {
+	err := api.DeleteObject(bp, dst, objName)
+	if httpErr := (&cmn.HTTPError{}); errors.As(err, &httpErr) && httpErr.Status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// retriable returns true if the error indicates that the remote cluster is offline,
+// unreachable, or overloaded
+func retriable(err error) bool {
+	if errors.As(err, new(*localError)) || errors.Is(err, errNoRemote) {
+		return false
+	}
+	httpErr := &cmn.HTTPError{}
+	if errors.As(err, &httpErr) {
+		return httpErr.Status >= http.StatusInternalServerError ||
+			httpErr.Status == http.StatusRequestTimeout || httpErr.Status == http.StatusTooManyRequests
+	}
+	return true // connection refused, timeout, etc.
+}
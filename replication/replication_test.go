@@ -0,0 +1,167 @@
+// Package replication asynchronously replicates PUTs, DELETEs, and renames of the objects
+// of AIS buckets to the buckets of attached remote AIS clusters.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+var testBck = cmn.Bck{Name: "src", Provider: cmn.ProviderAIS}
+
+func TestQueuePersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replqueue")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, QueueFname)
+
+	m := newManager(nil, nil, nil)
+	tassert.CheckFatal(t, m.load(path))
+	for i := 0; i < 10; i++ {
+		m.enqueue(&Op{Kind: OpPut, Bck: testBck, ObjName: fmt.Sprintf("obj-%d", i)})
+	}
+	m.enqueue(&Op{Kind: OpRename, Bck: testBck, ObjName: "obj-0", NewName: "obj-new"})
+	q := m.queues[""]
+	for i := 0; i < 4; i++ {
+		m.done(q, m.head(q))
+	}
+	m.stop()
+
+	// Partially written record (eg. crash) must be skipped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	tassert.CheckFatal(t, err)
+	_, err = f.WriteString(`{"seq": "12", "op": "del`)
+	tassert.CheckFatal(t, err)
+	f.Close()
+
+	loaded := newManager(nil, nil, nil)
+	tassert.CheckFatal(t, loaded.load(path))
+	defer loaded.stop()
+	ops := loaded.queues[""].ops
+	tassert.Fatalf(t, len(ops) == 7, "expected 7 pending operations, got %d", len(ops))
+	tassert.Errorf(t, ops[0].ObjName == "obj-4", "expected obj-4 at the head, got %s", ops[0])
+	tassert.Errorf(t, ops[6].Kind == OpRename && ops[6].NewName == "obj-new",
+		"expected rename at the tail, got %s", ops[6])
+	tassert.Errorf(t, loaded.fileCnt == 7, "expected the journal to be compacted, got %d records", loaded.fileCnt)
+
+	// Sequence numbers continue after restart.
+	tassert.CheckFatal(t, loaded.enqueue(&Op{Kind: OpDelete, Bck: testBck, ObjName: "obj-5"}))
+	ops = loaded.queues[""].ops
+	tassert.Errorf(t, ops[7].Seq == 12, "expected seq 12, got %d", ops[7].Seq)
+	cnt, lag := loaded.backlog()
+	tassert.Errorf(t, cnt == 8 && lag > 0, "unexpected backlog %d, lag %v", cnt, lag)
+}
+
+func TestQueueCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replqueue")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+
+	m := newManager(nil, nil, nil)
+	tassert.CheckFatal(t, m.load(filepath.Join(dir, QueueFname)))
+	defer m.stop()
+	for i := 0; i < 3*compactMinRecords; i++ {
+		m.enqueue(&Op{Kind: OpPut, Bck: testBck, ObjName: fmt.Sprintf("obj-%d", i)})
+		if i%2 == 0 {
+			q := m.queues[""]
+			m.done(q, m.head(q))
+		}
+	}
+	tassert.Errorf(t, m.pending == 3*compactMinRecords/2, "expected %d pending operations, got %d",
+		3*compactMinRecords/2, m.pending)
+	tassert.Errorf(t, m.fileCnt <= 2*m.pending+1, "expected the journal to be compacted, got %d records for %d operations",
+		m.fileCnt, m.pending)
+	tassert.Errorf(t, len(m.latest) == m.pending, "expected %d latest entries, got %d", m.pending, len(m.latest))
+}
+
+func TestQueueSuperseded(t *testing.T) {
+	m := newManager(nil, nil, nil)
+	put := &Op{Kind: OpPut, Bck: testBck, ObjName: "a"}
+	m.enqueue(put)
+	tassert.Errorf(t, !m.superseded(put), "expected %s to be the latest operation", put)
+	m.enqueue(&Op{Kind: OpRename, Bck: testBck, ObjName: "b", NewName: "a"})
+	tassert.Errorf(t, m.superseded(put), "expected %s to be superseded by rename", put)
+
+	other := &Op{Kind: OpPut, Bck: cmn.Bck{Name: "other", Provider: cmn.ProviderAIS}, ObjName: "a"}
+	m.enqueue(other)
+	tassert.Errorf(t, !m.superseded(other), "expected %s to be the latest operation", other)
+
+	m.done(m.queues[""], put)
+	tassert.Errorf(t, len(m.latest) == 3, "expected 3 latest entries, got %d", len(m.latest))
+}
+
+func TestQueueDestinations(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replqueue")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, QueueFname)
+
+	m := newManager(nil, nil, nil)
+	tassert.CheckFatal(t, m.load(path))
+	for i := 0; i < 6; i++ {
+		m.enqueue(&Op{Kind: OpPut, Bck: testBck, ObjName: fmt.Sprintf("obj-%d", i), Cluster: fmt.Sprintf("remais-%d", i%2)})
+	}
+	tassert.Fatalf(t, len(m.queues) == 2, "expected 2 queues, got %d", len(m.queues))
+	// Offline destination does not hold back the other one.
+	q := m.queues["remais-1"]
+	for i := 0; i < 3; i++ {
+		m.done(q, m.head(q))
+	}
+	tassert.Errorf(t, m.head(q) == nil, "expected empty queue")
+	tassert.Errorf(t, len(m.queues["remais-0"].ops) == 3, "expected 3 pending operations, got %d",
+		len(m.queues["remais-0"].ops))
+	m.stop()
+
+	loaded := newManager(nil, nil, nil)
+	tassert.CheckFatal(t, loaded.load(path))
+	defer loaded.stop()
+	tassert.Errorf(t, loaded.pending == 3 && len(loaded.queues["remais-0"].ops) == 3,
+		"expected 3 pending operations, got %d", loaded.pending)
+	tassert.Errorf(t, loaded.queues["remais-0"].ops[0].ObjName == "obj-0", "expected obj-0 at the head, got %s",
+		loaded.queues["remais-0"].ops[0])
+}
+
+func TestQueueFull(t *testing.T) {
+	m := newManager(nil, nil, nil)
+	m.maxPending = 4
+	for i := 0; i < 10; i++ {
+		err := m.enqueue(&Op{Kind: OpPut, Bck: testBck, ObjName: fmt.Sprintf("obj-%d", i)})
+		tassert.Errorf(t, (i < m.maxPending) == (err == nil), "obj-%d: unexpected err: %v", i, err)
+	}
+	tassert.Errorf(t, m.pending == 4 && len(m.latest) == 4, "expected 4 pending operations, got %d (%d latest)",
+		m.pending, len(m.latest))
+	tassert.Errorf(t, m.dropped == 6, "expected 6 dropped operations, got %d", m.dropped)
+
+	q := m.queues[""]
+	m.done(q, m.head(q))
+	tassert.CheckError(t, m.enqueue(&Op{Kind: OpDelete, Bck: testBck, ObjName: "obj-0"}))
+}
+
+func TestRetriable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retriable bool
+	}{
+		{errors.New("failed to PUT, err: dial tcp: connection refused"), true},
+		{fmt.Errorf("%w: unknown uuid", errNoRemote), false},
+		{&cmn.HTTPError{Status: http.StatusServiceUnavailable}, true},
+		{&cmn.HTTPError{Status: http.StatusTooManyRequests}, true},
+		{&cmn.HTTPError{Status: http.StatusNotFound}, false},
+		{&cmn.HTTPError{Status: http.StatusBadRequest}, false},
+		{&localError{errors.New("failed to open")}, false},
+	}
+	for _, test := range tests {
+		tassert.Errorf(t, retriable(test.err) == test.retriable, "%v: expected retriable=%t", test.err, test.retriable)
+	}
+}
@@ -100,9 +100,10 @@ type (
 		Value      int64
 	}
 	CoreStats struct {
-		Tracker   statsTracker
-		statsdC   *statsd.Client
-		statsTime time.Duration
+		Tracker    statsTracker
+		statsdC    *statsd.Client
+		statsTime  time.Duration
+		promLabels string // node labels of the exported Prometheus metrics
 	}
 
 	TargetStatus struct {
@@ -161,7 +162,8 @@ type (
 		kind       string
 		numSamples int64
 		cumulative int64
-		isCommon   bool // optional, common to the proxy and target
+		hist       *histogram // KindLatency only: exported to Prometheus
+		isCommon   bool       // optional, common to the proxy and target
 	}
 	copyValue struct {
 		Value int64 `json:"v,string"`
@@ -224,6 +226,7 @@ func (s *CoreStats) doAdd(name, nameSuffix string, val int64) {
 		v.Lock()
 		v.numSamples++
 		val = int64(time.Duration(val) / time.Microsecond)
+		v.hist.observe(val)
 		v.cumulative += val
 		v.Value += val
 		v.Unlock()
@@ -365,6 +368,9 @@ func (tracker statsTracker) register(key, kind string, isCommon ...bool) {
 	cmn.AssertMsg(cmn.StringInSlice(kind, kinds), "invalid stats kind '"+kind+"'")
 
 	tracker[key] = &statsValue{kind: kind}
+	if kind == KindLatency {
+		tracker[key].hist = newHistogram()
+	}
 	if len(isCommon) > 0 {
 		tracker[key].isCommon = isCommon[0]
 	}
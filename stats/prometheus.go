@@ -0,0 +1,296 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/fs"
+	"github.com/NVIDIA/aistore/ios"
+)
+
+// Prometheus exporter: every node serves its stats at GET /metrics in the
+// Prometheus text exposition format. Unlike StatsD (push), Prometheus scrapes
+// (pull) - the two exporters are independent and run at the same time.
+//
+// Naming: "get.n" => ais_get_total, "get.cold.size" => ais_get_cold_bytes_total,
+// "get.µs" => ais_get_latency_seconds (histogram), "get.bps" => ais_get_bytes_total.
+// All metrics are labeled with node_id and node_type.
+
+const (
+	PromContentType = "text/plain; version=0.0.4; charset=utf-8"
+	promPrefix      = "ais_"
+)
+
+// upper bounds of the latency histogram buckets (µs)
+var promLatencyBuckets = []int64{
+	100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000,
+	100000, 250000, 500000, 1000000, 2500000, 5000000, 10000000,
+}
+
+var promEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+type (
+	// cumulative latency histogram (never reset, unlike statsValue.Value)
+	histogram struct {
+		counts []int64 // per bucket (non-cumulative), the last one is +Inf
+		count  int64
+		sum    int64 // µs
+	}
+	promWriter struct {
+		w      *bufio.Writer
+		labels string // node labels
+	}
+)
+
+//
+// histogram (NOTE: protected by the statsValue lock)
+//
+
+func newHistogram() *histogram { return &histogram{counts: make([]int64, len(promLatencyBuckets)+1)} }
+
+func (h *histogram) observe(us int64) {
+	idx := sort.Search(len(promLatencyBuckets), func(i int) bool { return us <= promLatencyBuckets[i] })
+	h.counts[idx]++
+	h.count++
+	h.sum += us
+}
+
+//
+// promWriter
+//
+
+func newPromWriter(w io.Writer, labels string) *promWriter {
+	return &promWriter{w: bufio.NewWriter(w), labels: labels}
+}
+
+func (pw *promWriter) metric(name, typ, help string) {
+	pw.w.WriteString("# HELP " + name + " " + help + "\n")
+	pw.w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// labels: additional label name-value pairs
+func (pw *promWriter) sample(name, value string, labels ...string) {
+	pw.w.WriteString(name + "{" + pw.labels)
+	for i := 0; i < len(labels); i += 2 {
+		pw.w.WriteString("," + labels[i] + "=\"" + promEscaper.Replace(labels[i+1]) + "\"")
+	}
+	pw.w.WriteString("} " + value + "\n")
+}
+
+func (pw *promWriter) flush() error { return pw.w.Flush() }
+
+func promSeconds(us int64) string { return strconv.FormatFloat(float64(us)/1e6, 'g', -1, 64) }
+
+// converts stats name to Prometheus metric name (see naming above)
+func promName(name, kind string) string {
+	switch kind {
+	case KindCounter:
+		if strings.HasSuffix(name, ".size") {
+			name = strings.TrimSuffix(name, ".size") + "_bytes_total"
+		} else {
+			name = strings.TrimSuffix(name, ".n") + "_total"
+		}
+	case KindLatency:
+		name = strings.Replace(name, "µs", "latency", 1) + "_seconds"
+	case KindThroughput:
+		name = strings.TrimSuffix(name, ".bps") + "_bytes_total"
+	}
+	b := []byte(promPrefix + name)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '_' {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+//
+// CoreStats => /metrics
+//
+
+func (s *CoreStats) initProm(node *cluster.Snode) {
+	s.promLabels = "node_id=\"" + promEscaper.Replace(node.ID()) + "\",node_type=\"" + node.Type() + "\""
+}
+
+func (s *CoreStats) writeProm(pw *promWriter) {
+	names := make([]string, 0, len(s.Tracker))
+	for name := range s.Tracker {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := s.Tracker[name]
+		if name == Uptime {
+			v.RLock()
+			pw.metric(promPrefix+"uptime_seconds", "gauge", "time since the node has started")
+			pw.sample(promPrefix+"uptime_seconds", promSeconds(v.Value))
+			v.RUnlock()
+			continue
+		}
+		if name == ReplLag {
+			v.RLock()
+			pw.metric(promPrefix+"repl_lag_seconds", "gauge", "age of the oldest pending replication operation")
+			pw.sample(promPrefix+"repl_lag_seconds", promSeconds(v.Value))
+			v.RUnlock()
+			continue
+		}
+		pname := promName(name, v.kind)
+		v.RLock()
+		switch v.kind {
+		case KindCounter:
+			pw.metric(pname, "counter", name)
+			pw.sample(pname, strconv.FormatInt(v.Value, 10))
+		case KindThroughput:
+			pw.metric(pname, "counter", name+" (bytes): use rate() to compute throughput")
+			pw.sample(pname, strconv.FormatInt(v.cumulative, 10))
+		case KindLatency:
+			var cnt int64
+			pw.metric(pname, "histogram", name)
+			for i, bound := range promLatencyBuckets {
+				cnt += v.hist.counts[i]
+				pw.sample(pname+"_bucket", strconv.FormatInt(cnt, 10), "le", promSeconds(bound))
+			}
+			pw.sample(pname+"_bucket", strconv.FormatInt(v.hist.count, 10), "le", "+Inf")
+			pw.sample(pname+"_sum", promSeconds(v.hist.sum))
+			pw.sample(pname+"_count", strconv.FormatInt(v.hist.count, 10))
+		default:
+			pw.metric(pname, "gauge", name)
+			pw.sample(pname, strconv.FormatInt(v.Value, 10))
+		}
+		v.RUnlock()
+	}
+}
+
+func (r *Prunner) WriteProm(w io.Writer) error {
+	pw := newPromWriter(w, r.Core.promLabels)
+	r.Core.writeProm(pw)
+	return pw.flush()
+}
+
+// in addition to the core stats, target exports mountpath capacities and
+// utilizations, disk IO, and the stats of the given xactions
+func (r *Trunner) WriteProm(w io.Writer, xacts []XactStats) error {
+	pw := newPromWriter(w, r.Core.promLabels)
+	r.Core.writeProm(pw)
+	r.writePromMountpaths(pw)
+	writePromXactions(pw, xacts)
+	return pw.flush()
+}
+
+func (r *Trunner) writePromMountpaths(pw *promWriter) {
+	var (
+		capacity = r.capacity()
+		mpaths   = make([]string, 0, len(capacity))
+		utils    = fs.Mountpaths.GetAllMpathUtils(time.Now())
+		disks    = fs.Mountpaths.GetSelectedDiskStats()
+	)
+	for mpath := range capacity {
+		mpaths = append(mpaths, mpath)
+	}
+	sort.Strings(mpaths)
+	for _, m := range []struct {
+		name, help string
+		value      func(c *fscapacity) uint64
+	}{
+		{"mountpath_used_bytes", "used mountpath capacity", func(c *fscapacity) uint64 { return c.Used }},
+		{"mountpath_avail_bytes", "available mountpath capacity", func(c *fscapacity) uint64 { return c.Avail }},
+		{"mountpath_used_percent", "used mountpath capacity (%)", func(c *fscapacity) uint64 { return uint64(c.Usedpct) }},
+	} {
+		pw.metric(promPrefix+m.name, "gauge", m.help)
+		for _, mpath := range mpaths {
+			pw.sample(promPrefix+m.name, strconv.FormatUint(m.value(capacity[mpath]), 10), "mountpath", mpath)
+		}
+	}
+
+	mpaths = mpaths[:0]
+	for mpath := range utils {
+		mpaths = append(mpaths, mpath)
+	}
+	sort.Strings(mpaths)
+	pw.metric(promPrefix+"mountpath_util_percent", "gauge", "mountpath disk utilization (%)")
+	for _, mpath := range mpaths {
+		pw.sample(promPrefix+"mountpath_util_percent", strconv.FormatInt(utils[mpath], 10), "mountpath", mpath)
+	}
+
+	names := make([]string, 0, len(disks))
+	for disk := range disks {
+		names = append(names, disk)
+	}
+	sort.Strings(names)
+	for _, m := range []struct {
+		name, help string
+		value      func(ds *ios.SelectedDiskStats) int64
+	}{
+		{"disk_read_bytes_per_second", "disk read throughput", func(ds *ios.SelectedDiskStats) int64 { return ds.RBps }},
+		{"disk_write_bytes_per_second", "disk write throughput", func(ds *ios.SelectedDiskStats) int64 { return ds.WBps }},
+		{"disk_util_percent", "disk utilization (%)", func(ds *ios.SelectedDiskStats) int64 { return ds.Util }},
+	} {
+		pw.metric(promPrefix+m.name, "gauge", m.help)
+		for _, disk := range names {
+			pw.sample(promPrefix+m.name, strconv.FormatInt(m.value(disks[disk]), 10), "disk", disk)
+		}
+	}
+}
+
+// xactions are aggregated by kind and bucket: labeling by xaction ID would
+// create a new time series for every xaction run
+func writePromXactions(pw *promWriter, xacts []XactStats) {
+	type (
+		xactKey struct {
+			kind, bck string
+		}
+		xactAgg struct {
+			objects, bytes, running int64
+		}
+	)
+	var (
+		keys = make([]xactKey, 0, len(xacts))
+		aggs = make(map[xactKey]*xactAgg, len(xacts))
+	)
+	for _, xact := range xacts {
+		key := xactKey{kind: xact.Kind()}
+		if !xact.Bck().IsEmpty() {
+			key.bck = xact.Bck().String()
+		}
+		agg, ok := aggs[key]
+		if !ok {
+			agg = &xactAgg{}
+			aggs[key] = agg
+			keys = append(keys, key)
+		}
+		agg.objects += xact.ObjCount()
+		agg.bytes += xact.BytesCount()
+		if xact.Running() {
+			agg.running++
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].kind != keys[j].kind {
+			return keys[i].kind < keys[j].kind
+		}
+		return keys[i].bck < keys[j].bck
+	})
+	for _, m := range []struct {
+		name, help string
+		value      func(agg *xactAgg) int64
+	}{
+		{"xaction_objects", "number of objects processed by xactions", func(agg *xactAgg) int64 { return agg.objects }},
+		{"xaction_bytes", "number of bytes processed by xactions", func(agg *xactAgg) int64 { return agg.bytes }},
+		{"xaction_running", "number of running xactions", func(agg *xactAgg) int64 { return agg.running }},
+	} {
+		pw.metric(promPrefix+m.name, "gauge", m.help)
+		for _, key := range keys {
+			pw.sample(promPrefix+m.name, strconv.FormatInt(m.value(aggs[key]), 10), "kind", key.kind, "bucket", key.bck)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package stats provides methods and functionality to register, track, log,
+// and StatsD-notify statistics that, for the most part, include "counter" and "latency" kinds.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package stats
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cluster"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats/statsd"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func TestPromNames(t *testing.T) {
+	tests := []struct{ name, kind, expected string }{
+		{GetCount, KindCounter, "ais_get_total"},
+		{ErrGetCount, KindCounter, "ais_err_get_total"},
+		{GetColdSize, KindCounter, "ais_get_cold_bytes_total"},
+		{GetLatency, KindLatency, "ais_get_latency_seconds"},
+		{KeepAliveMinLatency, KindLatency, "ais_kalive_latency_min_seconds"},
+		{GetThroughput, KindThroughput, "ais_get_bytes_total"},
+	}
+	for _, test := range tests {
+		name := promName(test.name, test.kind)
+		tassert.Errorf(t, name == test.expected, "%q: expected %q, got %q", test.name, test.expected, name)
+	}
+}
+
+func TestPromWrite(t *testing.T) {
+	var (
+		buf bytes.Buffer
+		r   = &Prunner{Core: &CoreStats{statsdC: &statsd.Client{}}}
+	)
+	r.Core.init(24)
+	r.Core.initProm(&cluster.Snode{DaemonID: "p1", DaemonType: "proxy"})
+	r.Core.doAdd(GetCount, "", 3)
+	r.Core.doAdd(GetLatency, "", int64(300*time.Microsecond))
+	r.Core.doAdd(GetLatency, "", int64(2*time.Millisecond))
+	r.Core.doAdd(GetLatency, "", int64(time.Minute))
+	r.Core.copyT(make(copyTracker), nil) // resets the averages but not the histograms
+	tassert.CheckFatal(t, r.WriteProm(&buf))
+
+	out := buf.String()
+	for _, line := range []string{
+		"# TYPE ais_get_total counter",
+		`ais_get_total{node_id="p1",node_type="proxy"} 3`,
+		"# TYPE ais_get_latency_seconds histogram",
+		`ais_get_latency_seconds_bucket{node_id="p1",node_type="proxy",le="0.00025"} 0`,
+		`ais_get_latency_seconds_bucket{node_id="p1",node_type="proxy",le="0.0005"} 1`,
+		`ais_get_latency_seconds_bucket{node_id="p1",node_type="proxy",le="0.0025"} 2`,
+		`ais_get_latency_seconds_bucket{node_id="p1",node_type="proxy",le="10"} 2`,
+		`ais_get_latency_seconds_bucket{node_id="p1",node_type="proxy",le="+Inf"} 3`,
+		`ais_get_latency_seconds_sum{node_id="p1",node_type="proxy"} 60.0023`,
+		`ais_get_latency_seconds_count{node_id="p1",node_type="proxy"} 3`,
+		"# TYPE ais_uptime_seconds gauge",
+	} {
+		tassert.Errorf(t, strings.Contains(out, line+"\n"), "expected %q in:\n%s", line, out)
+	}
+}
+
+func TestPromXactions(t *testing.T) {
+	var (
+		bck   = cmn.Bck{Name: "abc", Provider: cmn.ProviderAIS, Ns: cmn.NsGlobal}
+		xacts = []XactStats{
+			&BaseXactStats{IDX: "1", KindX: "lru", ObjCountX: 1, BytesCountX: 10, EndTimeX: time.Now()},
+			&BaseXactStats{IDX: "2", KindX: "lru", ObjCountX: 2, BytesCountX: 20},
+			&BaseXactStats{IDX: "3", KindX: "lru", BckX: bck, ObjCountX: 4, BytesCountX: 40},
+		}
+		buf bytes.Buffer
+	)
+	pw := newPromWriter(&buf, `node_id="t1"`)
+	writePromXactions(pw, xacts)
+	tassert.CheckFatal(t, pw.flush())
+	out := buf.String()
+	for _, line := range []string{
+		`ais_xaction_objects{node_id="t1",kind="lru",bucket=""} 3`,
+		`ais_xaction_bytes{node_id="t1",kind="lru",bucket=""} 30`,
+		`ais_xaction_running{node_id="t1",kind="lru",bucket=""} 1`,
+		`ais_xaction_objects{node_id="t1",kind="lru",bucket="` + bck.String() + `"} 4`,
+	} {
+		tassert.Errorf(t, strings.Contains(out, line+"\n"), "expected %q in:\n%s", line, out)
+	}
+	tassert.Errorf(t, !strings.Contains(out, `,id="`), "unexpected xaction ID label in:\n%s", out)
+}
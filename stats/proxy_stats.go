@@ -46,6 +46,7 @@ func (r *Prunner) Init(p cluster.Proxy) *atomic.Bool {
 	r.Core.statsTime = cmn.GCO.Get().Periodic.StatsTime
 	r.ctracker = make(copyTracker, 24)
 	r.Core.initStatsD(p.Snode())
+	r.Core.initProm(p.Snode())
 
 	r.statsRunner.daemon = p
 
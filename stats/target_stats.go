@@ -7,6 +7,7 @@ package stats
 
 import (
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -39,6 +40,9 @@ const (
 	ErrMetadataCount = "err.md.n"
 	ErrIOCount       = "err.io.n"
 	DownloadSize     = "dl.size"
+	ReplCount        = "repl.n"
+	ReplSize         = "repl.size"
+	ErrReplCount     = "err.repl.n"
 
 	// KindLatency
 	PutLatency      = "put.µs"
@@ -57,6 +61,10 @@ const (
 
 	// KindID
 	RebGlobID = "reb.glob.id"
+
+	// KindSpecial (see UpdateReplication)
+	ReplBacklog = "repl.backlog" // number of pending replication operations
+	ReplLag     = "repl.lag.µs"  // age of the oldest pending replication operation
 )
 
 //
@@ -67,8 +75,9 @@ type (
 		statsRunner
 		T        cluster.Target         `json:"-"`
 		Core     *CoreStats             `json:"core"`
-		Capacity map[string]*fscapacity `json:"capacity"`
+		Capacity map[string]*fscapacity `json:"capacity"` // NOTE: replaced (not modified) under capMu
 		// inner state
+		capMu      sync.RWMutex
 		timecounts struct {
 			capLimit atomic.Int64
 			capIdx   int64 // update capacity: time interval counting
@@ -104,6 +113,7 @@ func (r *Trunner) Init(t cluster.Target) *atomic.Bool { // nolint:interfacer //
 	r.Core = &CoreStats{}
 	r.Core.init(48) // and register common stats (target's own stats are registered elsewhere via the Register() above)
 	r.Core.initStatsD(t.Snode())
+	r.Core.initProm(t.Snode())
 
 	r.ctracker = make(copyTracker, 48) // these two are allocated once and only used in serial context
 	r.lines = make([]string, 0, 16)
@@ -123,6 +133,18 @@ func (r *Trunner) Init(t cluster.Target) *atomic.Bool { // nolint:interfacer //
 	return &r.statsRunner.startedUp
 }
 
+// UpdateReplication sets the current replication backlog and lag
+func (r *Trunner) UpdateReplication(backlog int64, lag time.Duration) {
+	v := r.Core.Tracker[ReplBacklog]
+	v.Lock()
+	v.Value = backlog
+	v.Unlock()
+	v = r.Core.Tracker[ReplLag]
+	v.Lock()
+	v.Value = int64(lag / time.Microsecond)
+	v.Unlock()
+}
+
 func (r *Trunner) ConfigUpdate(oldConf, newConf *cmn.Config) {
 	r.statsRunner.ConfigUpdate(oldConf, newConf)
 	if oldConf.Periodic.StatsTime != newConf.Periodic.StatsTime {
@@ -134,11 +156,18 @@ func (r *Trunner) ConfigUpdate(oldConf, newConf *cmn.Config) {
 	}
 }
 
+// returns the latest mountpath capacities (read-only)
+func (r *Trunner) capacity() map[string]*fscapacity {
+	r.capMu.RLock()
+	defer r.capMu.RUnlock()
+	return r.Capacity
+}
+
 func (r *Trunner) GetWhatStats() []byte {
 	ctracker := make(copyTracker, 48)
 	r.Core.copyCumulative(ctracker)
 
-	crunner := &copyRunner{Tracker: ctracker, Capacity: r.Capacity}
+	crunner := &copyRunner{Tracker: ctracker, Capacity: r.capacity()}
 	return cmn.MustMarshal(crunner)
 }
 
@@ -162,7 +191,7 @@ func (r *Trunner) log(uptime time.Duration) {
 			}
 		}
 		r.timecounts.capIdx = 0
-		for mpath, fsCapacity := range r.Capacity {
+		for mpath, fsCapacity := range r.capacity() {
 			b := cmn.MustMarshal(fsCapacity)
 			r.lines = append(r.lines, mpath+": "+string(b))
 		}
@@ -206,7 +235,9 @@ func (r *Trunner) UpdateCapacityOOS(availableMountpaths fs.MPI) (runlru bool) {
 		}
 		usedNow += fsCap.Usedpct
 	}
+	r.capMu.Lock()
 	r.Capacity = capacities
+	r.capMu.Unlock()
 
 	// handle out-of-space
 	usedNow /= int32(l)
@@ -41,6 +41,14 @@ type (
 		BaseXactStats
 		Ext interface{} `json:"ext"`
 	}
+
+	// XactRecord is the record of finished xaction kept in the xaction
+	// history of the node (see: xaction.Registry.GetHistory).
+	XactRecord struct {
+		BaseXactStats
+		AbortReason string `json:"abort_reason,omitempty"`
+		Initiator   string `json:"initiator"`
+	}
 )
 
 var (
@@ -95,3 +103,54 @@ func (s *RebalanceTargetStats) FillFromTrunner(r *Trunner) {
 	s.ObjCountX = s.Ext.RxRebCount + s.Ext.TxRebCount
 	s.BytesCountX = s.Ext.RxRebSize + s.Ext.TxRebSize
 }
+
+type LifecycleStats struct {
+	BaseXactStats
+	Ext ExtLifecycleStats `json:"ext"`
+}
+
+type ExtLifecycleStats struct {
+	ExpiredCount    int64 `json:"expired.n,string"`
+	ExpiredSize     int64 `json:"expired.size,string"`
+	EvictedCount    int64 `json:"evicted.n,string"`
+	EvictedSize     int64 `json:"evicted.size,string"`
+	NoncurrentCount int64 `json:"noncurrent.n,string"`
+	NoncurrentSize  int64 `json:"noncurrent.size,string"`
+}
+
+type ECReencodeStats struct {
+	BaseXactStats
+	Ext ExtECReencodeStats `json:"ext"`
+}
+
+type ExtECReencodeStats struct {
+	ScannedCount int64 `json:"scanned.n,string"` // EC-encoded objects checked against the bucket's EC configuration
+	FailedCount  int64 `json:"failed.n,string"`  // objects that failed to get re-encoded
+}
+
+type ScrubStats struct {
+	BaseXactStats
+	Ext ExtScrubStats `json:"ext"`
+}
+
+type ExtScrubStats struct {
+	SliceCount     int64 `json:"slices.n,string"`          // verified EC slices
+	BadObjCount    int64 `json:"bad.obj.n,string"`         // corrupted objects
+	BadCopyCount   int64 `json:"bad.copy.n,string"`        // corrupted or missing copies
+	BadSliceCount  int64 `json:"bad.slice.n,string"`       // corrupted (and removed) EC slices
+	RepairedMirror int64 `json:"repaired.mirror.n,string"` // objects restored from local copies
+	RepairedEC     int64 `json:"repaired.ec.n,string"`     // objects restored from EC slices or replicas
+	RepairedCloud  int64 `json:"repaired.cloud.n,string"`  // objects fetched again from the Cloud
+	RepairedCopies int64 `json:"repaired.copy.n,string"`   // copies restored from their objects
+	Unrepaired     int64 `json:"unrepaired.n,string"`      // corrupted objects that could not be restored
+}
+
+type ReplBackfillStats struct {
+	BaseXactStats
+	Ext ExtReplBackfillStats `json:"ext"`
+}
+
+type ExtReplBackfillStats struct {
+	ScannedCount int64 `json:"scanned.n,string"` // objects checked against the remote bucket
+	InSyncCount  int64 `json:"insync.n,string"`  // objects that have been already replicated
+}
@@ -99,7 +99,7 @@ func extractRecords(target cluster.Target, lom *cluster.LOM) (*extract.RecordMan
 
 	keyExtractor, err := extract.NewNameKeyExtractor()
 	cmn.AssertNoErr(err) // err always nil
-	recordManager := extract.NewRecordManager(target, target.Snode().DaemonID, lom.Bck().Name, lom.Bck().Provider, ext, extractCreator, keyExtractor, onDuplicates)
+	recordManager := extract.NewRecordManager(target, target.Snode().DaemonID, lom.Bck().Name, lom.Bck().Provider, ext, extractCreator, keyExtractor, nil, onDuplicates)
 
 	f, err := os.Open(lom.FQN)
 	if err != nil {
@@ -481,6 +481,20 @@ func extAttrs(off int, from []byte) (n int, attr ObjectAttrs) {
 	off, attr.CksumType = extString(off, from)
 	off, attr.CksumValue = extString(off, from)
 	off, attr.Version = extString(off, from)
+	off, num := extInt64(off, from)
+	if num > 0 {
+		attr.CustomMD = make(cmn.SimpleKVs, num)
+		for i := int64(0); i < num; i++ {
+			var k, v string
+			off, k = extString(off, from)
+			off, v = extString(off, from)
+			attr.CustomMD[k] = v
+		}
+	}
+	off, attr.RetainUntil = extInt64(off, from)
+	off, hold := extInt64(off, from)
+	attr.LegalHold = hold != 0
+	off, attr.Ctime = extInt64(off, from)
 	return off, attr
 }
 
@@ -30,7 +30,7 @@ import (
 
 // transport defaults
 const (
-	maxHeaderSize  = 1024
+	maxHeaderSize  = 4*cmn.KiB + 2*cmn.MaxCustomMDSize // object names and user-defined metadata: in attrs and in EC Opaque
 	lastMarker     = math.MaxInt64
 	tickMarker     = math.MaxInt64 ^ 0xa5a5a5a5
 	tickUnit       = time.Second
@@ -109,11 +109,15 @@ type (
 
 	// object attrs
 	ObjectAttrs struct {
-		Atime      int64  // access time - nanoseconds since UNIX epoch
-		Size       int64  // size of objects in bytes
-		CksumType  string // checksum type
-		CksumValue string // checksum of the object produced by given checksum type
-		Version    string // version of the object
+		Atime       int64         // access time - nanoseconds since UNIX epoch
+		Size        int64         // size of objects in bytes
+		CksumType   string        // checksum type
+		CksumValue  string        // checksum of the object produced by given checksum type
+		Version     string        // version of the object
+		CustomMD    cmn.SimpleKVs // user-defined metadata
+		RetainUntil int64         // retention (WORM) - nanoseconds since UNIX epoch
+		LegalHold   bool          // retention (WORM) - legal hold
+		Ctime       int64         // creation time - nanoseconds since UNIX epoch
 	}
 	// object header
 	Header struct {
@@ -545,7 +549,15 @@ repeat:
 			}
 			return s.deactivate()
 		}
-		l := s.insHeader(s.sendoff.obj.Hdr)
+		l, err := s.insHeader(s.sendoff.obj.Hdr)
+		if err != nil {
+			// cannot be sent - complete with error and go on to the next one
+			err = fmt.Errorf("%s: %s/%s: %v", s, s.sendoff.obj.Hdr.Bck, s.sendoff.obj.Hdr.ObjName, err)
+			glog.Errorln(err)
+			s.cmplCh <- cmpl{s.sendoff.obj, err}
+			s.sendoff = sendoff{}
+			goto repeat
+		}
 		s.header = s.maxheader[:l]
 		return s.sendHdr(b)
 	case <-s.stopCh.Listen():
@@ -646,33 +658,40 @@ exit:
 //
 // stream helpers
 //
-func (s *Stream) insHeader(hdr Header) (l int) {
+func (s *Stream) insHeader(hdr Header) (l int, err error) {
+	to := s.maxheader
 	l = cmn.SizeofI64 * 2
-	l = insString(l, s.maxheader, hdr.Bck.Name)
-	l = insString(l, s.maxheader, hdr.ObjName)
-	l = insString(l, s.maxheader, hdr.Bck.Provider)
-	l = insString(l, s.maxheader, hdr.Bck.Ns.Name)
-	l = insString(l, s.maxheader, hdr.Bck.Ns.UUID)
-	l = insByte(l, s.maxheader, hdr.Opaque)
-	l = insAttrs(l, s.maxheader, hdr.ObjAttrs)
+	for _, str := range []string{hdr.Bck.Name, hdr.ObjName, hdr.Bck.Provider, hdr.Bck.Ns.Name, hdr.Bck.Ns.UUID} {
+		if l, err = insString(l, to, str); err != nil {
+			return
+		}
+	}
+	if l, err = insByte(l, to, hdr.Opaque); err != nil {
+		return
+	}
+	if l, err = insAttrs(l, to, hdr.ObjAttrs); err != nil {
+		return
+	}
 	hlen := l - cmn.SizeofI64*2
-	insInt64(0, s.maxheader, int64(hlen))
+	insInt64(0, to, int64(hlen))
 	checksum := xoshiro256.Hash(uint64(hlen))
-	insUint64(cmn.SizeofI64, s.maxheader, checksum)
+	insUint64(cmn.SizeofI64, to, checksum)
 	return
 }
 
-func insString(off int, to []byte, str string) int {
+func insString(off int, to []byte, str string) (int, error) {
 	return insByte(off, to, []byte(str))
 }
 
-func insByte(off int, to, b []byte) int {
+func insByte(off int, to, b []byte) (int, error) {
 	var l = len(b)
+	if off+cmn.SizeofI64+l > len(to) {
+		return off, errHeaderSize(off+cmn.SizeofI64+l, len(to))
+	}
 	binary.BigEndian.PutUint64(to[off:], uint64(l))
 	off += cmn.SizeofI64
-	n := copy(to[off:], b)
-	cmn.Assert(n == l)
-	return off + l
+	copy(to[off:], b)
+	return off + l, nil
 }
 
 func insInt64(off int, to []byte, i int64) int {
@@ -684,13 +703,48 @@ func insUint64(off int, to []byte, i uint64) int {
 	return off + cmn.SizeofI64
 }
 
-func insAttrs(off int, to []byte, attr ObjectAttrs) int {
-	off = insInt64(off, to, attr.Size)
-	off = insInt64(off, to, attr.Atime)
-	off = insString(off, to, attr.CksumType)
-	off = insString(off, to, attr.CksumValue)
-	off = insString(off, to, attr.Version)
-	return off
+func insInt64s(off int, to []byte, vals ...int64) (int, error) {
+	if off+len(vals)*cmn.SizeofI64 > len(to) {
+		return off, errHeaderSize(off+len(vals)*cmn.SizeofI64, len(to))
+	}
+	for _, i := range vals {
+		off = insInt64(off, to, i)
+	}
+	return off, nil
+}
+
+func insAttrs(off int, to []byte, attr ObjectAttrs) (int, error) {
+	var (
+		legalHold int64
+		err       error
+	)
+	if off, err = insInt64s(off, to, attr.Size, attr.Atime); err != nil {
+		return off, err
+	}
+	for _, str := range []string{attr.CksumType, attr.CksumValue, attr.Version} {
+		if off, err = insString(off, to, str); err != nil {
+			return off, err
+		}
+	}
+	if off, err = insInt64s(off, to, int64(len(attr.CustomMD))); err != nil {
+		return off, err
+	}
+	for k, v := range attr.CustomMD {
+		if off, err = insString(off, to, k); err != nil {
+			return off, err
+		}
+		if off, err = insString(off, to, v); err != nil {
+			return off, err
+		}
+	}
+	if attr.LegalHold {
+		legalHold = 1
+	}
+	return insInt64s(off, to, attr.RetainUntil, legalHold, attr.Ctime)
+}
+
+func errHeaderSize(size, max int) error {
+	return fmt.Errorf("header too large: %d bytes (max %d)", size, max)
 }
 
 //
@@ -21,6 +21,7 @@ package transport_test
 //
 
 import (
+	"bytes"
 	"encoding/binary"
 	"flag"
 	"fmt"
@@ -449,6 +450,61 @@ func Test_ObjAttrs(t *testing.T) {
 	}
 }
 
+// Object whose header does not fit is not sent: it gets completed with error
+// while the stream keeps going.
+func Test_HeaderTooLarge(t *testing.T) {
+	mux := mux.NewServeMux()
+	transport.SetMux("n1", mux)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	var receivedCount atomic.Int64
+	recvFunc := func(w http.ResponseWriter, hdr transport.Header, objReader io.Reader, err error) {
+		cmn.Assert(err == nil)
+		_, err = io.Copy(ioutil.Discard, objReader)
+		cmn.Assert(err == nil)
+		receivedCount.Inc()
+	}
+	path, err := transport.Register("n1", "toolarge", recvFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stream := transport.NewStream(transport.NewIntraDataClient(), ts.URL+path, nil)
+
+	var (
+		sendErr    error
+		customMD   = make(cmn.SimpleKVs, 1000)
+		callbackCh = make(chan struct{}, 1)
+	)
+	for i := 0; i < 1000; i++ {
+		customMD[fmt.Sprintf("k%d", i)] = "v"
+	}
+	hdr := transport.Header{
+		Bck:      cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS},
+		ObjName:  "large",
+		ObjAttrs: transport.ObjectAttrs{CustomMD: customMD},
+	}
+	cb := func(_ transport.Header, _ io.ReadCloser, _ unsafe.Pointer, err error) {
+		sendErr = err
+		callbackCh <- struct{}{}
+	}
+	if err := stream.Send(transport.Obj{Hdr: hdr, Reader: ioutil.NopCloser(&bytes.Buffer{}), Callback: cb}); err != nil {
+		t.Fatal(err)
+	}
+	hdr = transport.Header{Bck: cmn.Bck{Name: "bck", Provider: cmn.ProviderAIS}, ObjName: "small"}
+	if err := stream.Send(transport.Obj{Hdr: hdr, Reader: ioutil.NopCloser(&bytes.Buffer{})}); err != nil {
+		t.Fatal(err)
+	}
+	stream.Fin()
+	<-callbackCh
+	if sendErr == nil {
+		t.Error("expected the object with too large header to fail")
+	}
+	if receivedCount.Load() != 1 {
+		t.Fatalf("invalid received count: %d, expected: 1", receivedCount.Load())
+	}
+}
+
 //
 // test helpers
 //
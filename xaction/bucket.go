@@ -14,8 +14,10 @@ import (
 	"github.com/NVIDIA/aistore/cluster"
 	"github.com/NVIDIA/aistore/cmn"
 	"github.com/NVIDIA/aistore/ec"
+	"github.com/NVIDIA/aistore/housekeep/lifecycle"
 	"github.com/NVIDIA/aistore/memsys"
 	"github.com/NVIDIA/aistore/mirror"
+	"github.com/NVIDIA/aistore/replication"
 	"github.com/NVIDIA/aistore/stats"
 	"github.com/NVIDIA/aistore/tar2tf"
 )
@@ -191,9 +193,13 @@ func (r *registry) RenewObjsRedundancy(t cluster.Target) {
 	}
 }
 
-func (r *registry) RenewBckMakeNCopies(bck *cluster.Bck, t cluster.Target, copies int) {
+func (r *registry) RenewBckMakeNCopies(bck *cluster.Bck, t cluster.Target, copies int) *mirror.XactBckMakeNCopies {
 	e := &mncEntry{t: t, copies: copies}
-	_, _ = r.renewBucketXaction(e, bck)
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil {
+		return nil
+	}
+	return ee.Get().(*mirror.XactBckMakeNCopies)
 }
 
 //
@@ -253,6 +259,117 @@ func (e *loadLomCacheEntry) preRenewHook(_ bucketEntry) (bool, error) {
 	return true, nil
 }
 
+//
+// lifecycleEntry
+//
+type lifecycleEntry struct {
+	baseBckEntry
+	t    cluster.Target
+	xact *lifecycle.Xaction
+}
+
+func (e *lifecycleEntry) Start(bck cmn.Bck) error {
+	e.xact = lifecycle.NewXaction(e.t, bck)
+	return nil
+}
+func (*lifecycleEntry) Kind() string    { return cmn.ActLifecycle }
+func (e *lifecycleEntry) Get() cmn.Xact { return e.xact }
+
+func (e *lifecycleEntry) Stats(xact cmn.Xact) stats.XactStats {
+	cmn.Assert(xact == e.xact)
+	return e.xact.Stats()
+}
+
+// previous lifecycle is still running
+func (e *lifecycleEntry) preRenewHook(_ bucketEntry) (bool, error) {
+	return true, nil
+}
+
+// RenewLifecycle returns nil if the bucket's lifecycle xaction is already running
+func (r *registry) RenewLifecycle(t cluster.Target, bck *cluster.Bck) *lifecycle.Xaction {
+	e := &lifecycleEntry{t: t}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil || ee != bucketEntry(e) {
+		return nil
+	}
+	return e.xact
+}
+
+//
+// replBackfillEntry
+//
+type replBackfillEntry struct {
+	baseBckEntry
+	t    cluster.Target
+	xact *replication.Backfill
+}
+
+func (e *replBackfillEntry) Start(bck cmn.Bck) error {
+	e.xact = replication.NewBackfill(e.t, bck)
+	return nil
+}
+func (*replBackfillEntry) Kind() string    { return cmn.ActReplBackfill }
+func (e *replBackfillEntry) Get() cmn.Xact { return e.xact }
+
+func (e *replBackfillEntry) Stats(xact cmn.Xact) stats.XactStats {
+	cmn.Assert(xact == e.xact)
+	return e.xact.Stats()
+}
+
+// previous backfill is still running
+func (e *replBackfillEntry) preRenewHook(_ bucketEntry) (bool, error) {
+	return true, nil
+}
+
+// RenewReplBackfill returns nil if the bucket's backfill xaction is already running
+func (r *registry) RenewReplBackfill(t cluster.Target, bck *cluster.Bck) *replication.Backfill {
+	e := &replBackfillEntry{t: t}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil || ee != bucketEntry(e) {
+		return nil
+	}
+	return e.xact
+}
+
+//
+// ecReencodeEntry
+//
+type ecReencodeEntry struct {
+	baseBckEntry
+	t    cluster.Target
+	xact *ec.XactBckReencode
+}
+
+func (e *ecReencodeEntry) Start(bck cmn.Bck) error {
+	e.xact = ec.NewXactBckReencode(bck, e.t)
+	return nil
+}
+func (*ecReencodeEntry) Kind() string    { return cmn.ActECReencode }
+func (e *ecReencodeEntry) Get() cmn.Xact { return e.xact }
+
+func (e *ecReencodeEntry) Stats(xact cmn.Xact) stats.XactStats {
+	cmn.Assert(xact == e.xact)
+	s := &stats.ECReencodeStats{BaseXactStats: *stats.NewXactStats(e.xact)}
+	s.Ext.ScannedCount = e.xact.ScannedCnt()
+	s.Ext.FailedCount = e.xact.FailedCnt()
+	return s
+}
+
+// previous re-encoding is still running
+func (e *ecReencodeEntry) preRenewHook(_ bucketEntry) (bool, error) {
+	return true, nil
+}
+
+// RenewECReencode returns nil if the bucket's re-encoding xaction is already running
+func (r *registry) RenewECReencode(t cluster.Target, bck *cluster.Bck) *ec.XactBckReencode {
+	e := &ecReencodeEntry{t: t}
+	ee, err := r.renewBucketXaction(e, bck)
+	if err != nil || ee != bucketEntry(e) {
+		return nil
+	}
+	return e.xact
+}
+
 //
 // putLocReplicasEntry
 //
@@ -15,6 +15,7 @@ import (
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/fs"
 	"github.com/NVIDIA/aistore/housekeep/lru"
+	"github.com/NVIDIA/aistore/housekeep/scrub"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -47,6 +48,31 @@ func (e *lruEntry) Get() cmn.Xact { return e.xact }
 
 func (e *lruEntry) preRenewHook(_ globalEntry) bool { return true }
 
+//
+// scrubEntry
+//
+type scrubEntry struct {
+	baseGlobalEntry
+	id   string
+	t    cluster.Target
+	xact *scrub.Xaction
+}
+
+func (e *scrubEntry) Start(_ cmn.Bck) error {
+	e.xact = scrub.NewXaction(e.id, e.t)
+	return nil
+}
+
+func (e *scrubEntry) Kind() string  { return cmn.ActScrub }
+func (e *scrubEntry) Get() cmn.Xact { return e.xact }
+
+func (e *scrubEntry) Stats(xact cmn.Xact) stats.XactStats {
+	cmn.Assert(xact == e.xact)
+	return e.xact.Stats()
+}
+
+func (e *scrubEntry) preRenewHook(_ globalEntry) bool { return true }
+
 //
 // rebalanceEntry
 //
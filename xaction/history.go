@@ -0,0 +1,328 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/aistore/3rdparty/glog"
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/housekeep/hk"
+	"github.com/NVIDIA/aistore/stats"
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Xaction history is a bounded, on-disk journal of the finished xactions of
+// the node. The registry keeps the finished xactions only for a while (see:
+// cleanUpFinished) and in memory - the history survives both the cleanup
+// and the restart of the node.
+//
+// The records are appended to the journal file (one JSON record per line)
+// shortly after the xactions finish - by the housekeeper and right after the
+// xactions are aborted by the registry. The journal keeps at most
+// `historyMaxRecords` of the latest records: once the file grows twice as
+// large it is rewritten with only the latest ones.
+
+const (
+	HistoryFname = "xactions.history" // in the configuration directory
+
+	// Initiator of the xactions which have not been requested by a user.
+	InitiatorSystem = "system"
+
+	historyMaxRecords    = 1000
+	historyFlushInterval = 10 * time.Second
+)
+
+// Abort reasons recorded by the registry.
+const (
+	abortReasonRequest   = "aborted by request"
+	abortReasonBucket    = "bucket destroyed or evicted"
+	abortReasonMountpath = "mountpath added, removed, enabled or disabled"
+	abortReasonAll       = "node stopped or removed from the cluster"
+)
+
+type (
+	XactHistoryQuery struct {
+		Kind  string
+		Bck   cmn.Bck // only name is required
+		Since time.Time
+		Until time.Time
+		Limit int
+	}
+
+	history struct {
+		mtx        sync.Mutex
+		journalMtx sync.Mutex // serializes journalFinished (housekeeper, GetHistory, abort)
+		path       string
+		records    []*stats.XactRecord // oldest first
+		fileCnt    int                 // number of the records in the file
+
+		journaled    map[baseEntry]struct{} // finished entries (still in the registry) which have been journaled
+		initiators   map[cmn.Xact]string
+		abortReasons map[cmn.Xact]string
+	}
+)
+
+func newHistory() *history {
+	return &history{
+		journaled:    make(map[baseEntry]struct{}),
+		initiators:   make(map[cmn.Xact]string),
+		abortReasons: make(map[cmn.Xact]string),
+	}
+}
+
+// load reads the journal, records which cannot be parsed (eg. the last one,
+// partially written before the crash) are skipped.
+func (h *history) load(path string) error {
+	h.path = path
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		h.fileCnt++
+		rec := &stats.XactRecord{}
+		if err := jsoniter.Unmarshal(line, rec); err != nil {
+			glog.Warningf("xaction history %q: skipping invalid record: %v", path, err)
+			continue
+		}
+		h.records = append(h.records, rec)
+	}
+	h.trim()
+	return scanner.Err()
+}
+
+func (h *history) trim() {
+	if len(h.records) > historyMaxRecords {
+		h.records = append(h.records[:0:0], h.records[len(h.records)-historyMaxRecords:]...)
+	}
+}
+
+func (h *history) append(records []*stats.XactRecord) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	h.records = append(h.records, records...)
+	h.trim()
+	if h.path == "" {
+		return
+	}
+
+	var err error
+	if h.fileCnt+len(records) > 2*historyMaxRecords {
+		err = h.rewrite()
+	} else if err = writeRecords(h.path, os.O_APPEND, records); err == nil {
+		h.fileCnt += len(records)
+	}
+	if err != nil {
+		glog.Errorf("failed to write xaction history %q: %v", h.path, err)
+	}
+}
+
+// rewrite replaces the journal with the (latest) records kept in memory.
+func (h *history) rewrite() error {
+	tmpPath := h.path + ".tmp"
+	if err := writeRecords(tmpPath, os.O_TRUNC, h.records); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, h.path); err != nil {
+		return err
+	}
+	h.fileCnt = len(h.records)
+	return nil
+}
+
+func writeRecords(path string, flag int, records []*stats.XactRecord) error {
+	var buf bytes.Buffer
+	for _, rec := range records {
+		buf.Write(cmn.MustMarshal(rec))
+		buf.WriteByte('\n')
+	}
+	file, err := os.OpenFile(path, flag|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(buf.Bytes()); err == nil {
+		err = file.Sync()
+	}
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	return err
+}
+
+func (h *history) get(query XactHistoryQuery) []*stats.XactRecord {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	records := make([]*stats.XactRecord, 0, 16)
+	for idx := len(h.records) - 1; idx >= 0; idx-- {
+		rec := h.records[idx]
+		if query.Limit > 0 && len(records) == query.Limit {
+			break
+		}
+		if query.Kind != "" && rec.Kind() != query.Kind {
+			continue
+		}
+		if query.Bck.Name != "" && !matchHistoryBck(rec.Bck(), query.Bck) {
+			continue
+		}
+		if !query.Since.IsZero() && rec.EndTime().Before(query.Since) {
+			continue
+		}
+		if !query.Until.IsZero() && !rec.EndTime().Before(query.Until) {
+			continue
+		}
+		records = append(records, rec)
+	}
+	// oldest first
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records
+}
+
+// The bucket may not exist anymore, hence the provider and namespace are
+// matched only when specified.
+func matchHistoryBck(bck, query cmn.Bck) bool {
+	if bck.Name != query.Name {
+		return false
+	}
+	if query.Provider != "" && bck.Provider != query.Provider {
+		return false
+	}
+	return query.Ns.IsGlobal() || bck.Ns == query.Ns
+}
+
+//
+// registry
+//
+
+// InitHistory loads the xaction history of the node from the configuration
+// directory and starts journaling the finished xactions.
+func (r *registry) InitHistory(confdir string) {
+	if err := r.history.load(filepath.Join(confdir, HistoryFname)); err != nil {
+		glog.Errorf("failed to load xaction history: %v", err)
+	}
+	hk.Housekeeper.Register("xactions-history", r.journalFinished, historyFlushInterval)
+}
+
+// SetInitiator records who has requested the xaction (eg. the user), by
+// default the initiator is InitiatorSystem.
+func (r *registry) SetInitiator(xact cmn.Xact, initiator string) {
+	r.history.mtx.Lock()
+	r.history.initiators[xact] = initiator
+	r.history.mtx.Unlock()
+}
+
+func (r *registry) setAbortReason(xact cmn.Xact, reason string) {
+	r.history.mtx.Lock()
+	if _, ok := r.history.abortReasons[xact]; !ok {
+		r.history.abortReasons[xact] = reason
+	}
+	r.history.mtx.Unlock()
+}
+
+// GetHistory returns the records of the finished xactions (oldest first).
+func (r *registry) GetHistory(query XactHistoryQuery) []*stats.XactRecord {
+	r.journalFinished()
+	return r.history.get(query)
+}
+
+// journalFinished appends the records of xactions which have finished since
+// the last call to the history.
+func (r *registry) journalFinished() time.Duration {
+	var (
+		h        = r.history
+		finished = make([]baseEntry, 0, 8)
+		present  = make(map[cmn.Xact]struct{}, 64)
+	)
+	h.journalMtx.Lock()
+	defer h.journalMtx.Unlock()
+	r.entries.forEach(func(entry baseEntry) bool {
+		if cmn.XactsMeta[entry.Kind()].Type == cmn.XactTypeTask {
+			return true // tasks are short-lived queries (eg. list objects)
+		}
+		xact := entry.Get()
+		present[xact] = struct{}{}
+		if xact.Finished() {
+			finished = append(finished, entry)
+		}
+		return true
+	})
+
+	h.mtx.Lock()
+	var (
+		toJournal = finished[:0]
+		journaled = make(map[baseEntry]struct{}, len(finished))
+	)
+	for _, entry := range finished {
+		if _, ok := h.journaled[entry]; !ok {
+			toJournal = append(toJournal, entry)
+		}
+		journaled[entry] = struct{}{}
+	}
+	h.journaled = journaled
+	for xact := range h.initiators {
+		if _, ok := present[xact]; !ok {
+			delete(h.initiators, xact)
+		}
+	}
+	for xact := range h.abortReasons {
+		if _, ok := present[xact]; !ok {
+			delete(h.abortReasons, xact)
+		}
+	}
+	h.mtx.Unlock()
+
+	if len(toJournal) == 0 {
+		return historyFlushInterval
+	}
+	// NOTE: stats are collected outside of the locks (see: matchingXactsStats)
+	records := make([]*stats.XactRecord, 0, len(toJournal))
+	for _, entry := range toJournal {
+		xact := entry.Get()
+		xs := entry.Stats(xact)
+		records = append(records, &stats.XactRecord{
+			BaseXactStats: stats.BaseXactStats{
+				IDX:         xs.ID(),
+				KindX:       xs.Kind(),
+				BckX:        xs.Bck(),
+				StartTimeX:  xs.StartTime(),
+				EndTimeX:    xs.EndTime(),
+				ObjCountX:   xs.ObjCount(),
+				BytesCountX: xs.BytesCount(),
+				AbortedX:    xs.Aborted(),
+			},
+		})
+	}
+	h.mtx.Lock()
+	for i, entry := range toJournal {
+		xact := entry.Get()
+		records[i].Initiator = InitiatorSystem
+		if initiator, ok := h.initiators[xact]; ok {
+			records[i].Initiator = initiator
+		}
+		if records[i].AbortedX {
+			records[i].AbortReason = h.abortReasons[xact]
+		}
+	}
+	h.mtx.Unlock()
+	h.append(records)
+	return historyFlushInterval
+}
@@ -0,0 +1,155 @@
+// Package xaction provides core functionality for the AIStore extended actions.
+/*
+ * Copyright (c) 2020, NVIDIA CORPORATION. All rights reserved.
+ */
+package xaction
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/aistore/cmn"
+	"github.com/NVIDIA/aistore/stats"
+	"github.com/NVIDIA/aistore/tutils/tassert"
+)
+
+func newXactRecord(kind, bucket string, end time.Time) *stats.XactRecord {
+	return &stats.XactRecord{
+		BaseXactStats: stats.BaseXactStats{
+			KindX:      kind,
+			BckX:       cmn.Bck{Name: bucket, Provider: cmn.ProviderAIS},
+			StartTimeX: end.Add(-time.Minute),
+			EndTimeX:   end,
+		},
+		Initiator: InitiatorSystem,
+	}
+}
+
+func TestXactionHistoryPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "xhistory")
+	tassert.CheckFatal(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, HistoryFname)
+
+	var (
+		h   = newHistory()
+		now = time.Now()
+	)
+	tassert.CheckFatal(t, h.load(path))
+	for i := 0; i < 3*historyMaxRecords; i++ {
+		h.append([]*stats.XactRecord{newXactRecord(cmn.ActLRU, "", now.Add(time.Duration(i)*time.Second))})
+	}
+	tassert.Errorf(t, len(h.records) == historyMaxRecords, "expected %d records, got %d", historyMaxRecords, len(h.records))
+	tassert.Errorf(t, h.fileCnt <= 2*historyMaxRecords, "expected the journal to be rewritten, got %d records", h.fileCnt)
+
+	// Partially written record (eg. crash) must be skipped.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	tassert.CheckFatal(t, err)
+	_, err = f.WriteString(`{"kind": "lr`)
+	tassert.CheckFatal(t, err)
+	f.Close()
+
+	loaded := newHistory()
+	tassert.CheckFatal(t, loaded.load(path))
+	tassert.Fatalf(t, len(loaded.records) == historyMaxRecords, "expected %d records to be loaded, got %d",
+		historyMaxRecords, len(loaded.records))
+	for i, rec := range loaded.records {
+		expected := h.records[i]
+		tassert.Errorf(t, rec.Kind() == expected.Kind() && rec.EndTime().Equal(expected.EndTime()),
+			"record %d: expected %+v, got %+v", i, expected, rec)
+	}
+}
+
+func TestXactionHistoryQuery(t *testing.T) {
+	var (
+		h   = newHistory()
+		now = time.Now()
+	)
+	h.append([]*stats.XactRecord{
+		newXactRecord(cmn.ActLRU, "", now.Add(-3*time.Hour)),
+		newXactRecord(cmn.ActMakeNCopies, "bck1", now.Add(-2*time.Hour)),
+		newXactRecord(cmn.ActMakeNCopies, "bck2", now.Add(-time.Hour)),
+		newXactRecord(cmn.ActLRU, "", now),
+	})
+
+	tests := []struct {
+		name  string
+		query XactHistoryQuery
+		cnt   int
+	}{
+		{name: "all", query: XactHistoryQuery{}, cnt: 4},
+		{name: "kind", query: XactHistoryQuery{Kind: cmn.ActMakeNCopies}, cnt: 2},
+		{name: "bucket", query: XactHistoryQuery{Bck: cmn.Bck{Name: "bck1"}}, cnt: 1},
+		{name: "provider", query: XactHistoryQuery{Bck: cmn.Bck{Name: "bck1", Provider: cmn.ProviderAmazon}}, cnt: 0},
+		{name: "since", query: XactHistoryQuery{Since: now.Add(-90 * time.Minute)}, cnt: 2},
+		{name: "until", query: XactHistoryQuery{Until: now.Add(-time.Hour)}, cnt: 2},
+		{name: "range", query: XactHistoryQuery{Since: now.Add(-2 * time.Hour), Until: now}, cnt: 2},
+		{name: "limit", query: XactHistoryQuery{Kind: cmn.ActLRU, Limit: 1}, cnt: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			records := h.get(test.query)
+			tassert.Fatalf(t, len(records) == test.cnt, "expected %d records, got %d", test.cnt, len(records))
+			for i := 1; i < len(records); i++ {
+				tassert.Errorf(t, !records[i].EndTime().Before(records[i-1].EndTime()), "expected oldest record first")
+			}
+		})
+	}
+
+	// limit selects the latest records
+	records := h.get(XactHistoryQuery{Limit: 1})
+	tassert.Fatalf(t, len(records) == 1, "expected 1 record, got %d", len(records))
+	tassert.Errorf(t, records[0].EndTime().Equal(now), "expected the latest record, got %+v", records[0])
+}
+
+func TestXactionHistoryJournal(t *testing.T) {
+	xactions := newRegistry()
+	defer xactions.AbortAll()
+
+	xact := xactions.RenewLRU("")
+	tassert.Fatalf(t, xact != nil, "expected LRU xaction to be created")
+	xactions.SetInitiator(xact, "admin")
+	tassert.Errorf(t, len(xactions.GetHistory(XactHistoryQuery{})) == 0, "expected no records for running xaction")
+	xact.EndTime(time.Now())
+
+	xact = xactions.RenewLRU("")
+	tassert.Fatalf(t, xact != nil, "expected LRU xaction to be created")
+	xactions.AbortAll()
+
+	records := xactions.GetHistory(XactHistoryQuery{Kind: cmn.ActLRU})
+	tassert.Fatalf(t, len(records) == 2, "expected 2 records, got %d", len(records))
+	tassert.Errorf(t, !records[0].Aborted() && records[0].Initiator == "admin",
+		"expected finished xaction initiated by admin, got %+v", records[0])
+	tassert.Errorf(t, records[1].Aborted() && records[1].AbortReason == abortReasonAll && records[1].Initiator == InitiatorSystem,
+		"expected xaction aborted by the system, got %+v", records[1])
+
+	// already journaled xactions are not journaled again
+	records = xactions.GetHistory(XactHistoryQuery{})
+	tassert.Errorf(t, len(records) == 2, "expected 2 records, got %d", len(records))
+}
+
+func TestXactionHistoryJournalConcurrent(t *testing.T) {
+	xactions := newRegistry()
+	defer xactions.AbortAll()
+
+	for i := 0; i < 10; i++ {
+		xact := xactions.RenewLRU("")
+		tassert.Fatalf(t, xact != nil, "expected LRU xaction to be created")
+		xact.EndTime(time.Now())
+	}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			xactions.journalFinished()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+	records := xactions.GetHistory(XactHistoryQuery{})
+	tassert.Errorf(t, len(records) == 10, "expected 10 records, got %d", len(records))
+}
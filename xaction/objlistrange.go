@@ -8,7 +8,6 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"os"
 
 	"github.com/NVIDIA/aistore/3rdparty/glog"
 	"github.com/NVIDIA/aistore/cluster"
@@ -47,43 +46,6 @@ func parseTemplate(template string) (cmn.ParsedTemplate, error) {
 // Evict/Delete/Prefect
 //
 
-func (r *EvictDelete) objDelete(args *DeletePrefetchArgs, lom *cluster.LOM) error {
-	var (
-		cloudErr   error
-		delFromAIS bool
-	)
-	lom.Lock(true)
-	defer lom.Unlock(true)
-
-	delFromCloud := lom.Bck().IsRemote() && !args.Evict
-	if err := lom.Load(false); err == nil {
-		delFromAIS = true
-	} else if !cmn.IsErrObjNought(err) {
-		return err
-	}
-
-	if delFromCloud {
-		if err, _ := r.t.Cloud(lom.Bck()).DeleteObj(args.Ctx, lom); err != nil {
-			cloudErr = err
-		}
-	}
-	if delFromAIS {
-		errRet := lom.Remove()
-		if errRet != nil {
-			if !os.IsNotExist(errRet) {
-				if cloudErr != nil {
-					glog.Errorf("%s: failed to delete from cloud: %v", lom, cloudErr)
-				}
-				return errRet
-			}
-		}
-		if args.Evict {
-			cmn.Assert(lom.Bck().IsRemote())
-		}
-	}
-	return cloudErr
-}
-
 func (r *EvictDelete) doObjEvictDelete(args *DeletePrefetchArgs, objName string) error {
 	lom := &cluster.LOM{T: r.t, ObjName: objName}
 	err := lom.Init(r.Bck())
@@ -91,11 +53,16 @@ func (r *EvictDelete) doObjEvictDelete(args *DeletePrefetchArgs, objName string)
 		glog.Error(err)
 		return nil
 	}
-	err = r.objDelete(args, lom)
+	// the target's delete path takes care of EC slices, replicas, and cross-cluster replication
+	err, _ = r.t.DeleteObject(args.Ctx, lom, args.Evict)
 	if err != nil {
 		if cmn.IsObjNotExist(err) {
 			return nil
 		}
+		if cmn.IsErrObjRetained(err) {
+			glog.Warning(err) // skip retained object
+			return nil
+		}
 		httpErr, ok := err.(*cmn.HTTPError)
 		if ok && httpErr.Status == http.StatusNotFound {
 			return nil
@@ -17,6 +17,7 @@ import (
 	"github.com/NVIDIA/aistore/downloader"
 	"github.com/NVIDIA/aistore/housekeep/hk"
 	"github.com/NVIDIA/aistore/housekeep/lru"
+	"github.com/NVIDIA/aistore/housekeep/scrub"
 	"github.com/NVIDIA/aistore/stats"
 )
 
@@ -50,6 +51,8 @@ type (
 		// xactions matching type `ty` will be aborted.
 		all bool
 		ty  string
+
+		reason string // recorded in the xaction history
 	}
 
 	taskState struct {
@@ -111,6 +114,9 @@ type (
 		// All entries in the registry. The entries are periodically cleaned up
 		// to make sure that we don't keep old entries forever.
 		entries *registryEntries
+
+		// Finished xactions, persisted (see: history.go).
+		history *history
 	}
 )
 
@@ -266,6 +272,7 @@ func (e *registryEntries) len() int {
 func newRegistry() *registry {
 	xar := &registry{
 		entries: newRegistryEntries(),
+		history: newHistory(),
 	}
 	hk.Housekeeper.Register("xactions", xar.cleanUpFinished)
 	return xar
@@ -292,7 +299,7 @@ func (r *registry) GetLatest(query XactQuery) baseEntry {
 // It not only stops the "bucket xactions" but possibly "task xactions" which
 // are running on given bucket.
 func (r *registry) AbortAllBuckets(bcks ...*cluster.Bck) {
-	r.abort(abortArgs{bcks: bcks})
+	r.abort(abortArgs{bcks: bcks, reason: abortReasonBucket})
 }
 
 // AbortAll waits until abort of all xactions is finished
@@ -302,11 +309,11 @@ func (r *registry) AbortAll(tys ...string) {
 	if len(tys) > 0 {
 		ty = tys[0]
 	}
-	r.abort(abortArgs{all: true, ty: ty})
+	r.abort(abortArgs{all: true, ty: ty, reason: abortReasonAll})
 }
 
 func (r *registry) AbortAllMountpathsXactions() {
-	r.abort(abortArgs{mountpaths: true})
+	r.abort(abortArgs{mountpaths: true, reason: abortReasonMountpath})
 }
 
 func (r *registry) abort(args abortArgs) {
@@ -337,6 +344,7 @@ func (r *registry) abort(args abortArgs) {
 		}
 
 		if abort {
+			r.setAbortReason(xact, args.reason)
 			wg.Add(1)
 			go func() {
 				xact.Abort()
@@ -346,6 +354,7 @@ func (r *registry) abort(args abortArgs) {
 		return true
 	})
 	wg.Wait()
+	r.journalFinished()
 }
 
 func (r *registry) IsXactRunning(query XactQuery) (running bool) {
@@ -468,6 +477,7 @@ func (r *registry) DoAbort(kind string, bck *cluster.Bck) (aborted bool) {
 		if entry == nil {
 			return false
 		}
+		r.setAbortReason(entry.Get(), abortReasonRequest)
 		entry.Get().Abort()
 		return true
 	}
@@ -647,6 +657,17 @@ func (r *registry) RenewLRU(id string) *lru.Xaction {
 	return entry.xact
 }
 
+// RenewScrub returns nil if the scrubber is already running
+func (r *registry) RenewScrub(id string, t cluster.Target) *scrub.Xaction {
+	e := &scrubEntry{id: id, t: t}
+	ee, keep, _ := r.renewGlobalXaction(e)
+	entry := ee.(*scrubEntry)
+	if keep { // previous scrubber is still running
+		return nil
+	}
+	return entry.xact
+}
+
 func (r *registry) RenewRebalance(id int64, statRunner *stats.Trunner) *Rebalance {
 	e := &rebalanceEntry{id: rebID(id), statRunner: statRunner}
 	ee, keep, _ := r.renewGlobalXaction(e)